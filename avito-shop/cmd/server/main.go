@@ -3,9 +3,12 @@ package main
 import (
 	api "avito-shop/internal/api"
 	db "avito-shop/internal/db/sqlc"
+	"avito-shop/internal/storage"
 	"avito-shop/internal/util"
+	"avito-shop/internal/worker"
 	"context"
 	"log"
+	"os"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
@@ -18,9 +21,64 @@ func main() {
 		log.Fatal("can't load configurations: ", err)
 	}
 
-	tokenConfig := api.TokenConfig{
-		TokenSymmetricKey:   config.TokenKey,
-		AccessTokenDuration: 24 * time.Hour,
+	welcomeByDepartment, err := util.ParseAmounts(config.WelcomeBalanceByDepartment)
+	if err != nil {
+		log.Fatal("can't parse WELCOME_BALANCE_BY_DEPARTMENT: ", err)
+	}
+
+	if config.MarketplaceCommissionPercent < 0 || config.MarketplaceCommissionPercent >= 100 {
+		log.Fatal("MARKETPLACE_COMMISSION_PERCENT must be between 0 and 99")
+	}
+
+	// Периоды фоновых задач обязательны: time.NewTicker не принимает нулевой период
+	intervals := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"SETTLEMENT_INTERVAL", config.SettlementInterval},
+		{"COIN_EXPIRY_INTERVAL", config.CoinExpiryInterval},
+		{"PRICE_SCHEDULE_INTERVAL", config.PriceScheduleInterval},
+		{"WISHLIST_CHECK_INTERVAL", config.WishlistCheckInterval},
+		{"AUCTION_CLOSE_INTERVAL", config.AuctionCloseInterval},
+		{"RAFFLE_DRAW_INTERVAL", config.RaffleDrawInterval},
+		{"CART_RESERVATION_SWEEP_INTERVAL", config.CartReservationSweepInterval},
+	}
+	for _, interval := range intervals {
+		if interval.value <= 0 {
+			log.Fatal(interval.name, " must be a positive duration")
+		}
+	}
+
+	serverConfig := api.Config{
+		TokenConfig: api.TokenConfig{
+			TokenSymmetricKey:   config.TokenKey,
+			AccessTokenDuration: 24 * time.Hour,
+		},
+		TransferConfig: api.TransferConfig{
+			TransferHoldPeriod: config.TransferHoldPeriod,
+			TransferLimits: db.TransferLimits{
+				MaxAmount:    config.TransferMaxAmount,
+				DailyLimit:   config.TransferDailyLimit,
+				WeeklyLimit:  config.TransferWeeklyLimit,
+				MaxPerMinute: config.TransferMaxPerMinute,
+			},
+		},
+		OnboardingConfig: api.OnboardingConfig{
+			WelcomeBalance:             config.WelcomeBalance,
+			WelcomeBalanceByDepartment: welcomeByDepartment,
+		},
+		CoinConfig: api.CoinConfig{
+			CoinTTL: config.CoinTTL,
+		},
+		RefundConfig: api.RefundConfig{
+			ReturnWindow: config.ReturnWindow,
+		},
+		MarketplaceConfig: api.MarketplaceConfig{
+			CommissionPercent: config.MarketplaceCommissionPercent,
+		},
+		CartConfig: api.CartConfig{
+			ReservationTTL: config.CartReservationTTL,
+		},
 	}
 
 	conn, err := pgxpool.New(context.Background(), config.DBSource)
@@ -30,7 +88,37 @@ func main() {
 	defer conn.Close()
 
 	store := db.NewStore(conn)
-	server, err := api.NewServer(store, tokenConfig)
+
+	// Без каталога изображений сервер работает, но загрузка изображений отключена
+	if config.MediaDir != "" {
+		blobs, err := storage.NewLocalStorage(config.MediaDir, config.MediaURLPrefix)
+		if err != nil {
+			log.Fatal("can't open media storage: ", err)
+		}
+		serverConfig.Blobs = blobs
+	}
+
+	// Подкоманда сверки балансов: go run ./cmd/server reconcile [-fix]
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		code := runReconcile(context.Background(), store, os.Args[2:])
+		conn.Close()
+		os.Exit(code)
+	}
+
+	// Фоновое зачисление отложенных переводов, сжигание просроченных монет,
+	// ввод в действие запланированных цен, уведомления по спискам желаний
+	// и снятие истекших резервов корзин
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go worker.NewSettlementWorker(store, config.SettlementInterval).Start(ctx)
+	go worker.NewExpiryWorker(store, config.CoinExpiryInterval).Start(ctx)
+	go worker.NewPriceWorker(store, config.PriceScheduleInterval).Start(ctx)
+	go worker.NewWishlistWorker(store, config.WishlistCheckInterval).Start(ctx)
+	go worker.NewAuctionWorker(store, config.AuctionCloseInterval).Start(ctx)
+	go worker.NewRaffleWorker(store, config.RaffleDrawInterval).Start(ctx)
+	go worker.NewReservationWorker(store, config.CartReservationSweepInterval).Start(ctx)
+
+	server, err := api.NewServer(store, serverConfig)
 
 	err = server.Start(config.Address)
 	if err != nil {
@@ -0,0 +1,40 @@
+package main
+
+import (
+	api "avito-shop/internal/api"
+	db "avito-shop/internal/db/sqlc"
+	"context"
+	"encoding/json"
+	"flag"
+	"log"
+	"os"
+)
+
+// runReconcile выполняет подкоманду "reconcile": печатает отчет сверки балансов
+// в формате JSON и возвращает код выхода 1, если найдены расхождения.
+// С флагом -fix записывает корректирующие операции.
+func runReconcile(ctx context.Context, store db.Store, args []string) int {
+	flags := flag.NewFlagSet("reconcile", flag.ContinueOnError)
+	fix := flags.Bool("fix", false, "write correcting adjustments for users whose balance drifted from the ledger")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	result, err := store.ReconcileBalancesTx(ctx, db.ReconcileBalancesTxParams{Fix: *fix})
+	if err != nil {
+		log.Printf("reconciliation failed: %v", err)
+		return 1
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err = encoder.Encode(api.NewReconciliationResponse(result)); err != nil {
+		log.Printf("can't write reconciliation report: %v", err)
+		return 1
+	}
+
+	if !result.Consistent() && !*fix {
+		return 1
+	}
+	return 0
+}
@@ -2,11 +2,15 @@ package api
 
 import (
 	db "avito-shop/internal/db/sqlc"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -21,6 +25,16 @@ type SendCoinRequest struct {
 	Amount int32  `json:"amount" binding:"required,gt=0"`
 }
 
+// BuyItemRequest - необязательные параметры покупки в подарок: /api/buy/:item?recipient=...&message=...
+type BuyItemRequest struct {
+	Recipient string `form:"recipient"`
+	Message   string `form:"message" binding:"max=200"`
+	// Variant - артикул варианта, обязателен для товаров с вариантами
+	Variant string `form:"variant"`
+	// Promo - промокод на скидку
+	Promo string `form:"promo" binding:"max=50"`
+}
+
 // type InfoResponse struct {
 // 	Balance      int32                   `json:"balance"`
 // 	Purchases    []db.GetPurchasesRow    `json:"purchases"`
@@ -31,18 +45,75 @@ type InfoResponse struct {
 	Coins     int32 `json:"coins"`
 	Inventory []struct {
 		Type     string `json:"type"`
+		Variant  string `json:"variant,omitempty"`
 		Quantity int32  `json:"quantity"`
+		// Received - сколько единиц получено от других пользователей
+		Received int32 `json:"received,omitempty"`
+		// Orders - сколько единиц в каждом статусе выдачи
+		Orders map[string]int32 `json:"orders,omitempty"`
 	} `json:"inventory"`
 	CoinHistory struct {
 		Received []struct {
 			FromUser string `json:"fromUser"`
 			Amount   int32  `json:"amount"`
+			Kind     string `json:"kind,omitempty"`
 		} `json:"received"`
 		Sent []struct {
 			ToUser string `json:"toUser"`
 			Amount int32  `json:"amount"`
+			Kind   string `json:"kind,omitempty"`
 		} `json:"sent"`
 	} `json:"coinHistory"`
+	Pending        PendingCoins       `json:"pending"`
+	TransferLimits TransferLimitsInfo `json:"transferLimits"`
+	ExpiringCoins  []ExpiringCoins    `json:"expiringCoins"`
+	Gifts          GiftsInfo          `json:"gifts"`
+}
+
+// Gift - подарок, отправленный другому пользователю или полученный от него
+type Gift struct {
+	ID       int32     `json:"id"`
+	FromUser string    `json:"fromUser,omitempty"`
+	ToUser   string    `json:"toUser,omitempty"`
+	Item     string    `json:"item"`
+	Quantity int32     `json:"quantity"`
+	Message  string    `json:"message,omitempty"`
+	Date     time.Time `json:"date"`
+}
+
+// GiftsInfo - отправленные и полученные подарки
+type GiftsInfo struct {
+	Sent     []Gift `json:"sent"`
+	Received []Gift `json:"received"`
+}
+
+// ExpiringCoins - сколько монет сгорит в указанный день
+type ExpiringCoins struct {
+	Amount    int32  `json:"amount"`
+	ExpiresOn string `json:"expiresOn"`
+}
+
+// TransferLimitsInfo - остаток лимитов на исходящие переводы; null - без ограничения
+type TransferLimitsInfo struct {
+	MaxAmount       *int32 `json:"maxAmount"`
+	DailyRemaining  *int32 `json:"dailyRemaining"`
+	WeeklyRemaining *int32 `json:"weeklyRemaining"`
+	MinuteRemaining *int32 `json:"minuteRemaining"`
+}
+
+// PendingTransfer - перевод, удерживаемый до истечения окна отмены
+type PendingTransfer struct {
+	ID        int32     `json:"id"`
+	FromUser  string    `json:"fromUser,omitempty"`
+	ToUser    string    `json:"toUser,omitempty"`
+	Amount    int32     `json:"amount"`
+	SettlesAt time.Time `json:"settlesAt"`
+}
+
+// PendingCoins - монеты в эскроу, не входящие в доступный баланс
+type PendingCoins struct {
+	Sent     []PendingTransfer `json:"sent"`
+	Received []PendingTransfer `json:"received"`
 }
 
 // GET /api/info
@@ -62,7 +133,12 @@ func (server *Server) handleGetInfo(c *gin.Context) {
 		return
 	}
 
-	balance, err := server.store.GetCurrentBalance(c, user.ID)
+	// Показываем только монеты, которые можно потратить: просроченные лоты
+	// остаются на балансе до следующего прохода воркера сгорания
+	balance, err := server.store.GetSpendableBalance(c, db.GetSpendableBalanceParams{
+		UserID: user.ID,
+		Now:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
@@ -73,103 +149,260 @@ func (server *Server) handleGetInfo(c *gin.Context) {
 		Received []struct {
 			FromUser string `json:"fromUser"`
 			Amount   int32  `json:"amount"`
+			Kind     string `json:"kind,omitempty"`
 		} `json:"received"`
 		Sent []struct {
 			ToUser string `json:"toUser"`
 			Amount int32  `json:"amount"`
+			Kind   string `json:"kind,omitempty"`
 		} `json:"sent"`
 	}{}
 
 	for _, t := range transactions {
+		// Вид операции показываем только для операций с системным счетом
+		var kind string
+		if t.Kind != db.TransactionKindTransfer {
+			kind = t.Kind
+		}
+
 		if t.ReceiverUsername == username {
 			coinHistory.Received = append(coinHistory.Received, struct {
 				FromUser string `json:"fromUser"`
 				Amount   int32  `json:"amount"`
+				Kind     string `json:"kind,omitempty"`
 			}{
 				FromUser: t.SenderUsername,
 				Amount:   t.Amount,
+				Kind:     kind,
 			})
 		} else {
 			coinHistory.Sent = append(coinHistory.Sent, struct {
 				ToUser string `json:"toUser"`
 				Amount int32  `json:"amount"`
+				Kind   string `json:"kind,omitempty"`
 			}{
 				ToUser: t.ReceiverUsername,
 				Amount: t.Amount,
+				Kind:   kind,
 			})
 		}
 	}
 
-	// Получаем и группируем инвентарь
-	purchases, err := server.store.GetPurchases(c, userIDPg)
+	// Получаем отложенные переводы, которые еще не зачислены
+	pendingTransfers, err := server.store.GetPendingTransfers(c, userIDPg)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	inventory := make(map[string]int32)
-	for _, p := range purchases {
-		inventory[p.Name] += p.Quantity
+	var pending PendingCoins
+	for _, t := range pendingTransfers {
+		if t.ReceiverUsername == username {
+			pending.Received = append(pending.Received, PendingTransfer{
+				ID:        t.ID,
+				FromUser:  t.SenderUsername,
+				Amount:    t.Amount,
+				SettlesAt: t.SettlesAt.Time,
+			})
+		} else {
+			pending.Sent = append(pending.Sent, PendingTransfer{
+				ID:        t.ID,
+				ToUser:    t.ReceiverUsername,
+				Amount:    t.Amount,
+				SettlesAt: t.SettlesAt.Time,
+			})
+		}
 	}
 
-	var inventoryResponse []struct {
-		Type     string `json:"type"`
-		Quantity int32  `json:"quantity"`
+	// Получаем ближайшие сгорания монет
+	expirations, err := server.store.GetUpcomingExpirations(c, db.GetUpcomingExpirationsParams{
+		UserID: user.ID,
+		Now:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
 	}
-	for itemType, quantity := range inventory {
-		inventoryResponse = append(inventoryResponse, struct {
-			Type     string `json:"type"`
-			Quantity int32  `json:"quantity"`
-		}{
-			Type:     itemType,
-			Quantity: quantity,
+
+	expiringCoins := make([]ExpiringCoins, 0, len(expirations))
+	for _, e := range expirations {
+		expiringCoins = append(expiringCoins, ExpiringCoins{
+			Amount:    e.Amount,
+			ExpiresOn: e.ExpiresOn.Time.Format(time.DateOnly),
 		})
 	}
 
+	// Считаем остаток лимитов на переводы
+	var stats db.GetOutgoingTransferStatsRow
+	limits := server.config.TransferLimits
+	if limits.Enabled() {
+		stats, err = server.store.GetOutgoingTransferStats(c, db.NewTransferStatsParams(user.ID, time.Now()))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+	allowance := limits.Allowance(stats)
+
+	// Получаем и группируем инвентарь: только единицы, которыми пользователь владеет сейчас
+	units, err := server.store.GetInventory(c, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Группируем по товару и варианту в порядке выборки (сначала недавно полученные):
+	// разные варианты одного товара показываются отдельными позициями, а полученные
+	// от других пользователей единицы входят в количество и показываются отдельно
+	type inventoryKey struct {
+		name    string
+		variant string
+	}
+	var inventoryResponse []struct {
+		Type     string           `json:"type"`
+		Variant  string           `json:"variant,omitempty"`
+		Quantity int32            `json:"quantity"`
+		Received int32            `json:"received,omitempty"`
+		Orders   map[string]int32 `json:"orders,omitempty"`
+	}
+	groups := make(map[inventoryKey]int)
+	for _, u := range units {
+		key := inventoryKey{name: u.Name, variant: u.Variant.String}
+		i, ok := groups[key]
+		if !ok {
+			i = len(inventoryResponse)
+			groups[key] = i
+			inventoryResponse = append(inventoryResponse, struct {
+				Type     string           `json:"type"`
+				Variant  string           `json:"variant,omitempty"`
+				Quantity int32            `json:"quantity"`
+				Received int32            `json:"received,omitempty"`
+				Orders   map[string]int32 `json:"orders,omitempty"`
+			}{Type: key.name, Variant: key.variant})
+		}
+
+		group := &inventoryResponse[i]
+		group.Quantity += u.Quantity
+		group.Received += u.Received
+		if u.OrderStatus.Valid {
+			if group.Orders == nil {
+				group.Orders = make(map[string]int32)
+			}
+			group.Orders[u.OrderStatus.String] += u.Quantity
+		}
+	}
+
+	// Подарки видны и покупателю, и получателю
+	giftRows, err := server.store.GetGifts(c, userIDPg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	var gifts GiftsInfo
+	for _, g := range giftRows {
+		gift := Gift{
+			ID:       g.ID,
+			Item:     g.Name,
+			Quantity: g.Quantity,
+			Message:  g.GiftMessage.String,
+			Date:     g.PurchaseDate.Time,
+		}
+		if g.RecipientUsername == username {
+			gift.FromUser = g.BuyerUsername
+			gifts.Received = append(gifts.Received, gift)
+		} else {
+			gift.ToUser = g.RecipientUsername
+			gifts.Sent = append(gifts.Sent, gift)
+		}
+	}
+
 	response := gin.H{
 		"coins":       balance,
 		"inventory":   inventoryResponse,
 		"coinHistory": coinHistory,
+		"pending":     pending,
+		"transferLimits": TransferLimitsInfo{
+			MaxAmount:       allowance.MaxAmount,
+			DailyRemaining:  allowance.DailyRemaining,
+			WeeklyRemaining: allowance.WeeklyRemaining,
+			MinuteRemaining: allowance.MinuteRemaining,
+		},
+		"expiringCoins": expiringCoins,
+		"gifts":         gifts,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
 
-// POST /api/sendCoin
-func (server *Server) handleSendCoin(c *gin.Context) {
+// transferParams разбирает запрос перевода и находит отправителя и получателя
+func (server *Server) transferParams(c *gin.Context) (db.TransferTxParams, bool) {
 	var req SendCoinRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, errorResponse(err))
-		return
+		return db.TransferTxParams{}, false
 	}
 
 	senderUsername := c.MustGet("username").(string)
 	sender, err := server.store.GetUserByUsername(c, senderUsername)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return db.TransferTxParams{}, false
 	}
 
 	receiver, err := server.store.GetUserByUsername(c, req.ToUser)
 	if err != nil {
 		c.JSON(http.StatusNotFound, errorResponse(err))
-		return
+		return db.TransferTxParams{}, false
 	}
 
 	if sender.ID == receiver.ID {
 		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("cannot send coins to yourself")))
-		return
+		return db.TransferTxParams{}, false
 	}
 
-	arg := db.TransferTxParams{
+	return db.TransferTxParams{
 		FromUserID: sender.ID,
 		ToUserID:   receiver.ID,
 		Amount:     req.Amount,
+		HoldPeriod: server.config.TransferHoldPeriod,
+		Limits:     server.config.TransferLimits,
+	}, true
+}
+
+// transferLimitBody описывает превышенный лимит переводов
+func transferLimitBody(limitErr *db.TransferLimitError) gin.H {
+	return gin.H{
+		"error":     limitErr.Error(),
+		"limit":     limitErr.Limit,
+		"remaining": limitErr.Remaining,
+	}
+}
+
+// transferLimitResponse отвечает на превышение лимита переводов: частота - 429, сумма - 400
+func transferLimitResponse(c *gin.Context, limitErr *db.TransferLimitError) {
+	status := http.StatusBadRequest
+	if limitErr.Limit == db.TransferLimitRate {
+		status = http.StatusTooManyRequests
+	}
+	c.JSON(status, transferLimitBody(limitErr))
+}
+
+// POST /api/sendCoin
+func (server *Server) handleSendCoin(c *gin.Context) {
+	arg, ok := server.transferParams(c)
+	if !ok {
+		return
 	}
 
-	_, err = server.store.TransferTx(c, arg)
+	result, err := server.store.TransferTx(c, arg)
 	if err != nil {
-		if strings.Contains(err.Error(), "CHECK constraint") {
+		var limitErr *db.TransferLimitError
+		if errors.As(err, &limitErr) {
+			transferLimitResponse(c, limitErr)
+			return
+		}
+		if errors.Is(err, db.ErrInsufficientBalance) || strings.Contains(err.Error(), "CHECK constraint") {
 			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("insufficient balance")))
 			return
 		}
@@ -177,15 +410,94 @@ func (server *Server) handleSendCoin(c *gin.Context) {
 		return
 	}
 
+	if result.Transfer.Status == db.TransferStatusPending {
+		c.JSON(http.StatusOK, gin.H{
+			"message":    "transfer pending",
+			"transferId": result.Transfer.ID,
+			"settlesAt":  result.Transfer.SettlesAt.Time,
+		})
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message": "transfer successful",
 	})
 }
 
+// POST /api/sendCoin/:id/cancel
+func (server *Server) handleCancelTransfer(c *gin.Context) {
+	transferID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid transfer id")))
+		return
+	}
+
+	username := c.MustGet("username").(string)
+	user, err := server.store.GetUserByUsername(c, username)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.CancelTransferTxParams{
+		TransferID: int32(transferID),
+		UserID:     user.ID,
+	}
+
+	_, err = server.store.CancelTransferTx(c, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrTransferNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrTransferNotFound))
+		case errors.Is(err, db.ErrTransferNotPending):
+			c.JSON(http.StatusConflict, errorResponse(db.ErrTransferNotPending))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "transfer cancelled",
+	})
+}
+
+// resolveRecipient находит получателя покупки в подарок; пустое имя - покупка для себя
+func (server *Server) resolveRecipient(c *gin.Context, userID int32, username string) (int32, bool) {
+	if username == "" {
+		return 0, true
+	}
+
+	recipient, err := server.store.GetUserByUsername(c, username)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("recipient not found")))
+			return 0, false
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return 0, false
+	}
+	if recipient.ID == userID {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("cannot send a gift to yourself")))
+		return 0, false
+	}
+	return recipient.ID, true
+}
+
 // GET /api/buy/:item
 func (server *Server) handleBuyItem(c *gin.Context) {
 	itemName := c.Param("item")
 
+	var req BuyItemRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Message != "" && req.Recipient == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("gift message requires a recipient")))
+		return
+	}
+
 	username := c.MustGet("username").(string)
 	user, err := server.store.GetUserByUsername(c, username)
 	if err != nil {
@@ -193,28 +505,69 @@ func (server *Server) handleBuyItem(c *gin.Context) {
 		return
 	}
 
-	item, err := server.store.GetItemByName(c, itemName)
-	if err != nil {
-		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+	recipientID, ok := server.resolveRecipient(c, user.ID, req.Recipient)
+	if !ok {
+		return
+	}
+
+	item, ok := server.resolveItem(c, itemName, req.Variant)
+	if !ok {
 		return
 	}
 
 	arg := db.PurchaseTxParams{
-		UserID: user.ID,
-		ItemID: item.ID,
+		UserID:      user.ID,
+		ItemID:      item.ID,
+		RecipientID: recipientID,
+		GiftMessage: req.Message,
+		VariantID:   item.VariantID.Int32,
+		PromoCode:   req.Promo,
 	}
 
-	_, err = server.store.PurchaseTx(c, arg)
+	result, err := server.store.PurchaseTx(c, arg)
 	if err != nil {
-		if strings.Contains(err.Error(), "CHECK constraint") {
+		var promoErr *db.PromoCodeError
+		var limitErr *db.PurchaseLimitError
+		var unavailableErr *db.ItemUnavailableError
+		var queueErr *db.DropQueueError
+		if errors.Is(err, db.ErrInsufficientBalance) || strings.Contains(err.Error(), "CHECK constraint") {
 			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("insufficient balance")))
 			return
 		}
+		if errors.Is(err, db.ErrItemOutOfStock) {
+			c.JSON(http.StatusConflict, errorResponse(db.ErrItemOutOfStock))
+			return
+		}
+		if errors.Is(err, db.ErrPromoCodeNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrPromoCodeNotFound))
+			return
+		}
+		if errors.As(err, &promoErr) {
+			c.JSON(http.StatusBadRequest, errorResponse(promoErr))
+			return
+		}
+		if errors.As(err, &limitErr) {
+			purchaseLimitResponse(c, limitErr)
+			return
+		}
+		if errors.As(err, &unavailableErr) {
+			itemUnavailableResponse(c, unavailableErr)
+			return
+		}
+		if errors.As(err, &queueErr) {
+			dropQueueResponse(c, queueErr)
+			return
+		}
 		c.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
+	response := gin.H{
 		"message": "purchase successful",
-	})
+	}
+	if result.Purchase.Discount > 0 {
+		response["discount"] = result.Purchase.Discount
+		response["price"] = result.Purchase.TotalCost
+	}
+	c.JSON(http.StatusOK, response)
 }
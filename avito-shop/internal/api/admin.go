@@ -0,0 +1,178 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// AdjustCoinsRequest - начисление или списание монет у одного или нескольких пользователей
+type AdjustCoinsRequest struct {
+	Usernames  []string `json:"usernames" binding:"required,min=1,dive,required"`
+	Amount     int32    `json:"amount" binding:"required,gt=0"`
+	ReasonCode string   `json:"reasonCode" binding:"required,oneof=bonus event_reward compensation correction penalty other"`
+	Comment    string   `json:"comment" binding:"required,max=500"`
+}
+
+// AssignDepartmentRequest - отдел, который администратор назначает пользователю
+type AssignDepartmentRequest struct {
+	Department string `json:"department" binding:"required,max=100"`
+}
+
+type AdjustedBalance struct {
+	Username string `json:"username"`
+	Coins    int32  `json:"coins"`
+}
+
+// POST /api/admin/coins/grant
+func (server *Server) handleGrantCoins(c *gin.Context) {
+	server.adjustCoins(c, db.TransactionKindGrant)
+}
+
+// POST /api/admin/coins/deduct
+func (server *Server) handleDeductCoins(c *gin.Context) {
+	server.adjustCoins(c, db.TransactionKindDeduction)
+}
+
+func (server *Server) adjustCoins(c *gin.Context, kind string) {
+	var req AdjustCoinsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Убираем повторы, чтобы не начислить дважды одному пользователю
+	usernames := make([]string, 0, len(req.Usernames))
+	seen := make(map[string]bool)
+	for _, username := range req.Usernames {
+		if !seen[username] {
+			seen[username] = true
+			usernames = append(usernames, username)
+		}
+	}
+
+	users, err := server.store.GetUsersByUsernames(c, usernames)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	if len(users) != len(usernames) {
+		found := make(map[string]bool)
+		for _, user := range users {
+			found[user.Username] = true
+		}
+		var missing []string
+		for _, username := range usernames {
+			if !found[username] {
+				missing = append(missing, username)
+			}
+		}
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("users not found: %s", strings.Join(missing, ", "))))
+		return
+	}
+
+	userIDs := make([]int32, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.ID)
+	}
+
+	arg := db.AdjustBalanceTxParams{
+		AdminID:    admin.ID,
+		UserIDs:    userIDs,
+		Amount:     req.Amount,
+		Kind:       kind,
+		ReasonCode: req.ReasonCode,
+		Comment:    req.Comment,
+		CoinTTL:    server.config.CoinTTL,
+	}
+
+	result, err := server.store.AdjustBalanceTx(c, arg)
+	if err != nil {
+		if errors.Is(err, db.ErrInsufficientBalance) {
+			c.JSON(http.StatusBadRequest, errorResponse(db.ErrInsufficientBalance))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	balances := make([]AdjustedBalance, 0, len(result.Users))
+	for _, user := range result.Users {
+		balances = append(balances, AdjustedBalance{
+			Username: user.Username,
+			Coins:    user.Balance.Int32,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "balances adjusted",
+		"balances": balances,
+	})
+}
+
+// PUT /api/admin/users/:username/department
+// При первом назначении пользователь получает разницу между приветственным начислением
+// отдела и стандартным WelcomeBalance, которое было выдано при регистрации
+func (server *Server) handleAssignDepartment(c *gin.Context) {
+	var req AssignDepartmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	department := strings.TrimSpace(req.Department)
+	if department == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("department must not be empty")))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.Param("username"))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrUserNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.AssignDepartmentTx(c, db.AssignDepartmentTxParams{
+		AdminID:      admin.ID,
+		UserID:       user.ID,
+		Department:   department,
+		WelcomeTopUp: max(server.config.welcomeGrant(department)-server.config.WelcomeBalance, 0),
+		CoinTTL:      server.config.CoinTTL,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrUserNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"username":   result.User.Username,
+		"department": result.User.Department.String,
+		"granted":    result.Grant.Amount,
+		"coins":      result.User.Balance.Int32,
+	})
+}
@@ -0,0 +1,370 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleAdjustCoins(t *testing.T) {
+	admin := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "admin",
+		PasswordHash: "password",
+	}
+	users := []db.GetUsersByUsernamesRow{
+		{ID: 2, Username: "alice"},
+		{ID: 3, Username: "bob"},
+	}
+
+	testCases := []struct {
+		name          string
+		kind          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK_Grant",
+			kind: db.TransactionKindGrant,
+			body: gin.H{
+				"usernames":  []string{"alice", "bob", "alice"},
+				"amount":     100,
+				"reasonCode": "bonus",
+				"comment":    "hackathon winners",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				// Повторяющиеся имена отбрасываются до запроса в БД
+				store.EXPECT().
+					GetUsersByUsernames(gomock.Any(), []string{"alice", "bob"}).
+					Return(users, nil)
+
+				arg := db.AdjustBalanceTxParams{
+					AdminID:    admin.ID,
+					UserIDs:    []int32{2, 3},
+					Amount:     100,
+					Kind:       db.TransactionKindGrant,
+					ReasonCode: "bonus",
+					Comment:    "hackathon winners",
+				}
+
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), arg).
+					Return(db.AdjustBalanceTxResult{
+						Users: []db.User{
+							{ID: 2, Username: "alice", Balance: pgtype.Int4{Int32: 1100, Valid: true}},
+							{ID: 3, Username: "bob", Balance: pgtype.Int4{Int32: 1100, Valid: true}},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var gotResponse struct {
+					Balances []AdjustedBalance `json:"balances"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotResponse)
+				require.NoError(t, err)
+				require.Equal(t, []AdjustedBalance{
+					{Username: "alice", Coins: 1100},
+					{Username: "bob", Coins: 1100},
+				}, gotResponse.Balances)
+			},
+		},
+		{
+			name: "BadRequest_UnknownReasonCode",
+			kind: db.TransactionKindGrant,
+			body: gin.H{
+				"usernames":  []string{"alice"},
+				"amount":     100,
+				"reasonCode": "because",
+				"comment":    "no reason",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// Не ожидаем вызовов к store, так как запрос не проходит валидацию
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_MissingComment",
+			kind: db.TransactionKindDeduction,
+			body: gin.H{
+				"usernames":  []string{"alice"},
+				"amount":     100,
+				"reasonCode": "penalty",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				// Не ожидаем вызовов к store, так как запрос не проходит валидацию
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound_UnknownUser",
+			kind: db.TransactionKindGrant,
+			body: gin.H{
+				"usernames":  []string{"alice", "carol"},
+				"amount":     100,
+				"reasonCode": "bonus",
+				"comment":    "quarterly bonus",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				store.EXPECT().
+					GetUsersByUsernames(gomock.Any(), []string{"alice", "carol"}).
+					Return(users[:1], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "users not found: carol")
+			},
+		},
+		{
+			name: "BadRequest_InsufficientBalance",
+			kind: db.TransactionKindDeduction,
+			body: gin.H{
+				"usernames":  []string{"alice"},
+				"amount":     5000,
+				"reasonCode": "correction",
+				"comment":    "duplicate grant",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				store.EXPECT().
+					GetUsersByUsernames(gomock.Any(), []string{"alice"}).
+					Return(users[:1], nil)
+
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Return(db.AdjustBalanceTxResult{}, fmt.Errorf("adjust balance tx error: %w", db.ErrInsufficientBalance))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "insufficient balance")
+			},
+		},
+		{
+			name: "InternalError_AdjustError",
+			kind: db.TransactionKindGrant,
+			body: gin.H{
+				"usernames":  []string{"alice"},
+				"amount":     100,
+				"reasonCode": "bonus",
+				"comment":    "quarterly bonus",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				store.EXPECT().
+					GetUsersByUsernames(gomock.Any(), gomock.Any()).
+					Return(users[:1], nil)
+
+				store.EXPECT().
+					AdjustBalanceTx(gomock.Any(), gomock.Any()).
+					Return(db.AdjustBalanceTxResult{}, errors.New("internal error"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/coins", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", admin.Username)
+
+			server.adjustCoins(ctx, tc.kind)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleAssignDepartment(t *testing.T) {
+	admin := db.GetUserByUsernameRow{ID: 1, Username: "admin"}
+	user := db.GetUserByUsernameRow{ID: 2, Username: "alice"}
+
+	testCases := []struct {
+		name          string
+		username      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK_TopUp",
+			username: user.Username,
+			body:     gin.H{"department": "engineering"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				// Доплачивается разница с уже выданным стандартным начислением
+				arg := db.AssignDepartmentTxParams{
+					AdminID:      admin.ID,
+					UserID:       user.ID,
+					Department:   "engineering",
+					WelcomeTopUp: 500,
+				}
+				store.EXPECT().
+					AssignDepartmentTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.AssignDepartmentTxResult{
+						User: db.User{
+							Username:   user.Username,
+							Department: pgtype.Text{String: "engineering", Valid: true},
+							Balance:    pgtype.Int4{Int32: 1500, Valid: true},
+						},
+						Grant: db.Transaction{Amount: 500},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"username":"alice","department":"engineering","granted":500,"coins":1500}`, recorder.Body.String())
+			},
+		},
+		{
+			name:     "OK_DepartmentWithoutOverride",
+			username: user.Username,
+			body:     gin.H{"department": "sales"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				arg := db.AssignDepartmentTxParams{
+					AdminID:    admin.ID,
+					UserID:     user.ID,
+					Department: "sales",
+				}
+				store.EXPECT().
+					AssignDepartmentTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.AssignDepartmentTxResult{User: db.User{Username: user.Username}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound_User",
+			username: "ghost",
+			body:     gin.H{"department": "engineering"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), "ghost").
+					Return(db.GetUserByUsernameRow{}, pgx.ErrNoRows)
+				store.EXPECT().
+					AssignDepartmentTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "user not found")
+			},
+		},
+		{
+			name:     "BadRequest_EmptyDepartment",
+			username: user.Username,
+			body:     gin.H{"department": "  "},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AssignDepartmentTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{
+				store: store,
+				config: Config{OnboardingConfig: OnboardingConfig{
+					WelcomeBalance:             1000,
+					WelcomeBalanceByDepartment: map[string]int32{"engineering": 1500},
+				}},
+			}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/admin/users/"+tc.username+"/department", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "username", Value: tc.username}}
+			ctx.Set("username", admin.Username)
+
+			server.handleAssignDepartment(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const auctionListLimit = 100
+
+// CreateAuctionRequest - новый аукцион; reservePrice - минимальная ставка, при которой
+// товар продается, участникам она не показывается
+type CreateAuctionRequest struct {
+	Item         string    `json:"item" binding:"required"`
+	StartsAt     time.Time `json:"startsAt" binding:"required"`
+	EndsAt       time.Time `json:"endsAt" binding:"required"`
+	ReservePrice int32     `json:"reservePrice" binding:"gte=0"`
+	MinIncrement int32     `json:"minIncrement" binding:"omitempty,gt=0"`
+}
+
+// PlaceBidRequest - ставка на аукционе
+type PlaceBidRequest struct {
+	Amount int32 `json:"amount" binding:"required,gt=0"`
+}
+
+// AuctionResponse - аукцион глазами участника: резервная цена видна только администратору
+type AuctionResponse struct {
+	ID           int32     `json:"id"`
+	Item         string    `json:"item"`
+	StartsAt     time.Time `json:"startsAt"`
+	EndsAt       time.Time `json:"endsAt"`
+	Status       string    `json:"status"`
+	CurrentBid   *int32    `json:"currentBid,omitempty"`
+	MinBid       int32     `json:"minBid"`
+	Bids         int32     `json:"bids"`
+	ReserveMet   bool      `json:"reserveMet"`
+	Leading      bool      `json:"leading"`
+	ReservePrice *int32    `json:"reservePrice,omitempty"`
+}
+
+// NewAuctionResponse собирает ответ по аукциону; userID - кто смотрит,
+// admin - показывать ли резервную цену
+func NewAuctionResponse(row db.ListAuctionsRow, userID int32, admin bool) AuctionResponse {
+	response := AuctionResponse{
+		ID:         row.ID,
+		Item:       row.ItemName,
+		StartsAt:   row.StartsAt.Time,
+		EndsAt:     row.EndsAt.Time,
+		Status:     row.Status,
+		CurrentBid: int4Ptr(row.LeadingAmount),
+		MinBid:     db.MinAuctionBid(row.LeadingAmount, row.MinIncrement),
+		Bids:       row.Bids,
+		ReserveMet: row.LeadingAmount.Valid && row.LeadingAmount.Int32 >= row.ReservePrice,
+		Leading:    row.LeadingUserID.Valid && row.LeadingUserID.Int32 == userID,
+	}
+	if admin {
+		reservePrice := row.ReservePrice
+		response.ReservePrice = &reservePrice
+	}
+	return response
+}
+
+// parseAuctionID разбирает id аукциона из адреса
+func parseAuctionID(c *gin.Context) (int32, bool) {
+	auctionID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid auction id")))
+		return 0, false
+	}
+	return int32(auctionID), true
+}
+
+// GET /api/auctions - идущие и предстоящие аукционы
+func (server *Server) handleListAuctions(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.ListAuctions(c, db.ListAuctionsParams{
+		Status:   pgtype.Text{String: db.AuctionStatusActive, Valid: true},
+		RowLimit: auctionListLimit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	auctions := make([]AuctionResponse, 0, len(rows))
+	for _, row := range rows {
+		auctions = append(auctions, NewAuctionResponse(row, user.ID, false))
+	}
+	c.JSON(http.StatusOK, gin.H{"auctions": auctions})
+}
+
+// GET /api/auctions/:id
+func (server *Server) handleGetAuction(c *gin.Context) {
+	auctionID, ok := parseAuctionID(c)
+	if !ok {
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	summary, err := server.store.GetAuctionSummary(c, auctionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrAuctionNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewAuctionResponse(db.ListAuctionsRow(summary), user.ID, false))
+}
+
+// POST /api/auctions/:id/bids
+// Сумма ставки резервируется на балансе до тех пор, пока ставку не перебьют
+func (server *Server) handlePlaceBid(c *gin.Context) {
+	auctionID, ok := parseAuctionID(c)
+	if !ok {
+		return
+	}
+
+	var req PlaceBidRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.PlaceBidTx(c, db.PlaceBidTxParams{
+		AuctionID: auctionID,
+		UserID:    user.ID,
+		Amount:    req.Amount,
+		Now:       time.Now(),
+	})
+	if err != nil {
+		var lowErr *db.BidTooLowError
+		switch {
+		case errors.Is(err, db.ErrAuctionNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrAuctionNotFound))
+		case errors.Is(err, db.ErrAuctionClosed):
+			c.JSON(http.StatusConflict, errorResponse(db.ErrAuctionClosed))
+		case errors.As(err, &lowErr):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  lowErr.Error(),
+				"minBid": lowErr.MinBid,
+			})
+		case errors.Is(err, db.ErrInsufficientBalance) || strings.Contains(err.Error(), "CHECK constraint"):
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("insufficient balance")))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "bid placed",
+		"amount":  result.Bid.Amount,
+		"balance": result.User.Balance,
+	})
+}
+
+// POST /api/admin/auctions
+func (server *Server) handleCreateAuction(c *gin.Context) {
+	var req CreateAuctionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("endsAt must be after startsAt")))
+		return
+	}
+	if !req.EndsAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("endsAt must be in the future")))
+		return
+	}
+	if req.MinIncrement == 0 {
+		req.MinIncrement = 1
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: req.Item})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+	// Победитель получает сам товар: вариант на аукционе не выбирается
+	if item.HasVariants {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("items with variants cannot be auctioned")))
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	auction, err := server.store.CreateAuction(c, db.CreateAuctionParams{
+		ItemID:       item.ID,
+		StartsAt:     timestampFromPtr(&req.StartsAt),
+		EndsAt:       timestampFromPtr(&req.EndsAt),
+		ReservePrice: req.ReservePrice,
+		MinIncrement: req.MinIncrement,
+		CreatedBy:    pgtype.Int4{Int32: admin.ID, Valid: true},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewAuctionResponse(db.ListAuctionsRow{
+		ID:           auction.ID,
+		ItemID:       auction.ItemID,
+		ItemName:     item.Name,
+		StartsAt:     auction.StartsAt,
+		EndsAt:       auction.EndsAt,
+		ReservePrice: auction.ReservePrice,
+		MinIncrement: auction.MinIncrement,
+		Status:       auction.Status,
+	}, admin.ID, true))
+}
+
+// GET /api/admin/auctions?status=
+func (server *Server) handleAdminListAuctions(c *gin.Context) {
+	status := c.Query("status")
+	rows, err := server.store.ListAuctions(c, db.ListAuctionsParams{
+		Status:   pgtype.Text{String: status, Valid: status != ""},
+		RowLimit: auctionListLimit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	auctions := make([]AuctionResponse, 0, len(rows))
+	for _, row := range rows {
+		auctions = append(auctions, NewAuctionResponse(row, 0, true))
+	}
+	c.JSON(http.StatusOK, gin.H{"auctions": auctions})
+}
+
+// POST /api/admin/auctions/:id/cancel
+func (server *Server) handleCancelAuction(c *gin.Context) {
+	auctionID, ok := parseAuctionID(c)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.CancelAuctionTx(c, db.CancelAuctionTxParams{AuctionID: auctionID})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrAuctionNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrAuctionNotFound))
+		case errors.Is(err, db.ErrAuctionClosed):
+			c.JSON(http.StatusConflict, errorResponse(fmt.Errorf("auction is already closed")))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	response := gin.H{
+		"message": "auction cancelled",
+		"id":      result.Auction.ID,
+	}
+	if result.Released != nil {
+		response["refunded"] = result.Released.Amount
+	}
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,349 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuctionResponse(t *testing.T) {
+	row := db.ListAuctionsRow{
+		ID:            4,
+		ItemName:      "signed-hoody",
+		ReservePrice:  500,
+		MinIncrement:  25,
+		Status:        db.AuctionStatusActive,
+		LeadingAmount: pgtype.Int4{Int32: 450, Valid: true},
+		LeadingUserID: pgtype.Int4{Int32: 7, Valid: true},
+		Bids:          3,
+	}
+
+	response := NewAuctionResponse(row, 7, false)
+	require.Equal(t, int32(475), response.MinBid)
+	require.Equal(t, int32(450), *response.CurrentBid)
+	require.False(t, response.ReserveMet)
+	require.True(t, response.Leading)
+	require.Nil(t, response.ReservePrice)
+
+	response = NewAuctionResponse(db.ListAuctionsRow{ReservePrice: 500, MinIncrement: 25}, 7, true)
+	require.Equal(t, int32(1), response.MinBid)
+	require.Nil(t, response.CurrentBid)
+	require.False(t, response.Leading)
+	require.Equal(t, int32(500), *response.ReservePrice)
+}
+
+func TestHandlePlaceBid(t *testing.T) {
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+
+	testCases := []struct {
+		name          string
+		auctionID     string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			auctionID: "4",
+			body:      gin.H{"amount": 300},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					PlaceBidTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.PlaceBidTxParams) (db.PlaceBidTxResult, error) {
+						require.Equal(t, int32(4), arg.AuctionID)
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, int32(300), arg.Amount)
+						return db.PlaceBidTxResult{
+							Bid:  db.AuctionBid{Amount: 300},
+							User: db.User{Balance: pgtype.Int4{Int32: 700, Valid: true}},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "bid placed")
+			},
+		},
+		{
+			name:      "BadRequest_TooLow",
+			auctionID: "4",
+			body:      gin.H{"amount": 300},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					PlaceBidTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PlaceBidTxResult{}, fmt.Errorf("place bid tx error: %w", &db.BidTooLowError{MinBid: 350}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.JSONEq(t, `{"error":"bid must be at least 350","minBid":350}`, recorder.Body.String())
+			},
+		},
+		{
+			name:      "Conflict_Closed",
+			auctionID: "4",
+			body:      gin.H{"amount": 300},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					PlaceBidTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PlaceBidTxResult{}, fmt.Errorf("place bid tx error: %w", db.ErrAuctionClosed))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			auctionID: "4",
+			body:      gin.H{"amount": 300},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					PlaceBidTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PlaceBidTxResult{}, fmt.Errorf("place bid tx error: %w", db.ErrAuctionNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "BadRequest_InsufficientBalance",
+			auctionID: "4",
+			body:      gin.H{"amount": 5000},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					PlaceBidTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PlaceBidTxResult{}, fmt.Errorf("place bid tx error: %w", db.ErrInsufficientBalance))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "insufficient balance")
+			},
+		},
+		{
+			name:      "BadRequest_Amount",
+			auctionID: "4",
+			body:      gin.H{"amount": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					PlaceBidTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:      "BadRequest_AuctionID",
+			auctionID: "abc",
+			body:      gin.H{"amount": 300},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					PlaceBidTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/auctions/"+tc.auctionID+"/bids", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.auctionID}}
+			ctx.Set("username", user.Username)
+
+			server.handlePlaceBid(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleCreateAuction(t *testing.T) {
+	admin := db.GetUserByUsernameRow{ID: 9, Username: "admin"}
+	item := db.GetItemByNameRow{ID: 3, Name: "signed-hoody", Price: 300}
+	startsAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	endsAt := startsAt.Add(24 * time.Hour)
+	moscow := time.FixedZone("MSK", 3*60*60)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"item": item.Name, "startsAt": startsAt, "endsAt": endsAt, "reservePrice": 500},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				arg := db.CreateAuctionParams{
+					ItemID:       item.ID,
+					StartsAt:     pgtype.Timestamp{Time: startsAt, Valid: true},
+					EndsAt:       pgtype.Timestamp{Time: endsAt, Valid: true},
+					ReservePrice: 500,
+					MinIncrement: 1,
+					CreatedBy:    pgtype.Int4{Int32: admin.ID, Valid: true},
+				}
+				store.EXPECT().
+					CreateAuction(gomock.Any(), arg).
+					Times(1).
+					Return(db.Auction{
+						ID:           1,
+						ItemID:       item.ID,
+						StartsAt:     arg.StartsAt,
+						EndsAt:       arg.EndsAt,
+						ReservePrice: arg.ReservePrice,
+						MinIncrement: arg.MinIncrement,
+						Status:       db.AuctionStatusActive,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response AuctionResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, item.Name, response.Item)
+				require.Equal(t, int32(500), *response.ReservePrice)
+			},
+		},
+		{
+			// Время со смещением сохраняется в UTC: иначе аукцион откроется и закроется позже
+			name: "OK_Offset",
+			body: gin.H{"item": item.Name, "startsAt": startsAt.In(moscow), "endsAt": endsAt.In(moscow)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				arg := db.CreateAuctionParams{
+					ItemID:       item.ID,
+					StartsAt:     pgtype.Timestamp{Time: startsAt, Valid: true},
+					EndsAt:       pgtype.Timestamp{Time: endsAt, Valid: true},
+					MinIncrement: 1,
+					CreatedBy:    pgtype.Int4{Int32: admin.ID, Valid: true},
+				}
+				store.EXPECT().
+					CreateAuction(gomock.Any(), arg).
+					Times(1).
+					Return(db.Auction{ID: 2, ItemID: item.ID, StartsAt: arg.StartsAt, EndsAt: arg.EndsAt}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_Window",
+			body: gin.H{"item": item.Name, "startsAt": endsAt, "endsAt": startsAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAuction(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_Variants",
+			body: gin.H{"item": item.Name, "startsAt": startsAt, "endsAt": endsAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				withVariants := item
+				withVariants.HasVariants = true
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(withVariants, nil)
+				store.EXPECT().
+					CreateAuction(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/auctions", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", admin.Username)
+
+			server.handleCreateAuction(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
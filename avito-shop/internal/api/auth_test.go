@@ -20,13 +20,13 @@ import (
 )
 
 // Создаем свой матчер для проверки хеша пароля
-type eqCreateUserParamsMatcher struct {
-	arg      db.CreateUserParams
+type eqCreateUserTxParamsMatcher struct {
+	arg      db.CreateUserTxParams
 	password string
 }
 
-func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
-	arg, ok := x.(db.CreateUserParams)
+func (e eqCreateUserTxParamsMatcher) Matches(x interface{}) bool {
+	arg, ok := x.(db.CreateUserTxParams)
 	if !ok {
 		return false
 	}
@@ -37,15 +37,15 @@ func (e eqCreateUserParamsMatcher) Matches(x interface{}) bool {
 	}
 
 	e.arg.PasswordHash = arg.PasswordHash
-	return e.arg.Username == arg.Username
+	return e.arg == arg
 }
 
-func (e eqCreateUserParamsMatcher) String() string {
+func (e eqCreateUserTxParamsMatcher) String() string {
 	return fmt.Sprintf("matches arg %v and password %v", e.arg, e.password)
 }
 
-func EqCreateUserParams(arg db.CreateUserParams, password string) gomock.Matcher {
-	return eqCreateUserParamsMatcher{arg, password}
+func EqCreateUserTxParams(arg db.CreateUserTxParams, password string) gomock.Matcher {
+	return eqCreateUserTxParamsMatcher{arg, password}
 }
 
 func TestHandleLogin(t *testing.T) {
@@ -94,15 +94,46 @@ func TestHandleLogin(t *testing.T) {
 					GetUserByUsername(gomock.Any(), "new_user").
 					Return(db.GetUserByUsernameRow{}, pgx.ErrNoRows)
 
-				arg := db.CreateUserParams{
-					Username: "new_user",
+				arg := db.CreateUserTxParams{
+					Username:     "new_user",
+					WelcomeGrant: 1000,
 				}
 
 				// Мок для создания нового пользователя
 				store.EXPECT().
-					CreateUser(gomock.Any(), EqCreateUserParams(arg, password)).
-					Return(db.User{
-						Username: "new_user",
+					CreateUserTx(gomock.Any(), EqCreateUserTxParams(arg, password)).
+					Return(db.CreateUserTxResult{
+						User: db.User{Username: "new_user"},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchLoginResponse(t, recorder.Body.Bytes())
+			},
+		},
+		{
+			name: "OK_NewUserDepartmentIgnored",
+			body: gin.H{
+				"username":   "new_engineer",
+				"password":   password,
+				"department": "engineering",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), "new_engineer").
+					Return(db.GetUserByUsernameRow{}, pgx.ErrNoRows)
+
+				// Отдел из запроса не учитывается: начисление стандартное,
+				// пока отдел не назначит администратор
+				arg := db.CreateUserTxParams{
+					Username:     "new_engineer",
+					WelcomeGrant: 1000,
+				}
+
+				store.EXPECT().
+					CreateUserTx(gomock.Any(), EqCreateUserTxParams(arg, password)).
+					Return(db.CreateUserTxResult{
+						User: db.User{Username: "new_engineer"},
 					}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -169,8 +200,8 @@ func TestHandleLogin(t *testing.T) {
 
 				// Мок для ошибки создания пользователя
 				store.EXPECT().
-					CreateUser(gomock.Any(), gomock.Any()).
-					Return(db.User{}, errors.New("database error"))
+					CreateUserTx(gomock.Any(), gomock.Any()).
+					Return(db.CreateUserTxResult{}, errors.New("database error"))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -218,7 +249,12 @@ func TestHandleLogin(t *testing.T) {
 				AccessTokenDuration: 24,
 			}
 
-			server, err := NewServer(store, tokenConfig)
+			onboardingConfig := OnboardingConfig{
+				WelcomeBalance:             1000,
+				WelcomeBalanceByDepartment: map[string]int32{"engineering": 1500},
+			}
+
+			server, err := NewServer(store, Config{TokenConfig: tokenConfig, OnboardingConfig: onboardingConfig})
 			require.NoError(t, err)
 
 			recorder := httptest.NewRecorder()
@@ -0,0 +1,257 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// BundleItemRequest - товар в составе набора
+type BundleItemRequest struct {
+	Item string `json:"item" binding:"required"`
+	// Variant - артикул варианта, обязателен для товаров с вариантами
+	Variant  string `json:"variant"`
+	Quantity int32  `json:"quantity" binding:"required,gt=0,lte=100"`
+}
+
+// CreateBundleRequest - набор товаров по общей цене, которая ниже суммы цен товаров
+type CreateBundleRequest struct {
+	Name        string              `json:"name" binding:"required,max=100"`
+	Description string              `json:"description" binding:"max=500"`
+	Price       int32               `json:"price" binding:"required,gt=0"`
+	Items       []BundleItemRequest `json:"items" binding:"required,min=1,max=20,dive"`
+}
+
+// BundleItemResponse - позиция набора с текущей ценой товара по отдельности
+type BundleItemResponse struct {
+	Item     string `json:"item"`
+	Variant  string `json:"variant,omitempty"`
+	Quantity int32  `json:"quantity"`
+	Price    int32  `json:"price"`
+}
+
+// BundleResponse - набор в каталоге; listPrice - стоимость состава по отдельности
+type BundleResponse struct {
+	ID          int32                `json:"id"`
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Price       int32                `json:"price"`
+	ListPrice   int32                `json:"listPrice"`
+	Active      bool                 `json:"active"`
+	Items       []BundleItemResponse `json:"items"`
+}
+
+func NewBundleResponses(bundles []db.Bundle, items []db.ListBundleItemsRow) []BundleResponse {
+	byBundle := make(map[int32][]db.ListBundleItemsRow)
+	for _, item := range items {
+		byBundle[item.BundleID] = append(byBundle[item.BundleID], item)
+	}
+
+	response := make([]BundleResponse, 0, len(bundles))
+	for _, bundle := range bundles {
+		lines := make([]BundleItemResponse, 0, len(byBundle[bundle.ID]))
+		for _, item := range byBundle[bundle.ID] {
+			lines = append(lines, BundleItemResponse{
+				Item:     item.Name,
+				Variant:  item.Sku.String,
+				Quantity: item.Quantity,
+				Price:    item.Price,
+			})
+		}
+		response = append(response, BundleResponse{
+			ID:          bundle.ID,
+			Name:        bundle.Name,
+			Description: bundle.Description.String,
+			Price:       bundle.Price,
+			ListPrice:   db.BundleListPrice(byBundle[bundle.ID]),
+			Active:      bundle.Active,
+			Items:       lines,
+		})
+	}
+	return response
+}
+
+// parseBundleID разбирает id набора из адреса
+func parseBundleID(c *gin.Context) (int32, bool) {
+	bundleID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid bundle id")))
+		return 0, false
+	}
+	return int32(bundleID), true
+}
+
+// listBundles отвечает списком наборов вместе с их составом
+func (server *Server) listBundles(c *gin.Context, active pgtype.Bool) {
+	bundles, err := server.store.ListBundles(c, active)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ids := make([]int32, 0, len(bundles))
+	for _, bundle := range bundles {
+		ids = append(ids, bundle.ID)
+	}
+	items, err := server.store.ListBundleItems(c, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"bundles": NewBundleResponses(bundles, items)})
+}
+
+// GET /api/bundles - наборы, которые сейчас продаются
+func (server *Server) handleListBundles(c *gin.Context) {
+	server.listBundles(c, pgtype.Bool{Bool: true, Valid: true})
+}
+
+// GET /api/admin/bundles - все наборы, включая снятые с продажи
+func (server *Server) handleAdminListBundles(c *gin.Context) {
+	server.listBundles(c, pgtype.Bool{})
+}
+
+// POST /api/bundles/:id/buy
+func (server *Server) handleBuyBundle(c *gin.Context) {
+	bundleID, ok := parseBundleID(c)
+	if !ok {
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.BuyBundleTx(c, db.BuyBundleTxParams{
+		UserID:   user.ID,
+		BundleID: bundleID,
+	})
+	if err != nil {
+		var stockErr *db.ItemOutOfStockError
+		var limitErr *db.PurchaseLimitError
+		var unavailableErr *db.ItemUnavailableError
+		var queueErr *db.DropQueueError
+		switch {
+		case errors.Is(err, db.ErrBundleNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrBundleNotFound))
+		case errors.Is(err, db.ErrBundleInactive):
+			c.JSON(http.StatusConflict, errorResponse(db.ErrBundleInactive))
+		case errors.Is(err, db.ErrInsufficientBalance) || strings.Contains(err.Error(), "CHECK constraint"):
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("insufficient balance")))
+		case errors.As(err, &stockErr):
+			c.JSON(http.StatusConflict, errorResponse(stockErr))
+		case errors.As(err, &limitErr):
+			purchaseLimitResponse(c, limitErr)
+		case errors.As(err, &unavailableErr):
+			itemUnavailableResponse(c, unavailableErr)
+		case errors.As(err, &queueErr):
+			dropQueueResponse(c, queueErr)
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "bundle purchased",
+		"orderId": result.Order.ID,
+		"total":   result.Order.TotalCost,
+		"coins":   result.User.Balance.Int32,
+	})
+}
+
+// POST /api/admin/bundles
+func (server *Server) handleCreateBundle(c *gin.Context) {
+	var req CreateBundleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	// Одинаковые позиции в наборе задаются количеством, а не повтором
+	components := make([]db.BundleComponent, 0, len(req.Items))
+	seen := make(map[string]bool, len(req.Items))
+	for _, line := range req.Items {
+		key := line.Item + "\x00" + line.Variant
+		if seen[key] {
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("duplicate bundle item: %s", line.Item)))
+			return
+		}
+		seen[key] = true
+
+		item, ok := server.resolveItem(c, line.Item, line.Variant)
+		if !ok {
+			return
+		}
+		components = append(components, db.BundleComponent{
+			ItemID:    item.ID,
+			VariantID: item.VariantID,
+			Quantity:  line.Quantity,
+		})
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CreateBundleTx(c, db.CreateBundleTxParams{
+		Name:        strings.TrimSpace(req.Name),
+		Description: req.Description,
+		Price:       req.Price,
+		Items:       components,
+		CreatedBy:   admin.ID,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrBundlePriceTooHigh):
+			c.JSON(http.StatusBadRequest, errorResponse(db.ErrBundlePriceTooHigh))
+		case strings.Contains(err.Error(), "bundles_name_key"):
+			c.JSON(http.StatusConflict, errorResponse(fmt.Errorf("bundle already exists")))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":        result.Bundle.ID,
+		"name":      result.Bundle.Name,
+		"price":     result.Bundle.Price,
+		"listPrice": result.ListPrice,
+	})
+}
+
+// DELETE /api/admin/bundles/:id - снять набор с продажи; оформленные заказы сохраняют ссылку на него
+func (server *Server) handleDeactivateBundle(c *gin.Context) {
+	bundleID, ok := parseBundleID(c)
+	if !ok {
+		return
+	}
+
+	_, err := server.store.SetBundleActive(c, db.SetBundleActiveParams{
+		ID:     bundleID,
+		Active: false,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrBundleNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "bundle deactivated"})
+}
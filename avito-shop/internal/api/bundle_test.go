@@ -0,0 +1,391 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBundleResponses(t *testing.T) {
+	bundles := []db.Bundle{
+		{ID: 1, Name: "starter pack", Price: 500, Active: true},
+		{ID: 2, Name: "empty", Price: 10},
+	}
+	items := []db.ListBundleItemsRow{
+		{BundleID: 1, Name: "t-shirt", Quantity: 1, Price: 80},
+		{BundleID: 1, Name: "cup", Quantity: 2, Price: 20},
+		{BundleID: 1, Name: "hoody", Sku: pgtype.Text{String: "hoody-m", Valid: true}, Quantity: 1, Price: 400},
+	}
+
+	response := NewBundleResponses(bundles, items)
+	require.Len(t, response, 2)
+	require.Equal(t, int32(520), response[0].ListPrice)
+	require.Len(t, response[0].Items, 3)
+	require.Equal(t, "hoody-m", response[0].Items[2].Variant)
+	require.NotNil(t, response[1].Items)
+	require.Empty(t, response[1].Items)
+}
+
+func TestHandleBuyBundle(t *testing.T) {
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+
+	testCases := []struct {
+		name          string
+		bundleID      string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			bundleID: "3",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyBundleTx(gomock.Any(), db.BuyBundleTxParams{UserID: user.ID, BundleID: 3}).
+					Times(1).
+					Return(db.BuyBundleTxResult{
+						Order: db.Order{ID: 12, TotalCost: 100},
+						User:  db.User{Balance: pgtype.Int4{Int32: 900, Valid: true}},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"message":"bundle purchased","orderId":12,"total":100,"coins":900}`, recorder.Body.String())
+			},
+		},
+		{
+			name:     "NotFound",
+			bundleID: "3",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyBundleTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BuyBundleTxResult{}, fmt.Errorf("buy bundle tx error: %w", db.ErrBundleNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "Conflict_Inactive",
+			bundleID: "3",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyBundleTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BuyBundleTxResult{}, fmt.Errorf("buy bundle tx error: %w", db.ErrBundleInactive))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "bundle is not available")
+			},
+		},
+		{
+			name:     "Conflict_OutOfStock",
+			bundleID: "3",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyBundleTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BuyBundleTxResult{}, fmt.Errorf("buy bundle tx error: %w", &db.ItemOutOfStockError{Item: "cup"}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "item is out of stock: cup")
+			},
+		},
+		{
+			name:     "BadRequest_InsufficientBalance",
+			bundleID: "3",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyBundleTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BuyBundleTxResult{}, fmt.Errorf("buy bundle tx error: %w", db.ErrInsufficientBalance))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "insufficient balance")
+			},
+		},
+		{
+			name:     "BadRequest_BundleID",
+			bundleID: "abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BuyBundleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/bundles/"+tc.bundleID+"/buy", nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.bundleID}}
+			ctx.Set("username", user.Username)
+
+			server.handleBuyBundle(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleCreateBundle(t *testing.T) {
+	admin := db.GetUserByUsernameRow{ID: 1, Username: "admin"}
+	shirt := db.GetItemByNameRow{ID: 3, Name: "t-shirt", Price: 80}
+	cup := db.GetItemByNameRow{ID: 4, Name: "cup", Price: 20}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"name":  "starter pack",
+				"price": 90,
+				"items": []gin.H{{"item": shirt.Name, "quantity": 1}, {"item": cup.Name, "quantity": 2}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: shirt.Name}).
+					Return(shirt, nil)
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: cup.Name}).
+					Return(cup, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				arg := db.CreateBundleTxParams{
+					Name:  "starter pack",
+					Price: 90,
+					Items: []db.BundleComponent{
+						{ItemID: shirt.ID, Quantity: 1},
+						{ItemID: cup.ID, Quantity: 2},
+					},
+					CreatedBy: admin.ID,
+				}
+				store.EXPECT().
+					CreateBundleTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.CreateBundleTxResult{
+						Bundle:    db.Bundle{ID: 5, Name: "starter pack", Price: 90, Active: true},
+						ListPrice: 120,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"id":5,"name":"starter pack","price":90,"listPrice":120}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "BadRequest_PriceTooHigh",
+			body: gin.H{
+				"name":  "starter pack",
+				"price": 200,
+				"items": []gin.H{{"item": shirt.Name, "quantity": 1}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(shirt, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				store.EXPECT().
+					CreateBundleTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateBundleTxResult{}, fmt.Errorf("create bundle tx error: %w", db.ErrBundlePriceTooHigh))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "bundle price must be below the sum of its items")
+			},
+		},
+		{
+			name: "BadRequest_DuplicateItem",
+			body: gin.H{
+				"name":  "starter pack",
+				"price": 90,
+				"items": []gin.H{{"item": cup.Name, "quantity": 1}, {"item": cup.Name, "quantity": 1}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(cup, nil)
+				store.EXPECT().
+					CreateBundleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "duplicate bundle item: cup")
+			},
+		},
+		{
+			name: "NotFound_Item",
+			body: gin.H{
+				"name":  "starter pack",
+				"price": 90,
+				"items": []gin.H{{"item": "unknown", "quantity": 1}},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(db.GetItemByNameRow{}, pgx.ErrNoRows)
+				store.EXPECT().
+					CreateBundleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_NoItems",
+			body: gin.H{"name": "starter pack", "price": 90, "items": []gin.H{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateBundleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/bundles", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", admin.Username)
+
+			server.handleCreateBundle(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleDeactivateBundle(t *testing.T) {
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetBundleActive(gomock.Any(), db.SetBundleActiveParams{ID: 5, Active: false}).
+					Times(1).
+					Return(db.Bundle{ID: 5}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "bundle deactivated")
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetBundleActive(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Bundle{}, pgx.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, "/admin/bundles/5", nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: "5"}}
+
+			server.handleDeactivateBundle(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleCancelTransfer(t *testing.T) {
+	sender := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "sender",
+		PasswordHash: "password",
+	}
+	transferID := int32(42)
+
+	testCases := []struct {
+		name          string
+		transferID    string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			transferID: fmt.Sprint(transferID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+
+				arg := db.CancelTransferTxParams{
+					TransferID: transferID,
+					UserID:     sender.ID,
+				}
+
+				store.EXPECT().
+					CancelTransferTx(gomock.Any(), arg).
+					Return(db.CancelTransferTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "transfer cancelled")
+			},
+		},
+		{
+			name:       "BadRequest_InvalidID",
+			transferID: "abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				// Не ожидаем вызовов к store, так как идентификатор некорректен
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "NotFound_TransferNotFound",
+			transferID: fmt.Sprint(transferID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+
+				// Ошибка приходит обернутой в ошибку транзакции
+				store.EXPECT().
+					CancelTransferTx(gomock.Any(), gomock.Any()).
+					Return(db.CancelTransferTxResult{}, fmt.Errorf("cancel transfer tx error: %w", db.ErrTransferNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), db.ErrTransferNotFound.Error())
+			},
+		},
+		{
+			name:       "Conflict_AlreadySettled",
+			transferID: fmt.Sprint(transferID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+
+				store.EXPECT().
+					CancelTransferTx(gomock.Any(), gomock.Any()).
+					Return(db.CancelTransferTxResult{}, fmt.Errorf("cancel transfer tx error: %w", db.ErrTransferNotPending))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), db.ErrTransferNotPending.Error())
+			},
+		},
+		{
+			name:       "InternalError_CancelError",
+			transferID: fmt.Sprint(transferID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+
+				store.EXPECT().
+					CancelTransferTx(gomock.Any(), gomock.Any()).
+					Return(db.CancelTransferTxResult{}, errors.New("internal error"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/sendCoin/%s/cancel", tc.transferID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.transferID}}
+			ctx.Set("username", sender.Username)
+
+			server.handleCancelTransfer(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
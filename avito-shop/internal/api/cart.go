@@ -0,0 +1,294 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AddCartItemRequest - добавление товара в корзину; количество прибавляется к уже добавленному
+type AddCartItemRequest struct {
+	Item     string `json:"item" binding:"required"`
+	Variant  string `json:"variant"`
+	Quantity int32  `json:"quantity" binding:"required,gt=0,lte=100"`
+}
+
+// UpdateCartItemRequest - новое количество товара в корзине
+type UpdateCartItemRequest struct {
+	Quantity int32 `json:"quantity" binding:"required,gt=0,lte=100"`
+}
+
+// CheckoutRequest - необязательный промокод на заказ: /api/cart/checkout?promo=...
+type CheckoutRequest struct {
+	Promo string `form:"promo" binding:"max=50"`
+}
+
+// CartItemResponse - позиция корзины по текущей цене товара; reservedUntil - до какого
+// момента остаток зарезервирован под позицию
+type CartItemResponse struct {
+	Item          string     `json:"item"`
+	Variant       string     `json:"variant,omitempty"`
+	Price         int32      `json:"price"`
+	Quantity      int32      `json:"quantity"`
+	LineTotal     int32      `json:"lineTotal"`
+	ReservedUntil *time.Time `json:"reservedUntil,omitempty"`
+}
+
+// CartResponse - содержимое корзины и ее стоимость
+type CartResponse struct {
+	Items []CartItemResponse `json:"items"`
+	Total int32              `json:"total"`
+}
+
+var errItemNotInCart = errors.New("item not in cart")
+
+// NewCartResponse собирает ответ из позиций корзины
+func NewCartResponse(lines []db.ListCartItemsRow) CartResponse {
+	response := CartResponse{Items: make([]CartItemResponse, 0, len(lines))}
+	for _, line := range lines {
+		lineTotal := line.Price * line.Quantity
+		item := CartItemResponse{
+			Item:      line.Name,
+			Variant:   line.Sku.String,
+			Price:     line.Price,
+			Quantity:  line.Quantity,
+			LineTotal: lineTotal,
+		}
+		if line.ReservedQuantity > 0 {
+			item.ReservedUntil = timestampPtr(line.ReservedUntil)
+		}
+		response.Items = append(response.Items, item)
+		response.Total += lineTotal
+	}
+	return response
+}
+
+// GET /api/cart
+func (server *Server) handleGetCart(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	lines, err := server.store.ListCartItems(c, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewCartResponse(lines))
+}
+
+// POST /api/cart
+func (server *Server) handleAddCartItem(c *gin.Context) {
+	var req AddCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	item, ok := server.resolveItem(c, req.Item, req.Variant)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.AddCartItemTx(c, db.CartItemTxParams{
+		UserID:         user.ID,
+		ItemID:         item.ID,
+		VariantID:      item.VariantID,
+		Quantity:       req.Quantity,
+		ReservationTTL: server.config.ReservationTTL,
+		Now:            time.Now(),
+	})
+	if err != nil {
+		cartReservationError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"message":  "item added to cart",
+		"item":     item.Name,
+		"variant":  item.Sku.String,
+		"quantity": result.Quantity,
+	}
+	if result.ReservedUntil.Valid {
+		response["reserved"] = result.ReservedQuantity
+		response["reservedUntil"] = result.ReservedUntil.Time
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// PUT /api/cart/:item
+func (server *Server) handleUpdateCartItem(c *gin.Context) {
+	var req UpdateCartItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, item, ok := server.cartLine(c)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.UpdateCartItemTx(c, db.CartItemTxParams{
+		UserID:         user.ID,
+		ItemID:         item.ID,
+		VariantID:      item.VariantID,
+		Quantity:       req.Quantity,
+		ReservationTTL: server.config.ReservationTTL,
+		Now:            time.Now(),
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrCartItemNotFound) {
+			c.JSON(http.StatusNotFound, errorResponse(errItemNotInCart))
+			return
+		}
+		cartReservationError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"message":  "cart updated",
+		"item":     item.Name,
+		"quantity": result.Quantity,
+	}
+	if result.ReservedUntil.Valid {
+		response["reserved"] = result.ReservedQuantity
+		response["reservedUntil"] = result.ReservedUntil.Time
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// cartReservationError отвечает на ошибку изменения корзины: если свободного остатка
+// не хватает на резерв, ответ 409 содержит available; товар вне продажи, очередь дропа
+// и ограничения товара описываются так же, как при покупке
+func cartReservationError(c *gin.Context, err error) {
+	var stockErr *db.NotEnoughStockError
+	var limitErr *db.PurchaseLimitError
+	var unavailableErr *db.ItemUnavailableError
+	var queueErr *db.DropQueueError
+	switch {
+	case errors.As(err, &stockErr):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     stockErr.Error(),
+			"available": stockErr.Available,
+		})
+	case errors.As(err, &limitErr):
+		purchaseLimitResponse(c, limitErr)
+	case errors.As(err, &unavailableErr):
+		itemUnavailableResponse(c, unavailableErr)
+	case errors.As(err, &queueErr):
+		dropQueueResponse(c, queueErr)
+	default:
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+	}
+}
+
+// DELETE /api/cart/:item
+func (server *Server) handleRemoveCartItem(c *gin.Context) {
+	user, item, ok := server.cartLine(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := server.store.DeleteCartItem(c, db.DeleteCartItemParams{
+		UserID:    user.ID,
+		ItemID:    item.ID,
+		VariantID: item.VariantID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, errorResponse(errItemNotInCart))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "item removed from cart"})
+}
+
+// POST /api/cart/checkout
+func (server *Server) handleCheckout(c *gin.Context) {
+	var req CheckoutRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CheckoutTx(c, db.CheckoutTxParams{
+		UserID:    user.ID,
+		PromoCode: req.Promo,
+	})
+	if err != nil {
+		var promoErr *db.PromoCodeError
+		var stockErr *db.ItemOutOfStockError
+		var limitErr *db.PurchaseLimitError
+		var unavailableErr *db.ItemUnavailableError
+		var queueErr *db.DropQueueError
+		switch {
+		case errors.Is(err, db.ErrCartEmpty):
+			c.JSON(http.StatusBadRequest, errorResponse(db.ErrCartEmpty))
+		case errors.Is(err, db.ErrInsufficientBalance) || strings.Contains(err.Error(), "CHECK constraint"):
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("insufficient balance")))
+		case errors.As(err, &stockErr):
+			c.JSON(http.StatusConflict, errorResponse(stockErr))
+		case errors.Is(err, db.ErrPromoCodeNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrPromoCodeNotFound))
+		case errors.As(err, &promoErr):
+			c.JSON(http.StatusBadRequest, errorResponse(promoErr))
+		case errors.As(err, &limitErr):
+			purchaseLimitResponse(c, limitErr)
+		case errors.As(err, &unavailableErr):
+			itemUnavailableResponse(c, unavailableErr)
+		case errors.As(err, &queueErr):
+			dropQueueResponse(c, queueErr)
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "checkout successful",
+		"orderId": result.Order.ID,
+		"total":   result.Order.TotalCost,
+		"coins":   result.User.Balance.Int32,
+	})
+}
+
+// cartLine находит пользователя, товар из пути запроса и вариант из параметра variant,
+// при ошибке отвечает сам
+func (server *Server) cartLine(c *gin.Context) (db.GetUserByUsernameRow, db.GetItemByNameRow, bool) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.GetUserByUsernameRow{}, db.GetItemByNameRow{}, false
+	}
+
+	item, ok := server.resolveItem(c, c.Param("item"), c.Query("variant"))
+	if !ok {
+		return db.GetUserByUsernameRow{}, db.GetItemByNameRow{}, false
+	}
+
+	return user, item, true
+}
@@ -0,0 +1,471 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleAddCartItem(t *testing.T) {
+	user := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "testuser",
+		PasswordHash: "password",
+	}
+	item := db.GetItemByNameRow{ID: 3, Name: "cup", Price: 20}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"item": item.Name, "quantity": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				store.EXPECT().
+					AddCartItemTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CartItemTxParams) (db.CartItemTxResult, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, item.ID, arg.ItemID)
+						require.Equal(t, int32(2), arg.Quantity)
+						require.Equal(t, 15*time.Minute, arg.ReservationTTL)
+						return db.CartItemTxResult{
+							Quantity:         3,
+							ReservedQuantity: 2,
+							ReservedUntil:    pgtype.Timestamp{Time: arg.Now.Add(arg.ReservationTTL), Valid: true},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Quantity      int32      `json:"quantity"`
+					Reserved      int32      `json:"reserved"`
+					ReservedUntil *time.Time `json:"reservedUntil"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Equal(t, int32(3), response.Quantity)
+				require.Equal(t, int32(2), response.Reserved)
+				require.NotNil(t, response.ReservedUntil)
+			},
+		},
+		{
+			name: "Conflict_Reserved",
+			body: gin.H{"item": item.Name, "quantity": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				store.EXPECT().
+					AddCartItemTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CartItemTxResult{}, fmt.Errorf("add cart item tx error: %w", &db.NotEnoughStockError{Available: 1}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				require.JSONEq(t, `{"error":"item is out of stock: only 1 available","available":1}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "Forbidden_NotOnSale",
+			body: gin.H{"item": item.Name, "quantity": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				unavailableErr := &db.ItemUnavailableError{
+					Item:          item.Name,
+					Reason:        db.ItemUnavailableNotStarted,
+					AvailableFrom: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+				}
+				store.EXPECT().
+					AddCartItemTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CartItemTxResult{}, fmt.Errorf("add cart item tx error: %w", unavailableErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.JSONEq(t,
+					`{"error":"cup goes on sale at 2026-05-01T12:00:00Z","reason":"not_started","availableFrom":"2026-05-01T12:00:00Z"}`,
+					recorder.Body.String())
+			},
+		},
+		{
+			name: "BadRequest_PurchaseLimit",
+			body: gin.H{"item": item.Name, "quantity": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				limitErr := &db.PurchaseLimitError{Item: item.Name, Limit: db.PurchaseLimitMaxPerUser, Allowed: 1}
+				store.EXPECT().
+					AddCartItemTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CartItemTxResult{}, fmt.Errorf("add cart item tx error: %w", limitErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OK_Variant",
+			body: gin.H{"item": "hoody", "variant": "HOODY-PINK-L", "quantity": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{
+						Name: "hoody",
+						Sku:  pgtype.Text{String: "HOODY-PINK-L", Valid: true},
+					}).
+					Return(db.GetItemByNameRow{
+						ID:          6,
+						Name:        "hoody",
+						VariantID:   pgtype.Int4{Int32: 11, Valid: true},
+						Sku:         pgtype.Text{String: "HOODY-PINK-L", Valid: true},
+						HasVariants: true,
+					}, nil)
+
+				store.EXPECT().
+					AddCartItemTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CartItemTxParams) (db.CartItemTxResult, error) {
+						require.Equal(t, int32(6), arg.ItemID)
+						require.Equal(t, pgtype.Int4{Int32: 11, Valid: true}, arg.VariantID)
+						return db.CartItemTxResult{Quantity: 1}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_ZeroQuantity",
+			body: gin.H{"item": item.Name, "quantity": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddCartItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound_UnknownItem",
+			body: gin.H{"item": "unknown", "quantity": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: "unknown"}).
+					Return(db.GetItemByNameRow{}, pgx.ErrNoRows)
+
+				store.EXPECT().
+					AddCartItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{
+				store:  store,
+				config: Config{CartConfig: CartConfig{ReservationTTL: 15 * time.Minute}},
+			}
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/cart", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", user.Username)
+
+			server.handleAddCartItem(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleRemoveCartItem(t *testing.T) {
+	user := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "testuser",
+		PasswordHash: "password",
+	}
+	item := db.GetItemByNameRow{ID: 3, Name: "cup", Price: 20}
+
+	testCases := []struct {
+		name          string
+		deleted       int64
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			deleted: 1,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "item removed from cart")
+			},
+		},
+		{
+			name:    "NotFound_NotInCart",
+			deleted: 0,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), errItemNotInCart.Error())
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserByUsername(gomock.Any(), user.Username).
+				Return(user, nil)
+			store.EXPECT().
+				GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+				Return(item, nil)
+			store.EXPECT().
+				DeleteCartItem(gomock.Any(), db.DeleteCartItemParams{UserID: user.ID, ItemID: item.ID}).
+				Times(1).
+				Return(tc.deleted, nil)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, "/cart/"+item.Name, nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "item", Value: item.Name}}
+			ctx.Set("username", user.Username)
+
+			server.handleRemoveCartItem(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleCheckout(t *testing.T) {
+	user := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "testuser",
+		PasswordHash: "password",
+	}
+
+	testCases := []struct {
+		name          string
+		promo         string
+		result        db.CheckoutTxResult
+		err           error
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			result: db.CheckoutTxResult{
+				Order: db.Order{ID: 7, UserID: user.ID, TotalCost: 120},
+				User:  db.User{ID: user.ID, Balance: pgtype.Int4{Int32: 880, Valid: true}},
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Message string `json:"message"`
+					OrderID int32  `json:"orderId"`
+					Total   int32  `json:"total"`
+					Coins   int32  `json:"coins"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Equal(t, "checkout successful", response.Message)
+				require.Equal(t, int32(7), response.OrderID)
+				require.Equal(t, int32(120), response.Total)
+				require.Equal(t, int32(880), response.Coins)
+			},
+		},
+		{
+			name:  "OK_PromoCode",
+			promo: "SPRING10",
+			result: db.CheckoutTxResult{
+				Order: db.Order{ID: 8, UserID: user.ID, TotalCost: 108},
+				User:  db.User{ID: user.ID, Balance: pgtype.Int4{Int32: 892, Valid: true}},
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "NotFound_PromoCode",
+			promo: "MISSING",
+			err:   fmt.Errorf("checkout tx error: %w", db.ErrPromoCodeNotFound),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), db.ErrPromoCodeNotFound.Error())
+			},
+		},
+		{
+			name:  "BadRequest_PromoCodeNotApplies",
+			promo: "HOODY50",
+			err:   fmt.Errorf("checkout tx error: %w", &db.PromoCodeError{Code: "HOODY50", Reason: db.PromoReasonNotApplies}),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "promo code HOODY50 does not apply to this item")
+			},
+		},
+		{
+			name: "BadRequest_EmptyCart",
+			err:  fmt.Errorf("checkout tx error: %w", db.ErrCartEmpty),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), db.ErrCartEmpty.Error())
+			},
+		},
+		{
+			name: "BadRequest_InsufficientBalance",
+			err:  fmt.Errorf("checkout tx error: %w", db.ErrInsufficientBalance),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "insufficient balance")
+			},
+		},
+		{
+			name: "Conflict_OutOfStock",
+			err:  fmt.Errorf("checkout tx error: %w", &db.ItemOutOfStockError{Item: "cup"}),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "item is out of stock: cup")
+			},
+		},
+		{
+			name: "InternalError",
+			err:  fmt.Errorf("checkout tx error: %w", pgx.ErrTxClosed),
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserByUsername(gomock.Any(), user.Username).
+				Return(user, nil)
+			store.EXPECT().
+				CheckoutTx(gomock.Any(), db.CheckoutTxParams{UserID: user.ID, PromoCode: tc.promo}).
+				Times(1).
+				Return(tc.result, tc.err)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/cart/checkout?promo="+tc.promo, nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", user.Username)
+
+			server.handleCheckout(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestNewCartResponse(t *testing.T) {
+	response := NewCartResponse([]db.ListCartItemsRow{
+		{ItemID: 1, Name: "cup", Price: 20, Quantity: 3},
+		{
+			ItemID:           2,
+			Name:             "pen",
+			Price:            10,
+			Quantity:         1,
+			ReservedQuantity: 1,
+			ReservedUntil:    pgtype.Timestamp{Time: time.Now().Add(time.Minute), Valid: true},
+		},
+		{ItemID: 6, Name: "hoody", Sku: pgtype.Text{String: "HOODY-PINK-L", Valid: true}, Price: 500, Quantity: 1},
+	})
+
+	require.Len(t, response.Items, 3)
+	require.Equal(t, int32(60), response.Items[0].LineTotal)
+	require.Equal(t, int32(10), response.Items[1].LineTotal)
+	require.Nil(t, response.Items[0].ReservedUntil)
+	require.NotNil(t, response.Items[1].ReservedUntil)
+	require.Equal(t, "HOODY-PINK-L", response.Items[2].Variant)
+	require.Equal(t, int32(570), response.Total)
+}
@@ -0,0 +1,283 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// SearchItemsRequest - поиск по каталогу; пустые параметры не ограничивают выдачу
+type SearchItemsRequest struct {
+	Query    string `form:"q" binding:"max=100"`
+	Category string `form:"category"`
+	Tag      string `form:"tag"`
+	MinPrice *int32 `form:"minPrice" binding:"omitempty,gte=0"`
+	MaxPrice *int32 `form:"maxPrice" binding:"omitempty,gte=0"`
+	Limit    int32  `form:"limit" binding:"omitempty,gt=0,lte=100"`
+	Offset   int32  `form:"offset" binding:"omitempty,gte=0"`
+}
+
+// UpdateItemRequest - изменение карточки товара; отсутствующее поле не меняется,
+// пустая строка очищает категорию или описание, пустой список - теги или характеристики
+type UpdateItemRequest struct {
+	Category    *string           `json:"category"`
+	Description *string           `json:"description" binding:"omitempty,max=2000"`
+	Tags        []string          `json:"tags" binding:"omitempty,max=20,dive,max=30"`
+	Attributes  map[string]string `json:"attributes" binding:"omitempty,max=30,dive,keys,required,max=50,endkeys,required,max=255"`
+}
+
+// CreateCategoryRequest - новая категория каталога
+type CreateCategoryRequest struct {
+	Name string `json:"name" binding:"required,max=50"`
+}
+
+const defaultCatalogueLimit = 20
+
+// CatalogueItem - товар в выдаче каталога; stock - остаток за вычетом активных резервов в корзинах
+type CatalogueItem struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Price       int32           `json:"price"`
+	Stock       *int32          `json:"stock,omitempty"`
+	Category    string          `json:"category,omitempty"`
+	Tags        []string        `json:"tags"`
+	Images      []ImageResponse `json:"images"`
+}
+
+// FacetCount - сколько найденных товаров имеют значение фильтра
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int32  `json:"count"`
+}
+
+// CatalogueFacets - счетчики для уточнения поиска
+type CatalogueFacets struct {
+	Categories []FacetCount `json:"categories"`
+	Tags       []FacetCount `json:"tags"`
+}
+
+// NewCatalogueItems собирает выдачу каталога и общее число найденных товаров;
+// images - изображения товаров по id товара
+func NewCatalogueItems(rows []db.SearchItemsRow, images map[int32][]ImageResponse) ([]CatalogueItem, int64) {
+	var total int64
+	items := make([]CatalogueItem, 0, len(rows))
+	for _, row := range rows {
+		total = row.TotalCount
+		tags := row.Tags
+		if tags == nil {
+			tags = []string{}
+		}
+		itemImages := images[row.ID]
+		if itemImages == nil {
+			itemImages = []ImageResponse{}
+		}
+		items = append(items, CatalogueItem{
+			Name:        row.Name,
+			Description: row.Description.String,
+			Price:       row.Price,
+			Stock:       int4Ptr(db.AvailableStock(row.Stock, row.Reserved)),
+			Category:    row.Category.String,
+			Tags:        tags,
+			Images:      itemImages,
+		})
+	}
+	return items, total
+}
+
+// GET /api/items
+func (server *Server) handleSearchItems(c *gin.Context) {
+	var req SearchItemsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.MinPrice != nil && req.MaxPrice != nil && *req.MinPrice > *req.MaxPrice {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("minPrice cannot exceed maxPrice")))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultCatalogueLimit
+	}
+
+	query := strings.TrimSpace(req.Query)
+	tag := strings.ToLower(strings.TrimSpace(req.Tag))
+	// Товары вне окна продаж в выдачу не попадают
+	now := time.Now()
+	arg := db.SearchItemsParams{
+		Query:     pgtype.Text{String: query, Valid: query != ""},
+		Category:  pgtype.Text{String: req.Category, Valid: req.Category != ""},
+		Tag:       pgtype.Text{String: tag, Valid: tag != ""},
+		MinPrice:  int4FromPtr(req.MinPrice),
+		MaxPrice:  int4FromPtr(req.MaxPrice),
+		Now:       pgtype.Timestamp{Time: now, Valid: true},
+		RowLimit:  req.Limit,
+		RowOffset: req.Offset,
+	}
+
+	rows, err := server.store.SearchItems(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Счетчик измерения не учитывает фильтр по этому же измерению
+	byCategory, err := server.store.CountSearchItemsByCategory(c, db.CountSearchItemsByCategoryParams{
+		Query:    arg.Query,
+		Tag:      arg.Tag,
+		MinPrice: arg.MinPrice,
+		MaxPrice: arg.MaxPrice,
+		Now:      arg.Now,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	byTag, err := server.store.CountSearchItemsByTag(c, db.CountSearchItemsByTagParams{
+		Query:    arg.Query,
+		Category: arg.Category,
+		MinPrice: arg.MinPrice,
+		MaxPrice: arg.MaxPrice,
+		Now:      arg.Now,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	facets := CatalogueFacets{
+		Categories: make([]FacetCount, 0, len(byCategory)),
+		Tags:       make([]FacetCount, 0, len(byTag)),
+	}
+	for _, row := range byCategory {
+		facets.Categories = append(facets.Categories, FacetCount{Value: row.Category, Count: row.Items})
+	}
+	for _, row := range byTag {
+		facets.Tags = append(facets.Tags, FacetCount{Value: row.Tag, Count: row.Items})
+	}
+
+	itemIDs := make([]int32, 0, len(rows))
+	for _, row := range rows {
+		itemIDs = append(itemIDs, row.ID)
+	}
+	images, err := server.itemImages(c, itemIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	upcoming, err := server.upcomingDrops(c, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	items, total := NewCatalogueItems(rows, images)
+	c.JSON(http.StatusOK, gin.H{
+		"items":    items,
+		"total":    total,
+		"facets":   facets,
+		"upcoming": upcoming,
+	})
+}
+
+// GET /api/categories
+func (server *Server) handleListCategories(c *gin.Context) {
+	categories, err := server.store.ListCategories(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	names := make([]string, 0, len(categories))
+	for _, category := range categories {
+		names = append(names, category.Name)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"categories": names})
+}
+
+// POST /api/admin/categories
+func (server *Server) handleCreateCategory(c *gin.Context) {
+	var req CreateCategoryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	category, err := server.store.CreateCategory(c, strings.TrimSpace(req.Name))
+	if err != nil {
+		if strings.Contains(err.Error(), "categories_name_key") {
+			c.JSON(http.StatusConflict, errorResponse(fmt.Errorf("category already exists")))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":   category.ID,
+		"name": category.Name,
+	})
+}
+
+// PATCH /api/admin/items/:item
+func (server *Server) handleUpdateItem(c *gin.Context) {
+	var req UpdateItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	arg := db.UpdateItemTxParams{
+		ItemID:     item.ID,
+		Tags:       req.Tags,
+		Attributes: req.Attributes,
+	}
+	if req.Category != nil {
+		categoryID := pgtype.Int4{}
+		if *req.Category != "" {
+			category, err := server.store.GetCategoryByName(c, *req.Category)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("category not found")))
+					return
+				}
+				c.JSON(http.StatusInternalServerError, errorResponse(err))
+				return
+			}
+			categoryID = pgtype.Int4{Int32: category.ID, Valid: true}
+		}
+		arg.CategoryID = &categoryID
+	}
+	if req.Description != nil {
+		arg.Description = &pgtype.Text{String: *req.Description, Valid: *req.Description != ""}
+	}
+
+	result, err := server.store.UpdateItemTx(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"name":        result.Item.Name,
+		"description": result.Item.Description.String,
+		"categoryId":  int4Ptr(result.Item.CategoryID),
+		"tags":        result.Tags,
+		"attributes":  NewAttributes(result.Attributes),
+	})
+}
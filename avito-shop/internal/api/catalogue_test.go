@@ -0,0 +1,360 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+// Матчер параметров поиска: текущее время подставляет обработчик, поэтому
+// проверяется только то, что оно задано
+type eqSearchParamsMatcher struct {
+	arg interface{}
+}
+
+func (e eqSearchParamsMatcher) Matches(x interface{}) bool {
+	switch arg := x.(type) {
+	case db.SearchItemsParams:
+		if !arg.Now.Valid {
+			return false
+		}
+		arg.Now = pgtype.Timestamp{}
+		return e.arg == arg
+	case db.CountSearchItemsByCategoryParams:
+		if !arg.Now.Valid {
+			return false
+		}
+		arg.Now = pgtype.Timestamp{}
+		return e.arg == arg
+	case db.CountSearchItemsByTagParams:
+		if !arg.Now.Valid {
+			return false
+		}
+		arg.Now = pgtype.Timestamp{}
+		return e.arg == arg
+	}
+	return false
+}
+
+func (e eqSearchParamsMatcher) String() string {
+	return fmt.Sprintf("matches arg %v with current time", e.arg)
+}
+
+func EqSearchParams(arg interface{}) gomock.Matcher {
+	return eqSearchParamsMatcher{arg}
+}
+
+func TestHandleSearchItems(t *testing.T) {
+	rows := []db.SearchItemsRow{
+		{
+			ID:          1,
+			Name:        "t-shirt",
+			Description: pgtype.Text{String: "cotton t-shirt", Valid: true},
+			Price:       80,
+			Category:    pgtype.Text{String: "clothing", Valid: true},
+			Tags:        []string{"cotton", "summer"},
+			TotalCount:  3,
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "?q=shirt&category=clothing&tag=Summer&maxPrice=100&limit=1",
+			buildStubs: func(store *mockdb.MockStore) {
+				query := pgtype.Text{String: "shirt", Valid: true}
+				category := pgtype.Text{String: "clothing", Valid: true}
+				tag := pgtype.Text{String: "summer", Valid: true}
+				maxPrice := pgtype.Int4{Int32: 100, Valid: true}
+
+				store.EXPECT().
+					SearchItems(gomock.Any(), EqSearchParams(db.SearchItemsParams{
+						Query:    query,
+						Category: category,
+						Tag:      tag,
+						MaxPrice: maxPrice,
+						RowLimit: 1,
+					})).
+					Times(1).
+					Return(rows, nil)
+				// Фасет не учитывает фильтр по своему измерению
+				store.EXPECT().
+					CountSearchItemsByCategory(gomock.Any(), EqSearchParams(db.CountSearchItemsByCategoryParams{
+						Query:    query,
+						Tag:      tag,
+						MaxPrice: maxPrice,
+					})).
+					Times(1).
+					Return([]db.CountSearchItemsByCategoryRow{{Category: "clothing", Items: 3}, {Category: "accessories", Items: 1}}, nil)
+				store.EXPECT().
+					CountSearchItemsByTag(gomock.Any(), EqSearchParams(db.CountSearchItemsByTagParams{
+						Query:    query,
+						Category: category,
+						MaxPrice: maxPrice,
+					})).
+					Times(1).
+					Return([]db.CountSearchItemsByTagRow{{Tag: "summer", Items: 3}, {Tag: "cotton", Items: 2}}, nil)
+				store.EXPECT().
+					ListUpcomingDrops(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.ListUpcomingDropsRow{
+						{
+							ID:            5,
+							Name:          "sneakers",
+							Price:         900,
+							AvailableFrom: pgtype.Timestamp{Time: time.Now().Add(time.Hour), Valid: true},
+							QueueMinutes:  pgtype.Int4{Int32: 10, Valid: true},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Items    []CatalogueItem        `json:"items"`
+					Total    int64                  `json:"total"`
+					Facets   CatalogueFacets        `json:"facets"`
+					Upcoming []UpcomingDropResponse `json:"upcoming"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				require.Equal(t, int64(3), response.Total)
+				require.Len(t, response.Items, 1)
+				require.Equal(t, "cotton t-shirt", response.Items[0].Description)
+				require.Equal(t, "clothing", response.Items[0].Category)
+				require.Len(t, response.Facets.Categories, 2)
+				require.Equal(t, FacetCount{Value: "summer", Count: 3}, response.Facets.Tags[0])
+				require.Len(t, response.Upcoming, 1)
+				require.Equal(t, "sneakers", response.Upcoming[0].Name)
+				require.True(t, response.Upcoming[0].Queue)
+			},
+		},
+		{
+			name:  "OK_DefaultLimit",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchItems(gomock.Any(), EqSearchParams(db.SearchItemsParams{RowLimit: defaultCatalogueLimit})).
+					Times(1).
+					Return([]db.SearchItemsRow{}, nil)
+				store.EXPECT().
+					CountSearchItemsByCategory(gomock.Any(), gomock.Any()).
+					Return([]db.CountSearchItemsByCategoryRow{}, nil)
+				store.EXPECT().
+					CountSearchItemsByTag(gomock.Any(), gomock.Any()).
+					Return([]db.CountSearchItemsByTagRow{}, nil)
+				store.EXPECT().
+					ListUpcomingDrops(gomock.Any(), gomock.Any()).
+					Return([]db.ListUpcomingDropsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "BadRequest_PriceRange",
+			query: "?minPrice=100&maxPrice=10",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchItems(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "BadRequest_Limit",
+			query: "?limit=1000",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchItems(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/items"+tc.query, nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+
+			server.handleSearchItems(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleUpdateItem(t *testing.T) {
+	item := db.GetItemByNameRow{ID: 3, Name: "cup", Price: 20}
+	category := db.Category{ID: 2, Name: "accessories"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"category":    category.Name,
+				"description": "big cup",
+				"tags":        []string{"Kitchen"},
+				"attributes":  gin.H{"material": "ceramic"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				store.EXPECT().
+					GetCategoryByName(gomock.Any(), category.Name).
+					Return(category, nil)
+
+				arg := db.UpdateItemTxParams{
+					ItemID:      item.ID,
+					CategoryID:  &pgtype.Int4{Int32: category.ID, Valid: true},
+					Description: &pgtype.Text{String: "big cup", Valid: true},
+					Tags:        []string{"Kitchen"},
+					Attributes:  map[string]string{"material": "ceramic"},
+				}
+				store.EXPECT().
+					UpdateItemTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.UpdateItemTxResult{
+						Item: db.Item{ID: item.ID, Name: item.Name, Description: pgtype.Text{String: "big cup", Valid: true}},
+						Tags: []string{"kitchen"},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OK_ClearCategory",
+			body: gin.H{"category": ""},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					GetCategoryByName(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					UpdateItemTx(gomock.Any(), db.UpdateItemTxParams{ItemID: item.ID, CategoryID: &pgtype.Int4{}}).
+					Times(1).
+					Return(db.UpdateItemTxResult{Item: db.Item{ID: item.ID, Name: item.Name}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound_Category",
+			body: gin.H{"category": "food"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					GetCategoryByName(gomock.Any(), "food").
+					Return(db.Category{}, pgx.ErrNoRows)
+				store.EXPECT().
+					UpdateItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_EmptyAttributeValue",
+			body: gin.H{"attributes": gin.H{"material": ""}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_LongTag",
+			body: gin.H{"tags": []string{"this-tag-is-definitely-longer-than-thirty"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, "/admin/items/"+item.Name, bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "item", Value: item.Name}}
+
+			server.handleUpdateItem(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
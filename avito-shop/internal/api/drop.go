@@ -0,0 +1,257 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// UpdateItemAvailabilityRequest - окно продаж товара; отсутствующее поле снимает ограничение.
+// queueMinutes и queueRate включают честную очередь в первые минуты продаж
+type UpdateItemAvailabilityRequest struct {
+	AvailableFrom  *time.Time `json:"availableFrom"`
+	AvailableUntil *time.Time `json:"availableUntil"`
+	QueueMinutes   *int32     `json:"queueMinutes" binding:"omitempty,gt=0,lte=1440"`
+	QueueRate      *int32     `json:"queueRate" binding:"omitempty,gt=0"`
+}
+
+// validate проверяет согласованность окна продаж и очереди
+func (req UpdateItemAvailabilityRequest) validate() error {
+	if req.AvailableFrom != nil && req.AvailableUntil != nil && !req.AvailableUntil.After(*req.AvailableFrom) {
+		return fmt.Errorf("availableUntil must be after availableFrom")
+	}
+	if (req.QueueMinutes == nil) != (req.QueueRate == nil) {
+		return fmt.Errorf("queueMinutes and queueRate must be set together")
+	}
+	if req.QueueMinutes != nil && req.AvailableFrom == nil {
+		return fmt.Errorf("queue requires availableFrom")
+	}
+	return nil
+}
+
+// UpcomingDropResponse - анонс товара, продажи которого еще не начались
+type UpcomingDropResponse struct {
+	Name           string     `json:"name"`
+	Price          int32      `json:"price"`
+	Category       string     `json:"category,omitempty"`
+	AvailableFrom  time.Time  `json:"availableFrom"`
+	AvailableUntil *time.Time `json:"availableUntil,omitempty"`
+	Queue          bool       `json:"queue"`
+}
+
+// NewUpcomingDrops собирает анонсы каталога
+func NewUpcomingDrops(rows []db.ListUpcomingDropsRow) []UpcomingDropResponse {
+	drops := make([]UpcomingDropResponse, 0, len(rows))
+	for _, row := range rows {
+		drops = append(drops, UpcomingDropResponse{
+			Name:           row.Name,
+			Price:          row.Price,
+			Category:       row.Category.String,
+			AvailableFrom:  row.AvailableFrom.Time,
+			AvailableUntil: timestampPtr(row.AvailableUntil),
+			Queue:          row.QueueMinutes.Valid,
+		})
+	}
+	return drops
+}
+
+// itemUnavailableResponse отвечает на покупку вне окна продаж
+func itemUnavailableResponse(c *gin.Context, unavailableErr *db.ItemUnavailableError) {
+	c.JSON(http.StatusForbidden, itemUnavailableBody(unavailableErr))
+}
+
+// itemUnavailableBody описывает, почему товар сейчас не продается
+func itemUnavailableBody(unavailableErr *db.ItemUnavailableError) gin.H {
+	response := gin.H{
+		"error":  unavailableErr.Error(),
+		"reason": unavailableErr.Reason,
+	}
+	if !unavailableErr.AvailableFrom.IsZero() {
+		response["availableFrom"] = unavailableErr.AvailableFrom
+	}
+	if !unavailableErr.AvailableUntil.IsZero() {
+		response["availableUntil"] = unavailableErr.AvailableUntil
+	}
+	return response
+}
+
+// dropQueueResponse отвечает покупателю, которого очередь дропа еще не допустила: 429 с местом в очереди
+func dropQueueResponse(c *gin.Context, queueErr *db.DropQueueError) {
+	c.JSON(http.StatusTooManyRequests, dropQueueBody(queueErr))
+}
+
+// dropQueueBody описывает место покупателя в очереди дропа
+func dropQueueBody(queueErr *db.DropQueueError) gin.H {
+	response := gin.H{
+		"error":       queueErr.Error(),
+		"joined":      queueErr.Joined,
+		"admitted":    queueErr.Admitted,
+		"queueEndsAt": queueErr.QueueEndsAt,
+	}
+	if queueErr.Joined {
+		response["position"] = queueErr.Position
+	}
+	return response
+}
+
+// PUT /api/admin/items/:item/availability
+func (server *Server) handleUpdateItemAvailability(c *gin.Context) {
+	var req UpdateItemAvailabilityRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	updated, err := server.store.UpdateItemAvailability(c, db.UpdateItemAvailabilityParams{
+		AvailableFrom:  timestampFromPtr(req.AvailableFrom),
+		AvailableUntil: timestampFromPtr(req.AvailableUntil),
+		QueueMinutes:   int4FromPtr(req.QueueMinutes),
+		QueueRate:      int4FromPtr(req.QueueRate),
+		ID:             item.ID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"item":           updated.Name,
+		"availableFrom":  timestampPtr(updated.AvailableFrom),
+		"availableUntil": timestampPtr(updated.AvailableUntil),
+		"queueMinutes":   int4Ptr(updated.QueueMinutes),
+		"queueRate":      int4Ptr(updated.QueueRate),
+	})
+}
+
+// dropQueueItem находит товар с очередью, в которую еще можно встать
+func (server *Server) dropQueueItem(c *gin.Context) (db.Item, bool) {
+	found, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return db.Item{}, false
+	}
+
+	item, err := server.store.GetItemByID(c, found.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Item{}, false
+	}
+	if !item.Availability().HasQueue() {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item is not sold through a queue")))
+		return db.Item{}, false
+	}
+	return item, true
+}
+
+// POST /api/drops/:item/queue
+// Встать в очередь можно до конца действия очереди; повторный вход место не меняет
+func (server *Server) handleJoinDropQueue(c *gin.Context) {
+	item, ok := server.dropQueueItem(c)
+	if !ok {
+		return
+	}
+
+	availability := item.Availability()
+	now := time.Now()
+	if !now.Before(availability.QueueEndsAt()) {
+		c.JSON(http.StatusConflict, errorResponse(fmt.Errorf("queue is closed, the item is sold without a queue")))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	joined, err := server.store.JoinDropQueue(c, db.JoinDropQueueParams{
+		ItemID:   item.ID,
+		UserID:   user.ID,
+		Position: db.DropQueuePosition(availability.AvailableFrom.Time, now),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	message := "joined the queue"
+	if joined == 0 {
+		message = "already in the queue"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":       message,
+		"availableFrom": availability.AvailableFrom.Time,
+		"queueEndsAt":   availability.QueueEndsAt(),
+	})
+}
+
+// GET /api/drops/:item/queue
+// До начала продаж место в очереди не раскрывается: оно определится случайно
+func (server *Server) handleGetDropQueue(c *gin.Context) {
+	item, ok := server.dropQueueItem(c)
+	if !ok {
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	queued, err := server.store.CountDropQueue(c, item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	availability := item.Availability()
+	now := time.Now()
+	response := gin.H{
+		"availableFrom": availability.AvailableFrom.Time,
+		"queueEndsAt":   availability.QueueEndsAt(),
+		"queued":        queued,
+		"joined":        false,
+	}
+
+	ahead, err := server.store.GetDropQueueRank(c, db.GetDropQueueRankParams{ItemID: item.ID, UserID: user.ID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if err == nil {
+		response["joined"] = true
+		if !now.Before(availability.AvailableFrom.Time) {
+			admitted := availability.Admitted(now)
+			response["position"] = ahead + 1
+			response["admitted"] = ahead < admitted || !availability.QueueActive(now)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// upcomingDrops - анонсы для выдачи каталога
+func (server *Server) upcomingDrops(c *gin.Context, now time.Time) ([]UpcomingDropResponse, error) {
+	rows, err := server.store.ListUpcomingDrops(c, pgtype.Timestamp{Time: now, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+	return NewUpcomingDrops(rows), nil
+}
@@ -0,0 +1,357 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleUpdateItemAvailability(t *testing.T) {
+	item := db.GetItemByNameRow{ID: 3, Name: "sneakers", Price: 900}
+	from := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	until := from.Add(48 * time.Hour)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{
+				"availableFrom":  from,
+				"availableUntil": until,
+				"queueMinutes":   10,
+				"queueRate":      50,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				arg := db.UpdateItemAvailabilityParams{
+					AvailableFrom:  pgtype.Timestamp{Time: from, Valid: true},
+					AvailableUntil: pgtype.Timestamp{Time: until, Valid: true},
+					QueueMinutes:   pgtype.Int4{Int32: 10, Valid: true},
+					QueueRate:      pgtype.Int4{Int32: 50, Valid: true},
+					ID:             item.ID,
+				}
+				store.EXPECT().
+					UpdateItemAvailability(gomock.Any(), arg).
+					Times(1).
+					Return(db.Item{
+						ID:             item.ID,
+						Name:           item.Name,
+						AvailableFrom:  arg.AvailableFrom,
+						AvailableUntil: arg.AvailableUntil,
+						QueueMinutes:   arg.QueueMinutes,
+						QueueRate:      arg.QueueRate,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t,
+					`{"item":"sneakers","availableFrom":"2026-05-01T12:00:00Z","availableUntil":"2026-05-03T12:00:00Z","queueMinutes":10,"queueRate":50}`,
+					recorder.Body.String())
+			},
+		},
+		{
+			name: "OK_Clear",
+			body: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					UpdateItemAvailability(gomock.Any(), db.UpdateItemAvailabilityParams{ID: item.ID}).
+					Times(1).
+					Return(db.Item{ID: item.ID, Name: item.Name}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_Window",
+			body: gin.H{"availableFrom": until, "availableUntil": from},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateItemAvailability(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_QueueWithoutStart",
+			body: gin.H{"queueMinutes": 10, "queueRate": 50},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateItemAvailability(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "queue requires availableFrom")
+			},
+		},
+		{
+			name: "BadRequest_QueueRateMissing",
+			body: gin.H{"availableFrom": from, "queueMinutes": 10},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateItemAvailability(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, "/admin/items/"+item.Name+"/availability", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "item", Value: item.Name}}
+
+			server.handleUpdateItemAvailability(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleDropQueue(t *testing.T) {
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+	found := db.GetItemByNameRow{ID: 3, Name: "sneakers", Price: 900}
+	queued := func(from time.Time) db.Item {
+		return db.Item{
+			ID:            found.ID,
+			Name:          found.Name,
+			Price:         found.Price,
+			AvailableFrom: pgtype.Timestamp{Time: from, Valid: true},
+			QueueMinutes:  pgtype.Int4{Int32: 10, Valid: true},
+			QueueRate:     pgtype.Int4{Int32: 2, Valid: true},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK_Join",
+			method: http.MethodPost,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByID(gomock.Any(), found.ID).
+					Return(queued(time.Now().Add(time.Hour)), nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					JoinDropQueue(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "joined the queue")
+			},
+		},
+		{
+			name:   "OK_AlreadyJoined",
+			method: http.MethodPost,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByID(gomock.Any(), found.ID).
+					Return(queued(time.Now()), nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					JoinDropQueue(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "already in the queue")
+			},
+		},
+		{
+			name:   "Conflict_QueueClosed",
+			method: http.MethodPost,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByID(gomock.Any(), found.ID).
+					Return(queued(time.Now().Add(-time.Hour)), nil)
+				store.EXPECT().
+					JoinDropQueue(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound_NoQueue",
+			method: http.MethodPost,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByID(gomock.Any(), found.ID).
+					Return(db.Item{ID: found.ID, Name: found.Name}, nil)
+				store.EXPECT().
+					JoinDropQueue(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "OK_StatusBeforeStart",
+			method: http.MethodGet,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByID(gomock.Any(), found.ID).
+					Return(queued(time.Now().Add(time.Hour)), nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					CountDropQueue(gomock.Any(), found.ID).
+					Return(int32(7), nil)
+				store.EXPECT().
+					GetDropQueueRank(gomock.Any(), db.GetDropQueueRankParams{ItemID: found.ID, UserID: user.ID}).
+					Return(int32(3), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, true, response["joined"])
+				require.Equal(t, float64(7), response["queued"])
+				// До начала продаж место не раскрывается
+				require.NotContains(t, response, "position")
+			},
+		},
+		{
+			name:   "OK_StatusWaiting",
+			method: http.MethodGet,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByID(gomock.Any(), found.ID).
+					Return(queued(time.Now().Add(-30*time.Second)), nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					CountDropQueue(gomock.Any(), found.ID).
+					Return(int32(7), nil)
+				store.EXPECT().
+					GetDropQueueRank(gomock.Any(), gomock.Any()).
+					Return(int32(3), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, float64(4), response["position"])
+				require.Equal(t, false, response["admitted"])
+			},
+		},
+		{
+			name:   "OK_StatusNotJoined",
+			method: http.MethodGet,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByID(gomock.Any(), found.ID).
+					Return(queued(time.Now()), nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					CountDropQueue(gomock.Any(), found.ID).
+					Return(int32(0), nil)
+				store.EXPECT().
+					GetDropQueueRank(gomock.Any(), gomock.Any()).
+					Return(int32(0), pgx.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response map[string]interface{}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, false, response["joined"])
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: found.Name}).
+				Return(found, nil)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, "/drops/"+found.Name+"/queue", nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "item", Value: found.Name}}
+			ctx.Set("username", user.Username)
+
+			if tc.method == http.MethodPost {
+				server.handleJoinDropQueue(ctx)
+			} else {
+				server.handleGetDropQueue(ctx)
+			}
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
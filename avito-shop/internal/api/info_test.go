@@ -20,6 +20,7 @@ import (
 func TestHandleGetInfo(t *testing.T) {
 	testCases := []struct {
 		name          string
+		limits        db.TransferLimits
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
@@ -28,7 +29,7 @@ func TestHandleGetInfo(t *testing.T) {
 			buildStubs: func(store *mockdb.MockStore) {
 				username := "test_user"
 				userID := int32(1)
-				balance := pgtype.Int4{Int32: 1000, Valid: true}
+				balance := int32(1000)
 
 				// Создаем timestamp для тестов
 				now := time.Now()
@@ -50,18 +51,77 @@ func TestHandleGetInfo(t *testing.T) {
 						SenderUsername:   username,
 						ReceiverUsername: "user2",
 					},
+					{
+						Timestamp:        pgTimestamp,
+						Amount:           80,
+						Kind:             db.TransactionKindRefund,
+						SenderUsername:   "system",
+						ReceiverUsername: username,
+					},
+				}
+
+				settlesAt := pgtype.Timestamp{
+					Time:  time.Date(2025, 2, 1, 12, 0, 0, 0, time.UTC),
+					Valid: true,
+				}
+
+				pendingTransfers := []db.GetPendingTransfersRow{
+					{
+						ID:               7,
+						Amount:           50,
+						SettlesAt:        settlesAt,
+						SenderUsername:   username,
+						ReceiverUsername: "user3",
+					},
 				}
 
-				purchases := []db.GetPurchasesRow{
+				expirations := []db.GetUpcomingExpirationsRow{
 					{
-						Name:         "t-shirt",
-						Quantity:     2,
-						PurchaseDate: pgTimestamp,
+						ExpiresOn: pgtype.Date{Time: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+						Amount:    300,
 					},
+				}
+
+				gifts := []db.GetGiftsRow{
 					{
-						Name:         "cup",
-						Quantity:     3,
-						PurchaseDate: pgTimestamp,
+						ID:                11,
+						Name:              "cup",
+						Quantity:          1,
+						GiftMessage:       pgtype.Text{String: "Happy birthday!", Valid: true},
+						PurchaseDate:      pgtype.Timestamp{Time: time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC), Valid: true},
+						BuyerUsername:     "user1",
+						RecipientUsername: username,
+					},
+				}
+
+				inventory := []db.GetInventoryRow{
+					{
+						Name:        "t-shirt",
+						OrderStatus: pgtype.Text{String: db.OrderStatusDelivered, Valid: true},
+						Quantity:    2,
+						Received:    1,
+					},
+					{
+						Name:        "cup",
+						OrderStatus: pgtype.Text{String: db.OrderStatusReadyForPickup, Valid: true},
+						Quantity:    2,
+					},
+					{
+						Name:        "cup",
+						OrderStatus: pgtype.Text{String: db.OrderStatusPlaced, Valid: true},
+						Quantity:    1,
+					},
+					{
+						Name:        "hoody",
+						Variant:     pgtype.Text{String: "HOODY-PINK-L", Valid: true},
+						OrderStatus: pgtype.Text{String: db.OrderStatusPlaced, Valid: true},
+						Quantity:    1,
+					},
+					{
+						Name:        "hoody",
+						Variant:     pgtype.Text{String: "HOODY-GREY-M", Valid: true},
+						OrderStatus: pgtype.Text{String: db.OrderStatusPlaced, Valid: true},
+						Quantity:    1,
 					},
 				}
 
@@ -79,12 +139,24 @@ func TestHandleGetInfo(t *testing.T) {
 					Return(transactions, nil)
 
 				store.EXPECT().
-					GetCurrentBalance(gomock.Any(), userID).
+					GetSpendableBalance(gomock.Any(), gomock.Any()).
 					Return(balance, nil)
 
 				store.EXPECT().
-					GetPurchases(gomock.Any(), pgtype.Int4{Int32: userID, Valid: true}).
-					Return(purchases, nil)
+					GetPendingTransfers(gomock.Any(), pgtype.Int4{Int32: userID, Valid: true}).
+					Return(pendingTransfers, nil)
+
+				store.EXPECT().
+					GetUpcomingExpirations(gomock.Any(), gomock.Any()).
+					Return(expirations, nil)
+
+				store.EXPECT().
+					GetInventory(gomock.Any(), userID).
+					Return(inventory, nil)
+
+				store.EXPECT().
+					GetGifts(gomock.Any(), pgtype.Int4{Int32: userID, Valid: true}).
+					Return(gifts, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusOK, recorder.Code)
@@ -93,16 +165,40 @@ func TestHandleGetInfo(t *testing.T) {
 				expectedJSON := `{
 					"coins": 1000,
 					"inventory": [
-						{"type": "t-shirt", "quantity": 2},
-						{"type": "cup", "quantity": 3}
+						{"type": "t-shirt", "quantity": 2, "received": 1, "orders": {"delivered": 2}},
+						{"type": "cup", "quantity": 3, "orders": {"ready_for_pickup": 2, "placed": 1}},
+						{"type": "hoody", "variant": "HOODY-PINK-L", "quantity": 1, "orders": {"placed": 1}},
+						{"type": "hoody", "variant": "HOODY-GREY-M", "quantity": 1, "orders": {"placed": 1}}
 					],
 					"coinHistory": {
 						"received": [
-							{"fromUser": "user1", "amount": 100}
+							{"fromUser": "user1", "amount": 100},
+							{"fromUser": "system", "amount": 80, "kind": "refund"}
 						],
 						"sent": [
 							{"toUser": "user2", "amount": 200}
 						]
+					},
+					"pending": {
+						"sent": [
+							{"id": 7, "toUser": "user3", "amount": 50, "settlesAt": "2025-02-01T12:00:00Z"}
+						],
+						"received": null
+					},
+					"transferLimits": {
+						"maxAmount": null,
+						"dailyRemaining": null,
+						"weeklyRemaining": null,
+						"minuteRemaining": null
+					},
+					"expiringCoins": [
+						{"amount": 300, "expiresOn": "2025-03-01"}
+					],
+					"gifts": {
+						"sent": null,
+						"received": [
+							{"id": 11, "fromUser": "user1", "item": "cup", "quantity": 1, "message": "Happy birthday!", "date": "2025-01-15T09:00:00Z"}
+						]
 					}
 				}`
 
@@ -117,6 +213,66 @@ func TestHandleGetInfo(t *testing.T) {
 				require.Equal(t, expected, actual)
 			},
 		},
+		{
+			name: "OK_TransferLimits",
+			limits: db.TransferLimits{
+				MaxAmount:   300,
+				DailyLimit:  500,
+				WeeklyLimit: 2000,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Any()).
+					Return(db.GetUserByUsernameRow{
+						ID:           1,
+						Username:     "test_user",
+						PasswordHash: "hashed_password",
+					}, nil)
+
+				store.EXPECT().
+					GetTransactions(gomock.Any(), gomock.Any()).
+					Return([]db.GetTransactionsRow{}, nil)
+
+				store.EXPECT().
+					GetSpendableBalance(gomock.Any(), gomock.Any()).
+					Return(int32(1000), nil)
+
+				store.EXPECT().
+					GetPendingTransfers(gomock.Any(), gomock.Any()).
+					Return([]db.GetPendingTransfersRow{}, nil)
+
+				store.EXPECT().
+					GetUpcomingExpirations(gomock.Any(), gomock.Any()).
+					Return([]db.GetUpcomingExpirationsRow{}, nil)
+
+				// За сутки уже отправлено 450, за неделю 600
+				store.EXPECT().
+					GetOutgoingTransferStats(gomock.Any(), gomock.Any()).
+					Return(db.GetOutgoingTransferStatsRow{DailyAmount: 450, WeeklyAmount: 600, MinuteCount: 1}, nil)
+
+				store.EXPECT().
+					GetInventory(gomock.Any(), gomock.Any()).
+					Return([]db.GetInventoryRow{}, nil)
+
+				store.EXPECT().
+					GetGifts(gomock.Any(), gomock.Any()).
+					Return([]db.GetGiftsRow{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var actual struct {
+					TransferLimits TransferLimitsInfo `json:"transferLimits"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &actual)
+				require.NoError(t, err)
+
+				require.Equal(t, int32(300), *actual.TransferLimits.MaxAmount)
+				require.Equal(t, int32(50), *actual.TransferLimits.DailyRemaining)
+				require.Equal(t, int32(1400), *actual.TransferLimits.WeeklyRemaining)
+				require.Nil(t, actual.TransferLimits.MinuteRemaining)
+			},
+		},
 		{
 			name: "GetUserError",
 			buildStubs: func(store *mockdb.MockStore) {
@@ -163,15 +319,15 @@ func TestHandleGetInfo(t *testing.T) {
 					Return([]db.GetTransactionsRow{}, nil)
 
 				store.EXPECT().
-					GetCurrentBalance(gomock.Any(), gomock.Any()).
-					Return(pgtype.Int4{}, errors.New("database error"))
+					GetSpendableBalance(gomock.Any(), gomock.Any()).
+					Return(int32(0), errors.New("database error"))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
 		{
-			name: "GetPurchasesError",
+			name: "GetPendingTransfersError",
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetUserByUsername(gomock.Any(), gomock.Any()).
@@ -186,12 +342,117 @@ func TestHandleGetInfo(t *testing.T) {
 					Return([]db.GetTransactionsRow{}, nil)
 
 				store.EXPECT().
-					GetCurrentBalance(gomock.Any(), gomock.Any()).
-					Return(pgtype.Int4{Int32: 1000, Valid: true}, nil)
+					GetSpendableBalance(gomock.Any(), gomock.Any()).
+					Return(int32(1000), nil)
+
+				store.EXPECT().
+					GetPendingTransfers(gomock.Any(), gomock.Any()).
+					Return([]db.GetPendingTransfersRow{}, errors.New("database error"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "GetUpcomingExpirationsError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Any()).
+					Return(db.GetUserByUsernameRow{
+						ID:           1,
+						Username:     "test_user",
+						PasswordHash: "hashed_password",
+					}, nil)
+
+				store.EXPECT().
+					GetTransactions(gomock.Any(), gomock.Any()).
+					Return([]db.GetTransactionsRow{}, nil)
+
+				store.EXPECT().
+					GetSpendableBalance(gomock.Any(), gomock.Any()).
+					Return(int32(1000), nil)
+
+				store.EXPECT().
+					GetPendingTransfers(gomock.Any(), gomock.Any()).
+					Return([]db.GetPendingTransfersRow{}, nil)
+
+				store.EXPECT().
+					GetUpcomingExpirations(gomock.Any(), gomock.Any()).
+					Return([]db.GetUpcomingExpirationsRow{}, errors.New("database error"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "GetInventoryError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Any()).
+					Return(db.GetUserByUsernameRow{
+						ID:           1,
+						Username:     "test_user",
+						PasswordHash: "hashed_password",
+					}, nil)
+
+				store.EXPECT().
+					GetTransactions(gomock.Any(), gomock.Any()).
+					Return([]db.GetTransactionsRow{}, nil)
+
+				store.EXPECT().
+					GetSpendableBalance(gomock.Any(), gomock.Any()).
+					Return(int32(1000), nil)
+
+				store.EXPECT().
+					GetPendingTransfers(gomock.Any(), gomock.Any()).
+					Return([]db.GetPendingTransfersRow{}, nil)
+
+				store.EXPECT().
+					GetUpcomingExpirations(gomock.Any(), gomock.Any()).
+					Return([]db.GetUpcomingExpirationsRow{}, nil)
+
+				store.EXPECT().
+					GetInventory(gomock.Any(), gomock.Any()).
+					Return([]db.GetInventoryRow{}, errors.New("database error"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "GetGiftsError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), gomock.Any()).
+					Return(db.GetUserByUsernameRow{
+						ID:           1,
+						Username:     "test_user",
+						PasswordHash: "hashed_password",
+					}, nil)
+
+				store.EXPECT().
+					GetTransactions(gomock.Any(), gomock.Any()).
+					Return([]db.GetTransactionsRow{}, nil)
+
+				store.EXPECT().
+					GetSpendableBalance(gomock.Any(), gomock.Any()).
+					Return(int32(1000), nil)
+
+				store.EXPECT().
+					GetPendingTransfers(gomock.Any(), gomock.Any()).
+					Return([]db.GetPendingTransfersRow{}, nil)
+
+				store.EXPECT().
+					GetUpcomingExpirations(gomock.Any(), gomock.Any()).
+					Return([]db.GetUpcomingExpirationsRow{}, nil)
+
+				store.EXPECT().
+					GetInventory(gomock.Any(), gomock.Any()).
+					Return([]db.GetInventoryRow{}, nil)
 
 				store.EXPECT().
-					GetPurchases(gomock.Any(), gomock.Any()).
-					Return([]db.GetPurchasesRow{}, errors.New("database error"))
+					GetGifts(gomock.Any(), gomock.Any()).
+					Return([]db.GetGiftsRow{}, errors.New("database error"))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusInternalServerError, recorder.Code)
@@ -209,7 +470,10 @@ func TestHandleGetInfo(t *testing.T) {
 			store := mockdb.NewMockStore(ctrl)
 			tc.buildStubs(store)
 
-			server := &Server{store: store}
+			server := &Server{
+				store:  store,
+				config: Config{TransferConfig: TransferConfig{TransferLimits: tc.limits}},
+			}
 			recorder := httptest.NewRecorder()
 
 			ctx, _ := gin.CreateTestContext(recorder)
@@ -0,0 +1,188 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+)
+
+// GiveItemRequest - передача товара из инвентаря другому пользователю; quantity не задан - одна единица
+type GiveItemRequest struct {
+	ToUser string `json:"toUser" binding:"required"`
+	Item   string `json:"item" binding:"required"`
+	// Variant - артикул варианта, обязателен для товаров с вариантами
+	Variant  string `json:"variant"`
+	Quantity int32  `json:"quantity" binding:"omitempty,gt=0,lte=100"`
+	Message  string `json:"message" binding:"max=200"`
+}
+
+// InventoryUnitResponse - единица товара во владении пользователя и то, как она к нему попала
+type InventoryUnitResponse struct {
+	ID           int32     `json:"id"`
+	Item         string    `json:"item"`
+	Variant      string    `json:"variant,omitempty"`
+	AcquiredVia  string    `json:"acquiredVia"`
+	AcquiredFrom string    `json:"acquiredFrom,omitempty"`
+	AcquiredAt   time.Time `json:"acquiredAt"`
+	PurchaseID   *int32    `json:"purchaseId,omitempty"`
+	OrderStatus  string    `json:"orderStatus,omitempty"`
+	// ListingID - активное объявление о продаже единицы на маркетплейсе
+	ListingID *int32 `json:"listingId,omitempty"`
+}
+
+// InventoryTransferResponse - переход единицы от одного владельца к другому
+type InventoryTransferResponse struct {
+	ID        int32     `json:"id"`
+	FromUser  string    `json:"fromUser"`
+	ToUser    string    `json:"toUser"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// GET /api/inventory
+func (server *Server) handleListInventory(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.ListInventoryUnits(c, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	units := make([]InventoryUnitResponse, 0, len(rows))
+	for _, row := range rows {
+		units = append(units, InventoryUnitResponse{
+			ID:           row.ID,
+			Item:         row.Name,
+			Variant:      row.Variant.String,
+			AcquiredVia:  row.AcquiredVia,
+			AcquiredFrom: row.AcquiredFrom.String,
+			AcquiredAt:   row.AcquiredAt.Time,
+			PurchaseID:   int4Ptr(row.PurchaseID),
+			OrderStatus:  row.OrderStatus.String,
+			ListingID:    int4Ptr(row.ListingID),
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"units": units})
+}
+
+// GET /api/inventory/:id/history - история передач единицы; доступна только текущему владельцу
+func (server *Server) handleGetInventoryHistory(c *gin.Context) {
+	unitID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid inventory item id")))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	unit, err := server.store.GetInventoryItem(c, int32(unitID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	// Чужие единицы не отличаются от несуществующих
+	if err != nil || unit.OwnerID != user.ID {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("inventory item not found")))
+		return
+	}
+
+	rows, err := server.store.ListInventoryTransfers(c, unit.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	transfers := make([]InventoryTransferResponse, 0, len(rows))
+	for _, row := range rows {
+		transfers = append(transfers, InventoryTransferResponse{
+			ID:        row.ID,
+			FromUser:  row.FromUser.String,
+			ToUser:    row.ToUser.String,
+			Kind:      row.Kind,
+			Message:   row.Message.String,
+			CreatedAt: row.CreatedAt.Time,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":          unit.ID,
+		"acquiredVia": unit.AcquiredVia,
+		"acquiredAt":  unit.AcquiredAt.Time,
+		"transfers":   transfers,
+	})
+}
+
+// POST /api/giveItem
+func (server *Server) handleGiveItem(c *gin.Context) {
+	var req GiveItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Quantity == 0 {
+		req.Quantity = 1
+	}
+
+	sender, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	receiver, err := server.store.GetUserByUsername(c, req.ToUser)
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(err))
+		return
+	}
+
+	if sender.ID == receiver.ID {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("cannot give items to yourself")))
+		return
+	}
+
+	item, ok := server.resolveItem(c, req.Item, req.Variant)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.GiveItemTx(c, db.GiveItemTxParams{
+		FromUserID: sender.ID,
+		ToUserID:   receiver.ID,
+		ItemID:     item.ID,
+		VariantID:  item.VariantID.Int32,
+		Quantity:   req.Quantity,
+		Message:    req.Message,
+	})
+	if err != nil {
+		var itemsErr *db.NotEnoughItemsError
+		if errors.As(err, &itemsErr) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     itemsErr.Error(),
+				"available": itemsErr.Available,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "items given",
+		"units":   result.Units,
+	})
+}
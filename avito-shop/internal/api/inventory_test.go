@@ -0,0 +1,334 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleGiveItem(t *testing.T) {
+	sender := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+	receiver := db.GetUserByUsernameRow{ID: 2, Username: "user2"}
+	item := db.GetItemByNameRow{ID: 3, Name: "cup", Price: 20}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"toUser": receiver.Username, "item": item.Name, "quantity": 2, "message": "enjoy"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), receiver.Username).
+					Return(receiver, nil)
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				arg := db.GiveItemTxParams{
+					FromUserID: sender.ID,
+					ToUserID:   receiver.ID,
+					ItemID:     item.ID,
+					Quantity:   2,
+					Message:    "enjoy",
+				}
+				store.EXPECT().
+					GiveItemTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.GiveItemTxResult{Units: []int32{11, 12}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"message":"items given","units":[11,12]}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "OK_Variant",
+			body: gin.H{"toUser": receiver.Username, "item": "hoody", "variant": "HOODY-PINK-L"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), receiver.Username).
+					Return(receiver, nil)
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(db.GetItemByNameRow{
+						ID:          4,
+						Name:        "hoody",
+						VariantID:   pgtype.Int4{Int32: 8, Valid: true},
+						HasVariants: true,
+					}, nil)
+				store.EXPECT().
+					GiveItemTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.GiveItemTxParams) (db.GiveItemTxResult, error) {
+						require.Equal(t, int32(8), arg.VariantID)
+						require.Equal(t, int32(1), arg.Quantity)
+						return db.GiveItemTxResult{Units: []int32{5}}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_NotEnoughItems",
+			body: gin.H{"toUser": receiver.Username, "item": item.Name, "quantity": 3},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), receiver.Username).
+					Return(receiver, nil)
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					GiveItemTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.GiveItemTxResult{}, fmt.Errorf("give item tx error: %w", &db.NotEnoughItemsError{Available: 1}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.JSONEq(t, `{"error":"only 1 items can be transferred","available":1}`, recorder.Body.String())
+			},
+		},
+		{
+			name: "BadRequest_Self",
+			body: gin.H{"toUser": sender.Username, "item": item.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Times(2).
+					Return(sender, nil)
+				store.EXPECT().
+					GiveItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "cannot give items to yourself")
+			},
+		},
+		{
+			name: "NotFound_Receiver",
+			body: gin.H{"toUser": "ghost", "item": item.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), "ghost").
+					Return(db.GetUserByUsernameRow{}, pgx.ErrNoRows)
+				store.EXPECT().
+					GiveItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_VariantRequired",
+			body: gin.H{"toUser": receiver.Username, "item": "hoody"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), receiver.Username).
+					Return(receiver, nil)
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(db.GetItemByNameRow{ID: 4, Name: "hoody", HasVariants: true}, nil)
+				store.EXPECT().
+					GiveItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_Message",
+			body: gin.H{"toUser": receiver.Username, "item": item.Name, "message": string(make([]byte, 201))},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GiveItemTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/giveItem", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", sender.Username)
+
+			server.handleGiveItem(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleGetInventoryHistory(t *testing.T) {
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+
+	testCases := []struct {
+		name          string
+		unitID        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			unitID: "11",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					GetInventoryItem(gomock.Any(), int32(11)).
+					Return(db.InventoryItem{ID: 11, OwnerID: user.ID, AcquiredVia: db.AcquiredViaTransfer}, nil)
+				store.EXPECT().
+					ListInventoryTransfers(gomock.Any(), int32(11)).
+					Times(1).
+					Return([]db.ListInventoryTransfersRow{
+						{
+							ID:       3,
+							FromUser: pgtype.Text{String: "user2", Valid: true},
+							ToUser:   pgtype.Text{String: "user1", Valid: true},
+							Kind:     db.InventoryTransferKindGive,
+							Message:  pgtype.Text{String: "enjoy", Valid: true},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					ID          int32                       `json:"id"`
+					AcquiredVia string                      `json:"acquiredVia"`
+					Transfers   []InventoryTransferResponse `json:"transfers"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, db.AcquiredViaTransfer, response.AcquiredVia)
+				require.Len(t, response.Transfers, 1)
+				require.Equal(t, "user2", response.Transfers[0].FromUser)
+				require.Equal(t, "enjoy", response.Transfers[0].Message)
+			},
+		},
+		{
+			name:   "NotFound_OtherOwner",
+			unitID: "11",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					GetInventoryItem(gomock.Any(), int32(11)).
+					Return(db.InventoryItem{ID: 11, OwnerID: 2}, nil)
+				store.EXPECT().
+					ListInventoryTransfers(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			unitID: "11",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					GetInventoryItem(gomock.Any(), int32(11)).
+					Return(db.InventoryItem{}, pgx.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "BadRequest_ID",
+			unitID: "abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetInventoryItem(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/inventory/"+tc.unitID+"/history", nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.unitID}}
+			ctx.Set("username", user.Username)
+
+			server.handleGetInventoryHistory(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
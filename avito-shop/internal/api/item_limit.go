@@ -0,0 +1,70 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UpdateItemLimitsRequest - ограничения покупки товара одним сотрудником;
+// отсутствующее поле снимает соответствующее ограничение
+type UpdateItemLimitsRequest struct {
+	MaxPerUser   *int32 `json:"maxPerUser" binding:"omitempty,gt=0"`
+	CooldownDays *int32 `json:"cooldownDays" binding:"omitempty,gt=0"`
+}
+
+// purchaseLimitResponse отвечает на нарушение ограничения товара: период ожидания -
+// 429 со временем, когда покупка снова станет доступна, остальное - 400
+func purchaseLimitResponse(c *gin.Context, limitErr *db.PurchaseLimitError) {
+	status := http.StatusBadRequest
+	if !limitErr.AvailableAt.IsZero() {
+		status = http.StatusTooManyRequests
+	}
+	c.JSON(status, purchaseLimitBody(limitErr))
+}
+
+// purchaseLimitBody описывает превышенное ограничение товара
+func purchaseLimitBody(limitErr *db.PurchaseLimitError) gin.H {
+	response := gin.H{
+		"error":     limitErr.Error(),
+		"limit":     limitErr.Limit,
+		"remaining": limitErr.Remaining,
+	}
+	if !limitErr.AvailableAt.IsZero() {
+		response["availableAt"] = limitErr.AvailableAt
+	}
+	return response
+}
+
+// PUT /api/admin/items/:item/limits
+func (server *Server) handleUpdateItemLimits(c *gin.Context) {
+	var req UpdateItemLimitsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	updated, err := server.store.UpdateItemLimits(c, db.UpdateItemLimitsParams{
+		ID:           item.ID,
+		MaxPerUser:   int4FromPtr(req.MaxPerUser),
+		CooldownDays: int4FromPtr(req.CooldownDays),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"item":         updated.Name,
+		"maxPerUser":   int4Ptr(updated.MaxPerUser),
+		"cooldownDays": int4Ptr(updated.CooldownDays),
+	})
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const defaultListingsLimit = 50
+
+// ListListingsRequest - фильтр объявлений. По умолчанию - активные объявления всех продавцов;
+// mine=true - собственные объявления пользователя в любом статусе или в статусе status
+type ListListingsRequest struct {
+	Item   string `form:"item"`
+	Mine   bool   `form:"mine"`
+	Status string `form:"status" binding:"omitempty,oneof=active sold cancelled"`
+	Limit  int32  `form:"limit" binding:"omitempty,gt=0,lte=100"`
+	Offset int32  `form:"offset" binding:"omitempty,gte=0"`
+}
+
+// CreateListingRequest - продажа одной единицы товара из инвентаря
+type CreateListingRequest struct {
+	Item string `json:"item" binding:"required"`
+	// Variant - артикул варианта, обязателен для товаров с вариантами
+	Variant string `json:"variant"`
+	Price   int32  `json:"price" binding:"required,gt=0"`
+}
+
+// ListingResponse - объявление на маркетплейсе; покупатель и комиссия есть только у проданных
+type ListingResponse struct {
+	ID         int32      `json:"id"`
+	Seller     string     `json:"seller"`
+	Item       string     `json:"item"`
+	Variant    string     `json:"variant,omitempty"`
+	Price      int32      `json:"price"`
+	Status     string     `json:"status"`
+	Buyer      string     `json:"buyer,omitempty"`
+	Commission int32      `json:"commission,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	ClosedAt   *time.Time `json:"closedAt,omitempty"`
+}
+
+func NewListingResponse(row db.ListListingsRow) ListingResponse {
+	return ListingResponse{
+		ID:         row.ID,
+		Seller:     row.Seller,
+		Item:       row.ItemName,
+		Variant:    row.Variant.String,
+		Price:      row.Price,
+		Status:     row.Status,
+		Buyer:      row.Buyer.String,
+		Commission: row.Commission,
+		CreatedAt:  row.CreatedAt.Time,
+		ClosedAt:   timestampPtr(row.ClosedAt),
+	}
+}
+
+// parseListingID разбирает id объявления из адреса
+func parseListingID(c *gin.Context) (int32, bool) {
+	listingID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid listing id")))
+		return 0, false
+	}
+	return int32(listingID), true
+}
+
+// GET /api/marketplace/listings
+func (server *Server) handleListListings(c *gin.Context) {
+	var req ListListingsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultListingsLimit
+	}
+
+	arg := db.ListListingsParams{
+		Status:    pgtype.Text{String: db.ListingStatusActive, Valid: true},
+		RowLimit:  req.Limit,
+		RowOffset: req.Offset,
+	}
+
+	if req.Mine {
+		user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		arg.SellerID = pgtype.Int4{Int32: user.ID, Valid: true}
+		arg.Status = pgtype.Text{String: req.Status, Valid: req.Status != ""}
+	}
+
+	if req.Item != "" {
+		item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: req.Item})
+		if err != nil {
+			c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+			return
+		}
+		arg.ItemID = pgtype.Int4{Int32: item.ID, Valid: true}
+	}
+
+	rows, err := server.store.ListListings(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	listings := make([]ListingResponse, 0, len(rows))
+	for _, row := range rows {
+		listings = append(listings, NewListingResponse(row))
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"listings":          listings,
+		"commissionPercent": server.config.CommissionPercent,
+	})
+}
+
+// POST /api/marketplace/listings
+func (server *Server) handleCreateListing(c *gin.Context) {
+	var req CreateListingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	seller, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	item, ok := server.resolveItem(c, req.Item, req.Variant)
+	if !ok {
+		return
+	}
+
+	result, err := server.store.CreateListingTx(c, db.CreateListingTxParams{
+		SellerID:  seller.ID,
+		ItemID:    item.ID,
+		VariantID: item.VariantID.Int32,
+		Price:     req.Price,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrNotEnoughItems) {
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("no delivered items available for sale")))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	listing := result.Listing
+	response := NewListingResponse(db.ListListingsRow{
+		ID:        listing.ID,
+		Seller:    seller.Username,
+		ItemName:  item.Name,
+		Variant:   pgtype.Text{String: req.Variant, Valid: req.Variant != ""},
+		Price:     listing.Price,
+		Status:    listing.Status,
+		CreatedAt: listing.CreatedAt,
+	})
+	c.JSON(http.StatusOK, gin.H{
+		"listing": response,
+		"payout":  listing.Price - db.MarketplaceCommission(listing.Price, server.config.CommissionPercent),
+	})
+}
+
+// DELETE /api/marketplace/listings/:id - снять свое объявление с продажи
+func (server *Server) handleCancelListing(c *gin.Context) {
+	listingID, ok := parseListingID(c)
+	if !ok {
+		return
+	}
+
+	seller, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	listing, err := server.store.GetListing(c, listingID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	// Чужие объявления снять нельзя, и они не отличаются от несуществующих
+	if err != nil || listing.SellerID != seller.ID {
+		c.JSON(http.StatusNotFound, errorResponse(db.ErrListingNotFound))
+		return
+	}
+
+	_, err = server.store.CancelListing(c, db.CancelListingParams{
+		ID:       listingID,
+		SellerID: seller.ID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusConflict, errorResponse(db.ErrListingClosed))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "listing cancelled"})
+}
+
+// POST /api/marketplace/listings/:id/buy
+func (server *Server) handleBuyListing(c *gin.Context) {
+	listingID, ok := parseListingID(c)
+	if !ok {
+		return
+	}
+
+	buyer, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.MarketplaceTx(c, db.MarketplaceTxParams{
+		ListingID:         listingID,
+		BuyerID:           buyer.ID,
+		CommissionPercent: server.config.CommissionPercent,
+		Limits:            server.config.TransferLimits,
+		Now:               time.Now(),
+	})
+	if err != nil {
+		var limitErr *db.TransferLimitError
+		switch {
+		case errors.Is(err, db.ErrListingNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrListingNotFound))
+		case errors.Is(err, db.ErrListingClosed):
+			c.JSON(http.StatusConflict, errorResponse(db.ErrListingClosed))
+		case errors.Is(err, db.ErrOwnListing):
+			c.JSON(http.StatusBadRequest, errorResponse(db.ErrOwnListing))
+		case errors.As(err, &limitErr):
+			transferLimitResponse(c, limitErr)
+		case errors.Is(err, db.ErrInsufficientBalance) || strings.Contains(err.Error(), "CHECK constraint"):
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("insufficient balance")))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "item purchased",
+		"unit":    result.Listing.InventoryItemID,
+		"cost":    result.Listing.Price,
+		"balance": result.Buyer.Balance,
+	})
+}
@@ -0,0 +1,393 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleBuyListing(t *testing.T) {
+	buyer := db.GetUserByUsernameRow{ID: 2, Username: "user2"}
+
+	testCases := []struct {
+		name          string
+		listingID     string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			listingID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), buyer.Username).
+					Return(buyer, nil)
+				store.EXPECT().
+					MarketplaceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.MarketplaceTxParams) (db.MarketplaceTxResult, error) {
+						require.Equal(t, int32(5), arg.ListingID)
+						require.Equal(t, buyer.ID, arg.BuyerID)
+						require.Equal(t, int32(10), arg.CommissionPercent)
+						require.Equal(t, int32(500), arg.Limits.DailyLimit)
+						return db.MarketplaceTxResult{
+							Listing: db.MarketplaceListing{ID: 5, InventoryItemID: 40, Price: 50, Commission: 5},
+							Buyer:   db.User{Balance: pgtype.Int4{Int32: 950, Valid: true}},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"message":"item purchased","unit":40,"cost":50,"balance":950}`, recorder.Body.String())
+			},
+		},
+		{
+			name:      "Conflict_Closed",
+			listingID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), buyer.Username).
+					Return(buyer, nil)
+				store.EXPECT().
+					MarketplaceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MarketplaceTxResult{}, fmt.Errorf("marketplace tx error: %w", db.ErrListingClosed))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:      "BadRequest_OwnListing",
+			listingID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), buyer.Username).
+					Return(buyer, nil)
+				store.EXPECT().
+					MarketplaceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MarketplaceTxResult{}, fmt.Errorf("marketplace tx error: %w", db.ErrOwnListing))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "cannot buy your own listing")
+			},
+		},
+		{
+			name:      "BadRequest_InsufficientBalance",
+			listingID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), buyer.Username).
+					Return(buyer, nil)
+				store.EXPECT().
+					MarketplaceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MarketplaceTxResult{}, fmt.Errorf("marketplace tx error: %w", db.ErrInsufficientBalance))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "insufficient balance")
+			},
+		},
+		{
+			name:      "BadRequest_TransferLimit",
+			listingID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), buyer.Username).
+					Return(buyer, nil)
+				limitErr := &db.TransferLimitError{Limit: db.TransferLimitDaily, Allowed: 500, Remaining: 20}
+				store.EXPECT().
+					MarketplaceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MarketplaceTxResult{}, fmt.Errorf("marketplace tx error: %w", limitErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"limit":"daily"`)
+			},
+		},
+		{
+			name:      "NotFound",
+			listingID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), buyer.Username).
+					Return(buyer, nil)
+				store.EXPECT().
+					MarketplaceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MarketplaceTxResult{}, fmt.Errorf("marketplace tx error: %w", db.ErrListingNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:      "BadRequest_ListingID",
+			listingID: "abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					MarketplaceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{
+				store: store,
+				config: Config{
+					MarketplaceConfig: MarketplaceConfig{CommissionPercent: 10},
+					TransferConfig:    TransferConfig{TransferLimits: db.TransferLimits{DailyLimit: 500}},
+				},
+			}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/marketplace/listings/"+tc.listingID+"/buy", nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.listingID}}
+			ctx.Set("username", buyer.Username)
+
+			server.handleBuyListing(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleCreateListing(t *testing.T) {
+	seller := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+	item := db.GetItemByNameRow{ID: 3, Name: "socks", Price: 10}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"item": item.Name, "price": 15},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), seller.Username).
+					Return(seller, nil)
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				arg := db.CreateListingTxParams{
+					SellerID: seller.ID,
+					ItemID:   item.ID,
+					Price:    15,
+				}
+				store.EXPECT().
+					CreateListingTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.CreateListingTxResult{Listing: db.MarketplaceListing{
+						ID:       7,
+						SellerID: seller.ID,
+						ItemID:   item.ID,
+						Price:    15,
+						Status:   db.ListingStatusActive,
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Listing ListingResponse `json:"listing"`
+					Payout  int32           `json:"payout"`
+				}
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, int32(7), response.Listing.ID)
+				require.Equal(t, seller.Username, response.Listing.Seller)
+				require.Equal(t, db.ListingStatusActive, response.Listing.Status)
+				// Комиссия 10% от 15 округляется вниз до 1
+				require.Equal(t, int32(14), response.Payout)
+			},
+		},
+		{
+			name: "BadRequest_NoItems",
+			body: gin.H{"item": item.Name, "price": 15},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), seller.Username).
+					Return(seller, nil)
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					CreateListingTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateListingTxResult{}, fmt.Errorf("create listing tx error: %w", &db.NotEnoughItemsError{}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "no delivered items available for sale")
+			},
+		},
+		{
+			name: "BadRequest_Price",
+			body: gin.H{"item": item.Name, "price": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateListingTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{
+				store:  store,
+				config: Config{MarketplaceConfig: MarketplaceConfig{CommissionPercent: 10}},
+			}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/marketplace/listings", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", seller.Username)
+
+			server.handleCreateListing(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleCancelListing(t *testing.T) {
+	seller := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), seller.Username).
+					Return(seller, nil)
+				store.EXPECT().
+					GetListing(gomock.Any(), int32(7)).
+					Return(db.MarketplaceListing{ID: 7, SellerID: seller.ID, Status: db.ListingStatusActive}, nil)
+				store.EXPECT().
+					CancelListing(gomock.Any(), db.CancelListingParams{ID: 7, SellerID: seller.ID}).
+					Times(1).
+					Return(db.MarketplaceListing{ID: 7, Status: db.ListingStatusCancelled}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "listing cancelled")
+			},
+		},
+		{
+			name: "Conflict_Sold",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), seller.Username).
+					Return(seller, nil)
+				store.EXPECT().
+					GetListing(gomock.Any(), int32(7)).
+					Return(db.MarketplaceListing{ID: 7, SellerID: seller.ID, Status: db.ListingStatusSold}, nil)
+				store.EXPECT().
+					CancelListing(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.MarketplaceListing{}, pgx.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound_OtherSeller",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), seller.Username).
+					Return(seller, nil)
+				store.EXPECT().
+					GetListing(gomock.Any(), int32(7)).
+					Return(db.MarketplaceListing{ID: 7, SellerID: 2, Status: db.ListingStatusActive}, nil)
+				store.EXPECT().
+					CancelListing(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, "/marketplace/listings/7", nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: "7"}}
+			ctx.Set("username", seller.Username)
+
+			server.handleCancelListing(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,289 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"avito-shop/internal/storage"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// maxImageUploadSize - наибольший размер загружаемого изображения
+const maxImageUploadSize = 5 << 20
+
+// ImageResponse - изображение товара со ссылками на оригинал и миниатюру
+type ImageResponse struct {
+	ID           int32  `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnailUrl"`
+	Width        int32  `json:"width"`
+	Height       int32  `json:"height"`
+}
+
+// ItemDetailsResponse - карточка товара
+type ItemDetailsResponse struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Price       int32             `json:"price"`
+	Stock       *int32            `json:"stock,omitempty"`
+	Category    string            `json:"category,omitempty"`
+	Tags        []string          `json:"tags"`
+	Attributes  map[string]string `json:"attributes"`
+	Images      []ImageResponse   `json:"images"`
+}
+
+// NewImageResponses собирает ссылки на изображения товаров по ключам хранилища
+func NewImageResponses(images []db.ItemImage, blobs storage.BlobStorage) []ImageResponse {
+	responses := make([]ImageResponse, 0, len(images))
+	for _, image := range images {
+		responses = append(responses, ImageResponse{
+			ID:           image.ID,
+			URL:          blobs.URL(image.StorageKey),
+			ThumbnailURL: blobs.URL(image.ThumbnailKey),
+			Width:        image.Width,
+			Height:       image.Height,
+		})
+	}
+	return responses
+}
+
+// NewAttributes превращает характеристики товара в словарь название -> значение
+func NewAttributes(attributes []db.ItemAttribute) map[string]string {
+	result := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		result[attribute.Name] = attribute.Value
+	}
+	return result
+}
+
+// itemImages возвращает изображения товаров, сгруппированные по id товара.
+// Без настроенного хранилища ссылки построить нельзя, поэтому изображений нет
+func (server *Server) itemImages(c *gin.Context, itemIDs []int32) (map[int32][]ImageResponse, error) {
+	result := make(map[int32][]ImageResponse, len(itemIDs))
+	if server.config.Blobs == nil || len(itemIDs) == 0 {
+		return result, nil
+	}
+
+	images, err := server.store.ListImagesForItems(c, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+	byItem := make(map[int32][]db.ItemImage, len(itemIDs))
+	for _, image := range images {
+		byItem[image.ItemID] = append(byItem[image.ItemID], image)
+	}
+	for itemID, itemImages := range byItem {
+		result[itemID] = NewImageResponses(itemImages, server.config.Blobs)
+	}
+	return result, nil
+}
+
+// GET /api/items/:item
+func (server *Server) handleGetItem(c *gin.Context) {
+	found, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	item, err := server.store.GetItemByID(c, found.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := ItemDetailsResponse{
+		Name:        item.Name,
+		Description: item.Description.String,
+		Price:       item.Price,
+	}
+
+	// Остаток показывается за вычетом активных резервов в корзинах
+	if item.Stock.Valid {
+		reserved, err := server.store.GetReservedStock(c, db.GetReservedStockParams{
+			ItemID: item.ID,
+			Now:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		response.Stock = int4Ptr(db.AvailableStock(item.Stock, reserved))
+	}
+
+	if item.CategoryID.Valid {
+		category, err := server.store.GetCategoryByID(c, item.CategoryID.Int32)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		response.Category = category.Name
+	}
+
+	response.Tags, err = server.store.ListItemTags(c, item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	attributes, err := server.store.ListItemAttributes(c, item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	response.Attributes = NewAttributes(attributes)
+
+	images, err := server.itemImages(c, []int32{item.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	response.Images = images[item.ID]
+	if response.Images == nil {
+		response.Images = []ImageResponse{}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /api/admin/items/:item/images
+// Изображение передается в поле image формы multipart/form-data
+func (server *Server) handleUploadItemImage(c *gin.Context) {
+	blobs := server.config.Blobs
+	if blobs == nil {
+		c.JSON(http.StatusServiceUnavailable, errorResponse(fmt.Errorf("image storage is not configured")))
+		return
+	}
+
+	header, err := c.FormFile("image")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if header.Size > maxImageUploadSize {
+		c.JSON(http.StatusRequestEntityTooLarge, errorResponse(fmt.Errorf("image must not exceed %d bytes", maxImageUploadSize)))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxImageUploadSize))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	processed, err := storage.ProcessImage(data)
+	if err != nil {
+		if errors.Is(err, storage.ErrUnsupportedImage) {
+			c.JSON(http.StatusUnsupportedMediaType, errorResponse(err))
+			return
+		}
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	prefix := fmt.Sprintf("items/%d", item.ID)
+	key, err := storage.NewKey(prefix, processed.Ext)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	thumbnailKey, err := storage.NewKey(prefix, "_thumb"+processed.ThumbnailExt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	err = blobs.Put(c, key, bytes.NewReader(data), processed.ContentType)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	err = blobs.Put(c, thumbnailKey, bytes.NewReader(processed.Thumbnail), processed.ThumbnailContentType)
+	if err != nil {
+		blobs.Delete(c, key)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	image, err := server.store.CreateItemImage(c, db.CreateItemImageParams{
+		ItemID:       item.ID,
+		StorageKey:   key,
+		ThumbnailKey: thumbnailKey,
+		ContentType:  processed.ContentType,
+		Width:        int32(processed.Width),
+		Height:       int32(processed.Height),
+		CreatedBy:    pgtype.Int4{Int32: admin.ID, Valid: true},
+	})
+	if err != nil {
+		// Файлы без записи в базе никому не видны, убираем их
+		blobs.Delete(c, key)
+		blobs.Delete(c, thumbnailKey)
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewImageResponses([]db.ItemImage{image}, blobs)[0])
+}
+
+// DELETE /api/admin/items/:item/images/:id
+func (server *Server) handleDeleteItemImage(c *gin.Context) {
+	imageID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid image id")))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	image, err := server.store.DeleteItemImage(c, db.DeleteItemImageParams{ID: int32(imageID), ItemID: item.ID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("image not found")))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// Запись уже удалена: оставшийся файл никто не увидит, поэтому ошибка
+	// удаления из хранилища не отменяет операцию
+	if server.config.Blobs != nil {
+		server.config.Blobs.Delete(c, image.StorageKey)
+		server.config.Blobs.Delete(c, image.ThumbnailKey)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "image deleted",
+	})
+}
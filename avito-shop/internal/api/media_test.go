@@ -0,0 +1,196 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+	"avito-shop/internal/storage"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/stretchr/testify/require"
+)
+
+func multipartImage(t *testing.T, data []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("image", "photo.jpg")
+	require.NoError(t, err)
+	_, err = part.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestHandleUploadItemImage(t *testing.T) {
+	admin := db.GetUserByUsernameRow{ID: 1, Username: "admin"}
+	item := db.GetItemByNameRow{ID: 3, Name: "cup", Price: 20}
+
+	var photo bytes.Buffer
+	require.NoError(t, jpeg.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 800, 600)), nil))
+
+	testCases := []struct {
+		name          string
+		data          []byte
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, root string, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			data: photo.Bytes(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				store.EXPECT().
+					CreateItemImage(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateItemImageParams) (db.ItemImage, error) {
+						require.Equal(t, item.ID, arg.ItemID)
+						require.Equal(t, "image/jpeg", arg.ContentType)
+						require.Equal(t, int32(800), arg.Width)
+						require.Equal(t, int32(600), arg.Height)
+						return db.ItemImage{
+							ID:           5,
+							ItemID:       arg.ItemID,
+							StorageKey:   arg.StorageKey,
+							ThumbnailKey: arg.ThumbnailKey,
+							Width:        arg.Width,
+							Height:       arg.Height,
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, root string, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response ImageResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Equal(t, int32(5), response.ID)
+
+				// Оригинал и миниатюра сохранены в хранилище
+				for _, url := range []string{response.URL, response.ThumbnailURL} {
+					rel, err := filepath.Rel("/media", url)
+					require.NoError(t, err)
+					_, err = os.Stat(filepath.Join(root, rel))
+					require.NoError(t, err)
+				}
+			},
+		},
+		{
+			name: "UnsupportedMediaType",
+			data: []byte("plain text"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				store.EXPECT().
+					CreateItemImage(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, root string, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		{
+			name: "ItemNotFound",
+			data: photo.Bytes(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(db.GetItemByNameRow{}, pgx.ErrNoRows)
+				store.EXPECT().
+					CreateItemImage(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, root string, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			root := t.TempDir()
+			blobs, err := storage.NewLocalStorage(root, "/media")
+			require.NoError(t, err)
+
+			server := &Server{store: store, config: Config{MediaConfig: MediaConfig{Blobs: blobs}}}
+			recorder := httptest.NewRecorder()
+
+			body, contentType := multipartImage(t, tc.data)
+			request, err := http.NewRequest(http.MethodPost, "/admin/items/"+item.Name+"/images", body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", contentType)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "item", Value: item.Name}}
+			ctx.Set("username", admin.Username)
+
+			server.handleUploadItemImage(ctx)
+			tc.checkResponse(t, root, recorder)
+		})
+	}
+}
+
+func TestHandleUploadItemImageWithoutStorage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	server := &Server{store: store}
+	recorder := httptest.NewRecorder()
+
+	body, contentType := multipartImage(t, []byte("data"))
+	request, err := http.NewRequest(http.MethodPost, "/admin/items/cup/images", body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", contentType)
+
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request
+
+	server.handleUploadItemImage(ctx)
+	require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+}
+
+func TestNewImageResponses(t *testing.T) {
+	blobs, err := storage.NewLocalStorage(t.TempDir(), "/media")
+	require.NoError(t, err)
+
+	responses := NewImageResponses([]db.ItemImage{
+		{ID: 1, StorageKey: "items/3/a.jpg", ThumbnailKey: "items/3/a_thumb.jpg", Width: 800, Height: 600},
+	}, blobs)
+	require.Equal(t, []ImageResponse{{
+		ID:           1,
+		URL:          "/media/items/3/a.jpg",
+		ThumbnailURL: "/media/items/3/a_thumb.jpg",
+		Width:        800,
+		Height:       600,
+	}}, responses)
+}
@@ -0,0 +1,216 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ListOrdersRequest - фильтр очереди выдачи; без status возвращаются заказы во всех статусах
+type ListOrdersRequest struct {
+	Status string `form:"status" binding:"omitempty,oneof=placed ready_for_pickup shipped delivered cancelled"`
+	Limit  int32  `form:"limit" binding:"omitempty,gt=0,lte=100"`
+	Offset int32  `form:"offset" binding:"omitempty,gte=0"`
+}
+
+// UpdateOrderStatusRequest - перевод заказа в следующий статус
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=placed ready_for_pickup shipped delivered cancelled"`
+	Note   string `json:"note" binding:"max=500"`
+}
+
+const defaultOrdersLimit = 50
+
+// OrderLine - товар заказа к выдаче
+type OrderLine struct {
+	Item     string `json:"item"`
+	Quantity int32  `json:"quantity"`
+}
+
+// OrderResponse - заказ в очереди выдачи
+type OrderResponse struct {
+	ID              int32       `json:"id"`
+	User            string      `json:"user"`
+	TotalCost       int32       `json:"totalCost"`
+	Status          string      `json:"status"`
+	Bundle          string      `json:"bundle,omitempty"`
+	Items           []OrderLine `json:"items"`
+	CreatedAt       time.Time   `json:"createdAt"`
+	StatusUpdatedAt time.Time   `json:"statusUpdatedAt"`
+}
+
+// OrderStatusChange - запись истории статусов
+type OrderStatusChange struct {
+	Status    string    `json:"status"`
+	Note      string    `json:"note,omitempty"`
+	ChangedBy string    `json:"changedBy,omitempty"`
+	ChangedAt time.Time `json:"changedAt"`
+}
+
+// NewOrderResponses объединяет заказы с их позициями; позиции без единиц к выдаче пропускаются
+func NewOrderResponses(orders []db.ListOrdersRow, lines []db.ListOrderLinesRow) []OrderResponse {
+	byOrder := make(map[int32][]OrderLine)
+	for _, line := range lines {
+		if line.Quantity <= 0 {
+			continue
+		}
+		byOrder[line.OrderID.Int32] = append(byOrder[line.OrderID.Int32], OrderLine{
+			Item:     line.Name,
+			Quantity: line.Quantity,
+		})
+	}
+
+	response := make([]OrderResponse, 0, len(orders))
+	for _, o := range orders {
+		items := byOrder[o.ID]
+		if items == nil {
+			items = []OrderLine{}
+		}
+		response = append(response, OrderResponse{
+			ID:              o.ID,
+			User:            o.Username,
+			TotalCost:       o.TotalCost,
+			Status:          o.Status,
+			Bundle:          o.Bundle.String,
+			Items:           items,
+			CreatedAt:       o.CreatedAt.Time,
+			StatusUpdatedAt: o.StatusUpdatedAt.Time,
+		})
+	}
+	return response
+}
+
+// GET /api/admin/orders
+func (server *Server) handleListOrders(c *gin.Context) {
+	var req ListOrdersRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultOrdersLimit
+	}
+
+	orders, err := server.store.ListOrders(c, db.ListOrdersParams{
+		Status:    pgtype.Text{String: req.Status, Valid: req.Status != ""},
+		RowLimit:  req.Limit,
+		RowOffset: req.Offset,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	orderIDs := make([]int32, 0, len(orders))
+	for _, o := range orders {
+		orderIDs = append(orderIDs, o.ID)
+	}
+
+	var lines []db.ListOrderLinesRow
+	if len(orderIDs) > 0 {
+		lines, err = server.store.ListOrderLines(c, orderIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"orders": NewOrderResponses(orders, lines)})
+}
+
+// GET /api/admin/orders/:id/history
+func (server *Server) handleGetOrderHistory(c *gin.Context) {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid order id")))
+		return
+	}
+
+	order, err := server.store.GetOrderByID(c, int32(orderID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrOrderNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.GetOrderStatusHistory(c, order.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	history := make([]OrderStatusChange, 0, len(rows))
+	for _, h := range rows {
+		history = append(history, OrderStatusChange{
+			Status:    h.Status,
+			Note:      h.Note.String,
+			ChangedBy: h.ChangedBy.String,
+			ChangedAt: h.ChangedAt.Time,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":      order.ID,
+		"status":  order.Status,
+		"history": history,
+	})
+}
+
+// POST /api/admin/orders/:id/status
+func (server *Server) handleUpdateOrderStatus(c *gin.Context) {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid order id")))
+		return
+	}
+
+	var req UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.UpdateOrderStatusTx(c, db.UpdateOrderStatusTxParams{
+		OrderID: int32(orderID),
+		Status:  req.Status,
+		Note:    req.Note,
+		AdminID: admin.ID,
+		CoinTTL: server.config.CoinTTL,
+	})
+	if err != nil {
+		var transitionErr *db.OrderTransitionError
+		switch {
+		case errors.Is(err, db.ErrOrderNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrOrderNotFound))
+		case errors.As(err, &transitionErr):
+			c.JSON(http.StatusConflict, errorResponse(transitionErr))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":         "order status updated",
+		"id":              result.Order.ID,
+		"status":          result.Order.Status,
+		"statusUpdatedAt": result.Order.StatusUpdatedAt.Time,
+		"refunded":        result.Refunded,
+	})
+}
@@ -0,0 +1,210 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleUpdateOrderStatus(t *testing.T) {
+	admin := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "admin",
+		PasswordHash: "password",
+	}
+
+	testCases := []struct {
+		name          string
+		orderID       string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			orderID: "9",
+			body:    gin.H{"status": "ready_for_pickup", "note": "shelf B2"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				arg := db.UpdateOrderStatusTxParams{
+					OrderID: 9,
+					Status:  db.OrderStatusReadyForPickup,
+					Note:    "shelf B2",
+					AdminID: admin.ID,
+				}
+				store.EXPECT().
+					UpdateOrderStatusTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.UpdateOrderStatusTxResult{
+						Order: db.Order{ID: 9, Status: db.OrderStatusReadyForPickup},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Status string `json:"status"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Equal(t, db.OrderStatusReadyForPickup, response.Status)
+			},
+		},
+		{
+			name:    "BadRequest_UnknownStatus",
+			orderID: "9",
+			body:    gin.H{"status": "lost"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateOrderStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest_InvalidID",
+			orderID: "abc",
+			body:    gin.H{"status": "shipped"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateOrderStatusTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NotFound",
+			orderID: "9",
+			body:    gin.H{"status": "shipped"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				store.EXPECT().
+					UpdateOrderStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateOrderStatusTxResult{}, fmt.Errorf("update order status tx error: %w", db.ErrOrderNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "Conflict_InvalidTransition",
+			orderID: "9",
+			body:    gin.H{"status": "cancelled"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				transitionErr := &db.OrderTransitionError{From: db.OrderStatusDelivered, To: db.OrderStatusCancelled}
+				store.EXPECT().
+					UpdateOrderStatusTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UpdateOrderStatusTxResult{}, fmt.Errorf("update order status tx error: %w", transitionErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "cannot change order status from delivered to cancelled")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/orders/"+tc.orderID+"/status", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.orderID}}
+			ctx.Set("username", admin.Username)
+
+			server.handleUpdateOrderStatus(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleListOrders(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	created := pgtype.Timestamp{Time: time.Date(2025, 2, 1, 10, 0, 0, 0, time.UTC), Valid: true}
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListOrders(gomock.Any(), db.ListOrdersParams{
+			Status:   pgtype.Text{String: db.OrderStatusPlaced, Valid: true},
+			RowLimit: defaultOrdersLimit,
+		}).
+		Times(1).
+		Return([]db.ListOrdersRow{
+			{ID: 3, Username: "user1", TotalCost: 80, Status: db.OrderStatusPlaced, CreatedAt: created, StatusUpdatedAt: created},
+		}, nil)
+	store.EXPECT().
+		ListOrderLines(gomock.Any(), []int32{3}).
+		Times(1).
+		Return([]db.ListOrderLinesRow{
+			{OrderID: pgtype.Int4{Int32: 3, Valid: true}, Name: "cup", Quantity: 2},
+			{OrderID: pgtype.Int4{Int32: 3, Valid: true}, Name: "pen", Quantity: 0},
+		}, nil)
+
+	server := &Server{store: store}
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/admin/orders?status=placed", nil)
+	require.NoError(t, err)
+
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request
+
+	server.handleListOrders(ctx)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Orders []OrderResponse `json:"orders"`
+	}
+	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Len(t, response.Orders, 1)
+	require.Equal(t, "user1", response.Orders[0].User)
+	// Полностью возвращенные позиции не выдаются
+	require.Equal(t, []OrderLine{{Item: "cup", Quantity: 2}}, response.Orders[0].Items)
+}
@@ -0,0 +1,172 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ChangePriceRequest - новая цена товара; без effectiveFrom цена меняется сразу
+type ChangePriceRequest struct {
+	Price         int32      `json:"price" binding:"required,gt=0"`
+	EffectiveFrom *time.Time `json:"effectiveFrom"`
+}
+
+// PriceResponse - запись истории цен товара
+type PriceResponse struct {
+	ID            int32     `json:"id"`
+	Price         int32     `json:"price"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+}
+
+// NewPriceHistory делит записи цен на действовавшие к моменту now (от новых к старым)
+// и запланированные (от ближайших к дальним)
+func NewPriceHistory(prices []db.ItemPrice, now time.Time) (history, scheduled []PriceResponse) {
+	history = []PriceResponse{}
+	scheduled = []PriceResponse{}
+	for _, p := range prices {
+		price := PriceResponse{
+			ID:            p.ID,
+			Price:         p.Price,
+			EffectiveFrom: p.EffectiveFrom.Time,
+		}
+		if p.EffectiveFrom.Time.After(now) {
+			// Записи приходят от поздних к ранним
+			scheduled = append([]PriceResponse{price}, scheduled...)
+			continue
+		}
+		history = append(history, price)
+	}
+	return history, scheduled
+}
+
+// priceHistory отвечает историей цен товара; запланированные цены видны только администраторам
+func (server *Server) priceHistory(c *gin.Context, withScheduled bool) {
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	prices, err := server.store.ListItemPrices(c, item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	history, scheduled := NewPriceHistory(prices, time.Now())
+	response := gin.H{
+		"item":    item.Name,
+		"price":   item.Price,
+		"history": history,
+	}
+	if withScheduled {
+		response["scheduled"] = scheduled
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// GET /api/items/:item/prices
+func (server *Server) handleGetPriceHistory(c *gin.Context) {
+	server.priceHistory(c, false)
+}
+
+// GET /api/admin/items/:item/prices
+func (server *Server) handleAdminGetPriceHistory(c *gin.Context) {
+	server.priceHistory(c, true)
+}
+
+// POST /api/admin/items/:item/prices
+func (server *Server) handleChangePrice(c *gin.Context) {
+	var req ChangePriceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.EffectiveFrom != nil && !req.EffectiveFrom.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("effectiveFrom must be in the future")))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	arg := db.ChangeItemPriceTxParams{
+		ItemID:  item.ID,
+		Price:   req.Price,
+		AdminID: admin.ID,
+	}
+	if req.EffectiveFrom != nil {
+		arg.EffectiveFrom = req.EffectiveFrom.UTC()
+	}
+
+	result, err := server.store.ChangeItemPriceTx(c, arg)
+	if err != nil {
+		if strings.Contains(err.Error(), "item_prices_item_id_effective_from_key") {
+			c.JSON(http.StatusConflict, errorResponse(fmt.Errorf("price change already scheduled for this time")))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	message := "price changed"
+	if result.Scheduled {
+		message = "price change scheduled"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":       message,
+		"id":            result.Price.ID,
+		"price":         result.Price.Price,
+		"effectiveFrom": result.Price.EffectiveFrom.Time,
+	})
+}
+
+// DELETE /api/admin/items/:item/prices/:id
+// Отменить можно только еще не наступившее изменение цены
+func (server *Server) handleCancelPriceChange(c *gin.Context) {
+	priceID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid price id")))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	deleted, err := server.store.DeleteScheduledItemPrice(c, db.DeleteScheduledItemPriceParams{
+		ID:     int32(priceID),
+		ItemID: item.ID,
+		Now:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("scheduled price change not found")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "price change cancelled",
+	})
+}
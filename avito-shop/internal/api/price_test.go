@@ -0,0 +1,208 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleChangePrice(t *testing.T) {
+	admin := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "admin",
+		PasswordHash: "password",
+	}
+	item := db.GetItemByNameRow{ID: 3, Name: "cup", Price: 20}
+	future := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK_Immediate",
+			body: gin.H{"price": 25},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				arg := db.ChangeItemPriceTxParams{ItemID: item.ID, Price: 25, AdminID: admin.ID}
+				store.EXPECT().
+					ChangeItemPriceTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.ChangeItemPriceTxResult{
+						Price: db.ItemPrice{ID: 7, ItemID: item.ID, Price: 25},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "price changed")
+			},
+		},
+		{
+			name: "OK_Scheduled",
+			// Момент со смещением клиента планируется в UTC
+			body: gin.H{"price": 15, "effectiveFrom": future.In(time.FixedZone("MSK", 3*60*60))},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				arg := db.ChangeItemPriceTxParams{ItemID: item.ID, Price: 15, AdminID: admin.ID, EffectiveFrom: future}
+				store.EXPECT().
+					ChangeItemPriceTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.ChangeItemPriceTxResult{
+						Price:     db.ItemPrice{ID: 8, ItemID: item.ID, Price: 15, EffectiveFrom: pgtype.Timestamp{Time: future, Valid: true}},
+						Scheduled: true,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "price change scheduled")
+			},
+		},
+		{
+			name: "BadRequest_EffectiveFromInPast",
+			body: gin.H{"price": 15, "effectiveFrom": time.Now().Add(-time.Hour)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ChangeItemPriceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_ZeroPrice",
+			body: gin.H{"price": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ChangeItemPriceTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "Conflict_SameMoment",
+			body: gin.H{"price": 15, "effectiveFrom": future},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				store.EXPECT().
+					ChangeItemPriceTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ChangeItemPriceTxResult{}, fmt.Errorf("change item price tx error: %s",
+						`duplicate key value violates unique constraint "item_prices_item_id_effective_from_key"`))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/items/"+item.Name+"/prices", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "item", Value: item.Name}}
+			ctx.Set("username", admin.Username)
+
+			server.handleChangePrice(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleGetPriceHistory(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	now := time.Now()
+	item := db.GetItemByNameRow{ID: 3, Name: "cup", Price: 25}
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+		Return(item, nil)
+	store.EXPECT().
+		ListItemPrices(gomock.Any(), item.ID).
+		Times(1).
+		Return([]db.ItemPrice{
+			{ID: 3, Price: 15, EffectiveFrom: pgtype.Timestamp{Time: now.Add(48 * time.Hour), Valid: true}},
+			{ID: 2, Price: 25, EffectiveFrom: pgtype.Timestamp{Time: now.Add(-time.Hour), Valid: true}},
+			{ID: 1, Price: 20, EffectiveFrom: pgtype.Timestamp{Time: now.Add(-720 * time.Hour), Valid: true}},
+		}, nil)
+
+	server := &Server{store: store}
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/items/cup/prices", nil)
+	require.NoError(t, err)
+
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request
+	ctx.Params = []gin.Param{{Key: "item", Value: item.Name}}
+
+	server.handleGetPriceHistory(ctx)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response map[string]json.RawMessage
+	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	require.NoError(t, err)
+
+	// Запланированная цена пользователю не показывается
+	require.NotContains(t, response, "scheduled")
+
+	var history []PriceResponse
+	err = json.Unmarshal(response["history"], &history)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, int32(25), history[0].Price)
+	require.Equal(t, int32(20), history[1].Price)
+}
@@ -0,0 +1,320 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PromoCodeRequest - параметры промокода. Item и Category задаются по имени,
+// без них скидка действует на любой товар; пустые ограничения не применяются.
+type PromoCodeRequest struct {
+	Description    string     `json:"description" binding:"max=200"`
+	DiscountType   string     `json:"discountType" binding:"required,oneof=percent fixed"`
+	DiscountValue  int32      `json:"discountValue" binding:"required,gt=0"`
+	Item           string     `json:"item"`
+	Category       string     `json:"category"`
+	ValidFrom      *time.Time `json:"validFrom"`
+	ValidUntil     *time.Time `json:"validUntil"`
+	MaxUses        *int32     `json:"maxUses" binding:"omitempty,gt=0"`
+	MaxUsesPerUser *int32     `json:"maxUsesPerUser" binding:"omitempty,gt=0"`
+	Active         *bool      `json:"active"`
+}
+
+// CreatePromoCodeRequest - новый промокод; код сохраняется в верхнем регистре
+type CreatePromoCodeRequest struct {
+	Code string `json:"code" binding:"required,max=50"`
+	PromoCodeRequest
+}
+
+// PromoCodeResponse - промокод с числом использований
+type PromoCodeResponse struct {
+	ID             int32      `json:"id"`
+	Code           string     `json:"code"`
+	Description    string     `json:"description,omitempty"`
+	DiscountType   string     `json:"discountType"`
+	DiscountValue  int32      `json:"discountValue"`
+	ItemID         *int32     `json:"itemId,omitempty"`
+	CategoryID     *int32     `json:"categoryId,omitempty"`
+	ValidFrom      *time.Time `json:"validFrom,omitempty"`
+	ValidUntil     *time.Time `json:"validUntil,omitempty"`
+	MaxUses        *int32     `json:"maxUses,omitempty"`
+	MaxUsesPerUser *int32     `json:"maxUsesPerUser,omitempty"`
+	Uses           int32      `json:"uses"`
+	Active         bool       `json:"active"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+var errPromoCodeNotFound = fmt.Errorf("promo code not found")
+
+func int4Ptr(value pgtype.Int4) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	v := value.Int32
+	return &v
+}
+
+func timestampPtr(value pgtype.Timestamp) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	v := value.Time
+	return &v
+}
+
+func int4FromPtr(value *int32) pgtype.Int4 {
+	if value == nil {
+		return pgtype.Int4{}
+	}
+	return pgtype.Int4{Int32: *value, Valid: true}
+}
+
+// timestampFromPtr переводит время из запроса в UTC: timestamp без часового пояса
+// сохраняет время как есть, и смещение клиента иначе потеряется
+func timestampFromPtr(value *time.Time) pgtype.Timestamp {
+	if value == nil {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: value.UTC(), Valid: true}
+}
+
+// NewPromoCodeResponse собирает ответ по промокоду
+func NewPromoCodeResponse(promo db.PromoCode) PromoCodeResponse {
+	return PromoCodeResponse{
+		ID:             promo.ID,
+		Code:           promo.Code,
+		Description:    promo.Description.String,
+		DiscountType:   promo.DiscountType,
+		DiscountValue:  promo.DiscountValue,
+		ItemID:         int4Ptr(promo.ItemID),
+		CategoryID:     int4Ptr(promo.CategoryID),
+		ValidFrom:      timestampPtr(promo.ValidFrom),
+		ValidUntil:     timestampPtr(promo.ValidUntil),
+		MaxUses:        int4Ptr(promo.MaxUses),
+		MaxUsesPerUser: int4Ptr(promo.MaxUsesPerUser),
+		Uses:           promo.Uses,
+		Active:         promo.Active,
+		CreatedAt:      promo.CreatedAt.Time,
+	}
+}
+
+// validate проверяет то, что нельзя выразить тегами binding
+func (req PromoCodeRequest) validate() error {
+	if req.DiscountType == db.DiscountTypePercent && req.DiscountValue > 100 {
+		return fmt.Errorf("percent discount cannot exceed 100")
+	}
+	if req.Item != "" && req.Category != "" {
+		return fmt.Errorf("promo code can be limited to an item or a category, not both")
+	}
+	if req.ValidFrom != nil && req.ValidUntil != nil && !req.ValidFrom.Before(*req.ValidUntil) {
+		return fmt.Errorf("validFrom must be before validUntil")
+	}
+	return nil
+}
+
+// resolvePromoScope находит товар или категорию, на которые действует промокод.
+// При ошибке отвечает сам.
+func (server *Server) resolvePromoScope(c *gin.Context, req PromoCodeRequest) (itemID, categoryID pgtype.Int4, ok bool) {
+	if req.Item != "" {
+		item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: req.Item})
+		if err != nil {
+			c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+			return itemID, categoryID, false
+		}
+		itemID = pgtype.Int4{Int32: item.ID, Valid: true}
+	}
+	if req.Category != "" {
+		category, err := server.store.GetCategoryByName(c, req.Category)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("category not found")))
+				return itemID, categoryID, false
+			}
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+			return itemID, categoryID, false
+		}
+		categoryID = pgtype.Int4{Int32: category.ID, Valid: true}
+	}
+	return itemID, categoryID, true
+}
+
+func promoCodeID(c *gin.Context) (int32, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid promo code id")))
+		return 0, false
+	}
+	return int32(id), true
+}
+
+// GET /api/admin/promo-codes
+func (server *Server) handleListPromoCodes(c *gin.Context) {
+	promos, err := server.store.ListPromoCodes(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]PromoCodeResponse, 0, len(promos))
+	for _, promo := range promos {
+		response = append(response, NewPromoCodeResponse(promo))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"promoCodes": response,
+	})
+}
+
+// GET /api/admin/promo-codes/:id
+func (server *Server) handleGetPromoCode(c *gin.Context) {
+	id, ok := promoCodeID(c)
+	if !ok {
+		return
+	}
+
+	promo, err := server.store.GetPromoCodeByID(c, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(errPromoCodeNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPromoCodeResponse(promo))
+}
+
+// POST /api/admin/promo-codes
+func (server *Server) handleCreatePromoCode(c *gin.Context) {
+	var req CreatePromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	code := db.NormalizePromoCode(req.Code)
+	if code == "" {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("promo code cannot be empty")))
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	itemID, categoryID, ok := server.resolvePromoScope(c, req.PromoCodeRequest)
+	if !ok {
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	promo, err := server.store.CreatePromoCode(c, db.CreatePromoCodeParams{
+		Code:           code,
+		Description:    pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		DiscountType:   req.DiscountType,
+		DiscountValue:  req.DiscountValue,
+		ItemID:         itemID,
+		CategoryID:     categoryID,
+		ValidFrom:      timestampFromPtr(req.ValidFrom),
+		ValidUntil:     timestampFromPtr(req.ValidUntil),
+		MaxUses:        int4FromPtr(req.MaxUses),
+		MaxUsesPerUser: int4FromPtr(req.MaxUsesPerUser),
+		Active:         req.Active == nil || *req.Active,
+		CreatedBy:      pgtype.Int4{Int32: admin.ID, Valid: true},
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "promo_codes_code_key") {
+			c.JSON(http.StatusConflict, errorResponse(fmt.Errorf("promo code already exists")))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPromoCodeResponse(promo))
+}
+
+// PUT /api/admin/promo-codes/:id
+// Заменяет все параметры промокода, кроме кода и числа использований
+func (server *Server) handleUpdatePromoCode(c *gin.Context) {
+	id, ok := promoCodeID(c)
+	if !ok {
+		return
+	}
+
+	var req PromoCodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if err := req.validate(); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	itemID, categoryID, ok := server.resolvePromoScope(c, req)
+	if !ok {
+		return
+	}
+
+	promo, err := server.store.UpdatePromoCode(c, db.UpdatePromoCodeParams{
+		ID:             id,
+		Description:    pgtype.Text{String: req.Description, Valid: req.Description != ""},
+		DiscountType:   req.DiscountType,
+		DiscountValue:  req.DiscountValue,
+		ItemID:         itemID,
+		CategoryID:     categoryID,
+		ValidFrom:      timestampFromPtr(req.ValidFrom),
+		ValidUntil:     timestampFromPtr(req.ValidUntil),
+		MaxUses:        int4FromPtr(req.MaxUses),
+		MaxUsesPerUser: int4FromPtr(req.MaxUsesPerUser),
+		Active:         req.Active == nil || *req.Active,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(errPromoCodeNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewPromoCodeResponse(promo))
+}
+
+// DELETE /api/admin/promo-codes/:id
+// Покупки со скидкой сохраняют сумму скидки, ссылка на промокод обнуляется
+func (server *Server) handleDeletePromoCode(c *gin.Context) {
+	id, ok := promoCodeID(c)
+	if !ok {
+		return
+	}
+
+	deleted, err := server.store.DeletePromoCode(c, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if deleted == 0 {
+		c.JSON(http.StatusNotFound, errorResponse(errPromoCodeNotFound))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "promo code deleted",
+	})
+}
@@ -0,0 +1,265 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimestampFromPtr(t *testing.T) {
+	require.Equal(t, pgtype.Timestamp{}, timestampFromPtr(nil))
+
+	// Время со смещением сохраняется в UTC, а не как локальное время клиента
+	moscow := time.Date(2026, 5, 1, 15, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	ts := timestampFromPtr(&moscow)
+	require.True(t, ts.Valid)
+	require.Equal(t, time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), ts.Time)
+}
+
+func TestHandleCreatePromoCode(t *testing.T) {
+	admin := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "admin",
+		PasswordHash: "password",
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK_Category",
+			body: gin.H{
+				"code":           " spring10 ",
+				"discountType":   "percent",
+				"discountValue":  10,
+				"category":       "clothing",
+				"maxUsesPerUser": 1,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCategoryByName(gomock.Any(), "clothing").
+					Return(db.Category{ID: 3, Name: "clothing"}, nil)
+
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				arg := db.CreatePromoCodeParams{
+					Code:           "SPRING10",
+					DiscountType:   db.DiscountTypePercent,
+					DiscountValue:  10,
+					CategoryID:     pgtype.Int4{Int32: 3, Valid: true},
+					MaxUsesPerUser: pgtype.Int4{Int32: 1, Valid: true},
+					Active:         true,
+					CreatedBy:      pgtype.Int4{Int32: admin.ID, Valid: true},
+				}
+				store.EXPECT().
+					CreatePromoCode(gomock.Any(), arg).
+					Times(1).
+					Return(db.PromoCode{
+						ID:             5,
+						Code:           arg.Code,
+						DiscountType:   arg.DiscountType,
+						DiscountValue:  arg.DiscountValue,
+						CategoryID:     arg.CategoryID,
+						MaxUsesPerUser: arg.MaxUsesPerUser,
+						Active:         true,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response PromoCodeResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Equal(t, "SPRING10", response.Code)
+				require.NotNil(t, response.CategoryID)
+				require.Equal(t, int32(3), *response.CategoryID)
+				require.Nil(t, response.MaxUses)
+			},
+		},
+		{
+			name: "BadRequest_PercentOver100",
+			body: gin.H{"code": "HALF", "discountType": "percent", "discountValue": 150},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePromoCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_ItemAndCategory",
+			body: gin.H{
+				"code":          "BOTH",
+				"discountType":  "fixed",
+				"discountValue": 50,
+				"item":          "cup",
+				"category":      "accessories",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePromoCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_InvalidWindow",
+			body: gin.H{
+				"code":          "LATE",
+				"discountType":  "fixed",
+				"discountValue": 50,
+				"validFrom":     "2026-03-01T00:00:00Z",
+				"validUntil":    "2026-02-01T00:00:00Z",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreatePromoCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound_Category",
+			body: gin.H{"code": "MISC", "discountType": "fixed", "discountValue": 50, "category": "food"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCategoryByName(gomock.Any(), "food").
+					Return(db.Category{}, pgx.ErrNoRows)
+
+				store.EXPECT().
+					CreatePromoCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "category not found")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			body, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/promo-codes", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", admin.Username)
+
+			server.handleCreatePromoCode(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleDeletePromoCode(t *testing.T) {
+	testCases := []struct {
+		name          string
+		promoID       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			promoID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeletePromoCode(gomock.Any(), int32(5)).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "promo code deleted")
+			},
+		},
+		{
+			name:    "NotFound",
+			promoID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeletePromoCode(gomock.Any(), int32(5)).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "BadRequest_InvalidID",
+			promoID: "abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					DeletePromoCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, "/admin/promo-codes/"+tc.promoID, nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.promoID}}
+
+			server.handleDeletePromoCode(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	db "avito-shop/internal/db/sqlc"
 	mockdb "avito-shop/internal/mock"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,7 +25,7 @@ func TestHandleBuyItem(t *testing.T) {
 		PasswordHash: "password",
 	}
 
-	item := db.Item{
+	item := db.GetItemByNameRow{
 		ID:    1,
 		Name:  "t-shirt",
 		Price: 100,
@@ -31,6 +34,7 @@ func TestHandleBuyItem(t *testing.T) {
 	testCases := []struct {
 		name          string
 		itemName      string
+		query         string
 		setupAuth     func(t *testing.T, request *http.Request, username string)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
@@ -49,7 +53,7 @@ func TestHandleBuyItem(t *testing.T) {
 
 				// Мок для получения товара
 				store.EXPECT().
-					GetItemByName(gomock.Any(), item.Name).
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
 					Return(item, nil)
 
 				arg := db.PurchaseTxParams{
@@ -67,6 +71,198 @@ func TestHandleBuyItem(t *testing.T) {
 				requireBodyMatchMessage(t, recorder.Body.Bytes(), "purchase successful")
 			},
 		},
+		{
+			name:     "OK_Gift",
+			itemName: item.Name,
+			query:    "recipient=colleague&message=Happy+birthday",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), "colleague").
+					Return(db.GetUserByUsernameRow{ID: 2, Username: "colleague"}, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				// Платит покупатель, товар уходит получателю
+				arg := db.PurchaseTxParams{
+					UserID:      user.ID,
+					ItemID:      item.ID,
+					RecipientID: 2,
+					GiftMessage: "Happy birthday",
+				}
+
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.PurchaseTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "purchase successful")
+			},
+		},
+		{
+			name:     "OK_Variant",
+			itemName: "hoody",
+			query:    "variant=HOODY-PINK-L",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{
+						Name: "hoody",
+						Sku:  pgtype.Text{String: "HOODY-PINK-L", Valid: true},
+					}).
+					Return(db.GetItemByNameRow{
+						ID:           6,
+						Name:         "hoody",
+						Price:        300,
+						VariantID:    pgtype.Int4{Int32: 11, Valid: true},
+						Sku:          pgtype.Text{String: "HOODY-PINK-L", Valid: true},
+						VariantPrice: pgtype.Int4{Int32: 500, Valid: true},
+						HasVariants:  true,
+					}, nil)
+
+				arg := db.PurchaseTxParams{
+					UserID:    user.ID,
+					ItemID:    6,
+					VariantID: 11,
+				}
+
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.PurchaseTxResult{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:     "BadRequest_VariantRequired",
+			itemName: "hoody",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: "hoody"}).
+					Return(db.GetItemByNameRow{ID: 6, Name: "hoody", Price: 300, HasVariants: true}, nil)
+
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), db.ErrVariantRequired.Error())
+			},
+		},
+		{
+			name:     "NotFound_UnknownVariant",
+			itemName: "hoody",
+			query:    "variant=HOODY-GOLD-XL",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				// Товар найден, но вариант с таким артикулом не присоединился
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(db.GetItemByNameRow{ID: 6, Name: "hoody", Price: 300, HasVariants: true}, nil)
+
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), db.ErrVariantNotFound.Error())
+			},
+		},
+		{
+			name:     "NotFound_RecipientNotFound",
+			itemName: item.Name,
+			query:    "recipient=nobody",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), "nobody").
+					Return(db.GetUserByUsernameRow{}, pgx.ErrNoRows)
+
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "recipient not found")
+			},
+		},
+		{
+			name:     "BadRequest_GiftToSelf",
+			itemName: item.Name,
+			query:    "recipient=" + user.Username,
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Times(2).
+					Return(user, nil)
+
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "BadRequest_MessageWithoutRecipient",
+			itemName: item.Name,
+			query:    "message=hello",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 		{
 			name:     "NotFound_ItemNotFound",
 			itemName: "nonexistent",
@@ -81,8 +277,8 @@ func TestHandleBuyItem(t *testing.T) {
 
 				// Мок для получения несуществующего товара
 				store.EXPECT().
-					GetItemByName(gomock.Any(), "nonexistent").
-					Return(db.Item{}, errors.New("item not found"))
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: "nonexistent"}).
+					Return(db.GetItemByNameRow{}, errors.New("item not found"))
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusNotFound, recorder.Code)
@@ -119,7 +315,7 @@ func TestHandleBuyItem(t *testing.T) {
 
 				// Мок для получения товара
 				store.EXPECT().
-					GetItemByName(gomock.Any(), item.Name).
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
 					Return(item, nil)
 
 				arg := db.PurchaseTxParams{
@@ -137,6 +333,245 @@ func TestHandleBuyItem(t *testing.T) {
 				requireBodyMatchError(t, recorder.Body.Bytes(), "insufficient balance")
 			},
 		},
+		{
+			name:     "Conflict_OutOfStock",
+			itemName: item.Name,
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				// Остаток товара закончился
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Return(db.PurchaseTxResult{}, fmt.Errorf("purchase tx error: %w", db.ErrItemOutOfStock))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), db.ErrItemOutOfStock.Error())
+			},
+		},
+		{
+			name:     "OK_PromoCode",
+			itemName: item.Name,
+			query:    "promo=SPRING10",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				arg := db.PurchaseTxParams{
+					UserID:    user.ID,
+					ItemID:    item.ID,
+					PromoCode: "SPRING10",
+				}
+
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.PurchaseTxResult{
+						Purchase: db.Purchase{TotalCost: 90, Discount: 10},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"message":"purchase successful","discount":10,"price":90}`, recorder.Body.String())
+			},
+		},
+		{
+			name:     "NotFound_PromoCode",
+			itemName: item.Name,
+			query:    "promo=UNKNOWN",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PurchaseTxResult{}, fmt.Errorf("purchase tx error: %w", db.ErrPromoCodeNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "promo code not found")
+			},
+		},
+		{
+			name:     "BadRequest_PromoCodeRejected",
+			itemName: item.Name,
+			query:    "promo=SPRING10",
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				promoErr := &db.PromoCodeError{Code: "SPRING10", Reason: db.PromoReasonExpired}
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PurchaseTxResult{}, fmt.Errorf("purchase tx error: %w", promoErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "promo code SPRING10 has expired")
+			},
+		},
+		{
+			name:     "BadRequest_MaxPerUser",
+			itemName: item.Name,
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				limitErr := &db.PurchaseLimitError{Item: item.Name, Limit: db.PurchaseLimitMaxPerUser, Allowed: 1}
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PurchaseTxResult{}, fmt.Errorf("purchase tx error: %w", limitErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.JSONEq(t,
+					`{"error":"t-shirt is limited to 1 per user, 0 remaining","limit":"max_per_user","remaining":0}`,
+					recorder.Body.String())
+			},
+		},
+		{
+			name:     "TooManyRequests_Cooldown",
+			itemName: item.Name,
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				limitErr := &db.PurchaseLimitError{
+					Item:        item.Name,
+					Limit:       db.PurchaseLimitCooldown,
+					Allowed:     1,
+					AvailableAt: time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC),
+				}
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PurchaseTxResult{}, fmt.Errorf("purchase tx error: %w", limitErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.JSONEq(t,
+					`{"error":"t-shirt can be bought again after 2026-03-31T12:00:00Z","limit":"cooldown","remaining":0,"availableAt":"2026-03-31T12:00:00Z"}`,
+					recorder.Body.String())
+			},
+		},
+		{
+			name:     "Forbidden_DropNotStarted",
+			itemName: item.Name,
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				unavailableErr := &db.ItemUnavailableError{
+					Item:          item.Name,
+					Reason:        db.ItemUnavailableNotStarted,
+					AvailableFrom: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC),
+				}
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PurchaseTxResult{}, fmt.Errorf("purchase tx error: %w", unavailableErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+				require.JSONEq(t,
+					`{"error":"t-shirt goes on sale at 2026-05-01T12:00:00Z","reason":"not_started","availableFrom":"2026-05-01T12:00:00Z"}`,
+					recorder.Body.String())
+			},
+		},
+		{
+			name:     "TooManyRequests_DropQueue",
+			itemName: item.Name,
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				queueErr := &db.DropQueueError{
+					Item:        item.Name,
+					Joined:      true,
+					Position:    120,
+					Admitted:    100,
+					QueueEndsAt: time.Date(2026, 5, 1, 12, 10, 0, 0, time.UTC),
+				}
+				store.EXPECT().
+					PurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.PurchaseTxResult{}, fmt.Errorf("purchase tx error: %w", queueErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+				require.JSONEq(t,
+					`{"error":"t-shirt: queue position 120, 100 admitted so far","joined":true,"position":120,"admitted":100,"queueEndsAt":"2026-05-01T12:10:00Z"}`,
+					recorder.Body.String())
+			},
+		},
 		{
 			name:     "InternalError_PurchaseError",
 			itemName: item.Name,
@@ -151,7 +586,7 @@ func TestHandleBuyItem(t *testing.T) {
 
 				// Мок для получения товара
 				store.EXPECT().
-					GetItemByName(gomock.Any(), item.Name).
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
 					Return(item, nil)
 
 				arg := db.PurchaseTxParams{
@@ -186,12 +621,12 @@ func TestHandleBuyItem(t *testing.T) {
 				AccessTokenDuration: 24,
 			}
 
-			server, err := NewServer(store, tokenConfig)
+			server, err := NewServer(store, Config{TokenConfig: tokenConfig})
 			require.NoError(t, err)
 
 			recorder := httptest.NewRecorder()
 
-			url := fmt.Sprintf("/buy/%s", tc.itemName)
+			url := fmt.Sprintf("/buy/%s?%s", tc.itemName, tc.query)
 			request, err := http.NewRequest(http.MethodGet, url, nil)
 			require.NoError(t, err)
 
@@ -0,0 +1,111 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QuotePurchaseRequest - покупка, которую нужно рассчитать без выполнения
+type QuotePurchaseRequest struct {
+	Item string `json:"item" binding:"required"`
+	// Variant - артикул варианта, обязателен для товаров с вариантами
+	Variant   string `json:"variant"`
+	Recipient string `json:"recipient"`
+	Promo     string `json:"promo" binding:"max=50"`
+}
+
+// NewQuoteViolations описывает нарушения так же, как ответы на ошибки самих операций
+func NewQuoteViolations(violations []error) []gin.H {
+	response := make([]gin.H, 0, len(violations))
+	for _, violation := range violations {
+		var purchaseLimitErr *db.PurchaseLimitError
+		var transferLimitErr *db.TransferLimitError
+		var unavailableErr *db.ItemUnavailableError
+		var queueErr *db.DropQueueError
+		switch {
+		case errors.As(violation, &purchaseLimitErr):
+			response = append(response, purchaseLimitBody(purchaseLimitErr))
+		case errors.As(violation, &transferLimitErr):
+			response = append(response, transferLimitBody(transferLimitErr))
+		case errors.As(violation, &unavailableErr):
+			response = append(response, itemUnavailableBody(unavailableErr))
+		case errors.As(violation, &queueErr):
+			response = append(response, dropQueueBody(queueErr))
+		default:
+			response = append(response, gin.H{"error": violation.Error()})
+		}
+	}
+	return response
+}
+
+// quoteResponse - расчет операции: ok - операция пройдет, coinsAfter - баланс после нее
+func quoteResponse(quote db.QuoteTxResult) gin.H {
+	return gin.H{
+		"ok":         len(quote.Violations) == 0,
+		"cost":       quote.Cost,
+		"coins":      quote.Balance,
+		"coinsAfter": quote.BalanceAfter,
+		"violations": NewQuoteViolations(quote.Violations),
+	}
+}
+
+// POST /api/buy/quote - проверить покупку, ничего не покупая
+func (server *Server) handleQuotePurchase(c *gin.Context) {
+	var req QuotePurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	recipientID, ok := server.resolveRecipient(c, user.ID, req.Recipient)
+	if !ok {
+		return
+	}
+
+	item, ok := server.resolveItem(c, req.Item, req.Variant)
+	if !ok {
+		return
+	}
+
+	quote, err := server.store.QuotePurchaseTx(c, db.PurchaseTxParams{
+		UserID:      user.ID,
+		ItemID:      item.ID,
+		RecipientID: recipientID,
+		VariantID:   item.VariantID.Int32,
+		PromoCode:   req.Promo,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := quoteResponse(quote)
+	response["price"] = quote.Price
+	response["discount"] = quote.Discount
+	c.JSON(http.StatusOK, response)
+}
+
+// POST /api/sendCoin/quote - проверить перевод, ничего не переводя
+func (server *Server) handleQuoteTransfer(c *gin.Context) {
+	arg, ok := server.transferParams(c)
+	if !ok {
+		return
+	}
+
+	quote, err := server.store.QuoteTransferTx(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, quoteResponse(quote))
+}
@@ -0,0 +1,319 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+type quoteBody struct {
+	OK         bool                     `json:"ok"`
+	Price      int32                    `json:"price"`
+	Discount   int32                    `json:"discount"`
+	Cost       int32                    `json:"cost"`
+	Coins      int32                    `json:"coins"`
+	CoinsAfter int32                    `json:"coinsAfter"`
+	Violations []map[string]interface{} `json:"violations"`
+}
+
+func decodeQuote(t *testing.T, recorder *httptest.ResponseRecorder) quoteBody {
+	var response quoteBody
+	err := json.Unmarshal(recorder.Body.Bytes(), &response)
+	require.NoError(t, err)
+	return response
+}
+
+func TestNewQuoteViolations(t *testing.T) {
+	violations := NewQuoteViolations([]error{
+		&db.PurchaseLimitError{Item: "cup", Limit: db.PurchaseLimitMaxPerUser, Allowed: 1},
+		&db.TransferLimitError{Limit: db.TransferLimitDaily, Allowed: 500, Remaining: 20},
+		db.ErrInsufficientBalance,
+	})
+
+	require.Len(t, violations, 3)
+	require.Equal(t, db.PurchaseLimitMaxPerUser, violations[0]["limit"])
+	require.Equal(t, int32(20), violations[1]["remaining"])
+	require.Equal(t, gin.H{"error": "insufficient balance"}, violations[2])
+
+	require.Empty(t, NewQuoteViolations(nil))
+}
+
+func TestHandleQuotePurchase(t *testing.T) {
+	user := db.GetUserByUsernameRow{ID: 1, Username: "buyer"}
+	recipient := db.GetUserByUsernameRow{ID: 2, Username: "friend"}
+	item := db.GetItemByNameRow{ID: 5, Name: "cup", Price: 20}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"item": item.Name, "recipient": recipient.Username, "promo": "SALE"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), recipient.Username).
+					Return(recipient, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				arg := db.PurchaseTxParams{
+					UserID:      user.ID,
+					ItemID:      item.ID,
+					RecipientID: recipient.ID,
+					PromoCode:   "SALE",
+				}
+				store.EXPECT().
+					QuotePurchaseTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.QuoteTxResult{Price: 20, Discount: 5, Cost: 15, Balance: 100, BalanceAfter: 85}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := decodeQuote(t, recorder)
+				require.True(t, response.OK)
+				require.Equal(t, int32(20), response.Price)
+				require.Equal(t, int32(5), response.Discount)
+				require.Equal(t, int32(15), response.Cost)
+				require.Equal(t, int32(100), response.Coins)
+				require.Equal(t, int32(85), response.CoinsAfter)
+				require.Empty(t, response.Violations)
+			},
+		},
+		{
+			name: "OK_Violations",
+			body: gin.H{"item": item.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				store.EXPECT().
+					QuotePurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.QuoteTxResult{
+						Price:        20,
+						Cost:         20,
+						Balance:      10,
+						BalanceAfter: -10,
+						Violations:   []error{db.ErrItemOutOfStock, db.ErrInsufficientBalance},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := decodeQuote(t, recorder)
+				require.False(t, response.OK)
+				require.Equal(t, int32(-10), response.CoinsAfter)
+				require.Len(t, response.Violations, 2)
+				require.Equal(t, "item is out of stock", response.Violations[0]["error"])
+				require.Equal(t, "insufficient balance", response.Violations[1]["error"])
+			},
+		},
+		{
+			name: "BadRequest_MissingItem",
+			body: gin.H{"promo": "SALE"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					QuotePurchaseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_GiftToSelf",
+			body: gin.H{"item": item.Name, "recipient": user.Username},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Times(2).
+					Return(user, nil)
+
+				store.EXPECT().
+					QuotePurchaseTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "cannot send a gift to yourself")
+			},
+		},
+		{
+			name: "InternalError",
+			body: gin.H{"item": item.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+
+				store.EXPECT().
+					QuotePurchaseTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.QuoteTxResult{}, errors.New("connection lost"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/buy/quote", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", user.Username)
+
+			server.handleQuotePurchase(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleQuoteTransfer(t *testing.T) {
+	sender := db.GetUserByUsernameRow{ID: 1, Username: "sender"}
+	receiver := db.GetUserByUsernameRow{ID: 2, Username: "receiver"}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK_Violations",
+			body: gin.H{"toUser": receiver.Username, "amount": 300},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), receiver.Username).
+					Return(receiver, nil)
+
+				arg := db.TransferTxParams{
+					FromUserID: sender.ID,
+					ToUserID:   receiver.ID,
+					Amount:     300,
+				}
+				store.EXPECT().
+					QuoteTransferTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.QuoteTxResult{
+						Cost:         300,
+						Balance:      1000,
+						BalanceAfter: 700,
+						Violations: []error{
+							&db.TransferLimitError{Limit: db.TransferLimitDaily, Allowed: 500, Remaining: 200},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				response := decodeQuote(t, recorder)
+				require.False(t, response.OK)
+				require.Equal(t, int32(300), response.Cost)
+				require.Equal(t, int32(1000), response.Coins)
+				require.Equal(t, int32(700), response.CoinsAfter)
+				require.Len(t, response.Violations, 1)
+				require.Equal(t, db.TransferLimitDaily, response.Violations[0]["limit"])
+				require.Equal(t, float64(200), response.Violations[0]["remaining"])
+			},
+		},
+		{
+			name: "BadRequest_SendToSelf",
+			body: gin.H{"toUser": sender.Username, "amount": 10},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Times(2).
+					Return(sender, nil)
+
+				store.EXPECT().
+					QuoteTransferTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "cannot send coins to yourself")
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/sendCoin/quote", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", sender.Username)
+
+			server.handleQuoteTransfer(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
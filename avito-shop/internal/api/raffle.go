@@ -0,0 +1,336 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const raffleListLimit = 100
+
+// CreateRaffleRequest - новый розыгрыш; maxTicketsPerUser не задан - без ограничения
+type CreateRaffleRequest struct {
+	Item              string    `json:"item" binding:"required"`
+	TicketPrice       int32     `json:"ticketPrice" binding:"required,gt=0"`
+	MaxTicketsPerUser int32     `json:"maxTicketsPerUser" binding:"omitempty,gt=0"`
+	Winners           int32     `json:"winners" binding:"omitempty,gt=0"`
+	SalesEndAt        time.Time `json:"salesEndAt" binding:"required"`
+}
+
+// BuyRaffleTicketsRequest - покупка билетов розыгрыша, не больше 100 за запрос
+type BuyRaffleTicketsRequest struct {
+	Quantity int32 `json:"quantity" binding:"required,gt=0,lte=100"`
+}
+
+// RaffleResponse - розыгрыш; зерно показывается только после выбора победителей,
+// до этого опубликован лишь его хеш
+type RaffleResponse struct {
+	ID                int32      `json:"id"`
+	Item              string     `json:"item"`
+	TicketPrice       int32      `json:"ticketPrice"`
+	MaxTicketsPerUser *int32     `json:"maxTicketsPerUser,omitempty"`
+	Winners           int32      `json:"winners"`
+	SalesEndAt        time.Time  `json:"salesEndAt"`
+	Status            string     `json:"status"`
+	SeedHash          string     `json:"seedHash"`
+	Seed              string     `json:"seed,omitempty"`
+	DrawnAt           *time.Time `json:"drawnAt,omitempty"`
+	Tickets           int32      `json:"tickets"`
+	MyTickets         int32      `json:"myTickets"`
+}
+
+func NewRaffleResponse(row db.ListRafflesRow) RaffleResponse {
+	response := RaffleResponse{
+		ID:                row.ID,
+		Item:              row.ItemName,
+		TicketPrice:       row.TicketPrice,
+		MaxTicketsPerUser: int4Ptr(row.MaxTicketsPerUser),
+		Winners:           row.Winners,
+		SalesEndAt:        row.SalesEndAt.Time,
+		Status:            row.Status,
+		SeedHash:          row.SeedHash,
+		DrawnAt:           timestampPtr(row.DrawnAt),
+		Tickets:           row.Tickets,
+		MyTickets:         row.UserTickets,
+	}
+	if row.Status == db.RaffleStatusDrawn {
+		response.Seed = row.Seed
+	}
+	return response
+}
+
+// RaffleTicketResponse - билет в порядке номеров; position - позиция билета
+// в списке, по которой выбирается победитель
+type RaffleTicketResponse struct {
+	Position int    `json:"position"`
+	ID       int32  `json:"id"`
+	User     string `json:"user"`
+	Winner   bool   `json:"winner"`
+}
+
+// parseRaffleID разбирает id розыгрыша из адреса
+func parseRaffleID(c *gin.Context) (int32, bool) {
+	raffleID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid raffle id")))
+		return 0, false
+	}
+	return int32(raffleID), true
+}
+
+// GET /api/raffles - розыгрыши, по которым идет продажа билетов
+func (server *Server) handleListRaffles(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.ListRaffles(c, db.ListRafflesParams{
+		UserID:   user.ID,
+		Status:   pgtype.Text{String: db.RaffleStatusOpen, Valid: true},
+		RowLimit: raffleListLimit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	raffles := make([]RaffleResponse, 0, len(rows))
+	for _, row := range rows {
+		raffles = append(raffles, NewRaffleResponse(row))
+	}
+	c.JSON(http.StatusOK, gin.H{"raffles": raffles})
+}
+
+// GET /api/raffles/:id
+func (server *Server) handleGetRaffle(c *gin.Context) {
+	raffleID, ok := parseRaffleID(c)
+	if !ok {
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	summary, err := server.store.GetRaffleSummary(c, db.GetRaffleSummaryParams{
+		UserID: user.ID,
+		ID:     raffleID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrRaffleNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewRaffleResponse(db.ListRafflesRow(summary)))
+}
+
+// GET /api/raffles/:id/tickets
+// Билеты в порядке номеров вместе с зерном и его хешем: по ним любой участник
+// может проверить, что зерно совпадает с опубликованным хешем, и повторить выбор победителей
+func (server *Server) handleListRaffleTickets(c *gin.Context) {
+	raffleID, ok := parseRaffleID(c)
+	if !ok {
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	summary, err := server.store.GetRaffleSummary(c, db.GetRaffleSummaryParams{
+		UserID: user.ID,
+		ID:     raffleID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrRaffleNotFound))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.ListRaffleTickets(c, raffleID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	tickets := make([]RaffleTicketResponse, 0, len(rows))
+	for i, row := range rows {
+		tickets = append(tickets, RaffleTicketResponse{
+			Position: i,
+			ID:       row.ID,
+			User:     row.Username,
+			Winner:   row.PurchaseID.Valid,
+		})
+	}
+
+	raffle := NewRaffleResponse(db.ListRafflesRow(summary))
+	c.JSON(http.StatusOK, gin.H{
+		"id":       raffle.ID,
+		"status":   raffle.Status,
+		"winners":  raffle.Winners,
+		"seedHash": raffle.SeedHash,
+		"seed":     raffle.Seed,
+		"tickets":  tickets,
+	})
+}
+
+// POST /api/raffles/:id/tickets
+func (server *Server) handleBuyRaffleTickets(c *gin.Context) {
+	raffleID, ok := parseRaffleID(c)
+	if !ok {
+		return
+	}
+
+	var req BuyRaffleTicketsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.BuyRaffleTicketsTx(c, db.BuyRaffleTicketsTxParams{
+		RaffleID: raffleID,
+		UserID:   user.ID,
+		Quantity: req.Quantity,
+		Now:      time.Now(),
+	})
+	if err != nil {
+		var limitErr *db.RaffleTicketLimitError
+		switch {
+		case errors.Is(err, db.ErrRaffleNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrRaffleNotFound))
+		case errors.Is(err, db.ErrRaffleClosed):
+			c.JSON(http.StatusConflict, errorResponse(db.ErrRaffleClosed))
+		case errors.As(err, &limitErr):
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":     limitErr.Error(),
+				"remaining": limitErr.Remaining,
+			})
+		case errors.Is(err, db.ErrInsufficientBalance) || strings.Contains(err.Error(), "CHECK constraint"):
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("insufficient balance")))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	ticketIDs := make([]int32, 0, len(result.Tickets))
+	for _, ticket := range result.Tickets {
+		ticketIDs = append(ticketIDs, ticket.ID)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": "tickets purchased",
+		"tickets": ticketIDs,
+		"cost":    result.Payment.Amount,
+		"balance": result.User.Balance,
+	})
+}
+
+// POST /api/admin/raffles
+func (server *Server) handleCreateRaffle(c *gin.Context) {
+	var req CreateRaffleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if !req.SalesEndAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("salesEndAt must be in the future")))
+		return
+	}
+	if req.Winners == 0 {
+		req.Winners = 1
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: req.Item})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+	// Победитель получает сам товар: вариант в розыгрыше не выбирается
+	if item.HasVariants {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("items with variants cannot be raffled")))
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	result, err := server.store.CreateRaffleTx(c, db.CreateRaffleTxParams{
+		ItemID:            item.ID,
+		TicketPrice:       req.TicketPrice,
+		MaxTicketsPerUser: req.MaxTicketsPerUser,
+		Winners:           req.Winners,
+		SalesEndAt:        req.SalesEndAt.UTC(),
+		CreatedBy:         admin.ID,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrItemOutOfStock) {
+			c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("not enough stock for %d prizes", req.Winners)))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	raffle := result.Raffle
+	c.JSON(http.StatusOK, NewRaffleResponse(db.ListRafflesRow{
+		ID:                raffle.ID,
+		ItemID:            raffle.ItemID,
+		ItemName:          item.Name,
+		TicketPrice:       raffle.TicketPrice,
+		MaxTicketsPerUser: raffle.MaxTicketsPerUser,
+		Winners:           raffle.Winners,
+		SalesEndAt:        raffle.SalesEndAt,
+		SeedHash:          raffle.SeedHash,
+		Seed:              raffle.Seed,
+		Status:            raffle.Status,
+	}))
+}
+
+// GET /api/admin/raffles?status=
+func (server *Server) handleAdminListRaffles(c *gin.Context) {
+	status := c.Query("status")
+	rows, err := server.store.ListRaffles(c, db.ListRafflesParams{
+		Status:   pgtype.Text{String: status, Valid: status != ""},
+		RowLimit: raffleListLimit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	raffles := make([]RaffleResponse, 0, len(rows))
+	for _, row := range rows {
+		raffles = append(raffles, NewRaffleResponse(row))
+	}
+	c.JSON(http.StatusOK, gin.H{"raffles": raffles})
+}
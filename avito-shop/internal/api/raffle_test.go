@@ -0,0 +1,427 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRaffleResponse(t *testing.T) {
+	row := db.ListRafflesRow{
+		ID:                2,
+		ItemName:          "conf-ticket",
+		TicketPrice:       10,
+		MaxTicketsPerUser: pgtype.Int4{Int32: 5, Valid: true},
+		Winners:           2,
+		SeedHash:          "hash",
+		Seed:              "seed",
+		Status:            db.RaffleStatusOpen,
+		Tickets:           12,
+		UserTickets:       3,
+	}
+
+	response := NewRaffleResponse(row)
+	require.Equal(t, "hash", response.SeedHash)
+	require.Empty(t, response.Seed)
+	require.Equal(t, int32(5), *response.MaxTicketsPerUser)
+	require.Equal(t, int32(3), response.MyTickets)
+	require.Nil(t, response.DrawnAt)
+
+	row.Status = db.RaffleStatusDrawn
+	row.DrawnAt = pgtype.Timestamp{Time: time.Now(), Valid: true}
+	row.MaxTicketsPerUser = pgtype.Int4{}
+	response = NewRaffleResponse(row)
+	require.Equal(t, "seed", response.Seed)
+	require.NotNil(t, response.DrawnAt)
+	require.Nil(t, response.MaxTicketsPerUser)
+}
+
+func TestHandleBuyRaffleTickets(t *testing.T) {
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+
+	testCases := []struct {
+		name          string
+		raffleID      string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			raffleID: "2",
+			body:     gin.H{"quantity": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyRaffleTicketsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.BuyRaffleTicketsTxParams) (db.BuyRaffleTicketsTxResult, error) {
+						require.Equal(t, int32(2), arg.RaffleID)
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, int32(2), arg.Quantity)
+						return db.BuyRaffleTicketsTxResult{
+							Payment: db.Transaction{Amount: 20},
+							Tickets: []db.RaffleTicket{{ID: 7}, {ID: 8}},
+							User:    db.User{Balance: pgtype.Int4{Int32: 980, Valid: true}},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.JSONEq(t, `{"message":"tickets purchased","tickets":[7,8],"cost":20,"balance":980}`, recorder.Body.String())
+			},
+		},
+		{
+			name:     "BadRequest_TicketLimit",
+			raffleID: "2",
+			body:     gin.H{"quantity": 4},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyRaffleTicketsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BuyRaffleTicketsTxResult{}, fmt.Errorf("buy raffle tickets tx error: %w", &db.RaffleTicketLimitError{Max: 5, Remaining: 2}))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.JSONEq(t, `{"error":"at most 5 tickets per user, 2 left","remaining":2}`, recorder.Body.String())
+			},
+		},
+		{
+			name:     "Conflict_Closed",
+			raffleID: "2",
+			body:     gin.H{"quantity": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyRaffleTicketsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BuyRaffleTicketsTxResult{}, fmt.Errorf("buy raffle tickets tx error: %w", db.ErrRaffleClosed))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name:     "NotFound",
+			raffleID: "2",
+			body:     gin.H{"quantity": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyRaffleTicketsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BuyRaffleTicketsTxResult{}, fmt.Errorf("buy raffle tickets tx error: %w", db.ErrRaffleNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:     "BadRequest_InsufficientBalance",
+			raffleID: "2",
+			body:     gin.H{"quantity": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+				store.EXPECT().
+					BuyRaffleTicketsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BuyRaffleTicketsTxResult{}, fmt.Errorf("buy raffle tickets tx error: %w", db.ErrInsufficientBalance))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "insufficient balance")
+			},
+		},
+		{
+			name:     "BadRequest_Quantity",
+			raffleID: "2",
+			body:     gin.H{"quantity": 101},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BuyRaffleTicketsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "BadRequest_RaffleID",
+			raffleID: "abc",
+			body:     gin.H{"quantity": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					BuyRaffleTicketsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/raffles/"+tc.raffleID+"/tickets", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.raffleID}}
+			ctx.Set("username", user.Username)
+
+			server.handleBuyRaffleTickets(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleListRaffleTickets(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUserByUsername(gomock.Any(), user.Username).
+		Return(user, nil)
+	store.EXPECT().
+		GetRaffleSummary(gomock.Any(), db.GetRaffleSummaryParams{UserID: user.ID, ID: 2}).
+		Times(1).
+		Return(db.GetRaffleSummaryRow{
+			ID:       2,
+			Winners:  1,
+			SeedHash: "hash",
+			Seed:     "seed",
+			Status:   db.RaffleStatusDrawn,
+		}, nil)
+	store.EXPECT().
+		ListRaffleTickets(gomock.Any(), int32(2)).
+		Times(1).
+		Return([]db.ListRaffleTicketsRow{
+			{ID: 7, UserID: 1, Username: "user1"},
+			{ID: 9, UserID: 3, Username: "user3", PurchaseID: pgtype.Int4{Int32: 40, Valid: true}},
+		}, nil)
+
+	server := &Server{store: store}
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/raffles/2/tickets", nil)
+	require.NoError(t, err)
+
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request
+	ctx.Params = []gin.Param{{Key: "id", Value: "2"}}
+	ctx.Set("username", user.Username)
+
+	server.handleListRaffleTickets(ctx)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.JSONEq(t, `{
+		"id": 2,
+		"status": "drawn",
+		"winners": 1,
+		"seedHash": "hash",
+		"seed": "seed",
+		"tickets": [
+			{"position": 0, "id": 7, "user": "user1", "winner": false},
+			{"position": 1, "id": 9, "user": "user3", "winner": true}
+		]
+	}`, recorder.Body.String())
+}
+
+func TestHandleCreateRaffle(t *testing.T) {
+	admin := db.GetUserByUsernameRow{ID: 9, Username: "admin"}
+	item := db.GetItemByNameRow{ID: 3, Name: "conf-ticket", Price: 300}
+	salesEndAt := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"item": item.Name, "ticketPrice": 10, "maxTicketsPerUser": 5, "salesEndAt": salesEndAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				arg := db.CreateRaffleTxParams{
+					ItemID:            item.ID,
+					TicketPrice:       10,
+					MaxTicketsPerUser: 5,
+					Winners:           1,
+					SalesEndAt:        salesEndAt,
+					CreatedBy:         admin.ID,
+				}
+				store.EXPECT().
+					CreateRaffleTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.CreateRaffleTxResult{Raffle: db.Raffle{
+						ID:                1,
+						ItemID:            item.ID,
+						TicketPrice:       10,
+						MaxTicketsPerUser: pgtype.Int4{Int32: 5, Valid: true},
+						Winners:           1,
+						SalesEndAt:        pgtype.Timestamp{Time: salesEndAt, Valid: true},
+						SeedHash:          "hash",
+						Seed:              "seed",
+						Status:            db.RaffleStatusOpen,
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response RaffleResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, item.Name, response.Item)
+				require.Equal(t, "hash", response.SeedHash)
+				require.Empty(t, response.Seed)
+			},
+		},
+		{
+			// Время со смещением сохраняется в UTC: иначе продажа билетов закончится позже
+			name: "OK_Offset",
+			body: gin.H{"item": item.Name, "ticketPrice": 10, "salesEndAt": salesEndAt.In(time.FixedZone("MSK", 3*60*60))},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				arg := db.CreateRaffleTxParams{
+					ItemID:      item.ID,
+					TicketPrice: 10,
+					Winners:     1,
+					SalesEndAt:  salesEndAt,
+					CreatedBy:   admin.ID,
+				}
+				store.EXPECT().
+					CreateRaffleTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.CreateRaffleTxResult{Raffle: db.Raffle{ID: 2, ItemID: item.ID, SalesEndAt: pgtype.Timestamp{Time: salesEndAt, Valid: true}}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_NotEnoughStock",
+			body: gin.H{"item": item.Name, "ticketPrice": 10, "winners": 3, "salesEndAt": salesEndAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(item, nil)
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+				store.EXPECT().
+					CreateRaffleTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateRaffleTxResult{}, fmt.Errorf("create raffle tx error: %w", db.ErrItemOutOfStock))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), "not enough stock for 3 prizes")
+			},
+		},
+		{
+			name: "BadRequest_SalesEnded",
+			body: gin.H{"item": item.Name, "ticketPrice": 10, "salesEndAt": time.Now().Add(-time.Hour)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateRaffleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_Variants",
+			body: gin.H{"item": item.Name, "ticketPrice": 10, "salesEndAt": salesEndAt},
+			buildStubs: func(store *mockdb.MockStore) {
+				withVariants := item
+				withVariants.HasVariants = true
+				store.EXPECT().
+					GetItemByName(gomock.Any(), gomock.Any()).
+					Return(withVariants, nil)
+				store.EXPECT().
+					CreateRaffleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/raffles", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Set("username", admin.Username)
+
+			server.handleCreateRaffle(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BalanceDriftResponse - расхождение баланса пользователя с журналом операций
+type BalanceDriftResponse struct {
+	Username   string `json:"username"`
+	Balance    int32  `json:"balance"`
+	Expected   int32  `json:"expected"`
+	Drift      int32  `json:"drift"`
+	LotBalance int32  `json:"lotBalance"`
+	LotDrift   int32  `json:"lotDrift"`
+}
+
+// ConservationResponse - глобальный баланс монет системы
+type ConservationResponse struct {
+	TotalBalance int32 `json:"totalBalance"`
+	Issued       int32 `json:"issued"`
+	Burned       int32 `json:"burned"`
+	Spent        int32 `json:"spent"`
+	InEscrow     int32 `json:"inEscrow"`
+	Reserved     int32 `json:"reserved"`
+	Expected     int32 `json:"expected"`
+	Drift        int32 `json:"drift"`
+}
+
+// CorrectionResponse - записанная сверкой корректировка
+type CorrectionResponse struct {
+	ID       int32  `json:"id"`
+	Username string `json:"username"`
+	Kind     string `json:"kind"`
+	Amount   int32  `json:"amount"`
+}
+
+type ReconciliationResponse struct {
+	Consistent   bool                   `json:"consistent"`
+	CheckedUsers int                    `json:"checkedUsers"`
+	Drifts       []BalanceDriftResponse `json:"drifts"`
+	Conservation ConservationResponse   `json:"conservation"`
+	Corrections  []CorrectionResponse   `json:"corrections"`
+}
+
+// GET /api/admin/reconciliation
+func (server *Server) handleGetReconciliation(c *gin.Context) {
+	server.reconcile(c, db.ReconcileBalancesTxParams{})
+}
+
+// POST /api/admin/reconciliation - сверка с записью корректирующих операций
+func (server *Server) handleFixReconciliation(c *gin.Context) {
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.reconcile(c, db.ReconcileBalancesTxParams{Fix: true, AdminID: admin.ID})
+}
+
+func (server *Server) reconcile(c *gin.Context, arg db.ReconcileBalancesTxParams) {
+	result, err := server.store.ReconcileBalancesTx(c, arg)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewReconciliationResponse(result))
+}
+
+// NewReconciliationResponse переводит результат сверки в формат API и командной строки
+func NewReconciliationResponse(result db.ReconcileBalancesTxResult) ReconciliationResponse {
+	response := ReconciliationResponse{
+		Consistent:   result.Consistent(),
+		CheckedUsers: result.CheckedUsers,
+		Drifts:       make([]BalanceDriftResponse, 0, len(result.Drifts)),
+		Conservation: ConservationResponse{
+			TotalBalance: result.Conservation.TotalBalance,
+			Issued:       result.Conservation.Issued,
+			Burned:       result.Conservation.Burned,
+			Spent:        result.Conservation.Spent,
+			InEscrow:     result.Conservation.InEscrow,
+			Reserved:     result.Conservation.Reserved,
+			Expected:     result.Conservation.Expected,
+			Drift:        result.Conservation.Drift,
+		},
+		Corrections: make([]CorrectionResponse, 0, len(result.Corrections)),
+	}
+
+	usernames := make(map[int32]string, len(result.Drifts))
+	for _, drift := range result.Drifts {
+		usernames[drift.UserID] = drift.Username
+		response.Drifts = append(response.Drifts, BalanceDriftResponse{
+			Username:   drift.Username,
+			Balance:    drift.Balance,
+			Expected:   drift.Expected,
+			Drift:      drift.Drift,
+			LotBalance: drift.LotBalance,
+			LotDrift:   drift.LotDrift,
+		})
+	}
+
+	for _, correction := range result.Corrections {
+		// Начисление идет получателю, списание - с отправителя
+		userID := correction.ReceiverID.Int32
+		if correction.Kind == db.TransactionKindDeduction {
+			userID = correction.SenderID.Int32
+		}
+		response.Corrections = append(response.Corrections, CorrectionResponse{
+			ID:       correction.ID,
+			Username: usernames[userID],
+			Kind:     correction.Kind,
+			Amount:   correction.Amount,
+		})
+	}
+
+	return response
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleReconciliation(t *testing.T) {
+	admin := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "admin",
+		PasswordHash: "password",
+	}
+	drift := db.BalanceDrift{
+		UserID:     2,
+		Username:   "alice",
+		Balance:    1050,
+		Expected:   1000,
+		Drift:      50,
+		LotBalance: 1050,
+	}
+	conservation := db.LedgerConservation{
+		GetLedgerTotalsRow: db.GetLedgerTotalsRow{TotalBalance: 1050, Issued: 1000},
+		Expected:           1000,
+		Drift:              50,
+	}
+
+	testCases := []struct {
+		name          string
+		fix           bool
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK_Report",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileBalancesTx(gomock.Any(), db.ReconcileBalancesTxParams{}).
+					Times(1).
+					Return(db.ReconcileBalancesTxResult{
+						CheckedUsers: 3,
+						Drifts:       []db.BalanceDrift{drift},
+						Conservation: conservation,
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response ReconciliationResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				require.False(t, response.Consistent)
+				require.Equal(t, 3, response.CheckedUsers)
+				require.Equal(t, []BalanceDriftResponse{
+					{Username: "alice", Balance: 1050, Expected: 1000, Drift: 50, LotBalance: 1050},
+				}, response.Drifts)
+				require.Equal(t, int32(50), response.Conservation.Drift)
+				require.Empty(t, response.Corrections)
+			},
+		},
+		{
+			name: "OK_Fix",
+			fix:  true,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				store.EXPECT().
+					ReconcileBalancesTx(gomock.Any(), db.ReconcileBalancesTxParams{Fix: true, AdminID: admin.ID}).
+					Times(1).
+					Return(db.ReconcileBalancesTxResult{
+						CheckedUsers: 3,
+						Drifts:       []db.BalanceDrift{drift},
+						Conservation: conservation,
+						Corrections: []db.Transaction{
+							{
+								ID:         10,
+								ReceiverID: pgtype.Int4{Int32: drift.UserID, Valid: true},
+								Amount:     50,
+								Kind:       db.TransactionKindGrant,
+							},
+						},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response ReconciliationResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+
+				require.Equal(t, []CorrectionResponse{
+					{ID: 10, Username: "alice", Kind: db.TransactionKindGrant, Amount: 50},
+				}, response.Corrections)
+			},
+		},
+		{
+			name: "InternalError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReconcileBalancesTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ReconcileBalancesTxResult{}, errors.New("database error"))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = httptest.NewRequest(http.MethodGet, "/admin/reconciliation", nil)
+			ctx.Set("username", admin.Username)
+
+			if tc.fix {
+				server.handleFixReconciliation(ctx)
+			} else {
+				server.handleGetReconciliation(ctx)
+			}
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ReturnPurchaseRequest - самостоятельный возврат; без quantity возвращаются все невозвращенные единицы
+type ReturnPurchaseRequest struct {
+	Quantity int32 `json:"quantity" binding:"omitempty,gt=0"`
+}
+
+// RefundPurchaseRequest - возврат покупки администратором
+type RefundPurchaseRequest struct {
+	Quantity int32  `json:"quantity" binding:"omitempty,gt=0"`
+	Comment  string `json:"comment" binding:"required,max=500"`
+}
+
+// PurchaseResponse - покупка пользователя с информацией о возврате
+type PurchaseResponse struct {
+	ID               int32      `json:"id"`
+	Item             string     `json:"item"`
+	Quantity         int32      `json:"quantity"`
+	RefundedQuantity int32      `json:"refundedQuantity"`
+	TotalCost        int32      `json:"totalCost"`
+	UnitPrice        int32      `json:"unitPrice"`
+	Discount         int32      `json:"discount,omitempty"`
+	PurchasedAt      time.Time  `json:"purchasedAt"`
+	ReturnableUntil  *time.Time `json:"returnableUntil,omitempty"`
+}
+
+// GET /api/purchases
+func (server *Server) handleListPurchases(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	purchases, err := server.store.ListUserPurchases(c, pgtype.Int4{Int32: user.ID, Valid: true})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	now := time.Now()
+	response := make([]PurchaseResponse, 0, len(purchases))
+	for _, p := range purchases {
+		purchase := PurchaseResponse{
+			ID:               p.ID,
+			Item:             p.Name,
+			Quantity:         p.Quantity,
+			RefundedQuantity: p.RefundedQuantity,
+			TotalCost:        p.TotalCost,
+			UnitPrice:        p.UnitPrice,
+			Discount:         p.Discount,
+			PurchasedAt:      p.PurchaseDate.Time,
+		}
+		// Срок возврата показываем, только пока покупку еще можно вернуть
+		if server.config.ReturnWindow > 0 && p.RefundedQuantity < p.Quantity {
+			until := p.PurchaseDate.Time.Add(server.config.ReturnWindow)
+			if now.Before(until) {
+				purchase.ReturnableUntil = &until
+			}
+		}
+		response = append(response, purchase)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"purchases": response})
+}
+
+// POST /api/purchases/:id/return
+func (server *Server) handleReturnPurchase(c *gin.Context) {
+	purchaseID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid purchase id")))
+		return
+	}
+
+	// Тело запроса необязательно
+	var req ReturnPurchaseRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	if server.config.ReturnWindow <= 0 {
+		c.JSON(http.StatusForbidden, errorResponse(fmt.Errorf("self-service returns are disabled")))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.refundPurchase(c, db.RefundTxParams{
+		PurchaseID:   int32(purchaseID),
+		BuyerID:      user.ID,
+		Quantity:     req.Quantity,
+		ReturnWindow: server.config.ReturnWindow,
+		ReasonCode:   db.ReasonCodeReturn,
+		CoinTTL:      server.config.CoinTTL,
+	})
+}
+
+// POST /api/admin/purchases/:id/refund
+func (server *Server) handleRefundPurchase(c *gin.Context) {
+	purchaseID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid purchase id")))
+		return
+	}
+
+	var req RefundPurchaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	admin, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.refundPurchase(c, db.RefundTxParams{
+		PurchaseID: int32(purchaseID),
+		Quantity:   req.Quantity,
+		AdminID:    admin.ID,
+		ReasonCode: db.ReasonCodeRefund,
+		Comment:    req.Comment,
+		CoinTTL:    server.config.CoinTTL,
+	})
+}
+
+func (server *Server) refundPurchase(c *gin.Context, arg db.RefundTxParams) {
+	result, err := server.store.RefundTx(c, arg)
+	if err != nil {
+		switch {
+		case errors.Is(err, db.ErrPurchaseNotFound):
+			c.JSON(http.StatusNotFound, errorResponse(db.ErrPurchaseNotFound))
+		case errors.Is(err, db.ErrRefundQuantity):
+			c.JSON(http.StatusBadRequest, errorResponse(db.ErrRefundQuantity))
+		case errors.Is(err, db.ErrReturnWindowExpired):
+			c.JSON(http.StatusConflict, errorResponse(db.ErrReturnWindowExpired))
+		case errors.Is(err, db.ErrItemTransferred):
+			c.JSON(http.StatusConflict, errorResponse(db.ErrItemTransferred))
+		default:
+			c.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "purchase refunded",
+		"refunded":         result.Refund.Amount,
+		"refundedQuantity": result.Purchase.RefundedQuantity,
+		"coins":            result.User.Balance.Int32,
+	})
+}
@@ -0,0 +1,295 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandleReturnPurchase(t *testing.T) {
+	user := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "testuser",
+		PasswordHash: "password",
+	}
+	window := 72 * time.Hour
+
+	testCases := []struct {
+		name          string
+		purchaseID    string
+		body          gin.H
+		window        time.Duration
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:       "OK",
+			purchaseID: "5",
+			body:       gin.H{"quantity": 1},
+			window:     window,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				arg := db.RefundTxParams{
+					PurchaseID:   5,
+					BuyerID:      user.ID,
+					Quantity:     1,
+					ReturnWindow: window,
+					ReasonCode:   db.ReasonCodeReturn,
+				}
+
+				store.EXPECT().
+					RefundTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.RefundTxResult{
+						Purchase: db.Purchase{ID: 5, Quantity: 2, RefundedQuantity: 1},
+						Refund:   db.Transaction{Amount: 80},
+						User:     db.User{ID: user.ID, Balance: pgtype.Int4{Int32: 1080, Valid: true}},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response struct {
+					Refunded int32 `json:"refunded"`
+					Coins    int32 `json:"coins"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &response)
+				require.NoError(t, err)
+				require.Equal(t, int32(80), response.Refunded)
+				require.Equal(t, int32(1080), response.Coins)
+			},
+		},
+		{
+			name:       "Forbidden_ReturnsDisabled",
+			purchaseID: "5",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RefundTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:       "BadRequest_InvalidID",
+			purchaseID: "abc",
+			window:     window,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RefundTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:       "NotFound_ForeignPurchase",
+			purchaseID: "6",
+			window:     window,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					RefundTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RefundTxResult{}, fmt.Errorf("refund tx error: %w", db.ErrPurchaseNotFound))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:       "Conflict_WindowExpired",
+			purchaseID: "5",
+			window:     window,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					RefundTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RefundTxResult{}, fmt.Errorf("refund tx error: %w", db.ErrReturnWindowExpired))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+				requireBodyMatchError(t, recorder.Body.Bytes(), db.ErrReturnWindowExpired.Error())
+			},
+		},
+		{
+			name:       "BadRequest_QuantityExceeded",
+			purchaseID: "5",
+			body:       gin.H{"quantity": 3},
+			window:     window,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), user.Username).
+					Return(user, nil)
+
+				store.EXPECT().
+					RefundTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.RefundTxResult{}, fmt.Errorf("refund tx error: %w", db.ErrRefundQuantity))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{
+				store:  store,
+				config: Config{RefundConfig: RefundConfig{ReturnWindow: tc.window}},
+			}
+			recorder := httptest.NewRecorder()
+
+			var body []byte
+			if tc.body != nil {
+				var err error
+				body, err = json.Marshal(tc.body)
+				require.NoError(t, err)
+			}
+
+			request, err := http.NewRequest(http.MethodPost, "/purchases/"+tc.purchaseID+"/return", bytes.NewReader(body))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: tc.purchaseID}}
+			ctx.Set("username", user.Username)
+
+			server.handleReturnPurchase(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleRefundPurchase(t *testing.T) {
+	admin := db.GetUserByUsernameRow{
+		ID:           1,
+		Username:     "admin",
+		PasswordHash: "password",
+	}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK_FullRefund",
+			body: gin.H{"comment": "defective hoody"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), admin.Username).
+					Return(admin, nil)
+
+				// Без quantity возвращаются все невозвращенные единицы, окно возврата не проверяется
+				arg := db.RefundTxParams{
+					PurchaseID: 5,
+					AdminID:    admin.ID,
+					ReasonCode: db.ReasonCodeRefund,
+					Comment:    "defective hoody",
+				}
+
+				store.EXPECT().
+					RefundTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.RefundTxResult{
+						Purchase: db.Purchase{ID: 5, Quantity: 1, RefundedQuantity: 1},
+						Refund:   db.Transaction{Amount: 300},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "purchase refunded")
+			},
+		},
+		{
+			name: "BadRequest_MissingComment",
+			body: gin.H{"quantity": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RefundTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest_NegativeQuantity",
+			body: gin.H{"quantity": -1, "comment": "defective"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RefundTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/admin/purchases/5/refund", bytes.NewReader(data))
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", "application/json")
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "id", Value: "5"}}
+			ctx.Set("username", admin.Username)
+
+			server.handleRefundPurchase(ctx)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
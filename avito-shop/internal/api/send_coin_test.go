@@ -190,6 +190,69 @@ func TestHandleSendCoin(t *testing.T) {
 				requireBodyMatchError(t, recorder.Body.Bytes(), "insufficient balance")
 			},
 		},
+		{
+			name: "BadRequest_DailyLimitExceeded",
+			body: gin.H{
+				"toUser": receiver.Username,
+				"amount": amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), receiver.Username).
+					Return(receiver, nil)
+
+				limitErr := &db.TransferLimitError{Limit: db.TransferLimitDaily, Allowed: 500, Remaining: 30}
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Return(db.TransferTxResult{}, fmt.Errorf("transfer tx error: %w", limitErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				var gotResponse struct {
+					Limit     string `json:"limit"`
+					Remaining int32  `json:"remaining"`
+				}
+				err := json.Unmarshal(recorder.Body.Bytes(), &gotResponse)
+				require.NoError(t, err)
+				require.Equal(t, db.TransferLimitDaily, gotResponse.Limit)
+				require.Equal(t, int32(30), gotResponse.Remaining)
+			},
+		},
+		{
+			name: "TooManyRequests_RateLimitExceeded",
+			body: gin.H{
+				"toUser": receiver.Username,
+				"amount": amount,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, username string) {
+				request.Header.Set("Authorization", "Bearer token")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), sender.Username).
+					Return(sender, nil)
+
+				store.EXPECT().
+					GetUserByUsername(gomock.Any(), receiver.Username).
+					Return(receiver, nil)
+
+				limitErr := &db.TransferLimitError{Limit: db.TransferLimitRate, Allowed: 5}
+				store.EXPECT().
+					TransferTx(gomock.Any(), gomock.Any()).
+					Return(db.TransferTxResult{}, fmt.Errorf("transfer tx error: %w", limitErr))
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+			},
+		},
 	}
 
 	for i := range testCases {
@@ -208,7 +271,7 @@ func TestHandleSendCoin(t *testing.T) {
 				AccessTokenDuration: 24,
 			}
 
-			server, err := NewServer(store, tokenConfig)
+			server, err := NewServer(store, Config{TokenConfig: tokenConfig})
 			require.NoError(t, err)
 
 			recorder := httptest.NewRecorder()
@@ -3,6 +3,7 @@ package api
 import (
 	db "avito-shop/internal/db/sqlc"
 	middleware "avito-shop/internal/middleware"
+	"avito-shop/internal/storage"
 	"avito-shop/internal/token"
 	"avito-shop/internal/util"
 	"net/http"
@@ -17,8 +18,58 @@ type TokenConfig struct {
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 }
 
+type TransferConfig struct {
+	TransferHoldPeriod time.Duration `mapstructure:"TRANSFER_HOLD_PERIOD"`
+	TransferLimits     db.TransferLimits
+}
+
+// OnboardingConfig - политика приветственного начисления новым пользователям
+type OnboardingConfig struct {
+	WelcomeBalance int32
+	// WelcomeBalanceByDepartment переопределяет WelcomeBalance для отдельных отделов:
+	// разница доплачивается, когда администратор назначает пользователю отдел
+	WelcomeBalanceByDepartment map[string]int32
+}
+
+// CoinConfig - срок действия начисляемых монет, 0 - бессрочно
+type CoinConfig struct {
+	CoinTTL time.Duration
+}
+
+// RefundConfig - окно самостоятельного возврата покупок, 0 - возврат только через администратора
+type RefundConfig struct {
+	ReturnWindow time.Duration
+}
+
+// MarketplaceConfig - комиссия магазина с продаж между пользователями в процентах, 0 - без комиссии
+type MarketplaceConfig struct {
+	CommissionPercent int32
+}
+
+// CartConfig - срок резерва остатка под позиции корзины, 0 - остаток не резервируется
+type CartConfig struct {
+	ReservationTTL time.Duration
+}
+
+// MediaConfig - хранилище изображений товаров; без него загрузка изображений отключена
+type MediaConfig struct {
+	Blobs storage.BlobStorage
+}
+
+// Config объединяет настройки сервера
+type Config struct {
+	TokenConfig
+	TransferConfig
+	OnboardingConfig
+	CoinConfig
+	RefundConfig
+	MarketplaceConfig
+	CartConfig
+	MediaConfig
+}
+
 type Server struct {
-	config     TokenConfig
+	config     Config
 	store      db.Store
 	tokenMaker token.Maker
 	Router     *gin.Engine
@@ -29,7 +80,7 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-func NewServer(store db.Store, config TokenConfig) (*Server, error) {
+func NewServer(store db.Store, config Config) (*Server, error) {
 	tokenMaker, err := token.NewJWTMaker(config.TokenSymmetricKey)
 	if err != nil {
 		return nil, err
@@ -51,12 +102,96 @@ func (server *Server) setupRouter() {
 	// Публичные маршруты
 	router.POST("/api/auth", server.handleLogin)
 
+	// Изображения товаров из локального хранилища раздаются самим сервером
+	if local, ok := server.config.Blobs.(*storage.LocalStorage); ok {
+		router.Static(local.URLPrefix(), local.Root())
+	}
+
 	// Защищенные маршруты
 	protected := router.Group("/api").Use(middleware.AuthMiddleware(server.tokenMaker))
 	{
 		protected.GET("/info", server.handleGetInfo)
 		protected.GET("/buy/:item", server.handleBuyItem)
+		protected.POST("/buy/quote", server.handleQuotePurchase)
 		protected.POST("/sendCoin", server.handleSendCoin)
+		protected.POST("/sendCoin/quote", server.handleQuoteTransfer)
+		protected.POST("/sendCoin/:id/cancel", server.handleCancelTransfer)
+		protected.GET("/purchases", server.handleListPurchases)
+		protected.POST("/purchases/:id/return", server.handleReturnPurchase)
+		protected.GET("/inventory", server.handleListInventory)
+		protected.GET("/inventory/:id/history", server.handleGetInventoryHistory)
+		protected.POST("/giveItem", server.handleGiveItem)
+		protected.GET("/marketplace/listings", server.handleListListings)
+		protected.POST("/marketplace/listings", server.handleCreateListing)
+		protected.DELETE("/marketplace/listings/:id", server.handleCancelListing)
+		protected.POST("/marketplace/listings/:id/buy", server.handleBuyListing)
+		protected.GET("/bundles", server.handleListBundles)
+		protected.POST("/bundles/:id/buy", server.handleBuyBundle)
+		protected.GET("/items", server.handleSearchItems)
+		protected.GET("/items/:item", server.handleGetItem)
+		protected.GET("/categories", server.handleListCategories)
+		protected.GET("/items/:item/variants", server.handleListVariants)
+		protected.GET("/items/:item/prices", server.handleGetPriceHistory)
+		protected.GET("/cart", server.handleGetCart)
+		protected.POST("/cart", server.handleAddCartItem)
+		protected.PUT("/cart/:item", server.handleUpdateCartItem)
+		protected.DELETE("/cart/:item", server.handleRemoveCartItem)
+		protected.POST("/cart/checkout", server.handleCheckout)
+		protected.GET("/auctions", server.handleListAuctions)
+		protected.GET("/auctions/:id", server.handleGetAuction)
+		protected.POST("/auctions/:id/bids", server.handlePlaceBid)
+		protected.GET("/raffles", server.handleListRaffles)
+		protected.GET("/raffles/:id", server.handleGetRaffle)
+		protected.GET("/raffles/:id/tickets", server.handleListRaffleTickets)
+		protected.POST("/raffles/:id/tickets", server.handleBuyRaffleTickets)
+		protected.GET("/drops/:item/queue", server.handleGetDropQueue)
+		protected.POST("/drops/:item/queue", server.handleJoinDropQueue)
+		protected.GET("/wishlist", server.handleGetWishlist)
+		protected.POST("/wishlist/:item", server.handleAddWishlistItem)
+		protected.DELETE("/wishlist/:item", server.handleRemoveWishlistItem)
+		protected.GET("/notifications", server.handleListNotifications)
+		protected.POST("/notifications/read", server.handleMarkAllNotificationsRead)
+		protected.POST("/notifications/:id/read", server.handleMarkNotificationRead)
+	}
+
+	// Административные маршруты
+	admin := router.Group("/api/admin").Use(
+		middleware.AuthMiddleware(server.tokenMaker),
+		middleware.AdminMiddleware(server.store),
+	)
+	{
+		admin.POST("/coins/grant", server.handleGrantCoins)
+		admin.POST("/coins/deduct", server.handleDeductCoins)
+		admin.PUT("/users/:username/department", server.handleAssignDepartment)
+		admin.GET("/reconciliation", server.handleGetReconciliation)
+		admin.POST("/reconciliation", server.handleFixReconciliation)
+		admin.POST("/purchases/:id/refund", server.handleRefundPurchase)
+		admin.GET("/auctions", server.handleAdminListAuctions)
+		admin.POST("/auctions", server.handleCreateAuction)
+		admin.POST("/auctions/:id/cancel", server.handleCancelAuction)
+		admin.GET("/raffles", server.handleAdminListRaffles)
+		admin.POST("/raffles", server.handleCreateRaffle)
+		admin.GET("/bundles", server.handleAdminListBundles)
+		admin.POST("/bundles", server.handleCreateBundle)
+		admin.DELETE("/bundles/:id", server.handleDeactivateBundle)
+		admin.POST("/categories", server.handleCreateCategory)
+		admin.PATCH("/items/:item", server.handleUpdateItem)
+		admin.POST("/items/:item/images", server.handleUploadItemImage)
+		admin.DELETE("/items/:item/images/:id", server.handleDeleteItemImage)
+		admin.POST("/items/:item/variants", server.handleCreateVariant)
+		admin.GET("/items/:item/prices", server.handleAdminGetPriceHistory)
+		admin.POST("/items/:item/prices", server.handleChangePrice)
+		admin.DELETE("/items/:item/prices/:id", server.handleCancelPriceChange)
+		admin.PUT("/items/:item/limits", server.handleUpdateItemLimits)
+		admin.PUT("/items/:item/availability", server.handleUpdateItemAvailability)
+		admin.GET("/orders", server.handleListOrders)
+		admin.GET("/orders/:id/history", server.handleGetOrderHistory)
+		admin.POST("/orders/:id/status", server.handleUpdateOrderStatus)
+		admin.GET("/promo-codes", server.handleListPromoCodes)
+		admin.POST("/promo-codes", server.handleCreatePromoCode)
+		admin.GET("/promo-codes/:id", server.handleGetPromoCode)
+		admin.PUT("/promo-codes/:id", server.handleUpdatePromoCode)
+		admin.DELETE("/promo-codes/:id", server.handleDeletePromoCode)
 	}
 
 	server.Router = router
@@ -82,17 +217,20 @@ func (server *Server) handleLogin(c *gin.Context) {
 				return
 			}
 
-			arg := db.CreateUserParams{
+			arg := db.CreateUserTxParams{
 				Username:     req.Username,
 				PasswordHash: hashedPassword,
+				// Отдел назначает администратор, до этого действует стандартное начисление
+				WelcomeGrant: server.config.WelcomeBalance,
+				CoinTTL:      server.config.CoinTTL,
 			}
 
-			newUser, err := server.store.CreateUser(c, arg)
+			result, err := server.store.CreateUserTx(c, arg)
 			if err != nil {
 				c.JSON(http.StatusInternalServerError, errorResponse(err))
 				return
 			}
-			username = newUser.Username
+			username = result.User.Username
 
 		} else {
 			c.JSON(http.StatusInternalServerError, errorResponse(err))
@@ -122,6 +260,14 @@ func (server *Server) handleLogin(c *gin.Context) {
 	})
 }
 
+// welcomeGrant возвращает размер приветственного начисления для отдела
+func (config OnboardingConfig) welcomeGrant(department string) int32 {
+	if amount, ok := config.WelcomeBalanceByDepartment[department]; ok {
+		return amount
+	}
+	return config.WelcomeBalance
+}
+
 func (server *Server) Start(address string) error {
 	return server.Router.Run(address)
 }
@@ -0,0 +1,134 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// CreateVariantRequest - новый вариант товара; без price используется цена товара,
+// без stock остаток варианта не отслеживается
+type CreateVariantRequest struct {
+	Sku    string `json:"sku" binding:"required,max=64"`
+	Size   string `json:"size" binding:"max=20"`
+	Colour string `json:"colour" binding:"max=30"`
+	Price  *int32 `json:"price" binding:"omitempty,gt=0"`
+	Stock  *int32 `json:"stock" binding:"omitempty,gte=0"`
+}
+
+// VariantResponse - вариант товара с итоговой ценой
+type VariantResponse struct {
+	Sku    string `json:"sku"`
+	Size   string `json:"size,omitempty"`
+	Colour string `json:"colour,omitempty"`
+	Price  int32  `json:"price"`
+	Stock  *int32 `json:"stock,omitempty"`
+}
+
+// NewVariantResponse собирает ответ по варианту и цене товара
+func NewVariantResponse(variant db.ItemVariant, itemPrice int32) VariantResponse {
+	response := VariantResponse{
+		Sku:    variant.Sku,
+		Size:   variant.Size.String,
+		Colour: variant.Colour.String,
+		Price:  variant.UnitPrice(itemPrice),
+	}
+	if variant.Stock.Valid {
+		stock := variant.Stock.Int32
+		response.Stock = &stock
+	}
+	return response
+}
+
+// resolveItem находит товар и, если передан артикул, его вариант.
+// Товар с вариантами без артикула купить нельзя. При ошибке отвечает сам.
+func (server *Server) resolveItem(c *gin.Context, name, sku string) (db.GetItemByNameRow, bool) {
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{
+		Name: name,
+		Sku:  pgtype.Text{String: sku, Valid: sku != ""},
+	})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return db.GetItemByNameRow{}, false
+	}
+
+	if sku != "" && !item.VariantID.Valid {
+		c.JSON(http.StatusNotFound, errorResponse(db.ErrVariantNotFound))
+		return db.GetItemByNameRow{}, false
+	}
+	if sku == "" && item.HasVariants {
+		c.JSON(http.StatusBadRequest, errorResponse(db.ErrVariantRequired))
+		return db.GetItemByNameRow{}, false
+	}
+
+	return item, true
+}
+
+// GET /api/items/:item/variants
+func (server *Server) handleListVariants(c *gin.Context) {
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	variants, err := server.store.ListItemVariants(c, item.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]VariantResponse, 0, len(variants))
+	for _, variant := range variants {
+		response = append(response, NewVariantResponse(variant, item.Price))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"item":     item.Name,
+		"variants": response,
+	})
+}
+
+// POST /api/admin/items/:item/variants
+func (server *Server) handleCreateVariant(c *gin.Context) {
+	var req CreateVariantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	arg := db.CreateItemVariantParams{
+		ItemID: item.ID,
+		Sku:    req.Sku,
+		Size:   pgtype.Text{String: req.Size, Valid: req.Size != ""},
+		Colour: pgtype.Text{String: req.Colour, Valid: req.Colour != ""},
+	}
+	if req.Price != nil {
+		arg.Price = pgtype.Int4{Int32: *req.Price, Valid: true}
+	}
+	if req.Stock != nil {
+		arg.Stock = pgtype.Int4{Int32: *req.Stock, Valid: true}
+	}
+
+	variant, err := server.store.CreateItemVariant(c, arg)
+	if err != nil {
+		if strings.Contains(err.Error(), "item_variants_sku_key") {
+			c.JSON(http.StatusConflict, errorResponse(fmt.Errorf("sku already exists")))
+			return
+		}
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, NewVariantResponse(variant, item.Price))
+}
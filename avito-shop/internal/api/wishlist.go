@@ -0,0 +1,249 @@
+package api
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ListNotificationsRequest - параметры списка уведомлений
+type ListNotificationsRequest struct {
+	Unread bool  `form:"unread"`
+	Limit  int32 `form:"limit" binding:"omitempty,gt=0,lte=100"`
+}
+
+const defaultNotificationsLimit = 50
+
+// WishlistItemResponse - товар из списка желаний и сколько монет на него не хватает
+type WishlistItemResponse struct {
+	Name         string    `json:"name"`
+	Price        int32     `json:"price"`
+	Stock        *int32    `json:"stock,omitempty"`
+	MissingCoins int32     `json:"missingCoins"`
+	Affordable   bool      `json:"affordable"`
+	AddedAt      time.Time `json:"addedAt"`
+}
+
+// NotificationResponse - уведомление пользователя
+type NotificationResponse struct {
+	ID        int32     `json:"id"`
+	Kind      string    `json:"kind"`
+	Message   string    `json:"message"`
+	CreatedAt time.Time `json:"createdAt"`
+	Read      bool      `json:"read"`
+}
+
+// NewWishlist считает, сколько монет не хватает на каждый товар при балансе balance
+func NewWishlist(rows []db.ListWishlistItemsRow, balance int32) []WishlistItemResponse {
+	items := make([]WishlistItemResponse, 0, len(rows))
+	for _, row := range rows {
+		missing := max(row.Price-balance, 0)
+		items = append(items, WishlistItemResponse{
+			Name:         row.Name,
+			Price:        row.Price,
+			Stock:        int4Ptr(row.Stock),
+			MissingCoins: missing,
+			Affordable:   missing == 0,
+			AddedAt:      row.CreatedAt.Time,
+		})
+	}
+	return items
+}
+
+// GET /api/wishlist
+func (server *Server) handleGetWishlist(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	rows, err := server.store.ListWishlistItems(c, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	balance, err := server.store.GetSpendableBalance(c, db.GetSpendableBalanceParams{
+		UserID: user.ID,
+		Now:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"balance": balance,
+		"items":   NewWishlist(rows, balance),
+	})
+}
+
+// POST /api/wishlist/:item
+func (server *Server) handleAddWishlistItem(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	added, err := server.store.AddWishlistItem(c, db.AddWishlistItemParams{
+		Now:    pgtype.Timestamp{Time: time.Now(), Valid: true},
+		UserID: user.ID,
+		ItemID: item.ID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	message := "item added to wishlist"
+	if added == 0 {
+		message = "item already in wishlist"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+	})
+}
+
+// DELETE /api/wishlist/:item
+func (server *Server) handleRemoveWishlistItem(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	item, err := server.store.GetItemByName(c, db.GetItemByNameParams{Name: c.Param("item")})
+	if err != nil {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not found")))
+		return
+	}
+
+	removed, err := server.store.RemoveWishlistItem(c, db.RemoveWishlistItemParams{UserID: user.ID, ItemID: item.ID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if removed == 0 {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("item not in wishlist")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "item removed from wishlist",
+	})
+}
+
+// GET /api/notifications
+func (server *Server) handleListNotifications(c *gin.Context) {
+	var req ListNotificationsRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if req.Limit == 0 {
+		req.Limit = defaultNotificationsLimit
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	notifications, err := server.store.ListNotifications(c, db.ListNotificationsParams{
+		UserID:     user.ID,
+		UnreadOnly: req.Unread,
+		RowLimit:   req.Limit,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	unread, err := server.store.CountUnreadNotifications(c, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	response := make([]NotificationResponse, 0, len(notifications))
+	for _, n := range notifications {
+		response = append(response, NotificationResponse{
+			ID:        n.ID,
+			Kind:      n.Kind,
+			Message:   n.Message,
+			CreatedAt: n.CreatedAt.Time,
+			Read:      n.ReadAt.Valid,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"unread":        unread,
+		"notifications": response,
+	})
+}
+
+// POST /api/notifications/:id/read
+func (server *Server) handleMarkNotificationRead(c *gin.Context) {
+	notificationID, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid notification id")))
+		return
+	}
+
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	marked, err := server.store.MarkNotificationRead(c, db.MarkNotificationReadParams{
+		ID:     int32(notificationID),
+		UserID: user.ID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if marked == 0 {
+		c.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("notification not found")))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "notification marked as read",
+	})
+}
+
+// POST /api/notifications/read
+func (server *Server) handleMarkAllNotificationsRead(c *gin.Context) {
+	user, err := server.store.GetUserByUsername(c, c.MustGet("username").(string))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	marked, err := server.store.MarkAllNotificationsRead(c, user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "notifications marked as read",
+		"marked":  marked,
+	})
+}
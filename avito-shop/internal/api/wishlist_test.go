@@ -0,0 +1,220 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewWishlist(t *testing.T) {
+	items := NewWishlist([]db.ListWishlistItemsRow{
+		{ItemID: 1, Name: "hoody", Price: 300},
+		{ItemID: 2, Name: "cup", Price: 20, Stock: pgtype.Int4{Int32: 4, Valid: true}},
+	}, 120)
+
+	require.Len(t, items, 2)
+	require.Equal(t, int32(180), items[0].MissingCoins)
+	require.False(t, items[0].Affordable)
+	require.Equal(t, int32(0), items[1].MissingCoins)
+	require.True(t, items[1].Affordable)
+	require.Equal(t, int32(4), *items[1].Stock)
+}
+
+func TestHandleGetWishlist(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUserByUsername(gomock.Any(), user.Username).
+		Return(user, nil)
+	store.EXPECT().
+		ListWishlistItems(gomock.Any(), user.ID).
+		Times(1).
+		Return([]db.ListWishlistItemsRow{{ItemID: 1, Name: "hoody", Price: 300}}, nil)
+	store.EXPECT().
+		GetSpendableBalance(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int32(250), nil)
+
+	server := &Server{store: store}
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/wishlist", nil)
+	require.NoError(t, err)
+
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request
+	ctx.Set("username", user.Username)
+
+	server.handleGetWishlist(ctx)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Balance int32                  `json:"balance"`
+		Items   []WishlistItemResponse `json:"items"`
+	}
+	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Equal(t, int32(250), response.Balance)
+	require.Len(t, response.Items, 1)
+	require.Equal(t, int32(50), response.Items[0].MissingCoins)
+}
+
+func TestHandleWishlistItem(t *testing.T) {
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+	item := db.GetItemByNameRow{ID: 3, Name: "hoody", Price: 300}
+
+	testCases := []struct {
+		name          string
+		method        string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK_Add",
+			method: http.MethodPost,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddWishlistItem(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "item added to wishlist")
+			},
+		},
+		{
+			name:   "OK_AlreadyAdded",
+			method: http.MethodPost,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					AddWishlistItem(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchMessage(t, recorder.Body.Bytes(), "item already in wishlist")
+			},
+		},
+		{
+			name:   "OK_Remove",
+			method: http.MethodDelete,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RemoveWishlistItem(gomock.Any(), db.RemoveWishlistItemParams{UserID: user.ID, ItemID: item.ID}).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound_Remove",
+			method: http.MethodDelete,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RemoveWishlistItem(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserByUsername(gomock.Any(), user.Username).
+				Return(user, nil)
+			store.EXPECT().
+				GetItemByName(gomock.Any(), db.GetItemByNameParams{Name: item.Name}).
+				Return(item, nil)
+			tc.buildStubs(store)
+
+			server := &Server{store: store}
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(tc.method, "/wishlist/"+item.Name, nil)
+			require.NoError(t, err)
+
+			ctx, _ := gin.CreateTestContext(recorder)
+			ctx.Request = request
+			ctx.Params = []gin.Param{{Key: "item", Value: item.Name}}
+			ctx.Set("username", user.Username)
+
+			if tc.method == http.MethodPost {
+				server.handleAddWishlistItem(ctx)
+			} else {
+				server.handleRemoveWishlistItem(ctx)
+			}
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestHandleListNotifications(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	user := db.GetUserByUsernameRow{ID: 1, Username: "user1"}
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUserByUsername(gomock.Any(), user.Username).
+		Return(user, nil)
+	store.EXPECT().
+		ListNotifications(gomock.Any(), db.ListNotificationsParams{UserID: user.ID, UnreadOnly: true, RowLimit: defaultNotificationsLimit}).
+		Times(1).
+		Return([]db.Notification{
+			{ID: 7, UserID: user.ID, Kind: db.NotificationKindPriceDrop, Message: "hoody price dropped from 300 to 250"},
+		}, nil)
+	store.EXPECT().
+		CountUnreadNotifications(gomock.Any(), user.ID).
+		Times(1).
+		Return(int32(1), nil)
+
+	server := &Server{store: store}
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/notifications?unread=true", nil)
+	require.NoError(t, err)
+
+	ctx, _ := gin.CreateTestContext(recorder)
+	ctx.Request = request
+	ctx.Set("username", user.Username)
+
+	server.handleListNotifications(ctx)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response struct {
+		Unread        int32                  `json:"unread"`
+		Notifications []NotificationResponse `json:"notifications"`
+	}
+	err = json.Unmarshal(recorder.Body.Bytes(), &response)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), response.Unread)
+	require.Len(t, response.Notifications, 1)
+	require.Equal(t, db.NotificationKindPriceDrop, response.Notifications[0].Kind)
+	require.False(t, response.Notifications[0].Read)
+}
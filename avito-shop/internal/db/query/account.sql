@@ -2,9 +2,10 @@
 INSERT INTO users (
   username,
   password_hash,
-  balance
+  balance,
+  department
 ) VALUES (
-  $1, $2, 1000
+  $1, $2, 0, $3
 ) RETURNING *;
 
 -- name: CreateItem :one
@@ -13,7 +14,7 @@ INSERT INTO items (
     price
 ) VALUES (
     $1, $2
-) RETURNING id, name, price;
+) RETURNING *;
 
 -- name: GetUserByUsername :one
 SELECT id, username, password_hash 
@@ -22,18 +23,42 @@ WHERE username = $1
 LIMIT 1;
 
 -- name: GetUserByID :one
-SELECT id, username, password_hash, balance, created_at, updated_at
+SELECT id, username, password_hash, balance, created_at, updated_at, role, department
 FROM users
 WHERE id = $1 LIMIT 1;
 
+-- name: LockUsers :exec
+SELECT id FROM users
+WHERE id = ANY(sqlc.arg(ids)::int[])
+ORDER BY id
+FOR UPDATE;
+
+-- name: GetUserRole :one
+SELECT role
+FROM users
+WHERE username = $1
+LIMIT 1;
+
+-- name: GetUsersByUsernames :many
+SELECT id, username
+FROM users
+WHERE username = ANY(sqlc.arg(usernames)::text[])
+ORDER BY username;
+
 -- name: GetPurchases :many
 SELECT 
     i.name,
-    p.quantity,
-    p.purchase_date
+    (p.quantity - p.refunded_quantity)::int AS quantity,
+    p.refunded_quantity,
+    p.purchase_date,
+    o.status AS order_status,
+    v.sku AS variant
 FROM purchases p
 JOIN items i ON p.item_id = i.id
-WHERE p.buyer_id = $1
+LEFT JOIN orders o ON p.order_id = o.id
+LEFT JOIN item_variants v ON p.variant_id = v.id
+WHERE (p.buyer_id = $1 AND p.recipient_id IS NULL)
+   OR p.recipient_id = $1
 ORDER BY p.purchase_date DESC;
 
 -- name: GetCurrentBalance :one 
@@ -48,18 +73,20 @@ INSERT INTO transactions (
     amount
 ) VALUES (
     $1, $2, $3
-) RETURNING id, sender_id, receiver_id, amount, timestamp;;
+) RETURNING *;
 
 -- name: GetTransactions :many
 SELECT 
     t.timestamp,
     t.amount,
-    sender.username as sender_username,
-    receiver.username as receiver_username
+    t.kind,
+    COALESCE(sender.username, 'system')::text as sender_username,
+    COALESCE(receiver.username, 'system')::text as receiver_username
 FROM transactions t
-JOIN users sender ON t.sender_id = sender.id
-JOIN users receiver ON t.receiver_id = receiver.id
-WHERE t.sender_id = $1 OR t.receiver_id = $1
+LEFT JOIN users sender ON t.sender_id = sender.id
+LEFT JOIN users receiver ON t.receiver_id = receiver.id
+WHERE (t.sender_id = $1 OR t.receiver_id = $1)
+  AND t.status = 'completed'
 ORDER BY t.timestamp DESC;
 
 -- name: UpdateBalanceForTransfer :exec
@@ -79,20 +106,76 @@ SET
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1;
 
+-- name: UpdateBalance :exec
+UPDATE users
+SET
+    balance = balance + sqlc.arg(amount)::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
+
 -- name: GetItemByID :one
 SELECT * FROM items
 WHERE id = $1 LIMIT 1;
 
+-- name: GetItemStockForUpdate :one
+-- Блокирует остаток товара до конца транзакции, чтобы резервы корзин не менялись
+-- между проверкой и списанием
+SELECT stock FROM items
+WHERE id = $1
+FOR UPDATE;
+
 -- name: GetItemByName :one
-SELECT * FROM items
-WHERE name = $1 LIMIT 1;
+-- Находит товар и, если задан артикул, его вариант; has_variants - покупка возможна только с вариантом
+SELECT
+    i.id,
+    i.name,
+    i.price,
+    i.stock,
+    i.category_id,
+    v.id AS variant_id,
+    v.sku,
+    v.price AS variant_price,
+    v.stock AS variant_stock,
+    EXISTS (SELECT 1 FROM item_variants iv WHERE iv.item_id = i.id) AS has_variants
+FROM items i
+LEFT JOIN item_variants v ON v.item_id = i.id AND v.sku = sqlc.narg(sku)
+WHERE i.name = sqlc.arg(name)
+LIMIT 1;
 
 -- name: CreatePurchase :one
 INSERT INTO purchases (
     buyer_id,
     item_id,
     quantity,
-    total_cost
+    total_cost,
+    recipient_id,
+    gift_message,
+    order_id,
+    variant_id,
+    promo_code_id,
+    discount,
+    unit_price
 ) VALUES (
-    $1, $2, $3, $4
-) RETURNING *;
\ No newline at end of file
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING *;
+
+-- name: DecrementItemStock :execrows
+-- Уменьшает остаток, если он отслеживается; 0 строк - товара не хватает
+UPDATE items
+SET stock = stock - sqlc.arg(quantity)::int
+WHERE id = sqlc.arg(id)
+  AND stock IS NOT NULL
+  AND stock >= sqlc.arg(quantity)::int;
+
+-- name: RestoreItemStock :exec
+UPDATE items
+SET stock = stock + sqlc.arg(quantity)::int
+WHERE id = sqlc.arg(id)
+  AND stock IS NOT NULL;
+
+-- name: SetUserDepartment :exec
+UPDATE users
+SET
+    department = sqlc.arg(department),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id);
@@ -0,0 +1,12 @@
+-- name: CreateAdjustment :one
+INSERT INTO transactions (
+    sender_id,
+    receiver_id,
+    amount,
+    kind,
+    reason_code,
+    comment,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7
+) RETURNING *;
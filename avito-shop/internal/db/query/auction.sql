@@ -0,0 +1,106 @@
+-- name: CreateAuction :one
+INSERT INTO auctions (
+    item_id,
+    starts_at,
+    ends_at,
+    reserve_price,
+    min_increment,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetAuctionForUpdate :one
+SELECT * FROM auctions
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListAuctions :many
+-- Аукционы с лидирующей ставкой и числом ставок; без status - все аукционы
+SELECT
+    a.id,
+    a.item_id,
+    i.name AS item_name,
+    a.starts_at,
+    a.ends_at,
+    a.reserve_price,
+    a.min_increment,
+    a.status,
+    b.amount AS leading_amount,
+    b.user_id AS leading_user_id,
+    (SELECT COUNT(*) FROM auction_bids ab WHERE ab.auction_id = a.id)::int AS bids
+FROM auctions a
+JOIN items i ON a.item_id = i.id
+LEFT JOIN auction_bids b ON b.auction_id = a.id AND b.status = 'active'
+WHERE sqlc.narg(status)::text IS NULL OR a.status = sqlc.narg(status)::text
+ORDER BY a.ends_at, a.id
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: GetAuctionSummary :one
+SELECT
+    a.id,
+    a.item_id,
+    i.name AS item_name,
+    a.starts_at,
+    a.ends_at,
+    a.reserve_price,
+    a.min_increment,
+    a.status,
+    b.amount AS leading_amount,
+    b.user_id AS leading_user_id,
+    (SELECT COUNT(*) FROM auction_bids ab WHERE ab.auction_id = a.id)::int AS bids
+FROM auctions a
+JOIN items i ON a.item_id = i.id
+LEFT JOIN auction_bids b ON b.auction_id = a.id AND b.status = 'active'
+WHERE a.id = $1;
+
+-- name: ListDueAuctions :many
+-- Аукционы, прием ставок по которым закончился, а итог еще не подведен
+SELECT id FROM auctions
+WHERE status = 'active'
+  AND ends_at <= sqlc.arg(now)
+ORDER BY ends_at, id
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: CloseAuction :exec
+UPDATE auctions
+SET
+    status = $2,
+    purchase_id = $3,
+    closed_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: CreateAuctionBid :one
+INSERT INTO auction_bids (
+    auction_id,
+    user_id,
+    amount
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: GetLeadingAuctionBid :one
+SELECT * FROM auction_bids
+WHERE auction_id = $1
+  AND status = 'active';
+
+-- name: ResolveAuctionBid :exec
+UPDATE auction_bids
+SET
+    status = $2,
+    resolved_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: CreateAuctionBidLot :exec
+INSERT INTO auction_bid_lots (
+    bid_id,
+    amount,
+    expires_at
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetAuctionBidLots :many
+SELECT * FROM auction_bid_lots
+WHERE bid_id = $1
+ORDER BY id;
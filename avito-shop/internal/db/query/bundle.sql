@@ -0,0 +1,72 @@
+-- name: CreateBundle :one
+INSERT INTO bundles (
+    name,
+    description,
+    price,
+    created_by
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: CreateBundleItem :one
+INSERT INTO bundle_items (
+    bundle_id,
+    item_id,
+    variant_id,
+    quantity
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetBundle :one
+SELECT * FROM bundles
+WHERE id = $1;
+
+-- name: ListBundleComponents :many
+-- Состав набора с ценами, остатками и ограничениями товаров, для покупки
+SELECT
+    b.item_id,
+    b.variant_id,
+    i.name,
+    v.sku,
+    COALESCE(v.price, i.price) AS price,
+    i.stock,
+    v.stock AS variant_stock,
+    b.quantity,
+    i.max_per_user,
+    i.cooldown_days,
+    i.available_from,
+    i.available_until,
+    i.queue_minutes,
+    i.queue_rate
+FROM bundle_items b
+JOIN items i ON b.item_id = i.id
+LEFT JOIN item_variants v ON b.variant_id = v.id
+WHERE b.bundle_id = $1
+ORDER BY b.id;
+
+-- name: ListBundleItems :many
+-- Состав нескольких наборов для показа в каталоге
+SELECT
+    b.bundle_id,
+    i.name,
+    v.sku,
+    b.quantity,
+    COALESCE(v.price, i.price) AS price
+FROM bundle_items b
+JOIN items i ON b.item_id = i.id
+LEFT JOIN item_variants v ON b.variant_id = v.id
+WHERE b.bundle_id = ANY(sqlc.arg(bundle_ids)::int[])
+ORDER BY b.bundle_id, b.id;
+
+-- name: ListBundles :many
+-- Наборы, без фильтра - все, включая снятые с продажи
+SELECT * FROM bundles
+WHERE sqlc.narg(active)::boolean IS NULL OR active = sqlc.narg(active)::boolean
+ORDER BY id;
+
+-- name: SetBundleActive :one
+UPDATE bundles
+SET active = $2
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,124 @@
+-- name: AddCartItem :one
+-- Добавляет товар в корзину или увеличивает количество уже добавленного
+INSERT INTO cart_items (
+    user_id,
+    item_id,
+    variant_id,
+    quantity
+) VALUES (
+    $1, $2, $3, $4
+)
+ON CONFLICT (user_id, item_id, COALESCE(variant_id, 0)) DO UPDATE
+SET
+    quantity = cart_items.quantity + EXCLUDED.quantity,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: UpdateCartItemQuantity :execrows
+UPDATE cart_items
+SET
+    quantity = sqlc.arg(quantity),
+    updated_at = CURRENT_TIMESTAMP
+WHERE user_id = sqlc.arg(user_id)
+  AND item_id = sqlc.arg(item_id)
+  AND variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id);
+
+-- name: DeleteCartItem :execrows
+DELETE FROM cart_items
+WHERE user_id = sqlc.arg(user_id)
+  AND item_id = sqlc.arg(item_id)
+  AND variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id);
+
+-- name: DeleteCartItems :exec
+DELETE FROM cart_items
+WHERE user_id = sqlc.arg(user_id) AND id = ANY(sqlc.arg(ids)::int[]);
+
+-- name: ListCartItems :many
+SELECT
+    c.id,
+    c.item_id,
+    c.variant_id,
+    i.name,
+    v.sku,
+    COALESCE(v.price, i.price) AS price,
+    i.stock,
+    v.stock AS variant_stock,
+    c.quantity,
+    c.reserved_quantity,
+    c.reserved_until
+FROM cart_items c
+JOIN items i ON c.item_id = i.id
+LEFT JOIN item_variants v ON c.variant_id = v.id
+WHERE c.user_id = $1
+ORDER BY c.added_at, c.id;
+
+-- name: ListCartItemsForUpdate :many
+SELECT
+    c.id,
+    c.item_id,
+    c.variant_id,
+    i.name,
+    v.sku,
+    COALESCE(v.price, i.price) AS price,
+    i.stock,
+    v.stock AS variant_stock,
+    c.quantity,
+    i.max_per_user,
+    i.cooldown_days,
+    i.available_from,
+    i.available_until,
+    i.queue_minutes,
+    i.queue_rate,
+    i.category_id
+FROM cart_items c
+JOIN items i ON c.item_id = i.id
+LEFT JOIN item_variants v ON c.variant_id = v.id
+WHERE c.user_id = $1
+ORDER BY c.added_at, c.id
+FOR UPDATE OF c;
+
+-- name: ReserveCartItem :one
+-- Резервирует под позицию корзины reserved_quantity единиц. Срок активного резерва
+-- не продлевается: повторное добавление товара не удерживает остаток дольше TTL
+UPDATE cart_items
+SET
+    reserved_quantity = sqlc.arg(reserved_quantity),
+    reserved_until = CASE
+        WHEN reserved_quantity > 0 AND reserved_until > sqlc.arg(now)::timestamp THEN reserved_until
+        ELSE sqlc.arg(reserved_until)::timestamp
+    END
+WHERE user_id = sqlc.arg(user_id)
+  AND item_id = sqlc.arg(item_id)
+  AND variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id)
+RETURNING reserved_until;
+
+-- name: GetReservedStock :one
+-- Сколько единиц товара или варианта зарезервировано в корзинах других пользователей
+-- в момент now; user_id = 0 - в корзинах всех пользователей
+SELECT COALESCE(SUM(reserved_quantity), 0)::int AS reserved
+FROM cart_items
+WHERE item_id = sqlc.arg(item_id)
+  AND variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id)
+  AND user_id <> sqlc.arg(user_id)
+  AND reserved_quantity > 0
+  AND reserved_until > sqlc.arg(now);
+
+-- name: GetUserReservedQuantity :one
+-- Сколько единиц товара пользователь держит в активных резервах других позиций
+-- корзины, то есть других вариантов того же товара
+SELECT COALESCE(SUM(reserved_quantity), 0)::int AS reserved
+FROM cart_items
+WHERE user_id = sqlc.arg(user_id)
+  AND item_id = sqlc.arg(item_id)
+  AND variant_id IS DISTINCT FROM sqlc.narg(variant_id)
+  AND reserved_quantity > 0
+  AND reserved_until > sqlc.arg(now);
+
+-- name: ReleaseExpiredReservations :execrows
+-- Снимает резервы, срок которых истек к моменту now; позиции остаются в корзине
+UPDATE cart_items
+SET
+    reserved_quantity = 0,
+    reserved_until = NULL
+WHERE reserved_quantity > 0
+  AND reserved_until <= sqlc.arg(now);
@@ -0,0 +1,98 @@
+-- name: SearchItems :many
+-- Поиск по каталогу среди товаров, которые продаются в момент now: полнотекстовое
+-- совпадение по названию и описанию и фильтры; пустой фильтр не применяется.
+-- total_count - число найденных товаров без учета страницы, reserved - единицы,
+-- зарезервированные в корзинах
+SELECT
+    i.id,
+    i.name,
+    i.description,
+    i.price,
+    i.stock,
+    (SELECT COALESCE(SUM(r.reserved_quantity), 0)
+     FROM cart_items r
+     WHERE r.item_id = i.id
+       AND r.variant_id IS NULL
+       AND r.reserved_quantity > 0
+       AND r.reserved_until > sqlc.arg(now))::int AS reserved,
+    c.name AS category,
+    ARRAY(SELECT t.tag FROM item_tags t WHERE t.item_id = i.id ORDER BY t.tag)::text[] AS tags,
+    COUNT(*) OVER () AS total_count
+FROM items i
+LEFT JOIN categories c ON i.category_id = c.id
+WHERE (sqlc.narg(query)::text IS NULL
+       OR to_tsvector('simple', i.name || ' ' || COALESCE(i.description, '')) @@ plainto_tsquery('simple', sqlc.narg(query)::text))
+  AND (sqlc.narg(category)::text IS NULL OR c.name = sqlc.narg(category)::text)
+  AND (sqlc.narg(tag)::text IS NULL
+       OR EXISTS (SELECT 1 FROM item_tags ft WHERE ft.item_id = i.id AND ft.tag = sqlc.narg(tag)::text))
+  AND (sqlc.narg(min_price)::int IS NULL OR i.price >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR i.price <= sqlc.narg(max_price)::int)
+  AND (i.available_from IS NULL OR i.available_from <= sqlc.arg(now))
+  AND (i.available_until IS NULL OR i.available_until > sqlc.arg(now))
+ORDER BY
+    ts_rank(to_tsvector('simple', i.name || ' ' || COALESCE(i.description, '')),
+            plainto_tsquery('simple', COALESCE(sqlc.narg(query)::text, ''))) DESC,
+    i.name
+LIMIT sqlc.arg(row_limit)::int
+OFFSET sqlc.arg(row_offset)::int;
+
+-- name: CountSearchItemsByCategory :many
+-- Количество найденных товаров по категориям; фильтр категории не передается,
+-- чтобы были видны соседние категории
+SELECT
+    c.name AS category,
+    COUNT(*)::int AS items
+FROM items i
+JOIN categories c ON i.category_id = c.id
+WHERE (sqlc.narg(query)::text IS NULL
+       OR to_tsvector('simple', i.name || ' ' || COALESCE(i.description, '')) @@ plainto_tsquery('simple', sqlc.narg(query)::text))
+  AND (sqlc.narg(tag)::text IS NULL
+       OR EXISTS (SELECT 1 FROM item_tags ft WHERE ft.item_id = i.id AND ft.tag = sqlc.narg(tag)::text))
+  AND (sqlc.narg(min_price)::int IS NULL OR i.price >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR i.price <= sqlc.narg(max_price)::int)
+  AND (i.available_from IS NULL OR i.available_from <= sqlc.arg(now))
+  AND (i.available_until IS NULL OR i.available_until > sqlc.arg(now))
+GROUP BY c.name
+ORDER BY items DESC, c.name;
+
+-- name: CountSearchItemsByTag :many
+-- Количество найденных товаров по тегам; фильтр тега не передается
+SELECT
+    t.tag,
+    COUNT(*)::int AS items
+FROM items i
+JOIN item_tags t ON t.item_id = i.id
+LEFT JOIN categories c ON i.category_id = c.id
+WHERE (sqlc.narg(query)::text IS NULL
+       OR to_tsvector('simple', i.name || ' ' || COALESCE(i.description, '')) @@ plainto_tsquery('simple', sqlc.narg(query)::text))
+  AND (sqlc.narg(category)::text IS NULL OR c.name = sqlc.narg(category)::text)
+  AND (sqlc.narg(min_price)::int IS NULL OR i.price >= sqlc.narg(min_price)::int)
+  AND (sqlc.narg(max_price)::int IS NULL OR i.price <= sqlc.narg(max_price)::int)
+  AND (i.available_from IS NULL OR i.available_from <= sqlc.arg(now))
+  AND (i.available_until IS NULL OR i.available_until > sqlc.arg(now))
+GROUP BY t.tag
+ORDER BY items DESC, t.tag;
+
+-- name: ListItemTags :many
+SELECT tag FROM item_tags
+WHERE item_id = $1
+ORDER BY tag;
+
+-- name: DeleteItemTags :exec
+DELETE FROM item_tags
+WHERE item_id = $1;
+
+-- name: AddItemTags :exec
+INSERT INTO item_tags (item_id, tag)
+SELECT sqlc.arg(item_id)::int, unnest(sqlc.arg(tags)::text[])
+ON CONFLICT (item_id, tag) DO NOTHING;
+
+-- name: UpdateItemCategory :exec
+UPDATE items
+SET category_id = sqlc.narg(category_id)
+WHERE id = sqlc.arg(id);
+
+-- name: UpdateItemDescription :exec
+UPDATE items
+SET description = sqlc.narg(description)
+WHERE id = sqlc.arg(id);
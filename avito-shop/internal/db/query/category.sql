@@ -0,0 +1,16 @@
+-- name: GetCategoryByID :one
+SELECT * FROM categories
+WHERE id = $1 LIMIT 1;
+
+-- name: GetCategoryByName :one
+SELECT * FROM categories
+WHERE name = $1 LIMIT 1;
+
+-- name: CreateCategory :one
+INSERT INTO categories (name)
+VALUES ($1)
+RETURNING *;
+
+-- name: ListCategories :many
+SELECT * FROM categories
+ORDER BY name;
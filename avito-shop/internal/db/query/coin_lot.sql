@@ -0,0 +1,72 @@
+-- name: CreateCoinLot :one
+INSERT INTO coin_lots (
+    user_id,
+    source_transaction_id,
+    amount,
+    remaining,
+    expires_at
+) VALUES (
+    $1, $2, $3, $3, $4
+) RETURNING *;
+
+-- name: ListSpendableCoinLots :many
+SELECT * FROM coin_lots
+WHERE user_id = sqlc.arg(user_id)
+  AND remaining > 0
+  AND (expires_at IS NULL OR expires_at > sqlc.arg(now))
+ORDER BY expires_at NULLS LAST, id
+FOR UPDATE;
+
+-- name: GetSpendableBalance :one
+-- Сумма непросроченных лотов пользователя - сколько монет он может потратить в момент now
+SELECT COALESCE(SUM(remaining), 0)::int AS balance
+FROM coin_lots
+WHERE user_id = sqlc.arg(user_id)
+  AND remaining > 0
+  AND (expires_at IS NULL OR expires_at > sqlc.arg(now));
+
+-- name: ConsumeCoinLot :exec
+UPDATE coin_lots
+SET remaining = remaining - sqlc.arg(amount)::int
+WHERE id = sqlc.arg(id);
+
+-- name: CreateTransactionLot :exec
+INSERT INTO transaction_lots (
+    transaction_id,
+    amount,
+    expires_at
+) VALUES (
+    $1, $2, $3
+);
+
+-- name: GetTransactionLots :many
+SELECT * FROM transaction_lots
+WHERE transaction_id = $1
+ORDER BY id;
+
+-- name: ListUsersWithExpiredLots :many
+SELECT DISTINCT user_id FROM coin_lots
+WHERE remaining > 0
+  AND expires_at <= sqlc.arg(now)
+ORDER BY user_id
+LIMIT sqlc.arg(max_users);
+
+-- name: ListExpiredCoinLots :many
+SELECT * FROM coin_lots
+WHERE user_id = sqlc.arg(user_id)
+  AND remaining > 0
+  AND expires_at <= sqlc.arg(now)
+ORDER BY expires_at, id
+FOR UPDATE;
+
+-- name: GetUpcomingExpirations :many
+SELECT
+    expires_at::date AS expires_on,
+    SUM(remaining)::int AS amount
+FROM coin_lots
+WHERE user_id = sqlc.arg(user_id)
+  AND remaining > 0
+  AND expires_at > sqlc.arg(now)
+GROUP BY expires_on
+ORDER BY expires_on
+LIMIT 10;
@@ -0,0 +1,45 @@
+-- name: UpdateItemAvailability :one
+UPDATE items
+SET
+    available_from = sqlc.narg(available_from),
+    available_until = sqlc.narg(available_until),
+    queue_minutes = sqlc.narg(queue_minutes),
+    queue_rate = sqlc.narg(queue_rate)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListUpcomingDrops :many
+-- Анонсы: товары, продажи которых начнутся после now
+SELECT
+    i.id,
+    i.name,
+    i.price,
+    c.name AS category,
+    i.available_from,
+    i.available_until,
+    i.queue_minutes
+FROM items i
+LEFT JOIN categories c ON i.category_id = c.id
+WHERE i.available_from > sqlc.arg(now)
+ORDER BY i.available_from, i.name;
+
+-- name: JoinDropQueue :execrows
+INSERT INTO drop_queue (item_id, user_id, position)
+VALUES ($1, $2, $3)
+ON CONFLICT (item_id, user_id) DO NOTHING;
+
+-- name: GetDropQueueRank :one
+-- Сколько участников очереди стоят перед пользователем
+SELECT (
+    SELECT COUNT(*)
+    FROM drop_queue o
+    WHERE o.item_id = q.item_id
+      AND (o.position, o.user_id) < (q.position, q.user_id)
+)::int AS ahead
+FROM drop_queue q
+WHERE q.item_id = $1
+  AND q.user_id = $2;
+
+-- name: CountDropQueue :one
+SELECT COUNT(*)::int AS queued FROM drop_queue
+WHERE item_id = $1;
@@ -0,0 +1,16 @@
+-- name: GetGifts :many
+-- Подарки, которые пользователь отправил или получил
+SELECT
+    p.id,
+    i.name,
+    p.quantity,
+    p.gift_message,
+    p.purchase_date,
+    buyer.username AS buyer_username,
+    recipient.username AS recipient_username
+FROM purchases p
+JOIN items i ON p.item_id = i.id
+JOIN users buyer ON p.buyer_id = buyer.id
+JOIN users recipient ON p.recipient_id = recipient.id
+WHERE p.buyer_id = $1 OR p.recipient_id = $1
+ORDER BY p.purchase_date DESC;
@@ -0,0 +1,140 @@
+-- name: CreatePurchaseUnits :exec
+-- Единицы новой покупки переходят во владение получателя подарка или покупателя
+INSERT INTO inventory_items (
+    owner_id,
+    item_id,
+    variant_id,
+    purchase_id,
+    acquired_via,
+    acquired_from
+)
+SELECT
+    COALESCE(p.recipient_id, p.buyer_id),
+    p.item_id,
+    p.variant_id,
+    p.id,
+    CASE WHEN p.recipient_id IS NULL THEN 'purchase' ELSE 'gift' END,
+    CASE WHEN p.recipient_id IS NULL THEN NULL ELSE p.buyer_id END
+FROM purchases p
+CROSS JOIN LATERAL generate_series(1, p.quantity - p.refunded_quantity)
+WHERE p.id = $1;
+
+-- name: RemovePurchaseUnits :execrows
+-- Изымает возвращаемые единицы покупки, которые все еще у ее владельца и не выставлены на продажу
+DELETE FROM inventory_items
+WHERE id IN (
+    SELECT u.id
+    FROM inventory_items u
+    JOIN purchases p ON u.purchase_id = p.id
+    WHERE u.purchase_id = sqlc.arg(purchase_id)
+      AND u.owner_id = COALESCE(p.recipient_id, p.buyer_id)
+      AND NOT EXISTS (
+          SELECT 1 FROM marketplace_listings l
+          WHERE l.inventory_item_id = u.id AND l.status = 'active'
+      )
+    ORDER BY u.id
+    LIMIT sqlc.arg(quantity)::int
+    FOR UPDATE OF u
+);
+
+-- name: ListTransferableUnits :many
+-- Единицы, которые владелец может передать: заказ, с которым они пришли, уже выдан,
+-- и единица не выставлена на продажу. Первыми передаются полученные раньше
+SELECT u.id
+FROM inventory_items u
+LEFT JOIN purchases p ON u.purchase_id = p.id
+LEFT JOIN orders o ON p.order_id = o.id
+WHERE u.owner_id = sqlc.arg(owner_id)
+  AND u.item_id = sqlc.arg(item_id)
+  AND u.variant_id IS NOT DISTINCT FROM sqlc.narg(variant_id)
+  AND (o.id IS NULL OR o.status = 'delivered')
+  AND NOT EXISTS (
+      SELECT 1 FROM marketplace_listings l
+      WHERE l.inventory_item_id = u.id AND l.status = 'active'
+  )
+ORDER BY u.acquired_at, u.id
+LIMIT sqlc.arg(row_limit)::int
+FOR UPDATE OF u;
+
+-- name: TransferInventoryUnits :exec
+UPDATE inventory_items
+SET
+    owner_id = sqlc.arg(owner_id),
+    acquired_via = sqlc.arg(acquired_via),
+    acquired_from = sqlc.arg(acquired_from),
+    acquired_at = CURRENT_TIMESTAMP
+WHERE id = ANY(sqlc.arg(ids)::int[]);
+
+-- name: CreateInventoryTransfers :exec
+INSERT INTO inventory_transfers (
+    inventory_item_id,
+    from_user_id,
+    to_user_id,
+    kind,
+    message
+)
+SELECT
+    unit_id,
+    sqlc.arg(from_user_id)::int,
+    sqlc.arg(to_user_id)::int,
+    sqlc.arg(kind)::text,
+    sqlc.narg(message)::text
+FROM unnest(sqlc.arg(ids)::int[]) AS unit_id;
+
+-- name: GetInventory :many
+-- Текущие единицы пользователя по товарам, вариантам и статусам заказов, с которыми они пришли
+SELECT
+    i.name,
+    v.sku AS variant,
+    o.status AS order_status,
+    COUNT(*)::int AS quantity,
+    COUNT(u.acquired_from)::int AS received
+FROM inventory_items u
+JOIN items i ON u.item_id = i.id
+LEFT JOIN item_variants v ON u.variant_id = v.id
+LEFT JOIN purchases p ON u.purchase_id = p.id
+LEFT JOIN orders o ON p.order_id = o.id
+WHERE u.owner_id = $1
+GROUP BY i.name, v.sku, o.status
+ORDER BY MAX(u.acquired_at) DESC, i.name, v.sku;
+
+-- name: ListInventoryUnits :many
+-- Единицы пользователя с происхождением и активным объявлением о продаже
+SELECT
+    u.id,
+    i.name,
+    v.sku AS variant,
+    u.acquired_via,
+    f.username AS acquired_from,
+    u.acquired_at,
+    u.purchase_id,
+    o.status AS order_status,
+    l.id AS listing_id
+FROM inventory_items u
+JOIN items i ON u.item_id = i.id
+LEFT JOIN item_variants v ON u.variant_id = v.id
+LEFT JOIN users f ON u.acquired_from = f.id
+LEFT JOIN purchases p ON u.purchase_id = p.id
+LEFT JOIN orders o ON p.order_id = o.id
+LEFT JOIN marketplace_listings l ON l.inventory_item_id = u.id AND l.status = 'active'
+WHERE u.owner_id = $1
+ORDER BY u.acquired_at DESC, u.id DESC;
+
+-- name: GetInventoryItem :one
+SELECT * FROM inventory_items
+WHERE id = $1;
+
+-- name: ListInventoryTransfers :many
+-- Цепочка владельцев единицы от первой передачи к последней
+SELECT
+    t.id,
+    f.username AS from_user,
+    r.username AS to_user,
+    t.kind,
+    t.message,
+    t.created_at
+FROM inventory_transfers t
+LEFT JOIN users f ON t.from_user_id = f.id
+LEFT JOIN users r ON t.to_user_id = r.id
+WHERE t.inventory_item_id = $1
+ORDER BY t.created_at, t.id;
@@ -0,0 +1,28 @@
+-- name: GetUserItemPurchaseStats :one
+-- Сколько единиц товара пользователь получил без учета возвращенных: купил себе или в подарок коллеге, получил в подарок, передачей или на маркетплейсе. Отданные и проданные единицы остаются в счете, иначе ограничение обходится перепродажей. last_purchase_at - последняя покупка пользователя или для него
+WITH bought AS (
+    SELECT
+        COALESCE(SUM(quantity - refunded_quantity), 0) AS units,
+        MAX(purchase_date) FILTER (WHERE quantity > refunded_quantity) AS last_purchase_at
+    FROM purchases
+    WHERE item_id = sqlc.arg(item_id)
+      AND (buyer_id = sqlc.arg(user_id)::int OR recipient_id = sqlc.arg(user_id)::int)
+), received AS (
+    SELECT COUNT(*) AS units
+    FROM inventory_transfers t
+    JOIN inventory_items u ON t.inventory_item_id = u.id
+    WHERE t.to_user_id = sqlc.arg(user_id)::int
+      AND u.item_id = sqlc.arg(item_id)
+)
+SELECT
+    (bought.units + received.units)::int AS owned,
+    bought.last_purchase_at::timestamp AS last_purchase_at
+FROM bought, received;
+
+-- name: UpdateItemLimits :one
+UPDATE items
+SET
+    max_per_user = sqlc.narg(max_per_user),
+    cooldown_days = sqlc.narg(cooldown_days)
+WHERE id = sqlc.arg(id)
+RETURNING *;
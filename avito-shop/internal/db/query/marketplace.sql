@@ -0,0 +1,67 @@
+-- name: CreateListing :one
+INSERT INTO marketplace_listings (
+    seller_id,
+    inventory_item_id,
+    item_id,
+    variant_id,
+    price
+) VALUES (
+    $1, $2, $3, $4, $5
+) RETURNING *;
+
+-- name: GetListing :one
+SELECT * FROM marketplace_listings
+WHERE id = $1;
+
+-- name: GetListingForUpdate :one
+SELECT * FROM marketplace_listings
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SellListing :one
+UPDATE marketplace_listings
+SET
+    status = 'sold',
+    buyer_id = sqlc.arg(buyer_id),
+    commission = sqlc.arg(commission),
+    payment_id = sqlc.arg(payment_id),
+    closed_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CancelListing :one
+-- Снимает активное объявление продавца с продажи
+UPDATE marketplace_listings
+SET
+    status = 'cancelled',
+    closed_at = CURRENT_TIMESTAMP
+WHERE id = $1
+  AND seller_id = $2
+  AND status = 'active'
+RETURNING *;
+
+-- name: ListListings :many
+-- Объявления с продавцом и покупателем; без фильтра - все объявления, новые первыми
+SELECT
+    l.id,
+    l.seller_id,
+    s.username AS seller,
+    i.name AS item_name,
+    v.sku AS variant,
+    l.price,
+    l.status,
+    l.commission,
+    b.username AS buyer,
+    l.created_at,
+    l.closed_at
+FROM marketplace_listings l
+JOIN users s ON l.seller_id = s.id
+JOIN items i ON l.item_id = i.id
+LEFT JOIN item_variants v ON l.variant_id = v.id
+LEFT JOIN users b ON l.buyer_id = b.id
+WHERE (sqlc.narg(status)::text IS NULL OR l.status = sqlc.narg(status)::text)
+  AND (sqlc.narg(item_id)::int IS NULL OR l.item_id = sqlc.narg(item_id)::int)
+  AND (sqlc.narg(seller_id)::int IS NULL OR l.seller_id = sqlc.narg(seller_id)::int)
+ORDER BY l.created_at DESC, l.id DESC
+LIMIT sqlc.arg(row_limit)::int
+OFFSET sqlc.arg(row_offset)::int;
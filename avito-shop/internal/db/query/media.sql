@@ -0,0 +1,51 @@
+-- name: CreateItemImage :one
+-- Новое изображение встает в конец списка изображений товара
+INSERT INTO item_images (
+    item_id,
+    storage_key,
+    thumbnail_key,
+    content_type,
+    width,
+    height,
+    position,
+    created_by
+) VALUES (
+    sqlc.arg(item_id), sqlc.arg(storage_key), sqlc.arg(thumbnail_key), sqlc.arg(content_type),
+    sqlc.arg(width), sqlc.arg(height),
+    (SELECT COALESCE(MAX(position) + 1, 0) FROM item_images WHERE item_id = sqlc.arg(item_id)),
+    sqlc.narg(created_by)
+) RETURNING *;
+
+-- name: ListItemImages :many
+SELECT * FROM item_images
+WHERE item_id = $1
+ORDER BY position, id;
+
+-- name: ListImagesForItems :many
+SELECT * FROM item_images
+WHERE item_id = ANY(sqlc.arg(item_ids)::int[])
+ORDER BY item_id, position, id;
+
+-- name: DeleteItemImage :one
+DELETE FROM item_images
+WHERE id = sqlc.arg(id)
+  AND item_id = sqlc.arg(item_id)
+RETURNING *;
+
+-- name: ListItemAttributes :many
+SELECT * FROM item_attributes
+WHERE item_id = $1
+ORDER BY name;
+
+-- name: ListAttributesForItems :many
+SELECT * FROM item_attributes
+WHERE item_id = ANY(sqlc.arg(item_ids)::int[])
+ORDER BY item_id, name;
+
+-- name: DeleteItemAttributes :exec
+DELETE FROM item_attributes
+WHERE item_id = $1;
+
+-- name: AddItemAttributes :exec
+INSERT INTO item_attributes (item_id, name, value)
+SELECT sqlc.arg(item_id)::int, unnest(sqlc.arg(names)::text[]), unnest(sqlc.arg(values)::text[]);
@@ -0,0 +1,33 @@
+-- name: ListNotifications :many
+SELECT * FROM notifications
+WHERE user_id = sqlc.arg(user_id)
+  AND (NOT sqlc.arg(unread_only)::bool OR read_at IS NULL)
+ORDER BY id DESC
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)::int AS unread FROM notifications
+WHERE user_id = $1
+  AND read_at IS NULL;
+
+-- name: MarkNotificationRead :execrows
+UPDATE notifications
+SET read_at = COALESCE(read_at, NOW())
+WHERE id = $1
+  AND user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET read_at = NOW()
+WHERE user_id = $1
+  AND read_at IS NULL;
+
+-- name: CreateNotification :exec
+INSERT INTO notifications (
+    user_id,
+    kind,
+    item_id,
+    message
+) VALUES (
+    $1, $2, $3, $4
+);
@@ -0,0 +1,85 @@
+-- name: CreateOrder :one
+INSERT INTO orders (
+    user_id,
+    total_cost
+) VALUES (
+    $1, $2
+) RETURNING *;
+
+-- name: CreateOrderStatusChange :one
+INSERT INTO order_status_history (
+    order_id,
+    status,
+    note,
+    changed_by
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: GetOrderByID :one
+SELECT * FROM orders
+WHERE id = $1 LIMIT 1;
+
+-- name: GetOrderForUpdate :one
+SELECT * FROM orders
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: GetOrderStatusHistory :many
+SELECT
+    h.id,
+    h.status,
+    h.note,
+    u.username AS changed_by,
+    h.changed_at
+FROM order_status_history h
+LEFT JOIN users u ON h.changed_by = u.id
+WHERE h.order_id = $1
+ORDER BY h.changed_at, h.id;
+
+-- name: ListOrderLines :many
+-- Позиции заказов без возвращенных единиц, для выдачи
+SELECT
+    p.order_id,
+    i.name,
+    (p.quantity - p.refunded_quantity)::int AS quantity
+FROM purchases p
+JOIN items i ON p.item_id = i.id
+WHERE p.order_id = ANY(sqlc.arg(order_ids)::int[])
+ORDER BY p.order_id, p.id;
+
+-- name: ListOrderPurchasesForUpdate :many
+SELECT * FROM purchases
+WHERE order_id = $1
+ORDER BY id
+FOR UPDATE;
+
+-- name: ListOrders :many
+-- Заказы для выдачи, без статуса - все; сначала самые старые
+SELECT
+    o.id,
+    u.username,
+    o.total_cost,
+    o.status,
+    o.created_at,
+    o.status_updated_at,
+    b.name AS bundle
+FROM orders o
+JOIN users u ON o.user_id = u.id
+LEFT JOIN bundles b ON o.bundle_id = b.id
+WHERE sqlc.narg(status)::text IS NULL OR o.status = sqlc.narg(status)::text
+ORDER BY o.created_at, o.id
+LIMIT sqlc.arg(row_limit)::int OFFSET sqlc.arg(row_offset)::int;
+
+-- name: SetOrderBundle :exec
+UPDATE orders
+SET bundle_id = $2
+WHERE id = $1;
+
+-- name: UpdateOrderStatus :one
+UPDATE orders
+SET
+    status = $2,
+    status_updated_at = CURRENT_TIMESTAMP
+WHERE id = $1
+RETURNING *;
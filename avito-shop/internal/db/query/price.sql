@@ -0,0 +1,42 @@
+-- name: CreateItemPrice :one
+INSERT INTO item_prices (
+    item_id,
+    price,
+    effective_from,
+    created_by
+) VALUES (
+    $1, $2, $3, $4
+) RETURNING *;
+
+-- name: ListItemPrices :many
+SELECT * FROM item_prices
+WHERE item_id = $1
+ORDER BY effective_from DESC, id DESC;
+
+-- name: UpdateItemPrice :exec
+UPDATE items
+SET price = sqlc.arg(price)
+WHERE id = sqlc.arg(id);
+
+-- name: DeleteScheduledItemPrice :execrows
+-- Отменяет запланированное изменение цены; наступившие цены остаются в истории
+DELETE FROM item_prices
+WHERE id = sqlc.arg(id)
+  AND item_id = sqlc.arg(item_id)
+  AND effective_from > sqlc.arg(now);
+
+-- name: ApplyDueItemPrices :many
+-- Переносит в items.price последнюю наступившую цену каждого товара;
+-- возвращает только товары, цена которых изменилась, вместе с прежней ценой
+UPDATE items i
+SET price = due.price
+FROM (
+    SELECT DISTINCT ON (item_id) item_id, price
+    FROM item_prices
+    WHERE effective_from <= sqlc.arg(now)
+    ORDER BY item_id, effective_from DESC, id DESC
+) due, items old
+WHERE i.id = due.item_id
+  AND old.id = i.id
+  AND i.price <> due.price
+RETURNING i.id, i.name, old.price AS old_price, i.price;
@@ -0,0 +1,66 @@
+-- name: CreatePromoCode :one
+INSERT INTO promo_codes (
+    code,
+    description,
+    discount_type,
+    discount_value,
+    item_id,
+    category_id,
+    valid_from,
+    valid_until,
+    max_uses,
+    max_uses_per_user,
+    active,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
+) RETURNING *;
+
+-- name: GetPromoCode :one
+SELECT * FROM promo_codes
+WHERE code = $1 LIMIT 1;
+
+-- name: GetPromoCodeByID :one
+SELECT * FROM promo_codes
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPromoCodeForUpdate :one
+-- Блокирует промокод, чтобы параллельные покупки не превысили лимит использований
+SELECT * FROM promo_codes
+WHERE code = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListPromoCodes :many
+SELECT * FROM promo_codes
+ORDER BY created_at DESC, id DESC;
+
+-- name: UpdatePromoCode :one
+UPDATE promo_codes
+SET
+    description = $2,
+    discount_type = $3,
+    discount_value = $4,
+    item_id = $5,
+    category_id = $6,
+    valid_from = $7,
+    valid_until = $8,
+    max_uses = $9,
+    max_uses_per_user = $10,
+    active = $11
+WHERE id = $1
+RETURNING *;
+
+-- name: DeletePromoCode :execrows
+DELETE FROM promo_codes
+WHERE id = $1;
+
+-- name: IncrementPromoCodeUses :exec
+UPDATE promo_codes
+SET uses = uses + 1
+WHERE id = $1;
+
+-- name: CountUserPromoCodeUses :one
+-- Одно оформление корзины - одно использование, даже если скидка досталась нескольким позициям заказа
+SELECT COUNT(DISTINCT COALESCE(order_id, -id))::int AS uses
+FROM purchases
+WHERE promo_code_id = $1 AND buyer_id = $2;
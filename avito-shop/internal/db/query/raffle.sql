@@ -0,0 +1,107 @@
+-- name: CreateRaffle :one
+INSERT INTO raffles (
+    item_id,
+    ticket_price,
+    max_tickets_per_user,
+    winners,
+    sales_end_at,
+    seed_hash,
+    seed,
+    created_by
+) VALUES (
+    $1, $2, $3, $4, $5, $6, $7, $8
+) RETURNING *;
+
+-- name: GetRaffleForUpdate :one
+SELECT * FROM raffles
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListRaffles :many
+-- Розыгрыши с числом проданных билетов и билетов пользователя; без status - все розыгрыши
+SELECT
+    r.id,
+    r.item_id,
+    i.name AS item_name,
+    r.ticket_price,
+    r.max_tickets_per_user,
+    r.winners,
+    r.sales_end_at,
+    r.seed_hash,
+    r.seed,
+    r.status,
+    r.drawn_at,
+    (SELECT COUNT(*) FROM raffle_tickets t WHERE t.raffle_id = r.id)::int AS tickets,
+    (SELECT COUNT(*) FROM raffle_tickets t
+        WHERE t.raffle_id = r.id AND t.user_id = sqlc.arg(user_id))::int AS user_tickets
+FROM raffles r
+JOIN items i ON r.item_id = i.id
+WHERE sqlc.narg(status)::text IS NULL OR r.status = sqlc.narg(status)::text
+ORDER BY r.sales_end_at, r.id
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: GetRaffleSummary :one
+SELECT
+    r.id,
+    r.item_id,
+    i.name AS item_name,
+    r.ticket_price,
+    r.max_tickets_per_user,
+    r.winners,
+    r.sales_end_at,
+    r.seed_hash,
+    r.seed,
+    r.status,
+    r.drawn_at,
+    (SELECT COUNT(*) FROM raffle_tickets t WHERE t.raffle_id = r.id)::int AS tickets,
+    (SELECT COUNT(*) FROM raffle_tickets t
+        WHERE t.raffle_id = r.id AND t.user_id = sqlc.arg(user_id))::int AS user_tickets
+FROM raffles r
+JOIN items i ON r.item_id = i.id
+WHERE r.id = sqlc.arg(id);
+
+-- name: ListDueRaffles :many
+-- Розыгрыши, продажа билетов по которым закончилась, а победители еще не выбраны
+SELECT id FROM raffles
+WHERE status = 'open'
+  AND sales_end_at <= sqlc.arg(now)
+ORDER BY sales_end_at, id
+LIMIT sqlc.arg(row_limit)::int;
+
+-- name: DrawRaffle :exec
+UPDATE raffles
+SET
+    status = 'drawn',
+    drawn_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: CreateRaffleTicket :one
+INSERT INTO raffle_tickets (
+    raffle_id,
+    user_id,
+    transaction_id
+) VALUES (
+    $1, $2, $3
+) RETURNING *;
+
+-- name: CountUserRaffleTickets :one
+SELECT COUNT(*)::int AS tickets FROM raffle_tickets
+WHERE raffle_id = $1
+  AND user_id = $2;
+
+-- name: ListRaffleTickets :many
+-- Билеты в порядке номеров: по этому списку повторяется выбор победителей
+SELECT
+    t.id,
+    t.user_id,
+    u.username,
+    t.purchase_id
+FROM raffle_tickets t
+JOIN users u ON t.user_id = u.id
+WHERE t.raffle_id = $1
+ORDER BY t.id;
+
+-- name: SetRaffleTicketPurchase :exec
+UPDATE raffle_tickets
+SET purchase_id = $2
+WHERE id = $1;
@@ -0,0 +1,60 @@
+-- name: GetBalanceReconciliation :many
+-- Баланс каждого пользователя рядом с тем, что следует из журнала операций:
+-- полученные зачисления минус отправленные (включая удержанные) минус покупки
+-- минус монеты, зарезервированные ставками на аукционах
+SELECT
+    u.id,
+    u.username,
+    COALESCE(u.balance, 0)::int AS balance,
+    COALESCE(r.amount, 0)::int AS received,
+    COALESCE(s.amount, 0)::int AS sent,
+    COALESCE(p.amount, 0)::int AS spent,
+    COALESCE(b.amount, 0)::int AS reserved,
+    COALESCE(l.amount, 0)::int AS lot_balance
+FROM users u
+LEFT JOIN (
+    SELECT receiver_id AS user_id, SUM(amount) AS amount
+    FROM transactions
+    WHERE status = 'completed'
+    GROUP BY receiver_id
+) r ON r.user_id = u.id
+LEFT JOIN (
+    SELECT sender_id AS user_id, SUM(amount) AS amount
+    FROM transactions
+    WHERE status <> 'cancelled'
+    GROUP BY sender_id
+) s ON s.user_id = u.id
+LEFT JOIN (
+    SELECT buyer_id AS user_id, SUM(total_cost) AS amount
+    FROM purchases
+    GROUP BY buyer_id
+) p ON p.user_id = u.id
+LEFT JOIN (
+    SELECT user_id, SUM(amount) AS amount
+    FROM auction_bids
+    WHERE status = 'active'
+    GROUP BY user_id
+) b ON b.user_id = u.id
+LEFT JOIN (
+    SELECT user_id, SUM(remaining) AS amount
+    FROM coin_lots
+    GROUP BY user_id
+) l ON l.user_id = u.id
+WHERE sqlc.narg(user_id)::int IS NULL OR u.id = sqlc.narg(user_id)::int
+ORDER BY u.id;
+
+-- name: GetLedgerTotals :one
+-- Глобальный баланс монет: все, что выпущено системой, должно быть
+-- на счетах пользователей, в эскроу, зарезервировано ставками,
+-- потрачено на покупки или возвращено системе
+SELECT
+    (SELECT COALESCE(SUM(balance), 0) FROM users)::int AS total_balance,
+    (SELECT COALESCE(SUM(amount), 0) FROM transactions
+        WHERE sender_id IS NULL AND status = 'completed')::int AS issued,
+    (SELECT COALESCE(SUM(amount), 0) FROM transactions
+        WHERE receiver_id IS NULL AND status = 'completed')::int AS burned,
+    (SELECT COALESCE(SUM(total_cost), 0) FROM purchases)::int AS spent,
+    (SELECT COALESCE(SUM(amount), 0) FROM transactions
+        WHERE status = 'pending')::int AS in_escrow,
+    (SELECT COALESCE(SUM(amount), 0) FROM auction_bids
+        WHERE status = 'active')::int AS reserved;
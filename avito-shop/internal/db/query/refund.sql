@@ -0,0 +1,44 @@
+-- name: CreateRefund :one
+INSERT INTO transactions (
+    receiver_id,
+    amount,
+    kind,
+    reason_code,
+    comment,
+    created_by,
+    purchase_id
+) VALUES (
+    $1, $2, 'refund', $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetPurchaseByID :one
+SELECT * FROM purchases
+WHERE id = $1 LIMIT 1;
+
+-- name: GetPurchaseForUpdate :one
+SELECT * FROM purchases
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListUserPurchases :many
+SELECT
+    p.id,
+    i.name,
+    p.quantity,
+    p.refunded_quantity,
+    p.total_cost,
+    p.unit_price,
+    p.discount,
+    p.purchase_date
+FROM purchases p
+JOIN items i ON p.item_id = i.id
+WHERE p.buyer_id = $1
+ORDER BY p.purchase_date DESC, p.id DESC;
+
+-- name: RefundPurchase :one
+UPDATE purchases
+SET
+    refunded_quantity = refunded_quantity + sqlc.arg(quantity)::int,
+    refunded_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)
+RETURNING *;
@@ -0,0 +1,56 @@
+-- name: CreatePendingTransfer :one
+INSERT INTO transactions (
+    sender_id,
+    receiver_id,
+    amount,
+    status,
+    settles_at
+) VALUES (
+    $1, $2, $3, 'pending', $4
+) RETURNING *;
+
+-- name: GetTransferForUpdate :one
+SELECT * FROM transactions
+WHERE id = $1 LIMIT 1
+FOR UPDATE;
+
+-- name: ListDuePendingTransfers :many
+SELECT * FROM transactions
+WHERE status = 'pending'
+  AND settles_at <= $1
+ORDER BY settles_at
+LIMIT $2
+FOR UPDATE SKIP LOCKED;
+
+-- name: UpdateTransferStatus :exec
+UPDATE transactions
+SET
+    status = $2,
+    resolved_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: GetPendingTransfers :many
+SELECT
+    t.id,
+    t.amount,
+    t.settles_at,
+    sender.username as sender_username,
+    receiver.username as receiver_username
+FROM transactions t
+JOIN users sender ON t.sender_id = sender.id
+JOIN users receiver ON t.receiver_id = receiver.id
+WHERE (t.sender_id = $1 OR t.receiver_id = $1)
+  AND t.status = 'pending'
+ORDER BY t.settles_at;
+
+-- name: GetOutgoingTransferStats :one
+-- Исходящие переводы и оплаты объявлений маркетплейса: покупка у другого пользователя тоже передает ему монеты
+SELECT
+    COALESCE(SUM(amount) FILTER (WHERE timestamp >= sqlc.arg(day_start)), 0)::int AS daily_amount,
+    COALESCE(SUM(amount) FILTER (WHERE timestamp >= sqlc.arg(week_start)), 0)::int AS weekly_amount,
+    COUNT(*) FILTER (WHERE timestamp >= sqlc.arg(minute_start))::int AS minute_count
+FROM transactions
+WHERE sender_id = sqlc.arg(sender_id)
+  AND kind IN ('transfer', 'sale')
+  AND status <> 'cancelled'
+  AND timestamp >= sqlc.arg(week_start);
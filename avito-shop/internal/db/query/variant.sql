@@ -0,0 +1,39 @@
+-- name: CreateItemVariant :one
+INSERT INTO item_variants (
+    item_id,
+    sku,
+    size,
+    colour,
+    price,
+    stock
+) VALUES (
+    $1, $2, $3, $4, $5, $6
+) RETURNING *;
+
+-- name: GetItemVariantByID :one
+SELECT * FROM item_variants
+WHERE id = $1 LIMIT 1;
+
+-- name: ListItemVariants :many
+SELECT * FROM item_variants
+WHERE item_id = $1
+ORDER BY id;
+
+-- name: GetVariantStockForUpdate :one
+SELECT stock FROM item_variants
+WHERE id = $1
+FOR UPDATE;
+
+-- name: DecrementVariantStock :execrows
+-- Уменьшает остаток варианта, если он отслеживается; 0 строк - варианта не хватает
+UPDATE item_variants
+SET stock = stock - sqlc.arg(quantity)::int
+WHERE id = sqlc.arg(id)
+  AND stock IS NOT NULL
+  AND stock >= sqlc.arg(quantity)::int;
+
+-- name: RestoreVariantStock :exec
+UPDATE item_variants
+SET stock = stock + sqlc.arg(quantity)::int
+WHERE id = sqlc.arg(id)
+  AND stock IS NOT NULL;
@@ -0,0 +1,81 @@
+-- name: AddWishlistItem :execrows
+-- Товар, на который баланса уже хватает, не дает уведомления при добавлении.
+-- Баланс считается, как в GetSpendableBalance: по непросроченным лотам на момент now
+INSERT INTO wishlist_items (user_id, item_id, affordable_notified)
+SELECT u.id, i.id, (
+    SELECT COALESCE(SUM(l.remaining), 0)
+    FROM coin_lots l
+    WHERE l.user_id = u.id
+      AND l.remaining > 0
+      AND (l.expires_at IS NULL OR l.expires_at > sqlc.arg(now))
+) >= i.price
+FROM users u, items i
+WHERE u.id = sqlc.arg(user_id)
+  AND i.id = sqlc.arg(item_id)
+ON CONFLICT (user_id, item_id) DO NOTHING;
+
+-- name: RemoveWishlistItem :execrows
+DELETE FROM wishlist_items
+WHERE user_id = $1
+  AND item_id = $2;
+
+-- name: ListWishlistItems :many
+SELECT
+    i.id AS item_id,
+    i.name,
+    i.price,
+    i.stock,
+    w.created_at
+FROM wishlist_items w
+JOIN items i ON w.item_id = i.id
+WHERE w.user_id = $1
+ORDER BY w.created_at, i.name;
+
+-- name: CreatePriceDropNotifications :execrows
+-- Уведомляет всех, у кого товар в списке желаний, о снижении цены
+INSERT INTO notifications (user_id, kind, item_id, message)
+SELECT
+    w.user_id,
+    'price_drop',
+    w.item_id,
+    format('%s price dropped from %s to %s', i.name, sqlc.arg(old_price)::int, sqlc.arg(price)::int)
+FROM wishlist_items w
+JOIN items i ON w.item_id = i.id
+WHERE w.item_id = sqlc.arg(item_id);
+
+-- name: CreateAffordableNotifications :execrows
+-- Уведомляет о товарах из списков желаний, на которые непросроченных монет стало хватать,
+-- и отмечает их, чтобы не уведомлять повторно
+WITH covered AS (
+    UPDATE wishlist_items w
+    SET affordable_notified = TRUE
+    FROM items i
+    WHERE i.id = w.item_id
+      AND NOT w.affordable_notified
+      AND (
+          SELECT COALESCE(SUM(l.remaining), 0)
+          FROM coin_lots l
+          WHERE l.user_id = w.user_id
+            AND l.remaining > 0
+            AND (l.expires_at IS NULL OR l.expires_at > sqlc.arg(now))
+      ) >= i.price
+    RETURNING w.user_id, w.item_id, i.name
+)
+INSERT INTO notifications (user_id, kind, item_id, message)
+SELECT user_id, 'affordable', item_id, format('you now have enough coins for %s', name)
+FROM covered;
+
+-- name: ResetUncoveredWishlistItems :execrows
+-- Снимает отметку с товаров, на которые непросроченных монет снова не хватает
+UPDATE wishlist_items w
+SET affordable_notified = FALSE
+FROM items i
+WHERE i.id = w.item_id
+  AND w.affordable_notified
+  AND (
+      SELECT COALESCE(SUM(l.remaining), 0)
+      FROM coin_lots l
+      WHERE l.user_id = w.user_id
+        AND l.remaining > 0
+        AND (l.expires_at IS NULL OR l.expires_at > sqlc.arg(now))
+  ) < i.price;
@@ -17,7 +17,7 @@ INSERT INTO items (
     price
 ) VALUES (
     $1, $2
-) RETURNING id, name, price
+) RETURNING id, name, price, stock, category_id, max_per_user, cooldown_days, description, available_from, available_until, queue_minutes, queue_rate
 `
 
 type CreateItemParams struct {
@@ -28,7 +28,20 @@ type CreateItemParams struct {
 func (q *Queries) CreateItem(ctx context.Context, arg CreateItemParams) (Item, error) {
 	row := q.db.QueryRow(ctx, createItem, arg.Name, arg.Price)
 	var i Item
-	err := row.Scan(&i.ID, &i.Name, &i.Price)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.Stock,
+		&i.CategoryID,
+		&i.MaxPerUser,
+		&i.CooldownDays,
+		&i.Description,
+		&i.AvailableFrom,
+		&i.AvailableUntil,
+		&i.QueueMinutes,
+		&i.QueueRate,
+	)
 	return i, err
 }
 
@@ -37,17 +50,31 @@ INSERT INTO purchases (
     buyer_id,
     item_id,
     quantity,
-    total_cost
+    total_cost,
+    recipient_id,
+    gift_message,
+    order_id,
+    variant_id,
+    promo_code_id,
+    discount,
+    unit_price
 ) VALUES (
-    $1, $2, $3, $4
-) RETURNING id, buyer_id, item_id, quantity, total_cost, purchase_date
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, buyer_id, item_id, quantity, total_cost, purchase_date, refunded_quantity, refunded_at, recipient_id, gift_message, order_id, variant_id, promo_code_id, discount, unit_price
 `
 
 type CreatePurchaseParams struct {
-	BuyerID   pgtype.Int4 `json:"buyer_id"`
-	ItemID    pgtype.Int4 `json:"item_id"`
-	Quantity  int32       `json:"quantity"`
-	TotalCost int32       `json:"total_cost"`
+	BuyerID     pgtype.Int4 `json:"buyer_id"`
+	ItemID      pgtype.Int4 `json:"item_id"`
+	Quantity    int32       `json:"quantity"`
+	TotalCost   int32       `json:"total_cost"`
+	RecipientID pgtype.Int4 `json:"recipient_id"`
+	GiftMessage pgtype.Text `json:"gift_message"`
+	OrderID     pgtype.Int4 `json:"order_id"`
+	VariantID   pgtype.Int4 `json:"variant_id"`
+	PromoCodeID pgtype.Int4 `json:"promo_code_id"`
+	Discount    int32       `json:"discount"`
+	UnitPrice   int32       `json:"unit_price"`
 }
 
 func (q *Queries) CreatePurchase(ctx context.Context, arg CreatePurchaseParams) (Purchase, error) {
@@ -56,6 +83,13 @@ func (q *Queries) CreatePurchase(ctx context.Context, arg CreatePurchaseParams)
 		arg.ItemID,
 		arg.Quantity,
 		arg.TotalCost,
+		arg.RecipientID,
+		arg.GiftMessage,
+		arg.OrderID,
+		arg.VariantID,
+		arg.PromoCodeID,
+		arg.Discount,
+		arg.UnitPrice,
 	)
 	var i Purchase
 	err := row.Scan(
@@ -65,6 +99,15 @@ func (q *Queries) CreatePurchase(ctx context.Context, arg CreatePurchaseParams)
 		&i.Quantity,
 		&i.TotalCost,
 		&i.PurchaseDate,
+		&i.RefundedQuantity,
+		&i.RefundedAt,
+		&i.RecipientID,
+		&i.GiftMessage,
+		&i.OrderID,
+		&i.VariantID,
+		&i.PromoCodeID,
+		&i.Discount,
+		&i.UnitPrice,
 	)
 	return i, err
 }
@@ -76,7 +119,7 @@ INSERT INTO transactions (
     amount
 ) VALUES (
     $1, $2, $3
-) RETURNING id, sender_id, receiver_id, amount, timestamp
+) RETURNING id, sender_id, receiver_id, amount, timestamp, status, settles_at, resolved_at, kind, reason_code, comment, created_by, purchase_id
 `
 
 type CreateTransferParams struct {
@@ -94,6 +137,14 @@ func (q *Queries) CreateTransfer(ctx context.Context, arg CreateTransferParams)
 		&i.ReceiverID,
 		&i.Amount,
 		&i.Timestamp,
+		&i.Status,
+		&i.SettlesAt,
+		&i.ResolvedAt,
+		&i.Kind,
+		&i.ReasonCode,
+		&i.Comment,
+		&i.CreatedBy,
+		&i.PurchaseID,
 	)
 	return i, err
 }
@@ -102,19 +153,21 @@ const createUser = `-- name: CreateUser :one
 INSERT INTO users (
   username,
   password_hash,
-  balance
+  balance,
+  department
 ) VALUES (
-  $1, $2, 1000
-) RETURNING id, username, password_hash, balance, created_at, updated_at
+  $1, $2, 0, $3
+) RETURNING id, username, password_hash, balance, created_at, updated_at, role, department
 `
 
 type CreateUserParams struct {
-	Username     string `json:"username"`
-	PasswordHash string `json:"password_hash"`
+	Username     string      `json:"username"`
+	PasswordHash string      `json:"password_hash"`
+	Department   pgtype.Text `json:"department"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRow(ctx, createUser, arg.Username, arg.PasswordHash)
+	row := q.db.QueryRow(ctx, createUser, arg.Username, arg.PasswordHash, arg.Department)
 	var i User
 	err := row.Scan(
 		&i.ID,
@@ -123,10 +176,34 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.Department,
 	)
 	return i, err
 }
 
+const decrementItemStock = `-- name: DecrementItemStock :execrows
+UPDATE items
+SET stock = stock - $1::int
+WHERE id = $2
+  AND stock IS NOT NULL
+  AND stock >= $1::int
+`
+
+type DecrementItemStockParams struct {
+	Quantity int32 `json:"quantity"`
+	ID       int32 `json:"id"`
+}
+
+// Уменьшает остаток, если он отслеживается; 0 строк - товара не хватает
+func (q *Queries) DecrementItemStock(ctx context.Context, arg DecrementItemStockParams) (int64, error) {
+	result, err := q.db.Exec(ctx, decrementItemStock, arg.Quantity, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getCurrentBalance = `-- name: GetCurrentBalance :one
 SELECT balance 
 FROM users 
@@ -141,44 +218,124 @@ func (q *Queries) GetCurrentBalance(ctx context.Context, id int32) (pgtype.Int4,
 }
 
 const getItemByID = `-- name: GetItemByID :one
-SELECT id, name, price FROM items
+SELECT id, name, price, stock, category_id, max_per_user, cooldown_days, description, available_from, available_until, queue_minutes, queue_rate FROM items
 WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetItemByID(ctx context.Context, id int32) (Item, error) {
 	row := q.db.QueryRow(ctx, getItemByID, id)
 	var i Item
-	err := row.Scan(&i.ID, &i.Name, &i.Price)
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.Stock,
+		&i.CategoryID,
+		&i.MaxPerUser,
+		&i.CooldownDays,
+		&i.Description,
+		&i.AvailableFrom,
+		&i.AvailableUntil,
+		&i.QueueMinutes,
+		&i.QueueRate,
+	)
 	return i, err
 }
 
 const getItemByName = `-- name: GetItemByName :one
-SELECT id, name, price FROM items
-WHERE name = $1 LIMIT 1
+SELECT
+    i.id,
+    i.name,
+    i.price,
+    i.stock,
+    i.category_id,
+    v.id AS variant_id,
+    v.sku,
+    v.price AS variant_price,
+    v.stock AS variant_stock,
+    EXISTS (SELECT 1 FROM item_variants iv WHERE iv.item_id = i.id) AS has_variants
+FROM items i
+LEFT JOIN item_variants v ON v.item_id = i.id AND v.sku = $1
+WHERE i.name = $2
+LIMIT 1
 `
 
-func (q *Queries) GetItemByName(ctx context.Context, name string) (Item, error) {
-	row := q.db.QueryRow(ctx, getItemByName, name)
-	var i Item
-	err := row.Scan(&i.ID, &i.Name, &i.Price)
+type GetItemByNameParams struct {
+	Sku  pgtype.Text `json:"sku"`
+	Name string      `json:"name"`
+}
+
+type GetItemByNameRow struct {
+	ID           int32       `json:"id"`
+	Name         string      `json:"name"`
+	Price        int32       `json:"price"`
+	Stock        pgtype.Int4 `json:"stock"`
+	CategoryID   pgtype.Int4 `json:"category_id"`
+	VariantID    pgtype.Int4 `json:"variant_id"`
+	Sku          pgtype.Text `json:"sku"`
+	VariantPrice pgtype.Int4 `json:"variant_price"`
+	VariantStock pgtype.Int4 `json:"variant_stock"`
+	HasVariants  bool        `json:"has_variants"`
+}
+
+// Находит товар и, если задан артикул, его вариант; has_variants - покупка возможна только с вариантом
+func (q *Queries) GetItemByName(ctx context.Context, arg GetItemByNameParams) (GetItemByNameRow, error) {
+	row := q.db.QueryRow(ctx, getItemByName, arg.Sku, arg.Name)
+	var i GetItemByNameRow
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.Price,
+		&i.Stock,
+		&i.CategoryID,
+		&i.VariantID,
+		&i.Sku,
+		&i.VariantPrice,
+		&i.VariantStock,
+		&i.HasVariants,
+	)
 	return i, err
 }
 
+const getItemStockForUpdate = `-- name: GetItemStockForUpdate :one
+SELECT stock FROM items
+WHERE id = $1
+FOR UPDATE
+`
+
+// Блокирует остаток товара до конца транзакции, чтобы резервы корзин не менялись
+// между проверкой и списанием
+func (q *Queries) GetItemStockForUpdate(ctx context.Context, id int32) (pgtype.Int4, error) {
+	row := q.db.QueryRow(ctx, getItemStockForUpdate, id)
+	var stock pgtype.Int4
+	err := row.Scan(&stock)
+	return stock, err
+}
+
 const getPurchases = `-- name: GetPurchases :many
 SELECT 
     i.name,
-    p.quantity,
-    p.purchase_date
+    (p.quantity - p.refunded_quantity)::int AS quantity,
+    p.refunded_quantity,
+    p.purchase_date,
+    o.status AS order_status,
+    v.sku AS variant
 FROM purchases p
 JOIN items i ON p.item_id = i.id
-WHERE p.buyer_id = $1
+LEFT JOIN orders o ON p.order_id = o.id
+LEFT JOIN item_variants v ON p.variant_id = v.id
+WHERE (p.buyer_id = $1 AND p.recipient_id IS NULL)
+   OR p.recipient_id = $1
 ORDER BY p.purchase_date DESC
 `
 
 type GetPurchasesRow struct {
-	Name         string           `json:"name"`
-	Quantity     int32            `json:"quantity"`
-	PurchaseDate pgtype.Timestamp `json:"purchase_date"`
+	Name             string           `json:"name"`
+	Quantity         int32            `json:"quantity"`
+	RefundedQuantity int32            `json:"refunded_quantity"`
+	PurchaseDate     pgtype.Timestamp `json:"purchase_date"`
+	OrderStatus      pgtype.Text      `json:"order_status"`
+	Variant          pgtype.Text      `json:"variant"`
 }
 
 func (q *Queries) GetPurchases(ctx context.Context, buyerID pgtype.Int4) ([]GetPurchasesRow, error) {
@@ -190,7 +347,14 @@ func (q *Queries) GetPurchases(ctx context.Context, buyerID pgtype.Int4) ([]GetP
 	items := []GetPurchasesRow{}
 	for rows.Next() {
 		var i GetPurchasesRow
-		if err := rows.Scan(&i.Name, &i.Quantity, &i.PurchaseDate); err != nil {
+		if err := rows.Scan(
+			&i.Name,
+			&i.Quantity,
+			&i.RefundedQuantity,
+			&i.PurchaseDate,
+			&i.OrderStatus,
+			&i.Variant,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -205,18 +369,21 @@ const getTransactions = `-- name: GetTransactions :many
 SELECT 
     t.timestamp,
     t.amount,
-    sender.username as sender_username,
-    receiver.username as receiver_username
+    t.kind,
+    COALESCE(sender.username, 'system')::text as sender_username,
+    COALESCE(receiver.username, 'system')::text as receiver_username
 FROM transactions t
-JOIN users sender ON t.sender_id = sender.id
-JOIN users receiver ON t.receiver_id = receiver.id
-WHERE t.sender_id = $1 OR t.receiver_id = $1
+LEFT JOIN users sender ON t.sender_id = sender.id
+LEFT JOIN users receiver ON t.receiver_id = receiver.id
+WHERE (t.sender_id = $1 OR t.receiver_id = $1)
+  AND t.status = 'completed'
 ORDER BY t.timestamp DESC
 `
 
 type GetTransactionsRow struct {
 	Timestamp        pgtype.Timestamp `json:"timestamp"`
 	Amount           int32            `json:"amount"`
+	Kind             string           `json:"kind"`
 	SenderUsername   string           `json:"sender_username"`
 	ReceiverUsername string           `json:"receiver_username"`
 }
@@ -233,6 +400,7 @@ func (q *Queries) GetTransactions(ctx context.Context, senderID pgtype.Int4) ([]
 		if err := rows.Scan(
 			&i.Timestamp,
 			&i.Amount,
+			&i.Kind,
 			&i.SenderUsername,
 			&i.ReceiverUsername,
 		); err != nil {
@@ -247,7 +415,7 @@ func (q *Queries) GetTransactions(ctx context.Context, senderID pgtype.Int4) ([]
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT id, username, password_hash, balance, created_at, updated_at
+SELECT id, username, password_hash, balance, created_at, updated_at, role, department
 FROM users
 WHERE id = $1 LIMIT 1
 `
@@ -262,6 +430,8 @@ func (q *Queries) GetUserByID(ctx context.Context, id int32) (User, error) {
 		&i.Balance,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Role,
+		&i.Department,
 	)
 	return i, err
 }
@@ -286,6 +456,117 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (GetUs
 	return i, err
 }
 
+const getUserRole = `-- name: GetUserRole :one
+SELECT role
+FROM users
+WHERE username = $1
+LIMIT 1
+`
+
+func (q *Queries) GetUserRole(ctx context.Context, username string) (string, error) {
+	row := q.db.QueryRow(ctx, getUserRole, username)
+	var role string
+	err := row.Scan(&role)
+	return role, err
+}
+
+const getUsersByUsernames = `-- name: GetUsersByUsernames :many
+SELECT id, username
+FROM users
+WHERE username = ANY($1::text[])
+ORDER BY username
+`
+
+type GetUsersByUsernamesRow struct {
+	ID       int32  `json:"id"`
+	Username string `json:"username"`
+}
+
+func (q *Queries) GetUsersByUsernames(ctx context.Context, usernames []string) ([]GetUsersByUsernamesRow, error) {
+	rows, err := q.db.Query(ctx, getUsersByUsernames, usernames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetUsersByUsernamesRow{}
+	for rows.Next() {
+		var i GetUsersByUsernamesRow
+		if err := rows.Scan(&i.ID, &i.Username); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUsers = `-- name: LockUsers :exec
+SELECT id FROM users
+WHERE id = ANY($1::int[])
+ORDER BY id
+FOR UPDATE
+`
+
+func (q *Queries) LockUsers(ctx context.Context, ids []int32) error {
+	_, err := q.db.Exec(ctx, lockUsers, ids)
+	return err
+}
+
+const restoreItemStock = `-- name: RestoreItemStock :exec
+UPDATE items
+SET stock = stock + $1::int
+WHERE id = $2
+  AND stock IS NOT NULL
+`
+
+type RestoreItemStockParams struct {
+	Quantity int32 `json:"quantity"`
+	ID       int32 `json:"id"`
+}
+
+func (q *Queries) RestoreItemStock(ctx context.Context, arg RestoreItemStockParams) error {
+	_, err := q.db.Exec(ctx, restoreItemStock, arg.Quantity, arg.ID)
+	return err
+}
+
+const setUserDepartment = `-- name: SetUserDepartment :exec
+UPDATE users
+SET
+    department = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+`
+
+type SetUserDepartmentParams struct {
+	Department pgtype.Text `json:"department"`
+	ID         int32       `json:"id"`
+}
+
+func (q *Queries) SetUserDepartment(ctx context.Context, arg SetUserDepartmentParams) error {
+	_, err := q.db.Exec(ctx, setUserDepartment, arg.Department, arg.ID)
+	return err
+}
+
+const updateBalance = `-- name: UpdateBalance :exec
+UPDATE users
+SET
+    balance = balance + $1::int,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2
+`
+
+type UpdateBalanceParams struct {
+	Amount int32 `json:"amount"`
+	ID     int32 `json:"id"`
+}
+
+func (q *Queries) UpdateBalance(ctx context.Context, arg UpdateBalanceParams) error {
+	_, err := q.db.Exec(ctx, updateBalance, arg.Amount, arg.ID)
+	return err
+}
+
 const updateBalanceForPurchase = `-- name: UpdateBalanceForPurchase :exec
 UPDATE users 
 SET 
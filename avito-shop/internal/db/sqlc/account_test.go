@@ -21,10 +21,27 @@ func createRandomUser(t *testing.T) User {
 
 	require.Equal(t, arg.Username, user.Username)
 	require.Equal(t, arg.PasswordHash, user.PasswordHash)
-	require.Equal(t, int32(1000), user.Balance.Int32) // Начальный баланс
+	require.Equal(t, int32(0), user.Balance.Int32) // Стартовый баланс выдается отдельным начислением
 	require.NotZero(t, user.CreatedAt)
 	require.NotZero(t, user.UpdatedAt)
 
+	// Пополняем баланс бессрочным лотом, чтобы пользователь мог переводить и покупать
+	_, err = testQueries.CreateCoinLot(context.Background(), CreateCoinLotParams{
+		UserID: user.ID,
+		Amount: 1000,
+	})
+	require.NoError(t, err)
+
+	err = testQueries.UpdateBalance(context.Background(), UpdateBalanceParams{
+		ID:     user.ID,
+		Amount: 1000,
+	})
+	require.NoError(t, err)
+
+	user, err = testQueries.GetUserByID(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1000), user.Balance.Int32)
+
 	return user
 }
 
@@ -133,6 +150,7 @@ func TestCreatePurchase(t *testing.T) {
 		ItemID:    pgtype.Int4{Int32: item.ID, Valid: true},
 		Quantity:  2,
 		TotalCost: item.Price * 2,
+		UnitPrice: item.Price,
 	}
 
 	purchase, err := testQueries.CreatePurchase(context.Background(), arg)
@@ -143,6 +161,7 @@ func TestCreatePurchase(t *testing.T) {
 	require.Equal(t, arg.ItemID, purchase.ItemID)
 	require.Equal(t, arg.Quantity, purchase.Quantity)
 	require.Equal(t, arg.TotalCost, purchase.TotalCost)
+	require.Equal(t, arg.UnitPrice, purchase.UnitPrice)
 	require.NotZero(t, purchase.PurchaseDate)
 }
 
@@ -258,7 +277,7 @@ func TestGetItemByID(t *testing.T) {
 
 func TestGetItemByName(t *testing.T) {
 	item1 := createRandomItem(t)
-	item2, err := testQueries.GetItemByName(context.Background(), item1.Name)
+	item2, err := testQueries.GetItemByName(context.Background(), GetItemByNameParams{Name: item1.Name})
 	require.NoError(t, err)
 	require.NotEmpty(t, item2)
 
@@ -0,0 +1,39 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAdjustmentShowsSystemInHistory(t *testing.T) {
+	admin := createRandomUser(t)
+	user := createRandomUser(t)
+
+	arg := CreateAdjustmentParams{
+		ReceiverID: pgtype.Int4{Int32: user.ID, Valid: true},
+		Amount:     250,
+		Kind:       "grant",
+		ReasonCode: pgtype.Text{String: "bonus", Valid: true},
+		Comment:    pgtype.Text{String: "quarterly bonus", Valid: true},
+		CreatedBy:  pgtype.Int4{Int32: admin.ID, Valid: true},
+	}
+
+	adjustment, err := testQueries.CreateAdjustment(context.Background(), arg)
+	require.NoError(t, err)
+	require.False(t, adjustment.SenderID.Valid)
+	require.Equal(t, arg.ReceiverID, adjustment.ReceiverID)
+	require.Equal(t, "grant", adjustment.Kind)
+	require.Equal(t, "completed", adjustment.Status)
+	require.Equal(t, arg.ReasonCode, adjustment.ReasonCode)
+
+	// Начисление без отправителя отображается в истории как перевод от system
+	transactions, err := testQueries.GetTransactions(context.Background(), pgtype.Int4{Int32: user.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+	require.Equal(t, "system", transactions[0].SenderUsername)
+	require.Equal(t, user.Username, transactions[0].ReceiverUsername)
+	require.Equal(t, arg.Amount, transactions[0].Amount)
+}
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type AdjustBalanceTxParams struct {
+	AdminID int32   `json:"admin_id"`
+	UserIDs []int32 `json:"user_ids"`
+	// Amount всегда положительный, направление задает Kind (grant или deduction)
+	Amount     int32  `json:"amount"`
+	Kind       string `json:"kind"`
+	ReasonCode string `json:"reason_code"`
+	Comment    string `json:"comment"`
+	// CoinTTL - срок действия начисленных монет, 0 - бессрочно
+	CoinTTL time.Duration `json:"coin_ttl"`
+}
+
+type AdjustBalanceTxResult struct {
+	Adjustments []Transaction `json:"adjustments"`
+	Users       []User        `json:"users"`
+}
+
+// AdjustBalanceTx начисляет или списывает монеты от имени системы сразу у нескольких
+// пользователей. Корректировка атомарна: если хотя бы у одного пользователя
+// не хватает монет для списания, не применяется ни одна.
+func (store *SQLStore) AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error) {
+	var result AdjustBalanceTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем всех пользователей в порядке id
+		err := q.LockUsers(ctx, arg.UserIDs)
+		if err != nil {
+			return fmt.Errorf("error locking users: %v", err)
+		}
+
+		now := time.Now()
+		for _, userID := range arg.UserIDs {
+			user := pgtype.Int4{Int32: userID, Valid: true}
+			params := CreateAdjustmentParams{
+				Amount:     arg.Amount,
+				Kind:       arg.Kind,
+				ReasonCode: pgtype.Text{String: arg.ReasonCode, Valid: true},
+				Comment:    pgtype.Text{String: arg.Comment, Valid: arg.Comment != ""},
+				CreatedBy:  pgtype.Int4{Int32: arg.AdminID, Valid: true},
+			}
+
+			switch arg.Kind {
+			case TransactionKindGrant:
+				params.ReceiverID = user
+			case TransactionKindDeduction:
+				params.SenderID = user
+
+				// 2. Списываем монеты из лотов; списание не может увести баланс в минус
+				_, err := q.debitCoins(ctx, userID, arg.Amount, now)
+				if err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("unsupported adjustment kind %q", arg.Kind)
+			}
+
+			// 3. Записываем корректировку от системного счета
+			adjustment, err := q.CreateAdjustment(ctx, params)
+			if err != nil {
+				return fmt.Errorf("error creating adjustment: %v", err)
+			}
+			result.Adjustments = append(result.Adjustments, adjustment)
+
+			// 4. Начисленные монеты образуют новый лот со своим сроком действия
+			if arg.Kind == TransactionKindGrant {
+				err = q.creditCoins(ctx, userID, adjustment.ID, []lotSlice{
+					{Amount: arg.Amount, ExpiresAt: CoinExpiresAt(now, arg.CoinTTL)},
+				})
+				if err != nil {
+					return err
+				}
+			}
+
+			updatedUser, err := q.GetUserByID(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("error getting user: %v", err)
+			}
+			result.Users = append(result.Users, updatedUser)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return AdjustBalanceTxResult{}, fmt.Errorf("adjust balance tx error: %w", err)
+	}
+
+	return result, nil
+}
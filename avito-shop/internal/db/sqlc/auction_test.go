@@ -0,0 +1,138 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinAuctionBid(t *testing.T) {
+	require.Equal(t, int32(1), MinAuctionBid(pgtype.Int4{}, 50))
+	require.Equal(t, int32(150), MinAuctionBid(pgtype.Int4{Int32: 100, Valid: true}, 50))
+}
+
+func TestAuctionAcceptsBids(t *testing.T) {
+	start := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	auction := Auction{
+		StartsAt: pgtype.Timestamp{Time: start, Valid: true},
+		EndsAt:   pgtype.Timestamp{Time: start.Add(time.Hour), Valid: true},
+		Status:   AuctionStatusActive,
+	}
+
+	require.False(t, auction.AcceptsBids(start.Add(-time.Second)))
+	require.True(t, auction.AcceptsBids(start))
+	require.True(t, auction.AcceptsBids(start.Add(59*time.Minute)))
+	require.False(t, auction.AcceptsBids(start.Add(time.Hour)))
+
+	auction.Status = AuctionStatusCancelled
+	require.False(t, auction.AcceptsBids(start))
+}
+
+func TestBidTooLowError(t *testing.T) {
+	err := error(&BidTooLowError{MinBid: 120})
+	require.ErrorIs(t, err, ErrBidTooLow)
+	require.Equal(t, "bid must be at least 120", err.Error())
+}
+
+func createRandomAuction(t *testing.T, item Item, reservePrice int32, now time.Time) Auction {
+	auction, err := testQueries.CreateAuction(context.Background(), CreateAuctionParams{
+		ItemID:       item.ID,
+		StartsAt:     pgtype.Timestamp{Time: now.Add(-time.Hour), Valid: true},
+		EndsAt:       pgtype.Timestamp{Time: now.Add(time.Hour), Valid: true},
+		ReservePrice: reservePrice,
+		MinIncrement: 10,
+	})
+	require.NoError(t, err)
+	require.Equal(t, AuctionStatusActive, auction.Status)
+	return auction
+}
+
+func TestAuctionBidAndSettle(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	now := time.Now()
+
+	item := createRandomItem(t)
+	first := createRandomUser(t)
+	second := createRandomUser(t)
+	auction := createRandomAuction(t, item, 150, now)
+
+	// Ставка резервирует монеты на балансе
+	result, err := store.PlaceBidTx(ctx, PlaceBidTxParams{AuctionID: auction.ID, UserID: first.ID, Amount: 100, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, int32(900), result.User.Balance.Int32)
+
+	// Шаг аукциона - 10 монет
+	_, err = store.PlaceBidTx(ctx, PlaceBidTxParams{AuctionID: auction.ID, UserID: second.ID, Amount: 105, Now: now})
+	var lowErr *BidTooLowError
+	require.True(t, errors.As(err, &lowErr))
+	require.Equal(t, int32(110), lowErr.MinBid)
+
+	// Перебитая ставка возвращается участнику
+	result, err = store.PlaceBidTx(ctx, PlaceBidTxParams{AuctionID: auction.ID, UserID: second.ID, Amount: 200, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, int32(800), result.User.Balance.Int32)
+
+	refunded, err := testQueries.GetUserByID(ctx, first.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1000), refunded.Balance.Int32)
+
+	notifications, err := testQueries.ListNotifications(ctx, ListNotificationsParams{UserID: first.ID, RowLimit: 10})
+	require.NoError(t, err)
+	require.Len(t, notifications, 1)
+	require.Equal(t, NotificationKindOutbid, notifications[0].Kind)
+
+	// До окончания аукциона итог не подводится
+	settled, err := store.SettleAuctionTx(ctx, SettleAuctionTxParams{AuctionID: auction.ID, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, AuctionStatusActive, settled.Auction.Status)
+
+	// Победитель получает товар за зарезервированные монеты
+	settled, err = store.SettleAuctionTx(ctx, SettleAuctionTxParams{AuctionID: auction.ID, Now: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, AuctionStatusSettled, settled.Auction.Status)
+	require.Equal(t, int32(200), settled.Purchase.TotalCost)
+	require.Equal(t, second.ID, settled.Purchase.BuyerID.Int32)
+
+	winner, err := testQueries.GetUserByID(ctx, second.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(800), winner.Balance.Int32)
+
+	// Баланс победителя сходится с журналом: резерв превратился в покупку
+	rows, err := testQueries.GetBalanceReconciliation(ctx, pgtype.Int4{Int32: second.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Zero(t, rows[0].Reserved)
+	require.Equal(t, int32(200), rows[0].Spent)
+
+	// Повторное подведение итога ничего не меняет
+	settled, err = store.SettleAuctionTx(ctx, SettleAuctionTxParams{AuctionID: auction.ID, Now: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, AuctionStatusSettled, settled.Auction.Status)
+	require.Zero(t, settled.Purchase.ID)
+}
+
+func TestAuctionBelowReserve(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	now := time.Now()
+
+	item := createRandomItem(t)
+	bidder := createRandomUser(t)
+	auction := createRandomAuction(t, item, 500, now)
+
+	_, err := store.PlaceBidTx(ctx, PlaceBidTxParams{AuctionID: auction.ID, UserID: bidder.ID, Amount: 300, Now: now})
+	require.NoError(t, err)
+
+	settled, err := store.SettleAuctionTx(ctx, SettleAuctionTxParams{AuctionID: auction.ID, Now: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, AuctionStatusUnsold, settled.Auction.Status)
+
+	user, err := testQueries.GetUserByID(ctx, bidder.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1000), user.Balance.Int32)
+}
@@ -0,0 +1,420 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Статусы аукциона
+const (
+	AuctionStatusActive    = "active"
+	AuctionStatusSettled   = "settled"
+	AuctionStatusUnsold    = "unsold"
+	AuctionStatusCancelled = "cancelled"
+)
+
+// Статусы ставки
+const (
+	AuctionBidStatusActive   = "active"
+	AuctionBidStatusReleased = "released"
+	AuctionBidStatusWon      = "won"
+)
+
+// Виды уведомлений участников аукциона
+const (
+	NotificationKindOutbid        = "outbid"
+	NotificationKindAuctionWon    = "auction_won"
+	NotificationKindAuctionClosed = "auction_closed"
+)
+
+// Ошибки аукционов
+var (
+	ErrAuctionNotFound = errors.New("auction not found")
+	ErrAuctionClosed   = errors.New("auction is not accepting bids")
+	ErrBidTooLow       = errors.New("bid is too low")
+)
+
+// BidTooLowError - ставка меньше минимально допустимой
+type BidTooLowError struct {
+	MinBid int32
+}
+
+func (e *BidTooLowError) Error() string {
+	return fmt.Sprintf("bid must be at least %d", e.MinBid)
+}
+
+func (e *BidTooLowError) Unwrap() error {
+	return ErrBidTooLow
+}
+
+// MinAuctionBid - минимальная ставка: любая положительная, пока ставок нет,
+// иначе лидирующая ставка плюс шаг аукциона
+func MinAuctionBid(leading pgtype.Int4, minIncrement int32) int32 {
+	if !leading.Valid {
+		return 1
+	}
+	return leading.Int32 + minIncrement
+}
+
+// AcceptsBids сообщает, принимает ли аукцион ставки в момент now
+func (auction Auction) AcceptsBids(now time.Time) bool {
+	return auction.Status == AuctionStatusActive &&
+		!now.Before(auction.StartsAt.Time) &&
+		now.Before(auction.EndsAt.Time)
+}
+
+type PlaceBidTxParams struct {
+	AuctionID int32     `json:"auction_id"`
+	UserID    int32     `json:"user_id"`
+	Amount    int32     `json:"amount"`
+	Now       time.Time `json:"now"`
+}
+
+type PlaceBidTxResult struct {
+	Auction Auction    `json:"auction"`
+	Bid     AuctionBid `json:"bid"`
+	User    User       `json:"user"`
+}
+
+// PlaceBidTx принимает ставку и резервирует ее сумму на балансе участника.
+// Предыдущая лидирующая ставка освобождается, а ее участник получает уведомление;
+// лидер может повысить собственную ставку, резерв при этом пересчитывается.
+func (store *SQLStore) PlaceBidTx(ctx context.Context, arg PlaceBidTxParams) (PlaceBidTxResult, error) {
+	var result PlaceBidTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем аукцион: ставки по нему принимаются строго по очереди
+		auction, err := q.getAuctionForUpdate(ctx, arg.AuctionID)
+		if err != nil {
+			return err
+		}
+		if !auction.AcceptsBids(arg.Now) {
+			return ErrAuctionClosed
+		}
+
+		// 2. Проверяем шаг относительно лидирующей ставки
+		leading, ok, err := q.leadingBid(ctx, auction.ID)
+		if err != nil {
+			return err
+		}
+		leadingAmount := pgtype.Int4{Int32: leading.Amount, Valid: ok}
+		if minBid := MinAuctionBid(leadingAmount, auction.MinIncrement); arg.Amount < minBid {
+			return &BidTooLowError{MinBid: minBid}
+		}
+
+		// 3. Блокируем участника и прежнего лидера в порядке id
+		userIDs := []int32{arg.UserID}
+		if ok && leading.UserID != arg.UserID {
+			userIDs = append(userIDs, leading.UserID)
+		}
+		err = q.LockUsers(ctx, userIDs)
+		if err != nil {
+			return fmt.Errorf("error locking users: %v", err)
+		}
+
+		// 4. Освобождаем прежнюю ставку до резерва новой: лидер, повышающий
+		// ставку, может использовать уже зарезервированные монеты
+		if ok {
+			item, err := q.GetItemByID(ctx, auction.ItemID)
+			if err != nil {
+				return fmt.Errorf("error getting item: %v", err)
+			}
+			message := ""
+			if leading.UserID != arg.UserID {
+				message = fmt.Sprintf("Your bid of %d on %s was outbid, the coins are back on your balance", leading.Amount, item.Name)
+			}
+			err = q.releaseBid(ctx, leading, item.ID, NotificationKindOutbid, message)
+			if err != nil {
+				return err
+			}
+		}
+
+		// 5. Резервируем монеты новой ставки
+		slices, err := q.debitCoins(ctx, arg.UserID, arg.Amount, arg.Now)
+		if err != nil {
+			return err
+		}
+		result.Bid, err = q.CreateAuctionBid(ctx, CreateAuctionBidParams{
+			AuctionID: auction.ID,
+			UserID:    arg.UserID,
+			Amount:    arg.Amount,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating bid: %v", err)
+		}
+		for _, slice := range slices {
+			err = q.CreateAuctionBidLot(ctx, CreateAuctionBidLotParams{
+				BidID:     result.Bid.ID,
+				Amount:    slice.Amount,
+				ExpiresAt: slice.ExpiresAt,
+			})
+			if err != nil {
+				return fmt.Errorf("error reserving coin lot: %v", err)
+			}
+		}
+
+		result.Auction = auction
+		result.User, err = q.GetUserByID(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return PlaceBidTxResult{}, fmt.Errorf("place bid tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+type SettleAuctionTxParams struct {
+	AuctionID int32     `json:"auction_id"`
+	Now       time.Time `json:"now"`
+}
+
+type SettleAuctionTxResult struct {
+	// Auction - аукцион после подведения итога; статус active означает,
+	// что прием ставок еще не закончился
+	Auction Auction `json:"auction"`
+	// Bid и Purchase заполнены, если товар продан победителю
+	Bid      AuctionBid `json:"bid"`
+	Purchase Purchase   `json:"purchase"`
+}
+
+// SettleAuctionTx подводит итог закончившегося аукциона. Лидирующая ставка не ниже
+// резервной цены превращается в покупку: зарезервированные монеты становятся ее оплатой,
+// товар списывается со склада, создается заказ. Иначе ставка освобождается,
+// а аукцион завершается без продажи. Уже закрытый аукцион не меняется.
+func (store *SQLStore) SettleAuctionTx(ctx context.Context, arg SettleAuctionTxParams) (SettleAuctionTxResult, error) {
+	var result SettleAuctionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем аукцион, чтобы итог не подвели дважды
+		auction, err := q.getAuctionForUpdate(ctx, arg.AuctionID)
+		if err != nil {
+			return err
+		}
+		result.Auction = auction
+		if auction.Status != AuctionStatusActive || arg.Now.Before(auction.EndsAt.Time) {
+			return nil
+		}
+
+		leading, ok, err := q.leadingBid(ctx, auction.ID)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return q.closeAuction(ctx, &result.Auction, AuctionStatusUnsold, pgtype.Int4{})
+		}
+
+		err = q.LockUsers(ctx, []int32{leading.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		item, err := q.GetItemByID(ctx, auction.ItemID)
+		if err != nil {
+			return fmt.Errorf("error getting item: %v", err)
+		}
+
+		// 2. Ставка ниже резервной цены или товар закончился - продажи нет
+		sold := leading.Amount >= auction.ReservePrice
+		if sold {
+			err = q.decrementStock(ctx, item.ID, pgtype.Int4{}, item.Stock.Valid, 1, leading.UserID, arg.Now)
+			if err != nil && !errors.Is(err, ErrItemOutOfStock) {
+				return err
+			}
+			sold = err == nil
+		}
+		if !sold {
+			message := fmt.Sprintf("The auction for %s closed without a sale, your %d coins are back on your balance", item.Name, leading.Amount)
+			err = q.releaseBid(ctx, leading, item.ID, NotificationKindAuctionClosed, message)
+			if err != nil {
+				return err
+			}
+			return q.closeAuction(ctx, &result.Auction, AuctionStatusUnsold, pgtype.Int4{})
+		}
+
+		// 3. Оформляем покупку на победителя: монеты уже списаны резервом
+		order, err := q.placeOrder(ctx, leading.UserID, leading.Amount)
+		if err != nil {
+			return err
+		}
+		result.Purchase, err = q.createPurchase(ctx, CreatePurchaseParams{
+			BuyerID:   pgtype.Int4{Int32: leading.UserID, Valid: true},
+			ItemID:    pgtype.Int4{Int32: item.ID, Valid: true},
+			Quantity:  1,
+			TotalCost: leading.Amount,
+			OrderID:   pgtype.Int4{Int32: order.ID, Valid: true},
+			UnitPrice: leading.Amount,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.ResolveAuctionBid(ctx, ResolveAuctionBidParams{ID: leading.ID, Status: AuctionBidStatusWon})
+		if err != nil {
+			return fmt.Errorf("error resolving bid: %v", err)
+		}
+		result.Bid = leading
+		result.Bid.Status = AuctionBidStatusWon
+
+		err = q.CreateNotification(ctx, CreateNotificationParams{
+			UserID:  leading.UserID,
+			Kind:    NotificationKindAuctionWon,
+			ItemID:  pgtype.Int4{Int32: item.ID, Valid: true},
+			Message: fmt.Sprintf("You won the auction for %s with a bid of %d", item.Name, leading.Amount),
+		})
+		if err != nil {
+			return fmt.Errorf("error creating notification: %v", err)
+		}
+
+		return q.closeAuction(ctx, &result.Auction, AuctionStatusSettled, pgtype.Int4{Int32: result.Purchase.ID, Valid: true})
+	})
+
+	if err != nil {
+		return SettleAuctionTxResult{}, fmt.Errorf("settle auction tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+type CancelAuctionTxParams struct {
+	AuctionID int32 `json:"auction_id"`
+}
+
+type CancelAuctionTxResult struct {
+	Auction Auction `json:"auction"`
+	// Released - освобожденная лидирующая ставка, если она была
+	Released *AuctionBid `json:"released"`
+}
+
+// CancelAuctionTx отменяет аукцион, по которому еще не подведен итог,
+// и возвращает монеты лидирующей ставки
+func (store *SQLStore) CancelAuctionTx(ctx context.Context, arg CancelAuctionTxParams) (CancelAuctionTxResult, error) {
+	var result CancelAuctionTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		auction, err := q.getAuctionForUpdate(ctx, arg.AuctionID)
+		if err != nil {
+			return err
+		}
+		if auction.Status != AuctionStatusActive {
+			return ErrAuctionClosed
+		}
+		result.Auction = auction
+
+		leading, ok, err := q.leadingBid(ctx, auction.ID)
+		if err != nil {
+			return err
+		}
+		if ok {
+			err = q.LockUsers(ctx, []int32{leading.UserID})
+			if err != nil {
+				return fmt.Errorf("error locking user: %v", err)
+			}
+			item, err := q.GetItemByID(ctx, auction.ItemID)
+			if err != nil {
+				return fmt.Errorf("error getting item: %v", err)
+			}
+			message := fmt.Sprintf("The auction for %s was cancelled, your %d coins are back on your balance", item.Name, leading.Amount)
+			err = q.releaseBid(ctx, leading, item.ID, NotificationKindAuctionClosed, message)
+			if err != nil {
+				return err
+			}
+			leading.Status = AuctionBidStatusReleased
+			result.Released = &leading
+		}
+
+		return q.closeAuction(ctx, &result.Auction, AuctionStatusCancelled, pgtype.Int4{})
+	})
+
+	if err != nil {
+		return CancelAuctionTxResult{}, fmt.Errorf("cancel auction tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// getAuctionForUpdate блокирует аукцион до конца транзакции
+func (q *Queries) getAuctionForUpdate(ctx context.Context, auctionID int32) (Auction, error) {
+	auction, err := q.GetAuctionForUpdate(ctx, auctionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Auction{}, ErrAuctionNotFound
+		}
+		return Auction{}, fmt.Errorf("error getting auction: %v", err)
+	}
+	return auction, nil
+}
+
+// leadingBid возвращает лидирующую ставку; false - ставок еще нет
+func (q *Queries) leadingBid(ctx context.Context, auctionID int32) (AuctionBid, bool, error) {
+	bid, err := q.GetLeadingAuctionBid(ctx, auctionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return AuctionBid{}, false, nil
+		}
+		return AuctionBid{}, false, fmt.Errorf("error getting leading bid: %v", err)
+	}
+	return bid, true, nil
+}
+
+// releaseBid возвращает участнику зарезервированные ставкой монеты с исходным
+// сроком действия и, если задано сообщение, уведомляет его.
+// Строка участника должна быть заблокирована вызывающей транзакцией.
+func (q *Queries) releaseBid(ctx context.Context, bid AuctionBid, itemID int32, kind, message string) error {
+	err := q.ResolveAuctionBid(ctx, ResolveAuctionBidParams{ID: bid.ID, Status: AuctionBidStatusReleased})
+	if err != nil {
+		return fmt.Errorf("error resolving bid: %v", err)
+	}
+
+	reserved, err := q.GetAuctionBidLots(ctx, bid.ID)
+	if err != nil {
+		return fmt.Errorf("error getting reserved coin lots: %v", err)
+	}
+	slices := make([]lotSlice, 0, len(reserved))
+	for _, lot := range reserved {
+		slices = append(slices, lotSlice{Amount: lot.Amount, ExpiresAt: lot.ExpiresAt})
+	}
+	err = q.creditLots(ctx, bid.UserID, pgtype.Int4{}, slices)
+	if err != nil {
+		return err
+	}
+
+	if message == "" {
+		return nil
+	}
+	err = q.CreateNotification(ctx, CreateNotificationParams{
+		UserID:  bid.UserID,
+		Kind:    kind,
+		ItemID:  pgtype.Int4{Int32: itemID, Valid: true},
+		Message: message,
+	})
+	if err != nil {
+		return fmt.Errorf("error creating notification: %v", err)
+	}
+	return nil
+}
+
+// closeAuction подводит итог аукциона и обновляет переданную копию
+func (q *Queries) closeAuction(ctx context.Context, auction *Auction, status string, purchaseID pgtype.Int4) error {
+	err := q.CloseAuction(ctx, CloseAuctionParams{
+		ID:         auction.ID,
+		Status:     status,
+		PurchaseID: purchaseID,
+	})
+	if err != nil {
+		return fmt.Errorf("error closing auction: %v", err)
+	}
+	auction.Status = status
+	auction.PurchaseID = purchaseID
+	return nil
+}
@@ -0,0 +1,101 @@
+package db
+
+import (
+	util "avito-shop/internal/util"
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAllocateBundlePrice(t *testing.T) {
+	components := []ListBundleComponentsRow{
+		{Price: 80, Quantity: 1},
+		{Price: 20, Quantity: 2},
+		{Price: 10, Quantity: 1},
+	}
+
+	// 100 распределяется как 80:40:10 от стоимости 130, остаток - последней позиции
+	shares := allocateBundlePrice(100, components)
+	require.Equal(t, []int32{61, 30, 9}, shares)
+
+	var total int32
+	for _, share := range shares {
+		total += share
+	}
+	require.Equal(t, int32(100), total)
+
+	require.Equal(t, []int32{7}, allocateBundlePrice(7, components[:1]))
+}
+
+func TestBundleTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	shirt, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 80})
+	require.NoError(t, err)
+	cup, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 20})
+	require.NoError(t, err)
+	variant := createRandomVariant(t, cup, pgtype.Int4{}, pgtype.Int4{Int32: 2, Valid: true})
+	admin := createRandomUser(t)
+	user := createRandomUser(t)
+
+	components := []BundleComponent{
+		{ItemID: shirt.ID, Quantity: 1},
+		{ItemID: cup.ID, VariantID: pgtype.Int4{Int32: variant.ID, Valid: true}, Quantity: 2},
+	}
+
+	// Набор не может стоить столько же, сколько товары по отдельности
+	_, err = store.CreateBundleTx(ctx, CreateBundleTxParams{
+		Name:      util.RandomString(8),
+		Price:     120,
+		Items:     components,
+		CreatedBy: admin.ID,
+	})
+	require.ErrorIs(t, err, ErrBundlePriceTooHigh)
+
+	created, err := store.CreateBundleTx(ctx, CreateBundleTxParams{
+		Name:      util.RandomString(8),
+		Price:     100,
+		Items:     components,
+		CreatedBy: admin.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(120), created.ListPrice)
+	require.Len(t, created.Items, 2)
+	bundle := created.Bundle
+
+	bought, err := store.BuyBundleTx(ctx, BuyBundleTxParams{UserID: user.ID, BundleID: bundle.ID})
+	require.NoError(t, err)
+	require.Equal(t, user.Balance.Int32-100, bought.User.Balance.Int32)
+	require.Len(t, bought.Purchases, 2)
+	require.Equal(t, int32(66), bought.Purchases[0].TotalCost)
+	require.Equal(t, int32(34), bought.Purchases[1].TotalCost)
+
+	order, err := testQueries.GetOrderByID(ctx, bought.Order.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(100), order.TotalCost)
+	require.Equal(t, bundle.ID, order.BundleID.Int32)
+
+	// Набор раскладывается в инвентаре на отдельные единицы
+	units, err := testQueries.ListInventoryUnits(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, units, 3)
+
+	// Остаток варианта исчерпан
+	stock, err := testQueries.GetItemVariantByID(ctx, variant.ID)
+	require.NoError(t, err)
+	require.Zero(t, stock.Stock.Int32)
+
+	_, err = store.BuyBundleTx(ctx, BuyBundleTxParams{UserID: user.ID, BundleID: bundle.ID})
+	require.ErrorIs(t, err, ErrItemOutOfStock)
+
+	_, err = testQueries.SetBundleActive(ctx, SetBundleActiveParams{ID: bundle.ID, Active: false})
+	require.NoError(t, err)
+	_, err = store.BuyBundleTx(ctx, BuyBundleTxParams{UserID: user.ID, BundleID: bundle.ID})
+	require.ErrorIs(t, err, ErrBundleInactive)
+
+	_, err = store.BuyBundleTx(ctx, BuyBundleTxParams{UserID: user.ID, BundleID: bundle.ID + 1000000})
+	require.ErrorIs(t, err, ErrBundleNotFound)
+}
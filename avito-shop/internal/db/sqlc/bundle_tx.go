@@ -0,0 +1,285 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Ошибки наборов
+var (
+	ErrBundleNotFound     = errors.New("bundle not found")
+	ErrBundleInactive     = errors.New("bundle is not available")
+	ErrBundlePriceTooHigh = errors.New("bundle price must be below the sum of its items")
+)
+
+// BundleComponent - товар или вариант в составе набора
+type BundleComponent struct {
+	ItemID    int32       `json:"item_id"`
+	VariantID pgtype.Int4 `json:"variant_id"`
+	Quantity  int32       `json:"quantity"`
+}
+
+type CreateBundleTxParams struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Price       int32             `json:"price"`
+	Items       []BundleComponent `json:"items"`
+	CreatedBy   int32             `json:"created_by"`
+}
+
+type CreateBundleTxResult struct {
+	Bundle Bundle       `json:"bundle"`
+	Items  []BundleItem `json:"items"`
+	// ListPrice - сумма цен товаров набора по отдельности
+	ListPrice int32 `json:"list_price"`
+}
+
+// CreateBundleTx создает набор вместе с составом. Цена набора должна быть ниже суммы
+// текущих цен товаров, иначе покупать его нет смысла
+func (store *SQLStore) CreateBundleTx(ctx context.Context, arg CreateBundleTxParams) (CreateBundleTxResult, error) {
+	var result CreateBundleTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result.Bundle, err = q.CreateBundle(ctx, CreateBundleParams{
+			Name:        arg.Name,
+			Description: pgtype.Text{String: arg.Description, Valid: arg.Description != ""},
+			Price:       arg.Price,
+			CreatedBy:   pgtype.Int4{Int32: arg.CreatedBy, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error creating bundle: %v", err)
+		}
+
+		result.Items = make([]BundleItem, 0, len(arg.Items))
+		for _, component := range arg.Items {
+			item, err := q.CreateBundleItem(ctx, CreateBundleItemParams{
+				BundleID:  result.Bundle.ID,
+				ItemID:    component.ItemID,
+				VariantID: component.VariantID,
+				Quantity:  component.Quantity,
+			})
+			if err != nil {
+				return fmt.Errorf("error adding bundle item: %v", err)
+			}
+			result.Items = append(result.Items, item)
+		}
+
+		components, err := q.ListBundleComponents(ctx, result.Bundle.ID)
+		if err != nil {
+			return fmt.Errorf("error listing bundle items: %v", err)
+		}
+		listPrice := bundleListPrice(components)
+		if int64(arg.Price) >= listPrice {
+			return ErrBundlePriceTooHigh
+		}
+		result.ListPrice = int32(min(listPrice, math.MaxInt32))
+
+		return nil
+	})
+
+	if err != nil {
+		return CreateBundleTxResult{}, fmt.Errorf("create bundle tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+type BuyBundleTxParams struct {
+	UserID   int32 `json:"user_id"`
+	BundleID int32 `json:"bundle_id"`
+}
+
+type BuyBundleTxResult struct {
+	Bundle    Bundle     `json:"bundle"`
+	Order     Order      `json:"order"`
+	Purchases []Purchase `json:"purchases"`
+	User      User       `json:"user"`
+}
+
+// bundleListPrice считает стоимость товаров набора по отдельности
+func bundleListPrice(components []ListBundleComponentsRow) int64 {
+	var total int64
+	for _, component := range components {
+		total += int64(component.Price) * int64(component.Quantity)
+	}
+	return total
+}
+
+// allocateBundlePrice распределяет цену набора между позициями пропорционально их
+// стоимости по отдельности; остаток от округления достается последней позиции, чтобы
+// сумма покупок совпадала с ценой набора. По этим суммам считаются возвраты
+func allocateBundlePrice(price int32, components []ListBundleComponentsRow) []int32 {
+	listPrice := bundleListPrice(components)
+	shares := make([]int32, len(components))
+	var allocated int64
+	for i, component := range components {
+		if i == len(components)-1 {
+			shares[i] = int32(int64(price) - allocated)
+			break
+		}
+		value := int64(component.Price) * int64(component.Quantity)
+		if listPrice > 0 {
+			shares[i] = int32(int64(price) * value / listPrice)
+		}
+		allocated += int64(shares[i])
+	}
+	return shares
+}
+
+// BuyBundleTx покупает набор одним заказом: проверяет окно продаж и ограничения каждого
+// товара, уменьшает остатки, создает покупку на каждую позицию по доле цены набора
+// и списывает цену набора. Единицы попадают в инвентарь покупателя по отдельности
+func (store *SQLStore) BuyBundleTx(ctx context.Context, arg BuyBundleTxParams) (BuyBundleTxResult, error) {
+	var result BuyBundleTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем покупателя и находим набор
+		err := q.LockUsers(ctx, []int32{arg.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		result.Bundle, err = q.GetBundle(ctx, arg.BundleID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrBundleNotFound
+			}
+			return fmt.Errorf("error getting bundle: %v", err)
+		}
+		if !result.Bundle.Active {
+			return ErrBundleInactive
+		}
+
+		components, err := q.ListBundleComponents(ctx, arg.BundleID)
+		if err != nil {
+			return fmt.Errorf("error listing bundle items: %v", err)
+		}
+		if len(components) == 0 {
+			return ErrBundleInactive
+		}
+
+		// 2. Проверяем окно продаж и ограничения товаров; варианты одного товара считаются вместе
+		now := time.Now()
+		quantities := make(map[int32]int32, len(components))
+		for _, component := range components {
+			quantities[component.ItemID] += component.Quantity
+		}
+		for _, component := range components {
+			quantity, ok := quantities[component.ItemID]
+			if !ok {
+				continue
+			}
+			delete(quantities, component.ItemID)
+
+			availability := ItemAvailability{
+				AvailableFrom:  component.AvailableFrom,
+				AvailableUntil: component.AvailableUntil,
+				QueueMinutes:   component.QueueMinutes,
+				QueueRate:      component.QueueRate,
+			}
+			err = q.checkAvailability(ctx, component.ItemID, arg.UserID, component.Name, availability, now)
+			if err != nil {
+				return err
+			}
+
+			limits := ItemLimits{MaxPerUser: component.MaxPerUser, CooldownDays: component.CooldownDays}
+			err = q.checkItemLimits(ctx, component.ItemID, arg.UserID, component.Name, limits, quantity)
+			if err != nil {
+				return err
+			}
+		}
+
+		// 3. Уменьшаем остатки отслеживаемых товаров в порядке блокировки
+		ordered := make([]ListBundleComponentsRow, len(components))
+		copy(ordered, components)
+		sort.Slice(ordered, func(i, j int) bool {
+			return stockLockLess(ordered[i].ItemID, ordered[i].VariantID, ordered[j].ItemID, ordered[j].VariantID)
+		})
+		for _, component := range ordered {
+			tracked := component.Stock.Valid
+			if component.VariantID.Valid {
+				tracked = component.VariantStock.Valid
+			}
+			err = q.decrementStock(ctx, component.ItemID, component.VariantID, tracked, component.Quantity, arg.UserID, now)
+			if errors.Is(err, ErrItemOutOfStock) {
+				return &ItemOutOfStockError{Item: component.Name, Variant: component.Sku.String}
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// 4. Создаем заказ с отметкой о наборе и покупку на каждую позицию
+		result.Order, err = q.placeOrder(ctx, arg.UserID, result.Bundle.Price)
+		if err != nil {
+			return err
+		}
+
+		result.Order.BundleID = pgtype.Int4{Int32: result.Bundle.ID, Valid: true}
+		err = q.SetOrderBundle(ctx, SetOrderBundleParams{
+			ID:       result.Order.ID,
+			BundleID: result.Order.BundleID,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording order bundle: %v", err)
+		}
+
+		shares := allocateBundlePrice(result.Bundle.Price, components)
+		result.Purchases = make([]Purchase, 0, len(components))
+		for i, component := range components {
+			purchase, err := q.createPurchase(ctx, CreatePurchaseParams{
+				BuyerID:   pgtype.Int4{Int32: arg.UserID, Valid: true},
+				ItemID:    pgtype.Int4{Int32: component.ItemID, Valid: true},
+				Quantity:  component.Quantity,
+				TotalCost: shares[i],
+				OrderID:   pgtype.Int4{Int32: result.Order.ID, Valid: true},
+				VariantID: component.VariantID,
+				UnitPrice: shares[i] / component.Quantity,
+			})
+			if err != nil {
+				return err
+			}
+			result.Purchases = append(result.Purchases, purchase)
+		}
+
+		// 5. Списываем цену набора, начиная с монет, которые сгорят раньше
+		_, err = q.debitCoins(ctx, arg.UserID, result.Bundle.Price, now)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.GetUserByID(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("error getting updated user: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return BuyBundleTxResult{}, fmt.Errorf("buy bundle tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// BundleListPrice - стоимость состава набора по отдельности для показа в каталоге;
+// суммы больше int32 ограничиваются сверху
+func BundleListPrice(items []ListBundleItemsRow) int32 {
+	var total int64
+	for _, item := range items {
+		total += int64(item.Price) * int64(item.Quantity)
+	}
+	if total > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(total)
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCartItemAccumulates(t *testing.T) {
+	user := createRandomUser(t)
+	item := createRandomItem(t)
+
+	arg := AddCartItemParams{UserID: user.ID, ItemID: item.ID, Quantity: 2}
+	cartItem, err := testQueries.AddCartItem(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), cartItem.Quantity)
+
+	// Повторное добавление того же товара увеличивает количество
+	cartItem, err = testQueries.AddCartItem(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int32(4), cartItem.Quantity)
+
+	lines, err := testQueries.ListCartItems(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, lines, 1)
+	require.Equal(t, item.Name, lines[0].Name)
+	require.Equal(t, item.Price, lines[0].Price)
+	require.Equal(t, int32(4), lines[0].Quantity)
+}
+
+func TestUpdateAndDeleteCartItem(t *testing.T) {
+	user := createRandomUser(t)
+	item := createRandomItem(t)
+
+	_, err := testQueries.AddCartItem(context.Background(), AddCartItemParams{UserID: user.ID, ItemID: item.ID, Quantity: 1})
+	require.NoError(t, err)
+
+	updated, err := testQueries.UpdateCartItemQuantity(context.Background(), UpdateCartItemQuantityParams{
+		UserID:   user.ID,
+		ItemID:   item.ID,
+		Quantity: 5,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), updated)
+
+	deleted, err := testQueries.DeleteCartItem(context.Background(), DeleteCartItemParams{UserID: user.ID, ItemID: item.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	// Удаленной позиции больше нет
+	deleted, err = testQueries.DeleteCartItem(context.Background(), DeleteCartItemParams{UserID: user.ID, ItemID: item.ID})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+}
+
+func TestCartTotal(t *testing.T) {
+	total, err := cartTotal([]ListCartItemsForUpdateRow{
+		{Price: 20, Quantity: 3},
+		{Price: 500, Quantity: 1},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(560), total)
+
+	_, err = cartTotal([]ListCartItemsForUpdateRow{{Price: math.MaxInt32, Quantity: 2}})
+	require.ErrorIs(t, err, ErrInsufficientBalance)
+}
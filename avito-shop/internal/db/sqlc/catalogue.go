@@ -0,0 +1,120 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// NormalizeTags приводит теги к нижнему регистру, убирает пустые и повторы
+func NormalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	sort.Strings(normalized)
+	return normalized
+}
+
+type UpdateItemTxParams struct {
+	ItemID int32 `json:"item_id"`
+	// nil - поле не меняется; значение без Valid очищает поле
+	CategoryID  *pgtype.Int4 `json:"category_id"`
+	Description *pgtype.Text `json:"description"`
+	// Tags заменяют все теги товара, nil - теги не меняются
+	Tags []string `json:"tags"`
+	// Attributes заменяют все характеристики товара, nil - характеристики не меняются
+	Attributes map[string]string `json:"attributes"`
+}
+
+type UpdateItemTxResult struct {
+	Item       Item            `json:"item"`
+	Tags       []string        `json:"tags"`
+	Attributes []ItemAttribute `json:"attributes"`
+}
+
+// UpdateItemTx меняет карточку товара в каталоге: категорию, описание, теги и характеристики
+func (store *SQLStore) UpdateItemTx(ctx context.Context, arg UpdateItemTxParams) (UpdateItemTxResult, error) {
+	var result UpdateItemTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		if arg.CategoryID != nil {
+			err = q.UpdateItemCategory(ctx, UpdateItemCategoryParams{CategoryID: *arg.CategoryID, ID: arg.ItemID})
+			if err != nil {
+				return fmt.Errorf("error updating item category: %v", err)
+			}
+		}
+
+		if arg.Description != nil {
+			err = q.UpdateItemDescription(ctx, UpdateItemDescriptionParams{Description: *arg.Description, ID: arg.ItemID})
+			if err != nil {
+				return fmt.Errorf("error updating item description: %v", err)
+			}
+		}
+
+		if arg.Tags != nil {
+			err = q.DeleteItemTags(ctx, arg.ItemID)
+			if err != nil {
+				return fmt.Errorf("error deleting item tags: %v", err)
+			}
+			err = q.AddItemTags(ctx, AddItemTagsParams{ItemID: arg.ItemID, Tags: NormalizeTags(arg.Tags)})
+			if err != nil {
+				return fmt.Errorf("error adding item tags: %v", err)
+			}
+		}
+
+		if arg.Attributes != nil {
+			err = q.DeleteItemAttributes(ctx, arg.ItemID)
+			if err != nil {
+				return fmt.Errorf("error deleting item attributes: %v", err)
+			}
+			names := make([]string, 0, len(arg.Attributes))
+			for name := range arg.Attributes {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			values := make([]string, 0, len(names))
+			for _, name := range names {
+				values = append(values, arg.Attributes[name])
+			}
+			err = q.AddItemAttributes(ctx, AddItemAttributesParams{ItemID: arg.ItemID, Names: names, Values: values})
+			if err != nil {
+				return fmt.Errorf("error adding item attributes: %v", err)
+			}
+		}
+
+		result.Item, err = q.GetItemByID(ctx, arg.ItemID)
+		if err != nil {
+			return fmt.Errorf("error getting item: %v", err)
+		}
+
+		result.Tags, err = q.ListItemTags(ctx, arg.ItemID)
+		if err != nil {
+			return fmt.Errorf("error listing item tags: %v", err)
+		}
+
+		result.Attributes, err = q.ListItemAttributes(ctx, arg.ItemID)
+		if err != nil {
+			return fmt.Errorf("error listing item attributes: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return UpdateItemTxResult{}, fmt.Errorf("update item tx error: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,58 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	util "avito-shop/internal/util"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	require.Equal(t, []string{"cotton", "summer"}, NormalizeTags([]string{" Summer", "cotton", "", "SUMMER "}))
+	require.Empty(t, NormalizeTags(nil))
+}
+
+func TestSearchItems(t *testing.T) {
+	item := createRandomItem(t)
+	tag := util.RandomString(8)
+	description := util.RandomString(10)
+
+	err := testQueries.UpdateItemDescription(context.Background(), UpdateItemDescriptionParams{
+		Description: pgtype.Text{String: description, Valid: true},
+		ID:          item.ID,
+	})
+	require.NoError(t, err)
+
+	err = testQueries.AddItemTags(context.Background(), AddItemTagsParams{ItemID: item.ID, Tags: []string{tag}})
+	require.NoError(t, err)
+
+	// Поиск по слову из описания с фильтром по тегу
+	rows, err := testQueries.SearchItems(context.Background(), SearchItemsParams{
+		Query:    pgtype.Text{String: description, Valid: true},
+		Tag:      pgtype.Text{String: tag, Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, item.Name, rows[0].Name)
+	require.Equal(t, []string{tag}, rows[0].Tags)
+	require.Equal(t, int64(1), rows[0].TotalCount)
+
+	// Цена вне диапазона исключает товар
+	rows, err = testQueries.SearchItems(context.Background(), SearchItemsParams{
+		Tag:      pgtype.Text{String: tag, Valid: true},
+		MinPrice: pgtype.Int4{Int32: item.Price + 1, Valid: true},
+		RowLimit: 10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, rows)
+
+	tags, err := testQueries.CountSearchItemsByTag(context.Background(), CountSearchItemsByTagParams{
+		Query: pgtype.Text{String: description, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []CountSearchItemsByTagRow{{Tag: tag, Items: 1}}, tags)
+}
@@ -0,0 +1,210 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrCartEmpty возвращается при оформлении пустой корзины
+var ErrCartEmpty = errors.New("cart is empty")
+
+// ItemOutOfStockError указывает, какого товара (и варианта) из корзины не хватило на складе
+type ItemOutOfStockError struct {
+	Item    string
+	Variant string
+}
+
+func (e *ItemOutOfStockError) Error() string {
+	if e.Variant != "" {
+		return fmt.Sprintf("%s: %s (%s)", ErrItemOutOfStock, e.Item, e.Variant)
+	}
+	return fmt.Sprintf("%s: %s", ErrItemOutOfStock, e.Item)
+}
+
+func (e *ItemOutOfStockError) Unwrap() error {
+	return ErrItemOutOfStock
+}
+
+type CheckoutTxParams struct {
+	UserID int32 `json:"user_id"`
+	// PromoCode - промокод на заказ, пустая строка - без скидки
+	PromoCode string `json:"promo_code"`
+}
+
+type CheckoutTxResult struct {
+	Order     Order      `json:"order"`
+	Purchases []Purchase `json:"purchases"`
+	User      User       `json:"user"`
+}
+
+// cartTotal считает стоимость корзины; сумма больше int32 не может быть оплачена
+func cartTotal(lines []ListCartItemsForUpdateRow) (int32, error) {
+	var total int64
+	for _, line := range lines {
+		total += int64(line.Price) * int64(line.Quantity)
+	}
+	if total > math.MaxInt32 {
+		return 0, ErrInsufficientBalance
+	}
+	return int32(total), nil
+}
+
+// CheckoutTx оформляет всю корзину одним заказом: уменьшает остатки, применяет промокод,
+// создает покупку на каждую позицию и списывает общую сумму. Если хотя бы одна позиция
+// недоступна, промокод не принят или монет не хватает, не покупается ничего.
+func (store *SQLStore) CheckoutTx(ctx context.Context, arg CheckoutTxParams) (CheckoutTxResult, error) {
+	var result CheckoutTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем покупателя и его корзину
+		err := q.LockUsers(ctx, []int32{arg.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		lines, err := q.ListCartItemsForUpdate(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("error listing cart: %v", err)
+		}
+		if len(lines) == 0 {
+			return ErrCartEmpty
+		}
+
+		total, err := cartTotal(lines)
+		if err != nil {
+			return err
+		}
+
+		// 2. Проверяем окно продаж и ограничения товаров; варианты одного товара считаются вместе
+		now := time.Now()
+		quantities := make(map[int32]int32, len(lines))
+		for _, line := range lines {
+			quantities[line.ItemID] += line.Quantity
+		}
+		for _, line := range lines {
+			quantity, ok := quantities[line.ItemID]
+			if !ok {
+				continue
+			}
+			delete(quantities, line.ItemID)
+
+			availability := ItemAvailability{
+				AvailableFrom:  line.AvailableFrom,
+				AvailableUntil: line.AvailableUntil,
+				QueueMinutes:   line.QueueMinutes,
+				QueueRate:      line.QueueRate,
+			}
+			err = q.checkAvailability(ctx, line.ItemID, arg.UserID, line.Name, availability, now)
+			if err != nil {
+				return err
+			}
+
+			limits := ItemLimits{MaxPerUser: line.MaxPerUser, CooldownDays: line.CooldownDays}
+			err = q.checkItemLimits(ctx, line.ItemID, arg.UserID, line.Name, limits, quantity)
+			if err != nil {
+				return err
+			}
+		}
+
+		// 3. Уменьшаем остатки отслеживаемых товаров в порядке блокировки, а не корзины
+		ordered := make([]ListCartItemsForUpdateRow, len(lines))
+		copy(ordered, lines)
+		sort.Slice(ordered, func(i, j int) bool {
+			return stockLockLess(ordered[i].ItemID, ordered[i].VariantID, ordered[j].ItemID, ordered[j].VariantID)
+		})
+		for _, line := range ordered {
+			tracked := line.Stock.Valid
+			if line.VariantID.Valid {
+				tracked = line.VariantStock.Valid
+			}
+			err = q.decrementStock(ctx, line.ItemID, line.VariantID, tracked, line.Quantity, arg.UserID, now)
+			if errors.Is(err, ErrItemOutOfStock) {
+				return &ItemOutOfStockError{Item: line.Name, Variant: line.Sku.String}
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// 4. Применяем промокод: одно оформление расходует одно использование,
+		// а скидка достается только позициям, на которые он действует
+		var promoCodeID pgtype.Int4
+		discounts := make([]cartLineDiscount, len(lines))
+		if arg.PromoCode != "" {
+			var promo PromoCode
+			promo, discounts, err = q.applyCartPromoCode(ctx, arg.PromoCode, arg.UserID, lines)
+			if err != nil {
+				return err
+			}
+			promoCodeID = pgtype.Int4{Int32: promo.ID, Valid: true}
+		}
+		for _, discount := range discounts {
+			total -= discount.Amount
+		}
+
+		// 5. Создаем заказ и покупку на каждую позицию
+		result.Order, err = q.placeOrder(ctx, arg.UserID, total)
+		if err != nil {
+			return err
+		}
+
+		lineIDs := make([]int32, 0, len(lines))
+		result.Purchases = make([]Purchase, 0, len(lines))
+		for i, line := range lines {
+			var lineCodeID pgtype.Int4
+			if discounts[i].Applies {
+				lineCodeID = promoCodeID
+			}
+			purchase, err := q.createPurchase(ctx, CreatePurchaseParams{
+				BuyerID:     pgtype.Int4{Int32: arg.UserID, Valid: true},
+				ItemID:      pgtype.Int4{Int32: line.ItemID, Valid: true},
+				Quantity:    line.Quantity,
+				TotalCost:   line.Price*line.Quantity - discounts[i].Amount,
+				OrderID:     pgtype.Int4{Int32: result.Order.ID, Valid: true},
+				VariantID:   line.VariantID,
+				PromoCodeID: lineCodeID,
+				Discount:    discounts[i].Amount,
+				UnitPrice:   line.Price,
+			})
+			if err != nil {
+				return err
+			}
+			result.Purchases = append(result.Purchases, purchase)
+			lineIDs = append(lineIDs, line.ID)
+		}
+
+		// 6. Списываем общую сумму, начиная с монет, которые сгорят раньше
+		_, err = q.debitCoins(ctx, arg.UserID, total, now)
+		if err != nil {
+			return err
+		}
+
+		// 7. Очищаем оформленные позиции корзины
+		err = q.DeleteCartItems(ctx, DeleteCartItemsParams{
+			UserID: arg.UserID,
+			Ids:    lineIDs,
+		})
+		if err != nil {
+			return fmt.Errorf("error clearing cart: %v", err)
+		}
+
+		result.User, err = q.GetUserByID(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("error getting updated user: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return CheckoutTxResult{}, fmt.Errorf("checkout tx error: %w", err)
+	}
+
+	return result, nil
+}
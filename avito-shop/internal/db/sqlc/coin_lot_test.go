@@ -0,0 +1,89 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomCoinLot(t *testing.T, userID int32, amount int32, expiresAt pgtype.Timestamp) CoinLot {
+	lot, err := testQueries.CreateCoinLot(context.Background(), CreateCoinLotParams{
+		UserID:    userID,
+		Amount:    amount,
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, amount, lot.Amount)
+	require.Equal(t, amount, lot.Remaining)
+
+	return lot
+}
+
+func TestListSpendableCoinLotsFIFO(t *testing.T) {
+	user := createRandomUser(t)
+	now := time.Now()
+
+	later := createRandomCoinLot(t, user.ID, 100, CoinExpiresAt(now, 48*time.Hour))
+	sooner := createRandomCoinLot(t, user.ID, 50, CoinExpiresAt(now, 24*time.Hour))
+	createRandomCoinLot(t, user.ID, 30, pgtype.Timestamp{Time: now.Add(-time.Hour), Valid: true})
+
+	lots, err := testQueries.ListSpendableCoinLots(context.Background(), ListSpendableCoinLotsParams{
+		UserID: user.ID,
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+
+	// Первым идет лот, который сгорит раньше, бессрочный лот из createRandomUser - последним,
+	// просроченный лот в выборку не попадает
+	require.Len(t, lots, 3)
+	require.Equal(t, sooner.ID, lots[0].ID)
+	require.Equal(t, later.ID, lots[1].ID)
+	require.False(t, lots[2].ExpiresAt.Valid)
+}
+
+func TestListExpiredCoinLots(t *testing.T) {
+	user := createRandomUser(t)
+	now := time.Now()
+
+	expired := createRandomCoinLot(t, user.ID, 30, pgtype.Timestamp{Time: now.Add(-time.Hour), Valid: true})
+	createRandomCoinLot(t, user.ID, 100, CoinExpiresAt(now, time.Hour))
+
+	lots, err := testQueries.ListExpiredCoinLots(context.Background(), ListExpiredCoinLotsParams{
+		UserID: user.ID,
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, lots, 1)
+	require.Equal(t, expired.ID, lots[0].ID)
+
+	// Полностью израсходованный лот больше не считается просроченным
+	err = testQueries.ConsumeCoinLot(context.Background(), ConsumeCoinLotParams{ID: expired.ID, Amount: 30})
+	require.NoError(t, err)
+
+	lots, err = testQueries.ListExpiredCoinLots(context.Background(), ListExpiredCoinLotsParams{
+		UserID: user.ID,
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, lots)
+}
+
+func TestGetUpcomingExpirations(t *testing.T) {
+	user := createRandomUser(t)
+	now := time.Now()
+	expiresAt := CoinExpiresAt(now, 72*time.Hour)
+
+	createRandomCoinLot(t, user.ID, 100, expiresAt)
+	createRandomCoinLot(t, user.ID, 50, expiresAt)
+
+	expirations, err := testQueries.GetUpcomingExpirations(context.Background(), GetUpcomingExpirationsParams{
+		UserID: user.ID,
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Len(t, expirations, 1)
+	require.Equal(t, int32(150), expirations[0].Amount)
+}
@@ -0,0 +1,141 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ReasonCodeExpired - причина списания сгоревших монет
+const ReasonCodeExpired = "expired"
+
+// lotSlice - часть лота, переходящая между пользователями вместе со сроком действия
+type lotSlice struct {
+	Amount    int32
+	ExpiresAt pgtype.Timestamp
+}
+
+// CoinExpiresAt возвращает срок действия монет, полученных в момент now.
+// Нулевой ttl означает монеты без срока действия.
+func CoinExpiresAt(now time.Time, ttl time.Duration) pgtype.Timestamp {
+	if ttl <= 0 {
+		return pgtype.Timestamp{}
+	}
+	return pgtype.Timestamp{Time: now.Add(ttl), Valid: true}
+}
+
+// debitCoins списывает amount монет у пользователя, расходуя лоты в порядке FIFO:
+// первыми уходят монеты, которые сгорят раньше всех. Возвращает израсходованные части лотов.
+// Строка пользователя должна быть заблокирована вызывающей транзакцией.
+func (q *Queries) debitCoins(ctx context.Context, userID, amount int32, now time.Time) ([]lotSlice, error) {
+	lots, err := q.ListSpendableCoinLots(ctx, ListSpendableCoinLotsParams{
+		UserID: userID,
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing coin lots: %v", err)
+	}
+
+	var slices []lotSlice
+	left := amount
+	for _, lot := range lots {
+		if left == 0 {
+			break
+		}
+		take := min(lot.Remaining, left)
+		err = q.ConsumeCoinLot(ctx, ConsumeCoinLotParams{ID: lot.ID, Amount: take})
+		if err != nil {
+			return nil, fmt.Errorf("error consuming coin lot: %v", err)
+		}
+		slices = append(slices, lotSlice{Amount: take, ExpiresAt: lot.ExpiresAt})
+		left -= take
+	}
+	if left > 0 {
+		return nil, ErrInsufficientBalance
+	}
+
+	err = q.UpdateBalance(ctx, UpdateBalanceParams{ID: userID, Amount: -amount})
+	if err != nil {
+		return nil, fmt.Errorf("error updating balance: %v", err)
+	}
+
+	return slices, nil
+}
+
+// creditCoins зачисляет пользователю монеты, сохраняя сроки действия исходных лотов
+func (q *Queries) creditCoins(ctx context.Context, userID int32, sourceTransactionID int32, slices []lotSlice) error {
+	return q.creditLots(ctx, userID, pgtype.Int4{Int32: sourceTransactionID, Valid: true}, slices)
+}
+
+// creditLots зачисляет монеты в лоты с указанным источником; без источника
+// зачисляются монеты, которые не проходили через журнал операций, например
+// освобожденный резерв ставки
+func (q *Queries) creditLots(ctx context.Context, userID int32, source pgtype.Int4, slices []lotSlice) error {
+	var total int32
+	for _, slice := range slices {
+		_, err := q.CreateCoinLot(ctx, CreateCoinLotParams{
+			UserID:              userID,
+			SourceTransactionID: source,
+			Amount:              slice.Amount,
+			ExpiresAt:           slice.ExpiresAt,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating coin lot: %v", err)
+		}
+		total += slice.Amount
+	}
+
+	err := q.UpdateBalance(ctx, UpdateBalanceParams{ID: userID, Amount: total})
+	if err != nil {
+		return fmt.Errorf("error updating balance: %v", err)
+	}
+
+	return nil
+}
+
+// holdCoins запоминает части лотов, удерживаемые отложенным переводом
+func (q *Queries) holdCoins(ctx context.Context, transactionID int32, slices []lotSlice) error {
+	for _, slice := range slices {
+		err := q.CreateTransactionLot(ctx, CreateTransactionLotParams{
+			TransactionID: transactionID,
+			Amount:        slice.Amount,
+			ExpiresAt:     slice.ExpiresAt,
+		})
+		if err != nil {
+			return fmt.Errorf("error holding coin lot: %v", err)
+		}
+	}
+	return nil
+}
+
+// heldCoins возвращает части лотов, удержанные отложенным переводом
+func (q *Queries) heldCoins(ctx context.Context, transactionID int32) ([]lotSlice, error) {
+	held, err := q.GetTransactionLots(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("error getting held coin lots: %v", err)
+	}
+
+	slices := make([]lotSlice, 0, len(held))
+	for _, lot := range held {
+		slices = append(slices, lotSlice{Amount: lot.Amount, ExpiresAt: lot.ExpiresAt})
+	}
+	return slices, nil
+}
+
+// splitLots делит части лотов на первые amount монет и остаток, сохраняя сроки действия
+func splitLots(slices []lotSlice, amount int32) (head, tail []lotSlice) {
+	left := amount
+	for _, slice := range slices {
+		take := min(slice.Amount, left)
+		if take > 0 {
+			head = append(head, lotSlice{Amount: take, ExpiresAt: slice.ExpiresAt})
+			left -= take
+		}
+		if slice.Amount > take {
+			tail = append(tail, lotSlice{Amount: slice.Amount - take, ExpiresAt: slice.ExpiresAt})
+		}
+	}
+	return head, tail
+}
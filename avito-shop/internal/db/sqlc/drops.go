@@ -0,0 +1,176 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Причины, по которым товар вне окна продаж
+const (
+	ItemUnavailableNotStarted = "not_started"
+	ItemUnavailableEnded      = "ended"
+)
+
+var (
+	// ErrItemUnavailable позволяет проверить ItemUnavailableError через errors.Is
+	ErrItemUnavailable = errors.New("item is not on sale")
+	// ErrDropQueued позволяет проверить DropQueueError через errors.Is
+	ErrDropQueued = errors.New("drop queue admission required")
+)
+
+// ItemUnavailableError - покупка вне окна продаж товара
+type ItemUnavailableError struct {
+	Item           string
+	Reason         string
+	AvailableFrom  time.Time
+	AvailableUntil time.Time
+}
+
+func (e *ItemUnavailableError) Error() string {
+	if e.Reason == ItemUnavailableNotStarted {
+		return fmt.Sprintf("%s goes on sale at %s", e.Item, e.AvailableFrom.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s sale ended at %s", e.Item, e.AvailableUntil.Format(time.RFC3339))
+}
+
+func (e *ItemUnavailableError) Unwrap() error {
+	return ErrItemUnavailable
+}
+
+// DropQueueError - в первые минуты дропа покупка доступна только допущенным из очереди
+type DropQueueError struct {
+	Item string
+	// Joined - пользователь встал в очередь; Position - его место, считая с единицы
+	Joined   bool
+	Position int32
+	// Admitted - сколько участников очереди уже допущены к покупке
+	Admitted int32
+	// QueueEndsAt - после этого момента покупка доступна всем
+	QueueEndsAt time.Time
+}
+
+func (e *DropQueueError) Error() string {
+	if !e.Joined {
+		return fmt.Sprintf("%s is sold through a queue until %s, join the queue first", e.Item, e.QueueEndsAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s: queue position %d, %d admitted so far", e.Item, e.Position, e.Admitted)
+}
+
+func (e *DropQueueError) Unwrap() error {
+	return ErrDropQueued
+}
+
+// ItemAvailability - окно продаж товара и настройки честной очереди
+type ItemAvailability struct {
+	AvailableFrom  pgtype.Timestamp `json:"available_from"`
+	AvailableUntil pgtype.Timestamp `json:"available_until"`
+	QueueMinutes   pgtype.Int4      `json:"queue_minutes"`
+	QueueRate      pgtype.Int4      `json:"queue_rate"`
+}
+
+// Availability - окно продаж товара
+func (item Item) Availability() ItemAvailability {
+	return ItemAvailability{
+		AvailableFrom:  item.AvailableFrom,
+		AvailableUntil: item.AvailableUntil,
+		QueueMinutes:   item.QueueMinutes,
+		QueueRate:      item.QueueRate,
+	}
+}
+
+// Check проверяет, продается ли товар name в момент now
+func (a ItemAvailability) Check(name string, now time.Time) error {
+	if a.AvailableFrom.Valid && now.Before(a.AvailableFrom.Time) {
+		return &ItemUnavailableError{
+			Item:           name,
+			Reason:         ItemUnavailableNotStarted,
+			AvailableFrom:  a.AvailableFrom.Time,
+			AvailableUntil: a.AvailableUntil.Time,
+		}
+	}
+	if a.AvailableUntil.Valid && !now.Before(a.AvailableUntil.Time) {
+		return &ItemUnavailableError{
+			Item:           name,
+			Reason:         ItemUnavailableEnded,
+			AvailableFrom:  a.AvailableFrom.Time,
+			AvailableUntil: a.AvailableUntil.Time,
+		}
+	}
+	return nil
+}
+
+// HasQueue сообщает, продается ли товар через очередь
+func (a ItemAvailability) HasQueue() bool {
+	return a.AvailableFrom.Valid && a.QueueMinutes.Valid && a.QueueRate.Valid
+}
+
+// QueueEndsAt - момент, после которого покупка доступна без очереди
+func (a ItemAvailability) QueueEndsAt() time.Time {
+	return a.AvailableFrom.Time.Add(time.Duration(a.QueueMinutes.Int32) * time.Minute)
+}
+
+// QueueActive сообщает, действует ли очередь в момент now
+func (a ItemAvailability) QueueActive(now time.Time) bool {
+	return a.HasQueue() && !now.Before(a.AvailableFrom.Time) && now.Before(a.QueueEndsAt())
+}
+
+// Admitted - сколько первых участников очереди допущены к покупке в момент now:
+// в начале каждой минуты продаж допускается еще QueueRate участников
+func (a ItemAvailability) Admitted(now time.Time) int32 {
+	if !a.HasQueue() || now.Before(a.AvailableFrom.Time) {
+		return 0
+	}
+	minutes := int32(now.Sub(a.AvailableFrom.Time)/time.Minute) + 1
+	return minutes * a.QueueRate.Int32
+}
+
+// earlyQueueSpan отделяет случайные места вставших в очередь до начала продаж
+// от мест вставших после
+const earlyQueueSpan = 1 << 40
+
+// DropQueuePosition - порядок участника очереди. До начала продаж место случайное,
+// поэтому нет смысла заходить заранее или обновлять страницу ботами;
+// после начала участники встают в конец в порядке входа
+func DropQueuePosition(availableFrom, now time.Time) int64 {
+	if now.Before(availableFrom) {
+		return rand.Int63n(earlyQueueSpan)
+	}
+	return earlyQueueSpan + now.UnixMicro()
+}
+
+// checkAvailability проверяет окно продаж товара и, пока действует очередь,
+// допущен ли покупатель userID к покупке
+func (q *Queries) checkAvailability(ctx context.Context, itemID, userID int32, name string, a ItemAvailability, now time.Time) error {
+	if err := a.Check(name, now); err != nil {
+		return err
+	}
+	if !a.QueueActive(now) {
+		return nil
+	}
+
+	queueErr := &DropQueueError{
+		Item:        name,
+		Admitted:    a.Admitted(now),
+		QueueEndsAt: a.QueueEndsAt(),
+	}
+	ahead, err := q.GetDropQueueRank(ctx, GetDropQueueRankParams{ItemID: itemID, UserID: userID})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return queueErr
+		}
+		return fmt.Errorf("error getting queue position: %v", err)
+	}
+
+	if ahead < queueErr.Admitted {
+		return nil
+	}
+	queueErr.Joined = true
+	queueErr.Position = ahead + 1
+	return queueErr
+}
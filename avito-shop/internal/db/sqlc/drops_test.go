@@ -0,0 +1,140 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemAvailabilityCheck(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	window := ItemAvailability{
+		AvailableFrom:  pgtype.Timestamp{Time: start, Valid: true},
+		AvailableUntil: pgtype.Timestamp{Time: start.Add(24 * time.Hour), Valid: true},
+	}
+
+	testCases := []struct {
+		name         string
+		availability ItemAvailability
+		now          time.Time
+		wantReason   string
+	}{
+		{
+			name: "OK_NoWindow",
+			now:  start,
+		},
+		{
+			name:         "OK_Started",
+			availability: window,
+			now:          start,
+		},
+		{
+			name:         "NotStarted",
+			availability: window,
+			now:          start.Add(-time.Second),
+			wantReason:   ItemUnavailableNotStarted,
+		},
+		{
+			name:         "Ended",
+			availability: window,
+			now:          start.Add(24 * time.Hour),
+			wantReason:   ItemUnavailableEnded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.availability.Check("sneakers", tc.now)
+			if tc.wantReason == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var unavailableErr *ItemUnavailableError
+			require.True(t, errors.As(err, &unavailableErr))
+			require.ErrorIs(t, err, ErrItemUnavailable)
+			require.Equal(t, tc.wantReason, unavailableErr.Reason)
+		})
+	}
+}
+
+func TestItemAvailabilityQueue(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	a := ItemAvailability{
+		AvailableFrom: pgtype.Timestamp{Time: start, Valid: true},
+		QueueMinutes:  pgtype.Int4{Int32: 10, Valid: true},
+		QueueRate:     pgtype.Int4{Int32: 50, Valid: true},
+	}
+
+	require.True(t, a.HasQueue())
+	require.Equal(t, start.Add(10*time.Minute), a.QueueEndsAt())
+
+	require.False(t, a.QueueActive(start.Add(-time.Second)))
+	require.Zero(t, a.Admitted(start.Add(-time.Second)))
+
+	// В первую минуту допущены первые 50, во вторую - первые 100
+	require.True(t, a.QueueActive(start))
+	require.Equal(t, int32(50), a.Admitted(start))
+	require.Equal(t, int32(50), a.Admitted(start.Add(59*time.Second)))
+	require.Equal(t, int32(100), a.Admitted(start.Add(time.Minute)))
+
+	require.False(t, a.QueueActive(start.Add(10*time.Minute)))
+	require.False(t, ItemAvailability{AvailableFrom: a.AvailableFrom}.HasQueue())
+}
+
+func TestDropQueuePosition(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	// Вставшие до начала продаж всегда впереди вставших после
+	early := DropQueuePosition(start, start.Add(-time.Hour))
+	late := DropQueuePosition(start, start.Add(time.Second))
+	later := DropQueuePosition(start, start.Add(2*time.Second))
+	require.GreaterOrEqual(t, early, int64(0))
+	require.Less(t, early, late)
+	require.Less(t, late, later)
+}
+
+func TestDropQueueRank(t *testing.T) {
+	item := createRandomItem(t)
+	first := createRandomUser(t)
+	second := createRandomUser(t)
+	outsider := createRandomUser(t)
+
+	for i, user := range []User{second, first} {
+		joined, err := testQueries.JoinDropQueue(context.Background(), JoinDropQueueParams{
+			ItemID:   item.ID,
+			UserID:   user.ID,
+			Position: int64(20 - i*10),
+		})
+		require.NoError(t, err)
+		require.Equal(t, int64(1), joined)
+	}
+
+	// Повторный вход место не меняет
+	joined, err := testQueries.JoinDropQueue(context.Background(), JoinDropQueueParams{
+		ItemID:   item.ID,
+		UserID:   second.ID,
+		Position: 0,
+	})
+	require.NoError(t, err)
+	require.Zero(t, joined)
+
+	ahead, err := testQueries.GetDropQueueRank(context.Background(), GetDropQueueRankParams{ItemID: item.ID, UserID: first.ID})
+	require.NoError(t, err)
+	require.Zero(t, ahead)
+
+	ahead, err = testQueries.GetDropQueueRank(context.Background(), GetDropQueueRankParams{ItemID: item.ID, UserID: second.ID})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), ahead)
+
+	_, err = testQueries.GetDropQueueRank(context.Background(), GetDropQueueRankParams{ItemID: item.ID, UserID: outsider.ID})
+	require.Error(t, err)
+
+	queued, err := testQueries.CountDropQueue(context.Background(), item.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(2), queued)
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ExpireCoinsTxParams struct {
+	UserID int32     `json:"user_id"`
+	Now    time.Time `json:"now"`
+}
+
+type ExpireCoinsTxResult struct {
+	Expired []Transaction `json:"expired"`
+	User    User          `json:"user"`
+}
+
+// ExpireCoinsTx списывает у пользователя остатки лотов, срок действия которых истек к Now.
+// Каждый сгоревший лот записывается отдельной операцией expiry на системный счет.
+func (store *SQLStore) ExpireCoinsTx(ctx context.Context, arg ExpireCoinsTxParams) (ExpireCoinsTxResult, error) {
+	var result ExpireCoinsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем пользователя, чтобы сгорание не пересеклось с тратами
+		err := q.LockUsers(ctx, []int32{arg.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		lots, err := q.ListExpiredCoinLots(ctx, ListExpiredCoinLotsParams{
+			UserID: arg.UserID,
+			Now:    pgtype.Timestamp{Time: arg.Now, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error listing expired coin lots: %v", err)
+		}
+
+		for _, lot := range lots {
+			// 2. Записываем сгорание
+			expired, err := q.CreateAdjustment(ctx, CreateAdjustmentParams{
+				SenderID:   pgtype.Int4{Int32: arg.UserID, Valid: true},
+				Amount:     lot.Remaining,
+				Kind:       TransactionKindExpiry,
+				ReasonCode: pgtype.Text{String: ReasonCodeExpired, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error creating expiry: %v", err)
+			}
+			result.Expired = append(result.Expired, expired)
+
+			// 3. Обнуляем лот и уменьшаем баланс
+			err = q.ConsumeCoinLot(ctx, ConsumeCoinLotParams{ID: lot.ID, Amount: lot.Remaining})
+			if err != nil {
+				return fmt.Errorf("error consuming coin lot: %v", err)
+			}
+			err = q.UpdateBalance(ctx, UpdateBalanceParams{ID: arg.UserID, Amount: -lot.Remaining})
+			if err != nil {
+				return fmt.Errorf("error updating balance: %v", err)
+			}
+		}
+
+		result.User, err = q.GetUserByID(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return ExpireCoinsTxResult{}, fmt.Errorf("expire coins tx error: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,49 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGiftPurchase(t *testing.T) {
+	buyer := createRandomUser(t)
+	recipient := createRandomUser(t)
+	item := createRandomItem(t)
+
+	arg := CreatePurchaseParams{
+		BuyerID:     pgtype.Int4{Int32: buyer.ID, Valid: true},
+		ItemID:      pgtype.Int4{Int32: item.ID, Valid: true},
+		Quantity:    1,
+		TotalCost:   item.Price,
+		RecipientID: pgtype.Int4{Int32: recipient.ID, Valid: true},
+		GiftMessage: pgtype.Text{String: "Happy birthday!", Valid: true},
+	}
+
+	purchase, err := testQueries.CreatePurchase(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.RecipientID, purchase.RecipientID)
+	require.Equal(t, arg.GiftMessage, purchase.GiftMessage)
+
+	// Подарок лежит в инвентаре получателя, а не покупателя
+	recipientItems, err := testQueries.GetPurchases(context.Background(), pgtype.Int4{Int32: recipient.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, recipientItems, 1)
+	require.Equal(t, item.Name, recipientItems[0].Name)
+
+	buyerItems, err := testQueries.GetPurchases(context.Background(), pgtype.Int4{Int32: buyer.ID, Valid: true})
+	require.NoError(t, err)
+	require.Empty(t, buyerItems)
+
+	// Обе стороны видят подарок
+	for _, user := range []User{buyer, recipient} {
+		gifts, err := testQueries.GetGifts(context.Background(), pgtype.Int4{Int32: user.ID, Valid: true})
+		require.NoError(t, err)
+		require.Len(t, gifts, 1)
+		require.Equal(t, buyer.Username, gifts[0].BuyerUsername)
+		require.Equal(t, recipient.Username, gifts[0].RecipientUsername)
+		require.Equal(t, "Happy birthday!", gifts[0].GiftMessage.String)
+	}
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Как текущий владелец получил единицу товара
+const (
+	AcquiredViaPurchase    = "purchase"
+	AcquiredViaGift        = "gift"
+	AcquiredViaTransfer    = "transfer"
+	AcquiredViaMarketplace = "marketplace"
+)
+
+// Виды передачи единицы между пользователями
+const (
+	// InventoryTransferKindGive - единицу отдали другому пользователю
+	InventoryTransferKindGive = "give"
+	// InventoryTransferKindSale - единицу продали через маркетплейс
+	InventoryTransferKindSale = "sale"
+)
+
+// NotificationKindItemReceived - уведомление о полученных от другого пользователя товарах
+const NotificationKindItemReceived = "item_received"
+
+// Ошибки инвентаря
+var (
+	ErrNotEnoughItems  = errors.New("not enough items to transfer")
+	ErrItemTransferred = errors.New("purchased items were passed on to another user or listed for sale")
+)
+
+// NotEnoughItemsError - у владельца меньше передаваемых единиц, чем запрошено.
+// Передать можно только единицы из выданных заказов
+type NotEnoughItemsError struct {
+	Available int32
+}
+
+func (e *NotEnoughItemsError) Error() string {
+	return fmt.Sprintf("only %d items can be transferred", e.Available)
+}
+
+func (e *NotEnoughItemsError) Unwrap() error {
+	return ErrNotEnoughItems
+}
+
+// createPurchase записывает покупку и передает ее единицы во владение
+// получателю подарка или покупателю
+func (q *Queries) createPurchase(ctx context.Context, arg CreatePurchaseParams) (Purchase, error) {
+	purchase, err := q.CreatePurchase(ctx, arg)
+	if err != nil {
+		return Purchase{}, fmt.Errorf("error creating purchase: %v", err)
+	}
+
+	err = q.CreatePurchaseUnits(ctx, purchase.ID)
+	if err != nil {
+		return Purchase{}, fmt.Errorf("error adding purchase to inventory: %v", err)
+	}
+
+	return purchase, nil
+}
+
+// removePurchaseUnits изымает из инвентаря quantity возвращаемых единиц покупки.
+// Единицы, переданные другим пользователям или выставленные на продажу, вернуть нельзя
+func (q *Queries) removePurchaseUnits(ctx context.Context, purchaseID, quantity int32) error {
+	removed, err := q.RemovePurchaseUnits(ctx, RemovePurchaseUnitsParams{
+		PurchaseID: pgtype.Int4{Int32: purchaseID, Valid: true},
+		Quantity:   quantity,
+	})
+	if err != nil {
+		return fmt.Errorf("error removing items from inventory: %v", err)
+	}
+	if removed < int64(quantity) {
+		return ErrItemTransferred
+	}
+	return nil
+}
+
+type GiveItemTxParams struct {
+	FromUserID int32 `json:"from_user_id"`
+	ToUserID   int32 `json:"to_user_id"`
+	ItemID     int32 `json:"item_id"`
+	// VariantID - вариант товара, 0 - товар без вариантов
+	VariantID int32  `json:"variant_id"`
+	Quantity  int32  `json:"quantity"`
+	Message   string `json:"message"`
+}
+
+type GiveItemTxResult struct {
+	// Units - id переданных единиц
+	Units []int32 `json:"units"`
+}
+
+// GiveItemTx передает другому пользователю quantity единиц товара из инвентаря.
+// Первыми уходят единицы, полученные раньше; каждая передача записывается в историю единицы,
+// а получатель получает уведомление
+func (store *SQLStore) GiveItemTx(ctx context.Context, arg GiveItemTxParams) (GiveItemTxResult, error) {
+	var result GiveItemTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем обоих пользователей в порядке id
+		err := q.LockUsers(ctx, []int32{arg.FromUserID, arg.ToUserID})
+		if err != nil {
+			return fmt.Errorf("error locking users: %v", err)
+		}
+
+		// 2. Выбираем и блокируем передаваемые единицы
+		units, err := q.ListTransferableUnits(ctx, ListTransferableUnitsParams{
+			OwnerID:   arg.FromUserID,
+			ItemID:    arg.ItemID,
+			VariantID: pgtype.Int4{Int32: arg.VariantID, Valid: arg.VariantID != 0},
+			RowLimit:  arg.Quantity,
+		})
+		if err != nil {
+			return fmt.Errorf("error listing inventory: %v", err)
+		}
+		if int32(len(units)) < arg.Quantity {
+			return &NotEnoughItemsError{Available: int32(len(units))}
+		}
+
+		// 3. Меняем владельца и записываем передачу
+		err = q.TransferInventoryUnits(ctx, TransferInventoryUnitsParams{
+			OwnerID:      arg.ToUserID,
+			AcquiredVia:  AcquiredViaTransfer,
+			AcquiredFrom: pgtype.Int4{Int32: arg.FromUserID, Valid: true},
+			Ids:          units,
+		})
+		if err != nil {
+			return fmt.Errorf("error transferring items: %v", err)
+		}
+		err = q.CreateInventoryTransfers(ctx, CreateInventoryTransfersParams{
+			FromUserID: arg.FromUserID,
+			ToUserID:   arg.ToUserID,
+			Kind:       InventoryTransferKindGive,
+			Message:    pgtype.Text{String: arg.Message, Valid: arg.Message != ""},
+			Ids:        units,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording transfer: %v", err)
+		}
+		result.Units = units
+
+		// 4. Уведомляем получателя
+		from, err := q.GetUserByID(ctx, arg.FromUserID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+		item, err := q.GetItemByID(ctx, arg.ItemID)
+		if err != nil {
+			return fmt.Errorf("error getting item: %v", err)
+		}
+		message := fmt.Sprintf("%s gave you %d x %s", from.Username, arg.Quantity, item.Name)
+		if arg.Message != "" {
+			message += ": " + arg.Message
+		}
+		err = q.CreateNotification(ctx, CreateNotificationParams{
+			UserID:  arg.ToUserID,
+			Kind:    NotificationKindItemReceived,
+			ItemID:  pgtype.Int4{Int32: item.ID, Valid: true},
+			Message: message,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating notification: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return GiveItemTxResult{}, fmt.Errorf("give item tx error: %w", err)
+	}
+
+	return result, nil
+}
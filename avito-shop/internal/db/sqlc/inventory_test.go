@@ -0,0 +1,81 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotEnoughItemsError(t *testing.T) {
+	err := error(&NotEnoughItemsError{Available: 2})
+	require.ErrorIs(t, err, ErrNotEnoughItems)
+	require.Equal(t, "only 2 items can be transferred", err.Error())
+}
+
+func TestGiveItemTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	item := createRandomItem(t)
+	sender := createRandomUser(t)
+	receiver := createRandomUser(t)
+
+	bought, err := store.PurchaseTx(ctx, PurchaseTxParams{UserID: sender.ID, ItemID: item.ID, Quantity: 1})
+	require.NoError(t, err)
+
+	units, err := testQueries.ListInventoryUnits(ctx, sender.ID)
+	require.NoError(t, err)
+	require.Len(t, units, 1)
+	require.Equal(t, AcquiredViaPurchase, units[0].AcquiredVia)
+
+	// Пока заказ не выдан, товар передать нельзя
+	_, err = store.GiveItemTx(ctx, GiveItemTxParams{FromUserID: sender.ID, ToUserID: receiver.ID, ItemID: item.ID, Quantity: 1})
+	var itemsErr *NotEnoughItemsError
+	require.True(t, errors.As(err, &itemsErr))
+	require.Zero(t, itemsErr.Available)
+
+	for _, status := range []string{OrderStatusReadyForPickup, OrderStatusDelivered} {
+		_, err = store.UpdateOrderStatusTx(ctx, UpdateOrderStatusTxParams{
+			OrderID: bought.Purchase.OrderID.Int32,
+			Status:  status,
+		})
+		require.NoError(t, err)
+	}
+
+	given, err := store.GiveItemTx(ctx, GiveItemTxParams{
+		FromUserID: sender.ID,
+		ToUserID:   receiver.ID,
+		ItemID:     item.ID,
+		Quantity:   1,
+		Message:    "enjoy",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int32{units[0].ID}, given.Units)
+
+	// Единица перешла к получателю вместе с историей
+	inventory, err := testQueries.GetInventory(ctx, receiver.ID)
+	require.NoError(t, err)
+	require.Len(t, inventory, 1)
+	require.Equal(t, int32(1), inventory[0].Quantity)
+	require.Equal(t, int32(1), inventory[0].Received)
+
+	inventory, err = testQueries.GetInventory(ctx, sender.ID)
+	require.NoError(t, err)
+	require.Empty(t, inventory)
+
+	transfers, err := testQueries.ListInventoryTransfers(ctx, units[0].ID)
+	require.NoError(t, err)
+	require.Len(t, transfers, 1)
+	require.Equal(t, sender.Username, transfers[0].FromUser.String)
+	require.Equal(t, "enjoy", transfers[0].Message.String)
+
+	// Переданный товар вернуть нельзя
+	_, err = store.RefundTx(ctx, RefundTxParams{
+		PurchaseID: bought.Purchase.ID,
+		Quantity:   1,
+		ReasonCode: ReasonCodeRefund,
+	})
+	require.ErrorIs(t, err, ErrItemTransferred)
+}
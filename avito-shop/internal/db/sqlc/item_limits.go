@@ -0,0 +1,128 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Названия ограничений, которые возвращаются в PurchaseLimitError
+const (
+	PurchaseLimitMaxPerUser = "max_per_user"
+	PurchaseLimitCooldown   = "cooldown"
+)
+
+// ErrPurchaseLimitExceeded позволяет проверить любую ошибку ограничения покупки через errors.Is
+var ErrPurchaseLimitExceeded = errors.New("purchase limit exceeded")
+
+// PurchaseLimitError описывает, какое ограничение товара не позволяет покупку
+type PurchaseLimitError struct {
+	Item  string
+	Limit string
+	// Allowed - максимум единиц на пользователя или за один период ожидания
+	Allowed   int32
+	Remaining int32
+	// AvailableAt - когда закончится период ожидания; нулевое, если ждать не нужно
+	AvailableAt time.Time
+}
+
+func (e *PurchaseLimitError) Error() string {
+	if e.Limit == PurchaseLimitCooldown {
+		if e.AvailableAt.IsZero() {
+			return fmt.Sprintf("%s can be bought only one at a time", e.Item)
+		}
+		return fmt.Sprintf("%s can be bought again after %s", e.Item, e.AvailableAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("%s is limited to %d per user, %d remaining", e.Item, e.Allowed, e.Remaining)
+}
+
+func (e *PurchaseLimitError) Unwrap() error {
+	return ErrPurchaseLimitExceeded
+}
+
+// ItemLimits - ограничения покупки товара одним пользователем; NULL - без ограничения
+type ItemLimits struct {
+	MaxPerUser   pgtype.Int4 `json:"max_per_user"`
+	CooldownDays pgtype.Int4 `json:"cooldown_days"`
+}
+
+// Limits - ограничения покупки товара
+func (item Item) Limits() ItemLimits {
+	return ItemLimits{MaxPerUser: item.MaxPerUser, CooldownDays: item.CooldownDays}
+}
+
+// Enabled сообщает, задано ли хотя бы одно ограничение
+func (limits ItemLimits) Enabled() bool {
+	return limits.MaxPerUser.Valid || limits.CooldownDays.Valid
+}
+
+// Check проверяет, можно ли купить quantity единиц товара name в момент now
+// с учетом уже купленного пользователем
+func (limits ItemLimits) Check(name string, stats GetUserItemPurchaseStatsRow, quantity int32, now time.Time) error {
+	if limits.MaxPerUser.Valid && stats.Owned+quantity > limits.MaxPerUser.Int32 {
+		return &PurchaseLimitError{
+			Item:      name,
+			Limit:     PurchaseLimitMaxPerUser,
+			Allowed:   limits.MaxPerUser.Int32,
+			Remaining: max(limits.MaxPerUser.Int32-stats.Owned, 0),
+		}
+	}
+
+	if limits.CooldownDays.Valid {
+		if stats.LastPurchaseAt.Valid {
+			availableAt := stats.LastPurchaseAt.Time.AddDate(0, 0, int(limits.CooldownDays.Int32))
+			if now.Before(availableAt) {
+				return &PurchaseLimitError{
+					Item:        name,
+					Limit:       PurchaseLimitCooldown,
+					Allowed:     1,
+					AvailableAt: availableAt,
+				}
+			}
+		}
+		if quantity > 1 {
+			return &PurchaseLimitError{Item: name, Limit: PurchaseLimitCooldown, Allowed: 1, Remaining: 1}
+		}
+	}
+
+	return nil
+}
+
+// Allowance возвращает, сколько единиц товара пользователь еще может купить в момент now;
+// без ограничений - math.MaxInt32
+func (limits ItemLimits) Allowance(stats GetUserItemPurchaseStatsRow, now time.Time) int32 {
+	allowance := int32(math.MaxInt32)
+	if limits.MaxPerUser.Valid {
+		allowance = max(limits.MaxPerUser.Int32-stats.Owned, 0)
+	}
+	if limits.CooldownDays.Valid {
+		if stats.LastPurchaseAt.Valid && now.Before(stats.LastPurchaseAt.Time.AddDate(0, 0, int(limits.CooldownDays.Int32))) {
+			return 0
+		}
+		allowance = min(allowance, 1)
+	}
+	return allowance
+}
+
+// checkItemLimits проверяет ограничения товара для покупателя или получателя подарка.
+// Строка пользователя должна быть заблокирована вызывающей транзакцией,
+// иначе параллельные покупки увидят одну и ту же статистику.
+func (q *Queries) checkItemLimits(ctx context.Context, itemID, userID int32, name string, limits ItemLimits, quantity int32) error {
+	if !limits.Enabled() {
+		return nil
+	}
+
+	stats, err := q.GetUserItemPurchaseStats(ctx, GetUserItemPurchaseStatsParams{
+		ItemID: pgtype.Int4{Int32: itemID, Valid: true},
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("error getting purchase stats: %v", err)
+	}
+
+	return limits.Check(name, stats, quantity, time.Now())
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	util "avito-shop/internal/util"
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemLimitsCheck(t *testing.T) {
+	now := time.Now()
+	onePerUser := ItemLimits{MaxPerUser: pgtype.Int4{Int32: 1, Valid: true}}
+	monthly := ItemLimits{CooldownDays: pgtype.Int4{Int32: 30, Valid: true}}
+
+	testCases := []struct {
+		name          string
+		limits        ItemLimits
+		stats         GetUserItemPurchaseStatsRow
+		quantity      int32
+		wantLimit     string
+		wantRemaining int32
+	}{
+		{
+			name:     "OK_NoLimits",
+			stats:    GetUserItemPurchaseStatsRow{Owned: 100},
+			quantity: 5,
+		},
+		{
+			name:     "OK_FirstUnit",
+			limits:   onePerUser,
+			quantity: 1,
+		},
+		{
+			name:      "MaxPerUser",
+			limits:    onePerUser,
+			stats:     GetUserItemPurchaseStatsRow{Owned: 1},
+			quantity:  1,
+			wantLimit: PurchaseLimitMaxPerUser,
+		},
+		{
+			name:          "MaxPerUser_Quantity",
+			limits:        ItemLimits{MaxPerUser: pgtype.Int4{Int32: 3, Valid: true}},
+			stats:         GetUserItemPurchaseStatsRow{Owned: 1},
+			quantity:      3,
+			wantLimit:     PurchaseLimitMaxPerUser,
+			wantRemaining: 2,
+		},
+		{
+			name:     "OK_CooldownPassed",
+			limits:   monthly,
+			stats:    GetUserItemPurchaseStatsRow{Owned: 1, LastPurchaseAt: pgtype.Timestamp{Time: now.AddDate(0, 0, -31), Valid: true}},
+			quantity: 1,
+		},
+		{
+			name:      "Cooldown",
+			limits:    monthly,
+			stats:     GetUserItemPurchaseStatsRow{Owned: 1, LastPurchaseAt: pgtype.Timestamp{Time: now.AddDate(0, 0, -10), Valid: true}},
+			quantity:  1,
+			wantLimit: PurchaseLimitCooldown,
+		},
+		{
+			name:          "Cooldown_Quantity",
+			limits:        monthly,
+			quantity:      2,
+			wantLimit:     PurchaseLimitCooldown,
+			wantRemaining: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.limits.Check("pink-hoody", tc.stats, tc.quantity, now)
+			if tc.wantLimit == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var limitErr *PurchaseLimitError
+			require.True(t, errors.As(err, &limitErr))
+			require.ErrorIs(t, err, ErrPurchaseLimitExceeded)
+			require.Equal(t, tc.wantLimit, limitErr.Limit)
+			require.Equal(t, tc.wantRemaining, limitErr.Remaining)
+		})
+	}
+}
+
+func TestItemLimitsAllowance(t *testing.T) {
+	now := time.Now()
+	threePerUser := ItemLimits{MaxPerUser: pgtype.Int4{Int32: 3, Valid: true}}
+	monthly := ItemLimits{CooldownDays: pgtype.Int4{Int32: 30, Valid: true}}
+	recent := pgtype.Timestamp{Time: now.AddDate(0, 0, -10), Valid: true}
+
+	require.Equal(t, int32(math.MaxInt32), ItemLimits{}.Allowance(GetUserItemPurchaseStatsRow{Owned: 100}, now))
+	require.Equal(t, int32(2), threePerUser.Allowance(GetUserItemPurchaseStatsRow{Owned: 1}, now))
+	require.Zero(t, threePerUser.Allowance(GetUserItemPurchaseStatsRow{Owned: 4}, now))
+	require.Equal(t, int32(1), monthly.Allowance(GetUserItemPurchaseStatsRow{}, now))
+	require.Zero(t, monthly.Allowance(GetUserItemPurchaseStatsRow{Owned: 1, LastPurchaseAt: recent}, now))
+}
+
+func TestPurchaseLimitErrorAvailableAt(t *testing.T) {
+	last := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	limits := ItemLimits{CooldownDays: pgtype.Int4{Int32: 30, Valid: true}}
+	stats := GetUserItemPurchaseStatsRow{Owned: 1, LastPurchaseAt: pgtype.Timestamp{Time: last, Valid: true}}
+
+	err := limits.Check("cup", stats, 1, last.Add(time.Hour))
+
+	var limitErr *PurchaseLimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, last.AddDate(0, 0, 30), limitErr.AvailableAt)
+	require.Equal(t, "cup can be bought again after 2026-03-31T12:00:00Z", err.Error())
+}
+
+func TestGetUserItemPurchaseStats(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	buyer := createRandomUser(t)
+	recipient := createRandomUser(t)
+	colleague := createRandomUser(t)
+	item, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 10})
+	require.NoError(t, err)
+
+	getStats := func(userID int32) GetUserItemPurchaseStatsRow {
+		stats, err := testQueries.GetUserItemPurchaseStats(ctx, GetUserItemPurchaseStatsParams{
+			ItemID: pgtype.Int4{Int32: item.ID, Valid: true},
+			UserID: userID,
+		})
+		require.NoError(t, err)
+		return stats
+	}
+
+	// Подарок учитывается и у получателя, и у покупателя
+	bought, err := store.PurchaseTx(ctx, PurchaseTxParams{
+		UserID:      buyer.ID,
+		ItemID:      item.ID,
+		RecipientID: recipient.ID,
+	})
+	require.NoError(t, err)
+
+	for _, userID := range []int32{buyer.ID, recipient.ID} {
+		stats := getStats(userID)
+		require.Equal(t, int32(1), stats.Owned)
+		require.True(t, stats.LastPurchaseAt.Valid)
+	}
+
+	// Переданная единица остается в счете у отдавшего и добавляется получателю
+	for _, status := range []string{OrderStatusReadyForPickup, OrderStatusDelivered} {
+		_, err = store.UpdateOrderStatusTx(ctx, UpdateOrderStatusTxParams{
+			OrderID: bought.Purchase.OrderID.Int32,
+			Status:  status,
+		})
+		require.NoError(t, err)
+	}
+	_, err = store.GiveItemTx(ctx, GiveItemTxParams{FromUserID: recipient.ID, ToUserID: colleague.ID, ItemID: item.ID, Quantity: 1})
+	require.NoError(t, err)
+
+	require.Equal(t, int32(1), getStats(recipient.ID).Owned)
+	stats := getStats(colleague.ID)
+	require.Equal(t, int32(1), stats.Owned)
+	require.False(t, stats.LastPurchaseAt.Valid)
+
+	// Ограничение нельзя обойти ни перепродажей, ни покупками в подарок
+	_, err = testQueries.UpdateItemLimits(ctx, UpdateItemLimitsParams{
+		MaxPerUser:   pgtype.Int4{Int32: 1, Valid: true},
+		CooldownDays: pgtype.Int4{Int32: 30, Valid: true},
+		ID:           item.ID,
+	})
+	require.NoError(t, err)
+
+	other := createRandomUser(t)
+	for _, arg := range []PurchaseTxParams{
+		{UserID: recipient.ID, ItemID: item.ID},
+		{UserID: buyer.ID, ItemID: item.ID, RecipientID: other.ID},
+	} {
+		_, err = store.PurchaseTx(ctx, arg)
+		require.ErrorIs(t, err, ErrPurchaseLimitExceeded)
+	}
+}
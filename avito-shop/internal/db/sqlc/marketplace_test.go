@@ -0,0 +1,125 @@
+package db
+
+import (
+	util "avito-shop/internal/util"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarketplaceCommission(t *testing.T) {
+	require.Zero(t, MarketplaceCommission(100, 0))
+	require.Equal(t, int32(5), MarketplaceCommission(100, 5))
+	// Комиссия округляется вниз в пользу продавца
+	require.Equal(t, int32(1), MarketplaceCommission(15, 10))
+	require.Zero(t, MarketplaceCommission(9, 10))
+}
+
+func TestSplitLots(t *testing.T) {
+	expiresAt := pgtype.Timestamp{Time: time.Now(), Valid: true}
+	slices := []lotSlice{{Amount: 30, ExpiresAt: expiresAt}, {Amount: 20}}
+
+	head, tail := splitLots(slices, 40)
+	require.Equal(t, []lotSlice{{Amount: 30, ExpiresAt: expiresAt}, {Amount: 10}}, head)
+	require.Equal(t, []lotSlice{{Amount: 10}}, tail)
+
+	head, tail = splitLots(slices, 50)
+	require.Equal(t, slices, head)
+	require.Empty(t, tail)
+}
+
+func TestMarketplaceTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	item, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 10})
+	require.NoError(t, err)
+	seller := createRandomUser(t)
+	buyer := createRandomUser(t)
+
+	bought, err := store.PurchaseTx(ctx, PurchaseTxParams{UserID: seller.ID, ItemID: item.ID, Quantity: 1})
+	require.NoError(t, err)
+
+	// Единицу из невыданного заказа продать нельзя
+	_, err = store.CreateListingTx(ctx, CreateListingTxParams{SellerID: seller.ID, ItemID: item.ID, Price: 50})
+	require.ErrorIs(t, err, ErrNotEnoughItems)
+
+	for _, status := range []string{OrderStatusReadyForPickup, OrderStatusDelivered} {
+		_, err = store.UpdateOrderStatusTx(ctx, UpdateOrderStatusTxParams{
+			OrderID: bought.Purchase.OrderID.Int32,
+			Status:  status,
+		})
+		require.NoError(t, err)
+	}
+
+	created, err := store.CreateListingTx(ctx, CreateListingTxParams{SellerID: seller.ID, ItemID: item.ID, Price: 50})
+	require.NoError(t, err)
+	listing := created.Listing
+	require.Equal(t, ListingStatusActive, listing.Status)
+
+	// Выставленную единицу нельзя ни выставить повторно, ни отдать, ни вернуть
+	_, err = store.CreateListingTx(ctx, CreateListingTxParams{SellerID: seller.ID, ItemID: item.ID, Price: 40})
+	require.ErrorIs(t, err, ErrNotEnoughItems)
+	_, err = store.GiveItemTx(ctx, GiveItemTxParams{FromUserID: seller.ID, ToUserID: buyer.ID, ItemID: item.ID, Quantity: 1})
+	require.ErrorIs(t, err, ErrNotEnoughItems)
+	_, err = store.RefundTx(ctx, RefundTxParams{PurchaseID: bought.Purchase.ID, Quantity: 1, ReasonCode: ReasonCodeRefund})
+	require.ErrorIs(t, err, ErrItemTransferred)
+
+	_, err = store.MarketplaceTx(ctx, MarketplaceTxParams{ListingID: listing.ID, BuyerID: seller.ID, Now: time.Now()})
+	require.ErrorIs(t, err, ErrOwnListing)
+
+	// Оплата продавцу подчиняется лимитам на переводы покупателя
+	_, err = store.MarketplaceTx(ctx, MarketplaceTxParams{
+		ListingID: listing.ID,
+		BuyerID:   buyer.ID,
+		Limits:    TransferLimits{MaxAmount: 30},
+		Now:       time.Now(),
+	})
+	require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+	sold, err := store.MarketplaceTx(ctx, MarketplaceTxParams{
+		ListingID:         listing.ID,
+		BuyerID:           buyer.ID,
+		CommissionPercent: 10,
+		Now:               time.Now(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, ListingStatusSold, sold.Listing.Status)
+	require.Equal(t, int32(5), sold.Listing.Commission)
+	require.Equal(t, int32(950), sold.Buyer.Balance.Int32)
+	require.Equal(t, seller.Balance.Int32-item.Price+45, sold.Seller.Balance.Int32)
+	require.Equal(t, TransactionKindSale, sold.Payment.Kind)
+	require.Equal(t, TransactionKindCommission, sold.Commission.Kind)
+	require.False(t, sold.Commission.ReceiverID.Valid)
+
+	// Оплата продавцу учитывается в лимитах на переводы
+	stats, err := testQueries.GetOutgoingTransferStats(ctx, NewTransferStatsParams(buyer.ID, time.Now()))
+	require.NoError(t, err)
+	require.Equal(t, int32(45), stats.DailyAmount)
+
+	// Единица перешла покупателю
+	unit, err := testQueries.GetInventoryItem(ctx, listing.InventoryItemID)
+	require.NoError(t, err)
+	require.Equal(t, buyer.ID, unit.OwnerID)
+	require.Equal(t, AcquiredViaMarketplace, unit.AcquiredVia)
+
+	// Повторно купить нельзя
+	other := createRandomUser(t)
+	_, err = store.MarketplaceTx(ctx, MarketplaceTxParams{ListingID: listing.ID, BuyerID: other.ID, Now: time.Now()})
+	require.ErrorIs(t, err, ErrListingClosed)
+
+	// Балансы сходятся с журналом
+	for _, user := range []User{seller, buyer} {
+		rows, err := testQueries.GetBalanceReconciliation(ctx, pgtype.Int4{Int32: user.ID, Valid: true})
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, rows[0].Balance, rows[0].Received-rows[0].Sent-rows[0].Spent-rows[0].Reserved)
+		require.Equal(t, rows[0].Balance, rows[0].LotBalance)
+	}
+
+	_, err = store.MarketplaceTx(ctx, MarketplaceTxParams{ListingID: listing.ID + 1000000, BuyerID: other.ID, Now: time.Now()})
+	require.ErrorIs(t, err, ErrListingNotFound)
+}
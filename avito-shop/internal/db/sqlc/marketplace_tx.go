@@ -0,0 +1,260 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Статусы объявления на маркетплейсе
+const (
+	ListingStatusActive    = "active"
+	ListingStatusSold      = "sold"
+	ListingStatusCancelled = "cancelled"
+)
+
+// ReasonCodeMarketplaceCommission - причина списания комиссии магазина с продажи
+const ReasonCodeMarketplaceCommission = "marketplace_commission"
+
+// NotificationKindItemSold - уведомление продавцу о проданном товаре
+const NotificationKindItemSold = "item_sold"
+
+// Ошибки маркетплейса
+var (
+	ErrListingNotFound = errors.New("listing not found")
+	ErrListingClosed   = errors.New("listing is no longer active")
+	ErrOwnListing      = errors.New("cannot buy your own listing")
+)
+
+// MarketplaceCommission возвращает комиссию магазина с продажи по цене price,
+// округленную вниз: продавец получает price минус комиссию
+func MarketplaceCommission(price, percent int32) int32 {
+	if percent <= 0 {
+		return 0
+	}
+	return int32(int64(price) * int64(percent) / 100)
+}
+
+type CreateListingTxParams struct {
+	SellerID int32 `json:"seller_id"`
+	ItemID   int32 `json:"item_id"`
+	// VariantID - вариант товара, 0 - товар без вариантов
+	VariantID int32 `json:"variant_id"`
+	Price     int32 `json:"price"`
+}
+
+type CreateListingTxResult struct {
+	Listing MarketplaceListing `json:"listing"`
+}
+
+// CreateListingTx выставляет на продажу одну единицу товара из инвентаря продавца.
+// Продать, как и передать, можно только единицу из выданного заказа, которая еще не выставлена
+func (store *SQLStore) CreateListingTx(ctx context.Context, arg CreateListingTxParams) (CreateListingTxResult, error) {
+	var result CreateListingTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// Блокируем продавца: параллельные передачи и объявления не выберут одну единицу
+		err := q.LockUsers(ctx, []int32{arg.SellerID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		variantID := pgtype.Int4{Int32: arg.VariantID, Valid: arg.VariantID != 0}
+		units, err := q.ListTransferableUnits(ctx, ListTransferableUnitsParams{
+			OwnerID:   arg.SellerID,
+			ItemID:    arg.ItemID,
+			VariantID: variantID,
+			RowLimit:  1,
+		})
+		if err != nil {
+			return fmt.Errorf("error listing inventory: %v", err)
+		}
+		if len(units) == 0 {
+			return &NotEnoughItemsError{Available: 0}
+		}
+
+		result.Listing, err = q.CreateListing(ctx, CreateListingParams{
+			SellerID:        arg.SellerID,
+			InventoryItemID: units[0],
+			ItemID:          arg.ItemID,
+			VariantID:       variantID,
+			Price:           arg.Price,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating listing: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return CreateListingTxResult{}, fmt.Errorf("create listing tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+type MarketplaceTxParams struct {
+	ListingID int32 `json:"listing_id"`
+	BuyerID   int32 `json:"buyer_id"`
+	// CommissionPercent - доля цены, которая уходит магазину, 0 - без комиссии
+	CommissionPercent int32 `json:"commission_percent"`
+	// Limits - лимиты на переводы покупателя: оплата продавцу считается переводом,
+	// иначе объявление по завышенной цене обходит лимиты
+	Limits TransferLimits `json:"limits"`
+	Now    time.Time      `json:"now"`
+}
+
+type MarketplaceTxResult struct {
+	Listing MarketplaceListing `json:"listing"`
+	// Payment - зачисление продавцу за вычетом комиссии
+	Payment Transaction `json:"payment"`
+	// Commission - комиссия на системный счет; пустая, если комиссии нет
+	Commission Transaction `json:"commission"`
+	Buyer      User        `json:"buyer"`
+	Seller     User        `json:"seller"`
+}
+
+// MarketplaceTx продает единицу по объявлению: покупатель платит цену объявления,
+// продавец получает ее за вычетом комиссии магазина, комиссия уходит на системный счет,
+// а единица переходит покупателю. Монеты и товар переходят вместе или не переходят вовсе.
+func (store *SQLStore) MarketplaceTx(ctx context.Context, arg MarketplaceTxParams) (MarketplaceTxResult, error) {
+	var result MarketplaceTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем объявление: его не купят дважды и не снимут во время покупки
+		listing, err := q.GetListingForUpdate(ctx, arg.ListingID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrListingNotFound
+			}
+			return fmt.Errorf("error getting listing: %v", err)
+		}
+		if listing.Status != ListingStatusActive {
+			return ErrListingClosed
+		}
+		if listing.SellerID == arg.BuyerID {
+			return ErrOwnListing
+		}
+
+		err = q.LockUsers(ctx, []int32{arg.BuyerID, listing.SellerID})
+		if err != nil {
+			return fmt.Errorf("error locking users: %v", err)
+		}
+
+		// 2. Проверяем лимиты на переводы покупателя по сумме, которую получит продавец
+		commission := MarketplaceCommission(listing.Price, arg.CommissionPercent)
+		if arg.Limits.Enabled() {
+			stats, err := q.GetOutgoingTransferStats(ctx, NewTransferStatsParams(arg.BuyerID, arg.Now))
+			if err != nil {
+				return fmt.Errorf("error getting transfer stats: %v", err)
+			}
+			err = arg.Limits.Check(stats, listing.Price-commission)
+			if err != nil {
+				return err
+			}
+		}
+
+		// 3. Списываем цену у покупателя и делим ее между продавцом и системным счетом
+		slices, err := q.debitCoins(ctx, arg.BuyerID, listing.Price, arg.Now)
+		if err != nil {
+			return err
+		}
+		payout, _ := splitLots(slices, listing.Price-commission)
+
+		result.Payment, err = q.CreateAdjustment(ctx, CreateAdjustmentParams{
+			SenderID:   pgtype.Int4{Int32: arg.BuyerID, Valid: true},
+			ReceiverID: pgtype.Int4{Int32: listing.SellerID, Valid: true},
+			Amount:     listing.Price - commission,
+			Kind:       TransactionKindSale,
+			Comment:    pgtype.Text{String: fmt.Sprintf("marketplace listing #%d", listing.ID), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error creating payment: %v", err)
+		}
+		err = q.creditCoins(ctx, listing.SellerID, result.Payment.ID, payout)
+		if err != nil {
+			return err
+		}
+
+		if commission > 0 {
+			result.Commission, err = q.CreateAdjustment(ctx, CreateAdjustmentParams{
+				SenderID:   pgtype.Int4{Int32: arg.BuyerID, Valid: true},
+				Amount:     commission,
+				Kind:       TransactionKindCommission,
+				ReasonCode: pgtype.Text{String: ReasonCodeMarketplaceCommission, Valid: true},
+				Comment:    pgtype.Text{String: fmt.Sprintf("marketplace listing #%d", listing.ID), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error creating commission: %v", err)
+			}
+		}
+
+		// 4. Передаем единицу покупателю и записываем продажу в ее историю
+		units := []int32{listing.InventoryItemID}
+		err = q.TransferInventoryUnits(ctx, TransferInventoryUnitsParams{
+			OwnerID:      arg.BuyerID,
+			AcquiredVia:  AcquiredViaMarketplace,
+			AcquiredFrom: pgtype.Int4{Int32: listing.SellerID, Valid: true},
+			Ids:          units,
+		})
+		if err != nil {
+			return fmt.Errorf("error transferring item: %v", err)
+		}
+		err = q.CreateInventoryTransfers(ctx, CreateInventoryTransfersParams{
+			FromUserID: listing.SellerID,
+			ToUserID:   arg.BuyerID,
+			Kind:       InventoryTransferKindSale,
+			Ids:        units,
+		})
+		if err != nil {
+			return fmt.Errorf("error recording transfer: %v", err)
+		}
+
+		result.Listing, err = q.SellListing(ctx, SellListingParams{
+			BuyerID:    pgtype.Int4{Int32: arg.BuyerID, Valid: true},
+			Commission: commission,
+			PaymentID:  pgtype.Int4{Int32: result.Payment.ID, Valid: true},
+			ID:         listing.ID,
+		})
+		if err != nil {
+			return fmt.Errorf("error closing listing: %v", err)
+		}
+
+		result.Buyer, err = q.GetUserByID(ctx, arg.BuyerID)
+		if err != nil {
+			return fmt.Errorf("error getting buyer: %v", err)
+		}
+		result.Seller, err = q.GetUserByID(ctx, listing.SellerID)
+		if err != nil {
+			return fmt.Errorf("error getting seller: %v", err)
+		}
+
+		// 5. Уведомляем продавца
+		item, err := q.GetItemByID(ctx, listing.ItemID)
+		if err != nil {
+			return fmt.Errorf("error getting item: %v", err)
+		}
+		err = q.CreateNotification(ctx, CreateNotificationParams{
+			UserID:  listing.SellerID,
+			Kind:    NotificationKindItemSold,
+			ItemID:  pgtype.Int4{Int32: item.ID, Valid: true},
+			Message: fmt.Sprintf("%s bought your %s, you received %d coins", result.Buyer.Username, item.Name, result.Payment.Amount),
+		})
+		if err != nil {
+			return fmt.Errorf("error creating notification: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return MarketplaceTxResult{}, fmt.Errorf("marketplace tx error: %w", err)
+	}
+
+	return result, nil
+}
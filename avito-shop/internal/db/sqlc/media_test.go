@@ -0,0 +1,74 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	util "avito-shop/internal/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomItemImage(t *testing.T, item Item) ItemImage {
+	key := "items/" + util.RandomString(12)
+	image, err := testQueries.CreateItemImage(context.Background(), CreateItemImageParams{
+		ItemID:       item.ID,
+		StorageKey:   key + ".jpg",
+		ThumbnailKey: key + "_thumb.jpg",
+		ContentType:  "image/jpeg",
+		Width:        800,
+		Height:       600,
+	})
+	require.NoError(t, err)
+	require.Equal(t, item.ID, image.ItemID)
+	return image
+}
+
+func TestItemImages(t *testing.T) {
+	item := createRandomItem(t)
+	first := createRandomItemImage(t, item)
+	second := createRandomItemImage(t, item)
+
+	// Новые изображения добавляются в конец
+	require.Equal(t, int32(0), first.Position)
+	require.Equal(t, int32(1), second.Position)
+
+	images, err := testQueries.ListImagesForItems(context.Background(), []int32{item.ID})
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	require.Equal(t, first.ID, images[0].ID)
+
+	deleted, err := testQueries.DeleteItemImage(context.Background(), DeleteItemImageParams{ID: first.ID, ItemID: item.ID})
+	require.NoError(t, err)
+	require.Equal(t, first.StorageKey, deleted.StorageKey)
+
+	images, err = testQueries.ListItemImages(context.Background(), item.ID)
+	require.NoError(t, err)
+	require.Len(t, images, 1)
+	require.Equal(t, second.ID, images[0].ID)
+}
+
+func TestItemAttributes(t *testing.T) {
+	item := createRandomItem(t)
+
+	err := testQueries.AddItemAttributes(context.Background(), AddItemAttributesParams{
+		ItemID: item.ID,
+		Names:  []string{"color", "material"},
+		Values: []string{"white", "ceramic"},
+	})
+	require.NoError(t, err)
+
+	attributes, err := testQueries.ListItemAttributes(context.Background(), item.ID)
+	require.NoError(t, err)
+	require.Equal(t, []ItemAttribute{
+		{ItemID: item.ID, Name: "color", Value: "white"},
+		{ItemID: item.ID, Name: "material", Value: "ceramic"},
+	}, attributes)
+
+	err = testQueries.DeleteItemAttributes(context.Background(), item.ID)
+	require.NoError(t, err)
+
+	attributes, err = testQueries.ListAttributesForItems(context.Background(), []int32{item.ID})
+	require.NoError(t, err)
+	require.Empty(t, attributes)
+}
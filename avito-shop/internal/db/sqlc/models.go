@@ -8,19 +8,271 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Auction struct {
+	ID           int32            `json:"id"`
+	ItemID       int32            `json:"item_id"`
+	StartsAt     pgtype.Timestamp `json:"starts_at"`
+	EndsAt       pgtype.Timestamp `json:"ends_at"`
+	ReservePrice int32            `json:"reserve_price"`
+	MinIncrement int32            `json:"min_increment"`
+	Status       string           `json:"status"`
+	PurchaseID   pgtype.Int4      `json:"purchase_id"`
+	CreatedBy    pgtype.Int4      `json:"created_by"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+	ClosedAt     pgtype.Timestamp `json:"closed_at"`
+}
+
+type AuctionBid struct {
+	ID         int32            `json:"id"`
+	AuctionID  int32            `json:"auction_id"`
+	UserID     int32            `json:"user_id"`
+	Amount     int32            `json:"amount"`
+	Status     string           `json:"status"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+	ResolvedAt pgtype.Timestamp `json:"resolved_at"`
+}
+
+type AuctionBidLot struct {
+	ID        int32            `json:"id"`
+	BidID     int32            `json:"bid_id"`
+	Amount    int32            `json:"amount"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+type Bundle struct {
+	ID          int32            `json:"id"`
+	Name        string           `json:"name"`
+	Description pgtype.Text      `json:"description"`
+	Price       int32            `json:"price"`
+	Active      bool             `json:"active"`
+	CreatedBy   pgtype.Int4      `json:"created_by"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type BundleItem struct {
+	ID        int32       `json:"id"`
+	BundleID  int32       `json:"bundle_id"`
+	ItemID    int32       `json:"item_id"`
+	VariantID pgtype.Int4 `json:"variant_id"`
+	Quantity  int32       `json:"quantity"`
+}
+
+type CartItem struct {
+	UserID           int32            `json:"user_id"`
+	ItemID           int32            `json:"item_id"`
+	Quantity         int32            `json:"quantity"`
+	AddedAt          pgtype.Timestamp `json:"added_at"`
+	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	ID               int32            `json:"id"`
+	VariantID        pgtype.Int4      `json:"variant_id"`
+	ReservedQuantity int32            `json:"reserved_quantity"`
+	ReservedUntil    pgtype.Timestamp `json:"reserved_until"`
+}
+
+type Category struct {
+	ID   int32  `json:"id"`
+	Name string `json:"name"`
+}
+
+type CoinLot struct {
+	ID                  int32            `json:"id"`
+	UserID              int32            `json:"user_id"`
+	SourceTransactionID pgtype.Int4      `json:"source_transaction_id"`
+	Amount              int32            `json:"amount"`
+	Remaining           int32            `json:"remaining"`
+	AcquiredAt          pgtype.Timestamp `json:"acquired_at"`
+	ExpiresAt           pgtype.Timestamp `json:"expires_at"`
+}
+
+type DropQueue struct {
+	ItemID   int32            `json:"item_id"`
+	UserID   int32            `json:"user_id"`
+	Position int64            `json:"position"`
+	JoinedAt pgtype.Timestamp `json:"joined_at"`
+}
+
+type InventoryItem struct {
+	ID           int32            `json:"id"`
+	OwnerID      int32            `json:"owner_id"`
+	ItemID       int32            `json:"item_id"`
+	VariantID    pgtype.Int4      `json:"variant_id"`
+	PurchaseID   pgtype.Int4      `json:"purchase_id"`
+	AcquiredVia  string           `json:"acquired_via"`
+	AcquiredFrom pgtype.Int4      `json:"acquired_from"`
+	AcquiredAt   pgtype.Timestamp `json:"acquired_at"`
+}
+
+type InventoryTransfer struct {
+	ID              int32            `json:"id"`
+	InventoryItemID int32            `json:"inventory_item_id"`
+	FromUserID      pgtype.Int4      `json:"from_user_id"`
+	ToUserID        pgtype.Int4      `json:"to_user_id"`
+	Kind            string           `json:"kind"`
+	Message         pgtype.Text      `json:"message"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+}
+
 type Item struct {
-	ID    int32  `json:"id"`
-	Name  string `json:"name"`
-	Price int32  `json:"price"`
+	ID             int32            `json:"id"`
+	Name           string           `json:"name"`
+	Price          int32            `json:"price"`
+	Stock          pgtype.Int4      `json:"stock"`
+	CategoryID     pgtype.Int4      `json:"category_id"`
+	MaxPerUser     pgtype.Int4      `json:"max_per_user"`
+	CooldownDays   pgtype.Int4      `json:"cooldown_days"`
+	Description    pgtype.Text      `json:"description"`
+	AvailableFrom  pgtype.Timestamp `json:"available_from"`
+	AvailableUntil pgtype.Timestamp `json:"available_until"`
+	QueueMinutes   pgtype.Int4      `json:"queue_minutes"`
+	QueueRate      pgtype.Int4      `json:"queue_rate"`
 }
 
-type Purchase struct {
+type ItemAttribute struct {
+	ItemID int32  `json:"item_id"`
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+}
+
+type ItemImage struct {
 	ID           int32            `json:"id"`
-	BuyerID      pgtype.Int4      `json:"buyer_id"`
-	ItemID       pgtype.Int4      `json:"item_id"`
-	Quantity     int32            `json:"quantity"`
-	TotalCost    int32            `json:"total_cost"`
-	PurchaseDate pgtype.Timestamp `json:"purchase_date"`
+	ItemID       int32            `json:"item_id"`
+	StorageKey   string           `json:"storage_key"`
+	ThumbnailKey string           `json:"thumbnail_key"`
+	ContentType  string           `json:"content_type"`
+	Width        int32            `json:"width"`
+	Height       int32            `json:"height"`
+	Position     int32            `json:"position"`
+	CreatedBy    pgtype.Int4      `json:"created_by"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type ItemPrice struct {
+	ID            int32            `json:"id"`
+	ItemID        int32            `json:"item_id"`
+	Price         int32            `json:"price"`
+	EffectiveFrom pgtype.Timestamp `json:"effective_from"`
+	CreatedBy     pgtype.Int4      `json:"created_by"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type ItemTag struct {
+	ItemID int32  `json:"item_id"`
+	Tag    string `json:"tag"`
+}
+
+type ItemVariant struct {
+	ID        int32            `json:"id"`
+	ItemID    int32            `json:"item_id"`
+	Sku       string           `json:"sku"`
+	Size      pgtype.Text      `json:"size"`
+	Colour    pgtype.Text      `json:"colour"`
+	Price     pgtype.Int4      `json:"price"`
+	Stock     pgtype.Int4      `json:"stock"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type MarketplaceListing struct {
+	ID              int32            `json:"id"`
+	SellerID        int32            `json:"seller_id"`
+	InventoryItemID int32            `json:"inventory_item_id"`
+	ItemID          int32            `json:"item_id"`
+	VariantID       pgtype.Int4      `json:"variant_id"`
+	Price           int32            `json:"price"`
+	Status          string           `json:"status"`
+	BuyerID         pgtype.Int4      `json:"buyer_id"`
+	Commission      int32            `json:"commission"`
+	PaymentID       pgtype.Int4      `json:"payment_id"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	ClosedAt        pgtype.Timestamp `json:"closed_at"`
+}
+
+type Notification struct {
+	ID        int32            `json:"id"`
+	UserID    int32            `json:"user_id"`
+	Kind      string           `json:"kind"`
+	ItemID    pgtype.Int4      `json:"item_id"`
+	Message   string           `json:"message"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	ReadAt    pgtype.Timestamp `json:"read_at"`
+}
+
+type Order struct {
+	ID              int32            `json:"id"`
+	UserID          int32            `json:"user_id"`
+	TotalCost       int32            `json:"total_cost"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	Status          string           `json:"status"`
+	StatusUpdatedAt pgtype.Timestamp `json:"status_updated_at"`
+	BundleID        pgtype.Int4      `json:"bundle_id"`
+}
+
+type OrderStatusHistory struct {
+	ID        int32            `json:"id"`
+	OrderID   int32            `json:"order_id"`
+	Status    string           `json:"status"`
+	Note      pgtype.Text      `json:"note"`
+	ChangedBy pgtype.Int4      `json:"changed_by"`
+	ChangedAt pgtype.Timestamp `json:"changed_at"`
+}
+
+type PromoCode struct {
+	ID             int32            `json:"id"`
+	Code           string           `json:"code"`
+	Description    pgtype.Text      `json:"description"`
+	DiscountType   string           `json:"discount_type"`
+	DiscountValue  int32            `json:"discount_value"`
+	ItemID         pgtype.Int4      `json:"item_id"`
+	CategoryID     pgtype.Int4      `json:"category_id"`
+	ValidFrom      pgtype.Timestamp `json:"valid_from"`
+	ValidUntil     pgtype.Timestamp `json:"valid_until"`
+	MaxUses        pgtype.Int4      `json:"max_uses"`
+	MaxUsesPerUser pgtype.Int4      `json:"max_uses_per_user"`
+	Uses           int32            `json:"uses"`
+	Active         bool             `json:"active"`
+	CreatedBy      pgtype.Int4      `json:"created_by"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
+type Purchase struct {
+	ID               int32            `json:"id"`
+	BuyerID          pgtype.Int4      `json:"buyer_id"`
+	ItemID           pgtype.Int4      `json:"item_id"`
+	Quantity         int32            `json:"quantity"`
+	TotalCost        int32            `json:"total_cost"`
+	PurchaseDate     pgtype.Timestamp `json:"purchase_date"`
+	RefundedQuantity int32            `json:"refunded_quantity"`
+	RefundedAt       pgtype.Timestamp `json:"refunded_at"`
+	RecipientID      pgtype.Int4      `json:"recipient_id"`
+	GiftMessage      pgtype.Text      `json:"gift_message"`
+	OrderID          pgtype.Int4      `json:"order_id"`
+	VariantID        pgtype.Int4      `json:"variant_id"`
+	PromoCodeID      pgtype.Int4      `json:"promo_code_id"`
+	Discount         int32            `json:"discount"`
+	UnitPrice        int32            `json:"unit_price"`
+}
+
+type Raffle struct {
+	ID                int32            `json:"id"`
+	ItemID            int32            `json:"item_id"`
+	TicketPrice       int32            `json:"ticket_price"`
+	MaxTicketsPerUser pgtype.Int4      `json:"max_tickets_per_user"`
+	Winners           int32            `json:"winners"`
+	SalesEndAt        pgtype.Timestamp `json:"sales_end_at"`
+	SeedHash          string           `json:"seed_hash"`
+	Seed              string           `json:"seed"`
+	Status            string           `json:"status"`
+	CreatedBy         pgtype.Int4      `json:"created_by"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+	DrawnAt           pgtype.Timestamp `json:"drawn_at"`
+}
+
+type RaffleTicket struct {
+	ID            int32            `json:"id"`
+	RaffleID      int32            `json:"raffle_id"`
+	UserID        int32            `json:"user_id"`
+	TransactionID int32            `json:"transaction_id"`
+	PurchaseID    pgtype.Int4      `json:"purchase_id"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
 }
 
 type Transaction struct {
@@ -29,6 +281,21 @@ type Transaction struct {
 	ReceiverID pgtype.Int4      `json:"receiver_id"`
 	Amount     int32            `json:"amount"`
 	Timestamp  pgtype.Timestamp `json:"timestamp"`
+	Status     string           `json:"status"`
+	SettlesAt  pgtype.Timestamp `json:"settles_at"`
+	ResolvedAt pgtype.Timestamp `json:"resolved_at"`
+	Kind       string           `json:"kind"`
+	ReasonCode pgtype.Text      `json:"reason_code"`
+	Comment    pgtype.Text      `json:"comment"`
+	CreatedBy  pgtype.Int4      `json:"created_by"`
+	PurchaseID pgtype.Int4      `json:"purchase_id"`
+}
+
+type TransactionLot struct {
+	ID            int32            `json:"id"`
+	TransactionID int32            `json:"transaction_id"`
+	Amount        int32            `json:"amount"`
+	ExpiresAt     pgtype.Timestamp `json:"expires_at"`
 }
 
 type User struct {
@@ -38,4 +305,13 @@ type User struct {
 	Balance      pgtype.Int4      `json:"balance"`
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
 	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+	Role         string           `json:"role"`
+	Department   pgtype.Text      `json:"department"`
+}
+
+type WishlistItem struct {
+	UserID             int32            `json:"user_id"`
+	ItemID             int32            `json:"item_id"`
+	AffordableNotified bool             `json:"affordable_notified"`
+	CreatedAt          pgtype.Timestamp `json:"created_at"`
 }
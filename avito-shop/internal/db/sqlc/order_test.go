@@ -0,0 +1,44 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderStatusHistory(t *testing.T) {
+	user := createRandomUser(t)
+	admin := createRandomUser(t)
+
+	order, err := testQueries.placeOrder(context.Background(), user.ID, 120)
+	require.NoError(t, err)
+	require.Equal(t, OrderStatusPlaced, order.Status)
+	require.Equal(t, int32(120), order.TotalCost)
+
+	updated, err := testQueries.UpdateOrderStatus(context.Background(), UpdateOrderStatusParams{
+		ID:     order.ID,
+		Status: OrderStatusReadyForPickup,
+	})
+	require.NoError(t, err)
+	require.Equal(t, OrderStatusReadyForPickup, updated.Status)
+	require.False(t, updated.StatusUpdatedAt.Time.Before(order.StatusUpdatedAt.Time))
+
+	_, err = testQueries.CreateOrderStatusChange(context.Background(), CreateOrderStatusChangeParams{
+		OrderID:   order.ID,
+		Status:    OrderStatusReadyForPickup,
+		Note:      pgtype.Text{String: "shelf B2", Valid: true},
+		ChangedBy: pgtype.Int4{Int32: admin.ID, Valid: true},
+	})
+	require.NoError(t, err)
+
+	history, err := testQueries.GetOrderStatusHistory(context.Background(), order.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	require.Equal(t, OrderStatusPlaced, history[0].Status)
+	require.False(t, history[0].ChangedBy.Valid)
+	require.Equal(t, OrderStatusReadyForPickup, history[1].Status)
+	require.Equal(t, "shelf B2", history[1].Note.String)
+	require.Equal(t, admin.Username, history[1].ChangedBy.String)
+}
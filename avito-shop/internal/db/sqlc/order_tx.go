@@ -0,0 +1,182 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Статусы выдачи заказа
+const (
+	OrderStatusPlaced         = "placed"
+	OrderStatusReadyForPickup = "ready_for_pickup"
+	OrderStatusShipped        = "shipped"
+	OrderStatusDelivered      = "delivered"
+	OrderStatusCancelled      = "cancelled"
+)
+
+// ReasonCodeOrderCancelled - причина возврата монет за отмененный заказ
+const ReasonCodeOrderCancelled = "order_cancelled"
+
+// orderTransitions - допустимые переходы; delivered и cancelled конечные
+var orderTransitions = map[string][]string{
+	OrderStatusPlaced:         {OrderStatusReadyForPickup, OrderStatusShipped, OrderStatusCancelled},
+	OrderStatusReadyForPickup: {OrderStatusDelivered, OrderStatusCancelled},
+	OrderStatusShipped:        {OrderStatusDelivered},
+}
+
+// Ошибки смены статуса заказа
+var (
+	ErrOrderNotFound          = errors.New("order not found")
+	ErrInvalidOrderTransition = errors.New("invalid order status transition")
+)
+
+// OrderTransitionError описывает недопустимый переход между статусами
+type OrderTransitionError struct {
+	From string
+	To   string
+}
+
+func (e *OrderTransitionError) Error() string {
+	return fmt.Sprintf("cannot change order status from %s to %s", e.From, e.To)
+}
+
+func (e *OrderTransitionError) Unwrap() error {
+	return ErrInvalidOrderTransition
+}
+
+// CanTransitionOrder проверяет, можно ли перевести заказ из статуса from в статус to
+func CanTransitionOrder(from, to string) bool {
+	for _, next := range orderTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+type UpdateOrderStatusTxParams struct {
+	OrderID int32  `json:"order_id"`
+	Status  string `json:"status"`
+	Note    string `json:"note"`
+	// AdminID - кто изменил статус
+	AdminID int32 `json:"admin_id"`
+	// CoinTTL - срок действия монет, возвращаемых при отмене, 0 - бессрочно
+	CoinTTL time.Duration `json:"coin_ttl"`
+}
+
+type UpdateOrderStatusTxResult struct {
+	Order  Order              `json:"order"`
+	Change OrderStatusHistory `json:"change"`
+	// Refunded - сколько монет вернулось покупателю при отмене
+	Refunded int32 `json:"refunded"`
+}
+
+// UpdateOrderStatusTx переводит заказ в новый статус и записывает изменение в историю.
+// При отмене все невозвращенные единицы заказа возвращаются на склад, а монеты - покупателю.
+func (store *SQLStore) UpdateOrderStatusTx(ctx context.Context, arg UpdateOrderStatusTxParams) (UpdateOrderStatusTxResult, error) {
+	var result UpdateOrderStatusTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Находим покупателя, чтобы заблокировать его раньше заказа
+		order, err := q.GetOrderByID(ctx, arg.OrderID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrOrderNotFound
+			}
+			return fmt.Errorf("error getting order: %v", err)
+		}
+
+		err = q.LockUsers(ctx, []int32{order.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		// 2. Блокируем заказ и проверяем переход по актуальному статусу
+		order, err = q.GetOrderForUpdate(ctx, arg.OrderID)
+		if err != nil {
+			return fmt.Errorf("error getting order: %v", err)
+		}
+		if !CanTransitionOrder(order.Status, arg.Status) {
+			return &OrderTransitionError{From: order.Status, To: arg.Status}
+		}
+
+		// 3. При отмене возвращаем все, что еще не было возвращено
+		if arg.Status == OrderStatusCancelled {
+			purchases, err := q.ListOrderPurchasesForUpdate(ctx, pgtype.Int4{Int32: order.ID, Valid: true})
+			if err != nil {
+				return fmt.Errorf("error listing order purchases: %v", err)
+			}
+
+			refundArg := RefundTxParams{
+				AdminID:    arg.AdminID,
+				ReasonCode: ReasonCodeOrderCancelled,
+				Comment:    arg.Note,
+				CoinTTL:    arg.CoinTTL,
+			}
+			for _, purchase := range purchases {
+				left := purchase.Quantity - purchase.RefundedQuantity
+				if left <= 0 || !purchase.BuyerID.Valid {
+					continue
+				}
+				_, refund, err := q.refundUnits(ctx, purchase, left, refundArg)
+				if err != nil {
+					return err
+				}
+				result.Refunded += refund.Amount
+			}
+		}
+
+		// 4. Меняем статус и записываем изменение
+		result.Order, err = q.UpdateOrderStatus(ctx, UpdateOrderStatusParams{
+			ID:     order.ID,
+			Status: arg.Status,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating order status: %v", err)
+		}
+
+		result.Change, err = q.CreateOrderStatusChange(ctx, CreateOrderStatusChangeParams{
+			OrderID:   order.ID,
+			Status:    arg.Status,
+			Note:      pgtype.Text{String: arg.Note, Valid: arg.Note != ""},
+			ChangedBy: pgtype.Int4{Int32: arg.AdminID, Valid: arg.AdminID != 0},
+		})
+		if err != nil {
+			return fmt.Errorf("error recording status change: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return UpdateOrderStatusTxResult{}, fmt.Errorf("update order status tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// placeOrder создает заказ в статусе placed вместе с первой записью истории
+func (q *Queries) placeOrder(ctx context.Context, userID, totalCost int32) (Order, error) {
+	order, err := q.CreateOrder(ctx, CreateOrderParams{
+		UserID:    userID,
+		TotalCost: totalCost,
+	})
+	if err != nil {
+		return Order{}, fmt.Errorf("error creating order: %v", err)
+	}
+
+	_, err = q.CreateOrderStatusChange(ctx, CreateOrderStatusChangeParams{
+		OrderID: order.ID,
+		Status:  order.Status,
+	})
+	if err != nil {
+		return Order{}, fmt.Errorf("error recording order status: %v", err)
+	}
+
+	return order, nil
+}
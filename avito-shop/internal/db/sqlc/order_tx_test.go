@@ -0,0 +1,37 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCanTransitionOrder(t *testing.T) {
+	testCases := []struct {
+		from, to string
+		allowed  bool
+	}{
+		{OrderStatusPlaced, OrderStatusReadyForPickup, true},
+		{OrderStatusPlaced, OrderStatusShipped, true},
+		{OrderStatusPlaced, OrderStatusCancelled, true},
+		{OrderStatusPlaced, OrderStatusDelivered, false},
+		{OrderStatusReadyForPickup, OrderStatusDelivered, true},
+		{OrderStatusReadyForPickup, OrderStatusCancelled, true},
+		{OrderStatusReadyForPickup, OrderStatusShipped, false},
+		{OrderStatusShipped, OrderStatusDelivered, true},
+		{OrderStatusShipped, OrderStatusCancelled, false},
+		{OrderStatusDelivered, OrderStatusCancelled, false},
+		{OrderStatusCancelled, OrderStatusPlaced, false},
+		{OrderStatusPlaced, OrderStatusPlaced, false},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.allowed, CanTransitionOrder(tc.from, tc.to), "%s -> %s", tc.from, tc.to)
+	}
+}
+
+func TestOrderTransitionError(t *testing.T) {
+	err := &OrderTransitionError{From: OrderStatusDelivered, To: OrderStatusCancelled}
+	require.ErrorIs(t, err, ErrInvalidOrderTransition)
+	require.Equal(t, "cannot change order status from delivered to cancelled", err.Error())
+}
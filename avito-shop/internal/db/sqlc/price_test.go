@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDueItemPrices(t *testing.T) {
+	item := createRandomItem(t)
+	now := time.Now()
+
+	past, err := testQueries.CreateItemPrice(context.Background(), CreateItemPriceParams{
+		ItemID:        item.ID,
+		Price:         item.Price + 5,
+		EffectiveFrom: pgtype.Timestamp{Time: now.Add(-time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+
+	future, err := testQueries.CreateItemPrice(context.Background(), CreateItemPriceParams{
+		ItemID:        item.ID,
+		Price:         item.Price + 10,
+		EffectiveFrom: pgtype.Timestamp{Time: now.Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+
+	// Вводится в действие только наступившая цена, прежняя возвращается для сравнения
+	changed, err := testQueries.ApplyDueItemPrices(context.Background(), pgtype.Timestamp{Time: now, Valid: true})
+	require.NoError(t, err)
+
+	var found *ApplyDueItemPricesRow
+	for i := range changed {
+		if changed[i].ID == item.ID {
+			found = &changed[i]
+		}
+	}
+	require.NotNil(t, found)
+	require.Equal(t, item.Price, found.OldPrice)
+	require.Equal(t, past.Price, found.Price)
+
+	// Повторный запуск ничего не меняет
+	changed, err = testQueries.ApplyDueItemPrices(context.Background(), pgtype.Timestamp{Time: now, Valid: true})
+	require.NoError(t, err)
+	for _, row := range changed {
+		require.NotEqual(t, item.ID, row.ID)
+	}
+
+	// Наступившую цену отменить нельзя, запланированную - можно
+	deleted, err := testQueries.DeleteScheduledItemPrice(context.Background(), DeleteScheduledItemPriceParams{
+		ID:     past.ID,
+		ItemID: item.ID,
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, deleted)
+
+	deleted, err = testQueries.DeleteScheduledItemPrice(context.Background(), DeleteScheduledItemPriceParams{
+		ID:     future.ID,
+		ItemID: item.ID,
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), deleted)
+
+	prices, err := testQueries.ListItemPrices(context.Background(), item.ID)
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	require.Equal(t, past.ID, prices[0].ID)
+}
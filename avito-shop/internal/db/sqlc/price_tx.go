@@ -0,0 +1,83 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type ChangeItemPriceTxParams struct {
+	ItemID  int32 `json:"item_id"`
+	Price   int32 `json:"price"`
+	AdminID int32 `json:"admin_id"`
+	// EffectiveFrom - момент вступления цены в силу; нулевое значение или момент
+	// в прошлом - цена меняется сразу, иначе изменение планируется
+	EffectiveFrom time.Time `json:"effective_from"`
+}
+
+type ChangeItemPriceTxResult struct {
+	Price     ItemPrice `json:"price"`
+	Scheduled bool      `json:"scheduled"`
+}
+
+// ChangeItemPriceTx записывает новую цену в историю. Немедленное изменение
+// сразу обновляет items.price и уведомляет о снижении цены тех, у кого товар
+// в списке желаний; запланированное применит PriceWorker.
+// Задним числом цену изменить нельзя: история только дополняется.
+func (store *SQLStore) ChangeItemPriceTx(ctx context.Context, arg ChangeItemPriceTxParams) (ChangeItemPriceTxResult, error) {
+	var result ChangeItemPriceTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		effectiveFrom := arg.EffectiveFrom
+		now := time.Now()
+		result.Scheduled = effectiveFrom.After(now)
+		if !result.Scheduled {
+			effectiveFrom = now
+		}
+
+		var err error
+		result.Price, err = q.CreateItemPrice(ctx, CreateItemPriceParams{
+			ItemID:        arg.ItemID,
+			Price:         arg.Price,
+			EffectiveFrom: pgtype.Timestamp{Time: effectiveFrom, Valid: true},
+			CreatedBy:     pgtype.Int4{Int32: arg.AdminID, Valid: arg.AdminID != 0},
+		})
+		if err != nil {
+			return fmt.Errorf("error creating item price: %w", err)
+		}
+
+		if result.Scheduled {
+			return nil
+		}
+
+		item, err := q.GetItemByID(ctx, arg.ItemID)
+		if err != nil {
+			return fmt.Errorf("error getting item: %v", err)
+		}
+
+		err = q.UpdateItemPrice(ctx, UpdateItemPriceParams{Price: arg.Price, ID: arg.ItemID})
+		if err != nil {
+			return fmt.Errorf("error updating item price: %v", err)
+		}
+
+		if arg.Price < item.Price {
+			_, err = q.CreatePriceDropNotifications(ctx, CreatePriceDropNotificationsParams{
+				OldPrice: item.Price,
+				Price:    arg.Price,
+				ItemID:   arg.ItemID,
+			})
+			if err != nil {
+				return fmt.Errorf("error creating price drop notifications: %v", err)
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		return ChangeItemPriceTxResult{}, fmt.Errorf("change item price tx error: %w", err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Виды скидки промокода
+const (
+	DiscountTypePercent = "percent"
+	DiscountTypeFixed   = "fixed"
+)
+
+// Причины, по которым промокод не применяется к покупке
+const (
+	PromoReasonInactive     = "inactive"
+	PromoReasonNotStarted   = "not_started"
+	PromoReasonExpired      = "expired"
+	PromoReasonNotApplies   = "not_applicable"
+	PromoReasonExhausted    = "exhausted"
+	PromoReasonUserExceeded = "user_limit"
+)
+
+// Ошибки применения промокода
+var (
+	ErrPromoCodeNotFound = errors.New("promo code not found")
+	// ErrPromoCodeRejected позволяет проверить любую PromoCodeError через errors.Is
+	ErrPromoCodeRejected = errors.New("promo code rejected")
+)
+
+// PromoCodeError описывает, почему промокод нельзя применить
+type PromoCodeError struct {
+	Code   string
+	Reason string
+}
+
+func (e *PromoCodeError) Error() string {
+	switch e.Reason {
+	case PromoReasonNotStarted:
+		return fmt.Sprintf("promo code %s is not active yet", e.Code)
+	case PromoReasonExpired:
+		return fmt.Sprintf("promo code %s has expired", e.Code)
+	case PromoReasonNotApplies:
+		return fmt.Sprintf("promo code %s does not apply to this item", e.Code)
+	case PromoReasonExhausted:
+		return fmt.Sprintf("promo code %s has been used up", e.Code)
+	case PromoReasonUserExceeded:
+		return fmt.Sprintf("promo code %s usage limit per user reached", e.Code)
+	default:
+		return fmt.Sprintf("promo code %s is not active", e.Code)
+	}
+}
+
+func (e *PromoCodeError) Unwrap() error {
+	return ErrPromoCodeRejected
+}
+
+// NormalizePromoCode приводит код к виду, в котором он хранится в БД
+func NormalizePromoCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// Discount - скидка на цену price. Процент округляется вниз,
+// фиксированная скидка не может превышать цену.
+func (promo PromoCode) Discount(price int32) int32 {
+	var discount int64
+	switch promo.DiscountType {
+	case DiscountTypePercent:
+		discount = int64(price) * int64(promo.DiscountValue) / 100
+	case DiscountTypeFixed:
+		discount = int64(promo.DiscountValue)
+	}
+	if discount > int64(price) {
+		discount = int64(price)
+	}
+	return int32(discount)
+}
+
+// Check проверяет, можно ли применить промокод к товару item в момент now,
+// если пользователь уже использовал его userUses раз
+func (promo PromoCode) Check(item Item, now time.Time, userUses int32) error {
+	reject := func(reason string) error {
+		return &PromoCodeError{Code: promo.Code, Reason: reason}
+	}
+
+	if !promo.Active {
+		return reject(PromoReasonInactive)
+	}
+	if promo.ValidFrom.Valid && now.Before(promo.ValidFrom.Time) {
+		return reject(PromoReasonNotStarted)
+	}
+	if promo.ValidUntil.Valid && !now.Before(promo.ValidUntil.Time) {
+		return reject(PromoReasonExpired)
+	}
+	if promo.ItemID.Valid && promo.ItemID.Int32 != item.ID {
+		return reject(PromoReasonNotApplies)
+	}
+	if promo.CategoryID.Valid && (!item.CategoryID.Valid || item.CategoryID.Int32 != promo.CategoryID.Int32) {
+		return reject(PromoReasonNotApplies)
+	}
+	if promo.MaxUses.Valid && promo.Uses >= promo.MaxUses.Int32 {
+		return reject(PromoReasonExhausted)
+	}
+	if promo.MaxUsesPerUser.Valid && userUses >= promo.MaxUsesPerUser.Int32 {
+		return reject(PromoReasonUserExceeded)
+	}
+	return nil
+}
+
+// applyPromoCode блокирует промокод, проверяет его для покупки товара
+// пользователем и возвращает промокод со скидкой на цену price
+func (q *Queries) applyPromoCode(ctx context.Context, code string, userID int32, item Item, price int32) (PromoCode, int32, error) {
+	promo, err := q.lockPromoCode(ctx, code)
+	if err != nil {
+		return PromoCode{}, 0, err
+	}
+
+	if err = q.checkPromoCode(ctx, promo, userID, item); err != nil {
+		return PromoCode{}, 0, err
+	}
+
+	err = q.IncrementPromoCodeUses(ctx, promo.ID)
+	if err != nil {
+		return PromoCode{}, 0, fmt.Errorf("error updating promo code uses: %v", err)
+	}
+
+	return promo, promo.Discount(price), nil
+}
+
+// cartLineDiscount - скидка промокода на позицию корзины; Applies - действует ли
+// промокод на позицию, даже если после округления скидка нулевая
+type cartLineDiscount struct {
+	Applies bool
+	Amount  int32
+}
+
+// applyCartPromoCode блокирует промокод, проверяет его для позиций корзины и возвращает
+// промокод и скидку на каждую позицию. Скидка считается от суммы позиций, на которые
+// действует промокод, и делится между ними пропорционально их стоимости
+func (q *Queries) applyCartPromoCode(ctx context.Context, code string, userID int32, lines []ListCartItemsForUpdateRow) (PromoCode, []cartLineDiscount, error) {
+	promo, err := q.lockPromoCode(ctx, code)
+	if err != nil {
+		return PromoCode{}, nil, err
+	}
+
+	// Позиции, на которые промокод не действует, оплачиваются полностью;
+	// остальные причины отказа касаются всего заказа
+	discounts := make([]cartLineDiscount, len(lines))
+	costs := make([]int64, len(lines))
+	var total int64
+	applies := false
+	for i, line := range lines {
+		item := Item{ID: line.ItemID, CategoryID: line.CategoryID}
+		err = q.checkPromoCode(ctx, promo, userID, item)
+		var promoErr *PromoCodeError
+		if errors.As(err, &promoErr) && promoErr.Reason == PromoReasonNotApplies {
+			continue
+		}
+		if err != nil {
+			return PromoCode{}, nil, err
+		}
+		discounts[i].Applies = true
+		applies = true
+		costs[i] = int64(line.Price) * int64(line.Quantity)
+		total += costs[i]
+	}
+	if !applies {
+		return PromoCode{}, nil, &PromoCodeError{Code: promo.Code, Reason: PromoReasonNotApplies}
+	}
+
+	err = q.IncrementPromoCodeUses(ctx, promo.ID)
+	if err != nil {
+		return PromoCode{}, nil, fmt.Errorf("error updating promo code uses: %v", err)
+	}
+
+	// Делим по накопленной стоимости, как refundAmount: сумма скидок по позициям
+	// равна скидке на заказ, и ни одна позиция не получает скидку больше своей стоимости
+	discount := int64(promo.Discount(int32(total)))
+	var cumulative int64
+	for i, cost := range costs {
+		if total > 0 {
+			discounts[i].Amount = int32(discount*(cumulative+cost)/total - discount*cumulative/total)
+		}
+		cumulative += cost
+	}
+
+	return promo, discounts, nil
+}
+
+// lockPromoCode блокирует промокод до конца транзакции, чтобы параллельные покупки
+// не превысили лимиты использований
+func (q *Queries) lockPromoCode(ctx context.Context, code string) (PromoCode, error) {
+	promo, err := q.GetPromoCodeForUpdate(ctx, NormalizePromoCode(code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return PromoCode{}, ErrPromoCodeNotFound
+		}
+		return PromoCode{}, fmt.Errorf("error getting promo code: %v", err)
+	}
+	return promo, nil
+}
+
+// checkPromoCode проверяет промокод для покупки товара пользователем с учетом
+// его прошлых использований
+func (q *Queries) checkPromoCode(ctx context.Context, promo PromoCode, userID int32, item Item) error {
+	var userUses int32
+	if promo.MaxUsesPerUser.Valid {
+		var err error
+		userUses, err = q.CountUserPromoCodeUses(ctx, CountUserPromoCodeUsesParams{
+			PromoCodeID: pgtype.Int4{Int32: promo.ID, Valid: true},
+			BuyerID:     pgtype.Int4{Int32: userID, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error counting promo code uses: %v", err)
+		}
+	}
+
+	return promo.Check(item, time.Now(), userUses)
+}
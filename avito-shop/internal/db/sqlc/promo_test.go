@@ -0,0 +1,221 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	util "avito-shop/internal/util"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoCodeDiscount(t *testing.T) {
+	percent := PromoCode{DiscountType: DiscountTypePercent, DiscountValue: 15}
+	require.Equal(t, int32(15), percent.Discount(100))
+	// Процент округляется вниз
+	require.Equal(t, int32(1), percent.Discount(13))
+
+	fixed := PromoCode{DiscountType: DiscountTypeFixed, DiscountValue: 50}
+	require.Equal(t, int32(50), fixed.Discount(300))
+	// Скидка не больше цены
+	require.Equal(t, int32(20), fixed.Discount(20))
+}
+
+func TestPromoCodeCheck(t *testing.T) {
+	now := time.Now()
+	item := Item{ID: 7, CategoryID: pgtype.Int4{Int32: 2, Valid: true}}
+
+	testCases := []struct {
+		name       string
+		promo      PromoCode
+		userUses   int32
+		wantReason string
+	}{
+		{
+			name:  "OK_AnyItem",
+			promo: PromoCode{Active: true},
+		},
+		{
+			name: "OK_Category",
+			promo: PromoCode{
+				Active:         true,
+				CategoryID:     pgtype.Int4{Int32: 2, Valid: true},
+				ValidFrom:      pgtype.Timestamp{Time: now.Add(-time.Hour), Valid: true},
+				ValidUntil:     pgtype.Timestamp{Time: now.Add(time.Hour), Valid: true},
+				MaxUses:        pgtype.Int4{Int32: 10, Valid: true},
+				Uses:           9,
+				MaxUsesPerUser: pgtype.Int4{Int32: 2, Valid: true},
+			},
+			userUses: 1,
+		},
+		{
+			name:       "Inactive",
+			promo:      PromoCode{},
+			wantReason: PromoReasonInactive,
+		},
+		{
+			name:       "NotStarted",
+			promo:      PromoCode{Active: true, ValidFrom: pgtype.Timestamp{Time: now.Add(time.Hour), Valid: true}},
+			wantReason: PromoReasonNotStarted,
+		},
+		{
+			name:       "Expired",
+			promo:      PromoCode{Active: true, ValidUntil: pgtype.Timestamp{Time: now.Add(-time.Hour), Valid: true}},
+			wantReason: PromoReasonExpired,
+		},
+		{
+			name:       "OtherItem",
+			promo:      PromoCode{Active: true, ItemID: pgtype.Int4{Int32: 8, Valid: true}},
+			wantReason: PromoReasonNotApplies,
+		},
+		{
+			name:       "OtherCategory",
+			promo:      PromoCode{Active: true, CategoryID: pgtype.Int4{Int32: 3, Valid: true}},
+			wantReason: PromoReasonNotApplies,
+		},
+		{
+			name:       "Exhausted",
+			promo:      PromoCode{Active: true, MaxUses: pgtype.Int4{Int32: 5, Valid: true}, Uses: 5},
+			wantReason: PromoReasonExhausted,
+		},
+		{
+			name:       "UserLimit",
+			promo:      PromoCode{Active: true, MaxUsesPerUser: pgtype.Int4{Int32: 1, Valid: true}},
+			userUses:   1,
+			wantReason: PromoReasonUserExceeded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.promo.Check(item, now, tc.userUses)
+			if tc.wantReason == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			var promoErr *PromoCodeError
+			require.True(t, errors.As(err, &promoErr))
+			require.Equal(t, tc.wantReason, promoErr.Reason)
+			require.ErrorIs(t, err, ErrPromoCodeRejected)
+		})
+	}
+}
+
+func TestPromoCodeUses(t *testing.T) {
+	user := createRandomUser(t)
+	item := createRandomItem(t)
+
+	promo, err := testQueries.CreatePromoCode(context.Background(), CreatePromoCodeParams{
+		Code:           NormalizePromoCode(util.RandomString(8)),
+		DiscountType:   DiscountTypeFixed,
+		DiscountValue:  5,
+		ItemID:         pgtype.Int4{Int32: item.ID, Valid: true},
+		MaxUsesPerUser: pgtype.Int4{Int32: 1, Valid: true},
+		Active:         true,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreatePurchase(context.Background(), CreatePurchaseParams{
+		BuyerID:     pgtype.Int4{Int32: user.ID, Valid: true},
+		ItemID:      pgtype.Int4{Int32: item.ID, Valid: true},
+		Quantity:    1,
+		TotalCost:   item.Price - 5,
+		PromoCodeID: pgtype.Int4{Int32: promo.ID, Valid: true},
+		Discount:    5,
+		UnitPrice:   item.Price,
+	})
+	require.NoError(t, err)
+
+	err = testQueries.IncrementPromoCodeUses(context.Background(), promo.ID)
+	require.NoError(t, err)
+
+	locked, err := testQueries.GetPromoCodeForUpdate(context.Background(), promo.Code)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), locked.Uses)
+
+	uses, err := testQueries.CountUserPromoCodeUses(context.Background(), CountUserPromoCodeUsesParams{
+		PromoCodeID: pgtype.Int4{Int32: promo.ID, Valid: true},
+		BuyerID:     pgtype.Int4{Int32: user.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), uses)
+
+	var promoErr *PromoCodeError
+	err = locked.Check(item, time.Now(), uses)
+	require.True(t, errors.As(err, &promoErr))
+	require.Equal(t, PromoReasonUserExceeded, promoErr.Reason)
+}
+
+func TestCheckoutTxPromoCode(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	user := createRandomUser(t)
+
+	category, err := testQueries.CreateCategory(ctx, util.RandomString(8))
+	require.NoError(t, err)
+
+	// Промокод действует на две позиции из трех; скидка делится между ними по стоимости
+	prices := []int32{30, 70, 100}
+	for i, price := range prices {
+		item, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: price})
+		require.NoError(t, err)
+		if i < 2 {
+			err = testQueries.UpdateItemCategory(ctx, UpdateItemCategoryParams{
+				CategoryID: pgtype.Int4{Int32: category.ID, Valid: true},
+				ID:         item.ID,
+			})
+			require.NoError(t, err)
+		}
+		_, err = testQueries.AddCartItem(ctx, AddCartItemParams{UserID: user.ID, ItemID: item.ID, Quantity: 1})
+		require.NoError(t, err)
+	}
+
+	promo, err := testQueries.CreatePromoCode(ctx, CreatePromoCodeParams{
+		Code:           NormalizePromoCode(util.RandomString(8)),
+		DiscountType:   DiscountTypePercent,
+		DiscountValue:  25,
+		CategoryID:     pgtype.Int4{Int32: category.ID, Valid: true},
+		MaxUsesPerUser: pgtype.Int4{Int32: 1, Valid: true},
+		Active:         true,
+	})
+	require.NoError(t, err)
+
+	result, err := store.CheckoutTx(ctx, CheckoutTxParams{UserID: user.ID, PromoCode: promo.Code})
+	require.NoError(t, err)
+	require.Equal(t, int32(175), result.Order.TotalCost)
+	require.Equal(t, int32(825), result.User.Balance.Int32)
+
+	require.Len(t, result.Purchases, 3)
+	require.Equal(t, []int32{7, 18, 0}, []int32{
+		result.Purchases[0].Discount,
+		result.Purchases[1].Discount,
+		result.Purchases[2].Discount,
+	})
+	require.True(t, result.Purchases[1].PromoCodeID.Valid)
+	require.False(t, result.Purchases[2].PromoCodeID.Valid)
+
+	// Заказ расходует одно использование промокода
+	updated, err := testQueries.GetPromoCodeByID(ctx, promo.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), updated.Uses)
+
+	uses, err := testQueries.CountUserPromoCodeUses(ctx, CountUserPromoCodeUsesParams{
+		PromoCodeID: pgtype.Int4{Int32: promo.ID, Valid: true},
+		BuyerID:     pgtype.Int4{Int32: user.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), uses)
+
+	// Промокод, который не действует ни на одну позицию, отклоняет весь заказ
+	item := createRandomItem(t)
+	_, err = testQueries.AddCartItem(ctx, AddCartItemParams{UserID: user.ID, ItemID: item.ID, Quantity: 1})
+	require.NoError(t, err)
+	_, err = store.CheckoutTx(ctx, CheckoutTxParams{UserID: user.ID, PromoCode: promo.Code})
+	var promoErr *PromoCodeError
+	require.ErrorAs(t, err, &promoErr)
+	require.Equal(t, PromoReasonNotApplies, promoErr.Reason)
+}
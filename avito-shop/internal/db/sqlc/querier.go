@@ -11,19 +11,235 @@ import (
 )
 
 type Querier interface {
+	// Добавляет товар в корзину или увеличивает количество уже добавленного
+	AddCartItem(ctx context.Context, arg AddCartItemParams) (CartItem, error)
+	AddItemAttributes(ctx context.Context, arg AddItemAttributesParams) error
+	AddItemTags(ctx context.Context, arg AddItemTagsParams) error
+	// Товар, на который баланса уже хватает, не дает уведомления при добавлении.
+	// Баланс считается, как в GetSpendableBalance: по непросроченным лотам на момент now
+	AddWishlistItem(ctx context.Context, arg AddWishlistItemParams) (int64, error)
+	// Переносит в items.price последнюю наступившую цену каждого товара;
+	// возвращает только товары, цена которых изменилась, вместе с прежней ценой
+	ApplyDueItemPrices(ctx context.Context, now pgtype.Timestamp) ([]ApplyDueItemPricesRow, error)
+	// Снимает активное объявление продавца с продажи
+	CancelListing(ctx context.Context, arg CancelListingParams) (MarketplaceListing, error)
+	CloseAuction(ctx context.Context, arg CloseAuctionParams) error
+	ConsumeCoinLot(ctx context.Context, arg ConsumeCoinLotParams) error
+	CountDropQueue(ctx context.Context, itemID int32) (int32, error)
+	// Количество найденных товаров по категориям; фильтр категории не передается,
+	// чтобы были видны соседние категории
+	CountSearchItemsByCategory(ctx context.Context, arg CountSearchItemsByCategoryParams) ([]CountSearchItemsByCategoryRow, error)
+	// Количество найденных товаров по тегам; фильтр тега не передается
+	CountSearchItemsByTag(ctx context.Context, arg CountSearchItemsByTagParams) ([]CountSearchItemsByTagRow, error)
+	CountUnreadNotifications(ctx context.Context, userID int32) (int32, error)
+	// Одно оформление корзины - одно использование, даже если скидка досталась нескольким позициям заказа
+	CountUserPromoCodeUses(ctx context.Context, arg CountUserPromoCodeUsesParams) (int32, error)
+	CountUserRaffleTickets(ctx context.Context, arg CountUserRaffleTicketsParams) (int32, error)
+	CreateAdjustment(ctx context.Context, arg CreateAdjustmentParams) (Transaction, error)
+	// Уведомляет о товарах из списков желаний, на которые непросроченных монет стало хватать,
+	// и отмечает их, чтобы не уведомлять повторно
+	CreateAffordableNotifications(ctx context.Context, now pgtype.Timestamp) (int64, error)
+	CreateAuction(ctx context.Context, arg CreateAuctionParams) (Auction, error)
+	CreateAuctionBid(ctx context.Context, arg CreateAuctionBidParams) (AuctionBid, error)
+	CreateAuctionBidLot(ctx context.Context, arg CreateAuctionBidLotParams) error
+	CreateBundle(ctx context.Context, arg CreateBundleParams) (Bundle, error)
+	CreateBundleItem(ctx context.Context, arg CreateBundleItemParams) (BundleItem, error)
+	CreateCategory(ctx context.Context, name string) (Category, error)
+	CreateCoinLot(ctx context.Context, arg CreateCoinLotParams) (CoinLot, error)
+	CreateInventoryTransfers(ctx context.Context, arg CreateInventoryTransfersParams) error
 	CreateItem(ctx context.Context, arg CreateItemParams) (Item, error)
+	// Новое изображение встает в конец списка изображений товара
+	CreateItemImage(ctx context.Context, arg CreateItemImageParams) (ItemImage, error)
+	CreateItemPrice(ctx context.Context, arg CreateItemPriceParams) (ItemPrice, error)
+	CreateItemVariant(ctx context.Context, arg CreateItemVariantParams) (ItemVariant, error)
+	CreateListing(ctx context.Context, arg CreateListingParams) (MarketplaceListing, error)
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) error
+	CreateOrder(ctx context.Context, arg CreateOrderParams) (Order, error)
+	CreateOrderStatusChange(ctx context.Context, arg CreateOrderStatusChangeParams) (OrderStatusHistory, error)
+	CreatePendingTransfer(ctx context.Context, arg CreatePendingTransferParams) (Transaction, error)
+	// Уведомляет всех, у кого товар в списке желаний, о снижении цены
+	CreatePriceDropNotifications(ctx context.Context, arg CreatePriceDropNotificationsParams) (int64, error)
+	CreatePromoCode(ctx context.Context, arg CreatePromoCodeParams) (PromoCode, error)
 	CreatePurchase(ctx context.Context, arg CreatePurchaseParams) (Purchase, error)
+	// Единицы новой покупки переходят во владение получателя подарка или покупателя
+	CreatePurchaseUnits(ctx context.Context, id int32) error
+	CreateRaffle(ctx context.Context, arg CreateRaffleParams) (Raffle, error)
+	CreateRaffleTicket(ctx context.Context, arg CreateRaffleTicketParams) (RaffleTicket, error)
+	CreateRefund(ctx context.Context, arg CreateRefundParams) (Transaction, error)
+	CreateTransactionLot(ctx context.Context, arg CreateTransactionLotParams) error
 	CreateTransfer(ctx context.Context, arg CreateTransferParams) (Transaction, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	// Уменьшает остаток, если он отслеживается; 0 строк - товара не хватает
+	DecrementItemStock(ctx context.Context, arg DecrementItemStockParams) (int64, error)
+	// Уменьшает остаток варианта, если он отслеживается; 0 строк - варианта не хватает
+	DecrementVariantStock(ctx context.Context, arg DecrementVariantStockParams) (int64, error)
+	DeleteCartItem(ctx context.Context, arg DeleteCartItemParams) (int64, error)
+	DeleteCartItems(ctx context.Context, arg DeleteCartItemsParams) error
+	DeleteItemAttributes(ctx context.Context, itemID int32) error
+	DeleteItemImage(ctx context.Context, arg DeleteItemImageParams) (ItemImage, error)
+	DeleteItemTags(ctx context.Context, itemID int32) error
+	DeletePromoCode(ctx context.Context, id int32) (int64, error)
+	// Отменяет запланированное изменение цены; наступившие цены остаются в истории
+	DeleteScheduledItemPrice(ctx context.Context, arg DeleteScheduledItemPriceParams) (int64, error)
+	DrawRaffle(ctx context.Context, id int32) error
+	GetAuctionBidLots(ctx context.Context, bidID int32) ([]AuctionBidLot, error)
+	GetAuctionForUpdate(ctx context.Context, id int32) (Auction, error)
+	GetAuctionSummary(ctx context.Context, id int32) (GetAuctionSummaryRow, error)
+	// Баланс каждого пользователя рядом с тем, что следует из журнала операций:
+	// полученные зачисления минус отправленные (включая удержанные) минус покупки
+	// минус монеты, зарезервированные ставками на аукционах
+	GetBalanceReconciliation(ctx context.Context, userID pgtype.Int4) ([]GetBalanceReconciliationRow, error)
+	GetBundle(ctx context.Context, id int32) (Bundle, error)
+	GetCategoryByID(ctx context.Context, id int32) (Category, error)
+	GetCategoryByName(ctx context.Context, name string) (Category, error)
 	GetCurrentBalance(ctx context.Context, id int32) (pgtype.Int4, error)
+	// Сколько участников очереди стоят перед пользователем
+	GetDropQueueRank(ctx context.Context, arg GetDropQueueRankParams) (int32, error)
+	// Подарки, которые пользователь отправил или получил
+	GetGifts(ctx context.Context, buyerID pgtype.Int4) ([]GetGiftsRow, error)
+	// Текущие единицы пользователя по товарам, вариантам и статусам заказов, с которыми они пришли
+	GetInventory(ctx context.Context, ownerID int32) ([]GetInventoryRow, error)
+	GetInventoryItem(ctx context.Context, id int32) (InventoryItem, error)
 	GetItemByID(ctx context.Context, id int32) (Item, error)
-	GetItemByName(ctx context.Context, name string) (Item, error)
+	// Находит товар и, если задан артикул, его вариант; has_variants - покупка возможна только с вариантом
+	GetItemByName(ctx context.Context, arg GetItemByNameParams) (GetItemByNameRow, error)
+	// Блокирует остаток товара до конца транзакции, чтобы резервы корзин не менялись
+	// между проверкой и списанием
+	GetItemStockForUpdate(ctx context.Context, id int32) (pgtype.Int4, error)
+	GetItemVariantByID(ctx context.Context, id int32) (ItemVariant, error)
+	GetLeadingAuctionBid(ctx context.Context, auctionID int32) (AuctionBid, error)
+	// Глобальный баланс монет: все, что выпущено системой, должно быть
+	// на счетах пользователей, в эскроу, зарезервировано ставками,
+	// потрачено на покупки или возвращено системе
+	GetLedgerTotals(ctx context.Context) (GetLedgerTotalsRow, error)
+	GetListing(ctx context.Context, id int32) (MarketplaceListing, error)
+	GetListingForUpdate(ctx context.Context, id int32) (MarketplaceListing, error)
+	GetOrderByID(ctx context.Context, id int32) (Order, error)
+	GetOrderForUpdate(ctx context.Context, id int32) (Order, error)
+	GetOrderStatusHistory(ctx context.Context, orderID int32) ([]GetOrderStatusHistoryRow, error)
+	// Исходящие переводы и оплаты объявлений маркетплейса: покупка у другого пользователя тоже передает ему монеты
+	GetOutgoingTransferStats(ctx context.Context, arg GetOutgoingTransferStatsParams) (GetOutgoingTransferStatsRow, error)
+	GetPendingTransfers(ctx context.Context, senderID pgtype.Int4) ([]GetPendingTransfersRow, error)
+	GetPromoCode(ctx context.Context, code string) (PromoCode, error)
+	GetPromoCodeByID(ctx context.Context, id int32) (PromoCode, error)
+	// Блокирует промокод, чтобы параллельные покупки не превысили лимит использований
+	GetPromoCodeForUpdate(ctx context.Context, code string) (PromoCode, error)
+	GetPurchaseByID(ctx context.Context, id int32) (Purchase, error)
+	GetPurchaseForUpdate(ctx context.Context, id int32) (Purchase, error)
 	GetPurchases(ctx context.Context, buyerID pgtype.Int4) ([]GetPurchasesRow, error)
+	GetRaffleForUpdate(ctx context.Context, id int32) (Raffle, error)
+	GetRaffleSummary(ctx context.Context, arg GetRaffleSummaryParams) (GetRaffleSummaryRow, error)
+	// Сколько единиц товара или варианта зарезервировано в корзинах других пользователей
+	// в момент now; user_id = 0 - в корзинах всех пользователей
+	GetReservedStock(ctx context.Context, arg GetReservedStockParams) (int32, error)
+	// Сумма непросроченных лотов пользователя - сколько монет он может потратить в момент now
+	GetSpendableBalance(ctx context.Context, arg GetSpendableBalanceParams) (int32, error)
+	GetTransactionLots(ctx context.Context, transactionID int32) ([]TransactionLot, error)
 	GetTransactions(ctx context.Context, senderID pgtype.Int4) ([]GetTransactionsRow, error)
+	GetTransferForUpdate(ctx context.Context, id int32) (Transaction, error)
+	GetUpcomingExpirations(ctx context.Context, arg GetUpcomingExpirationsParams) ([]GetUpcomingExpirationsRow, error)
 	GetUserByID(ctx context.Context, id int32) (User, error)
 	GetUserByUsername(ctx context.Context, username string) (GetUserByUsernameRow, error)
+	// Сколько единиц товара пользователь получил без учета возвращенных: купил себе или в подарок коллеге, получил в подарок, передачей или на маркетплейсе. Отданные и проданные единицы остаются в счете, иначе ограничение обходится перепродажей. last_purchase_at - последняя покупка пользователя или для него
+	GetUserItemPurchaseStats(ctx context.Context, arg GetUserItemPurchaseStatsParams) (GetUserItemPurchaseStatsRow, error)
+	// Сколько единиц товара пользователь держит в активных резервах других позиций
+	// корзины, то есть других вариантов того же товара
+	GetUserReservedQuantity(ctx context.Context, arg GetUserReservedQuantityParams) (int32, error)
+	GetUserRole(ctx context.Context, username string) (string, error)
+	GetUsersByUsernames(ctx context.Context, usernames []string) ([]GetUsersByUsernamesRow, error)
+	GetVariantStockForUpdate(ctx context.Context, id int32) (pgtype.Int4, error)
+	IncrementPromoCodeUses(ctx context.Context, id int32) error
+	JoinDropQueue(ctx context.Context, arg JoinDropQueueParams) (int64, error)
+	ListAttributesForItems(ctx context.Context, itemIds []int32) ([]ItemAttribute, error)
+	// Аукционы с лидирующей ставкой и числом ставок; без status - все аукционы
+	ListAuctions(ctx context.Context, arg ListAuctionsParams) ([]ListAuctionsRow, error)
+	// Состав набора с ценами, остатками и ограничениями товаров, для покупки
+	ListBundleComponents(ctx context.Context, bundleID int32) ([]ListBundleComponentsRow, error)
+	// Состав нескольких наборов для показа в каталоге
+	ListBundleItems(ctx context.Context, bundleIds []int32) ([]ListBundleItemsRow, error)
+	// Наборы, без фильтра - все, включая снятые с продажи
+	ListBundles(ctx context.Context, active pgtype.Bool) ([]Bundle, error)
+	ListCartItems(ctx context.Context, userID int32) ([]ListCartItemsRow, error)
+	ListCartItemsForUpdate(ctx context.Context, userID int32) ([]ListCartItemsForUpdateRow, error)
+	ListCategories(ctx context.Context) ([]Category, error)
+	// Аукционы, прием ставок по которым закончился, а итог еще не подведен
+	ListDueAuctions(ctx context.Context, arg ListDueAuctionsParams) ([]int32, error)
+	ListDuePendingTransfers(ctx context.Context, arg ListDuePendingTransfersParams) ([]Transaction, error)
+	// Розыгрыши, продажа билетов по которым закончилась, а победители еще не выбраны
+	ListDueRaffles(ctx context.Context, arg ListDueRafflesParams) ([]int32, error)
+	ListExpiredCoinLots(ctx context.Context, arg ListExpiredCoinLotsParams) ([]CoinLot, error)
+	ListImagesForItems(ctx context.Context, itemIds []int32) ([]ItemImage, error)
+	// Цепочка владельцев единицы от первой передачи к последней
+	ListInventoryTransfers(ctx context.Context, inventoryItemID int32) ([]ListInventoryTransfersRow, error)
+	// Единицы пользователя с происхождением и активным объявлением о продаже
+	ListInventoryUnits(ctx context.Context, ownerID int32) ([]ListInventoryUnitsRow, error)
+	ListItemAttributes(ctx context.Context, itemID int32) ([]ItemAttribute, error)
+	ListItemImages(ctx context.Context, itemID int32) ([]ItemImage, error)
+	ListItemPrices(ctx context.Context, itemID int32) ([]ItemPrice, error)
+	ListItemTags(ctx context.Context, itemID int32) ([]string, error)
+	ListItemVariants(ctx context.Context, itemID int32) ([]ItemVariant, error)
+	// Объявления с продавцом и покупателем; без фильтра - все объявления, новые первыми
+	ListListings(ctx context.Context, arg ListListingsParams) ([]ListListingsRow, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	// Позиции заказов без возвращенных единиц, для выдачи
+	ListOrderLines(ctx context.Context, orderIds []int32) ([]ListOrderLinesRow, error)
+	ListOrderPurchasesForUpdate(ctx context.Context, orderID pgtype.Int4) ([]Purchase, error)
+	// Заказы для выдачи, без статуса - все; сначала самые старые
+	ListOrders(ctx context.Context, arg ListOrdersParams) ([]ListOrdersRow, error)
+	ListPromoCodes(ctx context.Context) ([]PromoCode, error)
+	// Билеты в порядке номеров: по этому списку повторяется выбор победителей
+	ListRaffleTickets(ctx context.Context, raffleID int32) ([]ListRaffleTicketsRow, error)
+	// Розыгрыши с числом проданных билетов и билетов пользователя; без status - все розыгрыши
+	ListRaffles(ctx context.Context, arg ListRafflesParams) ([]ListRafflesRow, error)
+	ListSpendableCoinLots(ctx context.Context, arg ListSpendableCoinLotsParams) ([]CoinLot, error)
+	// Единицы, которые владелец может передать: заказ, с которым они пришли, уже выдан,
+	// и единица не выставлена на продажу. Первыми передаются полученные раньше
+	ListTransferableUnits(ctx context.Context, arg ListTransferableUnitsParams) ([]int32, error)
+	// Анонсы: товары, продажи которых начнутся после now
+	ListUpcomingDrops(ctx context.Context, now pgtype.Timestamp) ([]ListUpcomingDropsRow, error)
+	ListUserPurchases(ctx context.Context, buyerID pgtype.Int4) ([]ListUserPurchasesRow, error)
+	ListUsersWithExpiredLots(ctx context.Context, arg ListUsersWithExpiredLotsParams) ([]int32, error)
+	ListWishlistItems(ctx context.Context, userID int32) ([]ListWishlistItemsRow, error)
+	LockUsers(ctx context.Context, ids []int32) error
+	MarkAllNotificationsRead(ctx context.Context, userID int32) (int64, error)
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (int64, error)
+	RefundPurchase(ctx context.Context, arg RefundPurchaseParams) (Purchase, error)
+	// Снимает резервы, срок которых истек к моменту now; позиции остаются в корзине
+	ReleaseExpiredReservations(ctx context.Context, now pgtype.Timestamp) (int64, error)
+	// Изымает возвращаемые единицы покупки, которые все еще у ее владельца и не выставлены на продажу
+	RemovePurchaseUnits(ctx context.Context, arg RemovePurchaseUnitsParams) (int64, error)
+	RemoveWishlistItem(ctx context.Context, arg RemoveWishlistItemParams) (int64, error)
+	// Резервирует под позицию корзины reserved_quantity единиц. Срок активного резерва
+	// не продлевается: повторное добавление товара не удерживает остаток дольше TTL
+	ReserveCartItem(ctx context.Context, arg ReserveCartItemParams) (pgtype.Timestamp, error)
+	// Снимает отметку с товаров, на которые непросроченных монет снова не хватает
+	ResetUncoveredWishlistItems(ctx context.Context, now pgtype.Timestamp) (int64, error)
+	ResolveAuctionBid(ctx context.Context, arg ResolveAuctionBidParams) error
+	RestoreItemStock(ctx context.Context, arg RestoreItemStockParams) error
+	RestoreVariantStock(ctx context.Context, arg RestoreVariantStockParams) error
+	// Поиск по каталогу среди товаров, которые продаются в момент now: полнотекстовое
+	// совпадение по названию и описанию и фильтры; пустой фильтр не применяется.
+	// total_count - число найденных товаров без учета страницы, reserved - единицы,
+	// зарезервированные в корзинах
+	SearchItems(ctx context.Context, arg SearchItemsParams) ([]SearchItemsRow, error)
+	SellListing(ctx context.Context, arg SellListingParams) (MarketplaceListing, error)
+	SetBundleActive(ctx context.Context, arg SetBundleActiveParams) (Bundle, error)
+	SetOrderBundle(ctx context.Context, arg SetOrderBundleParams) error
+	SetRaffleTicketPurchase(ctx context.Context, arg SetRaffleTicketPurchaseParams) error
+	SetUserDepartment(ctx context.Context, arg SetUserDepartmentParams) error
+	TransferInventoryUnits(ctx context.Context, arg TransferInventoryUnitsParams) error
+	UpdateBalance(ctx context.Context, arg UpdateBalanceParams) error
 	UpdateBalanceForPurchase(ctx context.Context, arg UpdateBalanceForPurchaseParams) error
 	UpdateBalanceForTransfer(ctx context.Context, arg UpdateBalanceForTransferParams) error
+	UpdateCartItemQuantity(ctx context.Context, arg UpdateCartItemQuantityParams) (int64, error)
+	UpdateItemAvailability(ctx context.Context, arg UpdateItemAvailabilityParams) (Item, error)
+	UpdateItemCategory(ctx context.Context, arg UpdateItemCategoryParams) error
+	UpdateItemDescription(ctx context.Context, arg UpdateItemDescriptionParams) error
+	UpdateItemLimits(ctx context.Context, arg UpdateItemLimitsParams) (Item, error)
+	UpdateItemPrice(ctx context.Context, arg UpdateItemPriceParams) error
+	UpdateOrderStatus(ctx context.Context, arg UpdateOrderStatusParams) (Order, error)
+	UpdatePromoCode(ctx context.Context, arg UpdatePromoCodeParams) (PromoCode, error)
+	UpdateTransferStatus(ctx context.Context, arg UpdateTransferStatusParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -0,0 +1,222 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// quoteViolation сообщает, является ли ошибка нарушением правил операции. В отличие
+// от ошибок запросов, после нарушения расчет продолжается
+func quoteViolation(err error) bool {
+	for _, violation := range []error{
+		ErrInsufficientBalance,
+		ErrItemOutOfStock,
+		ErrPurchaseLimitExceeded,
+		ErrItemUnavailable,
+		ErrDropQueued,
+		ErrPromoCodeNotFound,
+		ErrPromoCodeRejected,
+		ErrTransferLimitExceeded,
+	} {
+		if errors.Is(err, violation) {
+			return true
+		}
+	}
+	return false
+}
+
+// quoteChecks собирает нарушения правил при расчете операции
+type quoteChecks struct {
+	violations []error
+}
+
+func (checks *quoteChecks) check(err error) error {
+	if err != nil && quoteViolation(err) {
+		checks.violations = append(checks.violations, err)
+		return nil
+	}
+	return err
+}
+
+type QuoteTxResult struct {
+	// Price - цена товара до скидки, Discount - скидка по промокоду; у переводов не заполняются
+	Price    int32 `json:"price"`
+	Discount int32 `json:"discount"`
+	// Cost - сколько монет спишется у пользователя
+	Cost int32 `json:"cost"`
+	// Balance - монеты, которые можно потратить до операции, BalanceAfter - после нее;
+	// при нехватке монет отрицательный
+	Balance      int32 `json:"balance"`
+	BalanceAfter int32 `json:"balance_after"`
+	// Violations - нарушения, из-за которых операция не прошла бы; пусто - операция пройдет
+	Violations []error `json:"violations"`
+}
+
+// QuotePurchaseTx проверяет покупку по правилам PurchaseTx и возвращает баланс после нее
+// и все найденные нарушения, а не только первое. Расчет только читает данные: ни строки,
+// ни монеты не блокируются, поэтому параллельная операция может изменить результат
+func (store *SQLStore) QuotePurchaseTx(ctx context.Context, arg PurchaseTxParams) (QuoteTxResult, error) {
+	var result QuoteTxResult
+
+	err := store.execReadTx(ctx, func(q *Queries) error {
+		var checks quoteChecks
+		now := time.Now()
+
+		// 1. Ограничения товара для покупателя и получателя подарка, окно продаж
+		// и очередь для покупателя
+		item, err := q.GetItemByID(ctx, arg.ItemID)
+		if err != nil {
+			return fmt.Errorf("error getting item: %v", err)
+		}
+
+		userIDs := []int32{arg.UserID}
+		if arg.RecipientID != 0 {
+			userIDs = append(userIDs, arg.RecipientID)
+		}
+		for _, userID := range userIDs {
+			err = q.checkItemLimits(ctx, item.ID, userID, item.Name, item.Limits(), 1)
+			if err = checks.check(err); err != nil {
+				return err
+			}
+		}
+
+		err = q.checkAvailability(ctx, item.ID, arg.UserID, item.Name, item.Availability(), now)
+		if err = checks.check(err); err != nil {
+			return err
+		}
+
+		// 2. Цена варианта и свободный остаток за вычетом чужих резервов
+		price := item.Price
+		stock := item.Stock
+		variantID := pgtype.Int4{Int32: arg.VariantID, Valid: arg.VariantID != 0}
+		if variantID.Valid {
+			variant, err := q.GetItemVariantByID(ctx, arg.VariantID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrVariantNotFound
+				}
+				return fmt.Errorf("error getting variant: %v", err)
+			}
+			if variant.ItemID != item.ID {
+				return ErrVariantNotFound
+			}
+			price = variant.UnitPrice(item.Price)
+			stock = variant.Stock
+		}
+
+		if stock.Valid {
+			reserved, err := q.GetReservedStock(ctx, GetReservedStockParams{
+				ItemID:    item.ID,
+				VariantID: variantID,
+				UserID:    arg.UserID,
+				Now:       pgtype.Timestamp{Time: now, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error getting reserved stock: %v", err)
+			}
+			if AvailableStock(stock, reserved).Int32 < 1 {
+				checks.check(ErrItemOutOfStock)
+			}
+		}
+
+		// 3. Скидка по промокоду
+		var discount int32
+		if arg.PromoCode != "" {
+			discount, err = q.quotePromoCode(ctx, arg.PromoCode, arg.UserID, item, price)
+			if err = checks.check(err); err != nil {
+				return err
+			}
+		}
+
+		// 4. Монеты, которые покупатель может потратить
+		result, err = q.quoteCost(ctx, arg.UserID, price-discount, now, &checks)
+		if err != nil {
+			return err
+		}
+		result.Price = price
+		result.Discount = discount
+		return nil
+	})
+
+	if err != nil {
+		return QuoteTxResult{}, fmt.Errorf("quote purchase tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// QuoteTransferTx проверяет перевод по правилам TransferTx, только читая данные
+func (store *SQLStore) QuoteTransferTx(ctx context.Context, arg TransferTxParams) (QuoteTxResult, error) {
+	var result QuoteTxResult
+
+	err := store.execReadTx(ctx, func(q *Queries) error {
+		var checks quoteChecks
+		now := time.Now()
+
+		// 1. Лимиты по уже совершенным и отложенным переводам
+		var stats GetOutgoingTransferStatsRow
+		if arg.Limits.Enabled() {
+			var err error
+			stats, err = q.GetOutgoingTransferStats(ctx, NewTransferStatsParams(arg.FromUserID, now))
+			if err != nil {
+				return fmt.Errorf("error getting transfer stats: %v", err)
+			}
+		}
+		checks.check(arg.Limits.Check(stats, arg.Amount))
+
+		// 2. Монеты, которые отправитель может потратить
+		var err error
+		result, err = q.quoteCost(ctx, arg.FromUserID, arg.Amount, now, &checks)
+		return err
+	})
+
+	if err != nil {
+		return QuoteTxResult{}, fmt.Errorf("quote transfer tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// quotePromoCode проверяет промокод, не блокируя его и не расходуя использование,
+// и возвращает скидку на цену price
+func (q *Queries) quotePromoCode(ctx context.Context, code string, userID int32, item Item, price int32) (int32, error) {
+	promo, err := q.GetPromoCode(ctx, NormalizePromoCode(code))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrPromoCodeNotFound
+		}
+		return 0, fmt.Errorf("error getting promo code: %v", err)
+	}
+
+	if err = q.checkPromoCode(ctx, promo, userID, item); err != nil {
+		return 0, err
+	}
+	return promo.Discount(price), nil
+}
+
+// quoteCost сравнивает стоимость с непросроченными монетами пользователя и дополняет
+// расчет нарушениями из checks
+func (q *Queries) quoteCost(ctx context.Context, userID, cost int32, now time.Time, checks *quoteChecks) (QuoteTxResult, error) {
+	balance, err := q.GetSpendableBalance(ctx, GetSpendableBalanceParams{
+		UserID: userID,
+		Now:    pgtype.Timestamp{Time: now, Valid: true},
+	})
+	if err != nil {
+		return QuoteTxResult{}, fmt.Errorf("error getting balance: %v", err)
+	}
+	if balance < cost {
+		checks.check(ErrInsufficientBalance)
+	}
+
+	return QuoteTxResult{
+		Cost:         cost,
+		Balance:      balance,
+		BalanceAfter: balance - cost,
+		Violations:   checks.violations,
+	}, nil
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	util "avito-shop/internal/util"
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteViolation(t *testing.T) {
+	require.True(t, quoteViolation(ErrInsufficientBalance))
+	require.True(t, quoteViolation(&PurchaseLimitError{Item: "cup", Limit: PurchaseLimitMaxPerUser}))
+	require.True(t, quoteViolation(&TransferLimitError{Limit: TransferLimitDaily}))
+	require.True(t, quoteViolation(&NotEnoughStockError{Available: 1}))
+	require.False(t, quoteViolation(ErrVariantNotFound))
+	require.False(t, quoteViolation(fmt.Errorf("error getting item: connection lost")))
+
+	var checks quoteChecks
+	require.NoError(t, checks.check(nil))
+	require.NoError(t, checks.check(ErrItemOutOfStock))
+	require.Error(t, checks.check(ErrVariantNotFound))
+	require.Equal(t, []error{ErrItemOutOfStock}, checks.violations)
+}
+
+func TestQuotePurchaseTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	item, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 1500})
+	require.NoError(t, err)
+	variant := createRandomVariant(t, item, pgtype.Int4{}, pgtype.Int4{Int32: 0, Valid: true})
+	user := createRandomUser(t)
+
+	// Расчет не останавливается на первом нарушении
+	quote, err := store.QuotePurchaseTx(ctx, PurchaseTxParams{
+		UserID:    user.ID,
+		ItemID:    item.ID,
+		VariantID: variant.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1500), quote.Price)
+	require.Equal(t, int32(1500), quote.Cost)
+	require.Equal(t, int32(1000), quote.Balance)
+	require.Equal(t, int32(-500), quote.BalanceAfter)
+	require.Len(t, quote.Violations, 2)
+	require.ErrorIs(t, quote.Violations[0], ErrItemOutOfStock)
+	require.ErrorIs(t, quote.Violations[1], ErrInsufficientBalance)
+
+	// Расчет ничего не меняет
+	updated, err := testQueries.GetUserByID(ctx, user.ID)
+	require.NoError(t, err)
+	require.Equal(t, user.Balance, updated.Balance)
+
+	purchases, err := testQueries.ListInventoryUnits(ctx, user.ID)
+	require.NoError(t, err)
+	require.Empty(t, purchases)
+
+	cheap, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 80})
+	require.NoError(t, err)
+	quote, err = store.QuotePurchaseTx(ctx, PurchaseTxParams{UserID: user.ID, ItemID: cheap.ID})
+	require.NoError(t, err)
+	require.Empty(t, quote.Violations)
+	require.Equal(t, int32(920), quote.BalanceAfter)
+
+	_, err = store.QuotePurchaseTx(ctx, PurchaseTxParams{UserID: user.ID, ItemID: cheap.ID, VariantID: variant.ID})
+	require.ErrorIs(t, err, ErrVariantNotFound)
+}
+
+func TestQuotePurchaseTxPromoAndExpiredLots(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	user := createRandomUser(t)
+	item := createRandomItem(t)
+	// Просроченный, но еще не списанный лот потратить нельзя
+	createRandomCoinLot(t, user.ID, 500, pgtype.Timestamp{Time: time.Now().Add(-time.Hour), Valid: true})
+
+	promo, err := testQueries.CreatePromoCode(ctx, CreatePromoCodeParams{
+		Code:           NormalizePromoCode(util.RandomString(8)),
+		DiscountType:   DiscountTypeFixed,
+		DiscountValue:  5,
+		MaxUsesPerUser: pgtype.Int4{Int32: 1, Valid: true},
+		Active:         true,
+	})
+	require.NoError(t, err)
+
+	quote, err := store.QuotePurchaseTx(ctx, PurchaseTxParams{
+		UserID:    user.ID,
+		ItemID:    item.ID,
+		PromoCode: promo.Code,
+	})
+	require.NoError(t, err)
+	require.Empty(t, quote.Violations)
+	require.Equal(t, int32(5), quote.Discount)
+	require.Equal(t, int32(1000), quote.Balance)
+	require.Equal(t, int32(1000)-item.Price+5, quote.BalanceAfter)
+
+	// Расчет не расходует использование промокода
+	updated, err := testQueries.GetPromoCodeByID(ctx, promo.ID)
+	require.NoError(t, err)
+	require.Zero(t, updated.Uses)
+
+	quote, err = store.QuotePurchaseTx(ctx, PurchaseTxParams{UserID: user.ID, ItemID: item.ID, PromoCode: util.RandomString(10)})
+	require.NoError(t, err)
+	require.Len(t, quote.Violations, 1)
+	require.ErrorIs(t, quote.Violations[0], ErrPromoCodeNotFound)
+}
+
+func TestQuoteTransferTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	sender := createRandomUser(t)
+	receiver := createRandomUser(t)
+
+	quote, err := store.QuoteTransferTx(ctx, TransferTxParams{
+		FromUserID: sender.ID,
+		ToUserID:   receiver.ID,
+		Amount:     1200,
+		Limits:     TransferLimits{DailyLimit: 500},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1200), quote.Cost)
+	require.Equal(t, int32(-200), quote.BalanceAfter)
+	require.Len(t, quote.Violations, 2)
+	require.ErrorIs(t, quote.Violations[0], ErrTransferLimitExceeded)
+	require.ErrorIs(t, quote.Violations[1], ErrInsufficientBalance)
+
+	quote, err = store.QuoteTransferTx(ctx, TransferTxParams{
+		FromUserID: sender.ID,
+		ToUserID:   receiver.ID,
+		Amount:     300,
+	})
+	require.NoError(t, err)
+	require.Empty(t, quote.Violations)
+	require.Equal(t, int32(700), quote.BalanceAfter)
+
+	// Монеты не переведены
+	updated, err := testQueries.GetUserByID(ctx, receiver.ID)
+	require.NoError(t, err)
+	require.Equal(t, receiver.Balance, updated.Balance)
+}
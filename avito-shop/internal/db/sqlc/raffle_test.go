@@ -0,0 +1,155 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRaffleSeedHash(t *testing.T) {
+	seed, seedHash, err := NewRaffleSeed()
+	require.NoError(t, err)
+	require.Len(t, seed, 64)
+	require.Equal(t, seedHash, RaffleSeedHash(seed))
+	require.Equal(t, "2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae", RaffleSeedHash("foo"))
+}
+
+func TestDrawRaffleWinners(t *testing.T) {
+	owners := []int32{1, 1, 1, 2, 3, 3}
+
+	// Выбор определяется только зерном и порядком билетов
+	positions := DrawRaffleWinners("seed", owners, 2)
+	require.Len(t, positions, 2)
+	require.Equal(t, positions, DrawRaffleWinners("seed", owners, 2))
+	require.NotEqual(t, owners[positions[0]], owners[positions[1]])
+
+	// Участник выигрывает не больше одного раза
+	positions = DrawRaffleWinners("seed", owners, 5)
+	require.Len(t, positions, 3)
+	winners := map[int32]bool{}
+	for _, position := range positions {
+		winners[owners[position]] = true
+	}
+	require.Len(t, winners, 3)
+
+	require.Empty(t, DrawRaffleWinners("seed", nil, 1))
+}
+
+func TestRaffleSellsTickets(t *testing.T) {
+	end := time.Date(2026, 6, 1, 10, 0, 0, 0, time.UTC)
+	raffle := Raffle{
+		SalesEndAt: pgtype.Timestamp{Time: end, Valid: true},
+		Status:     RaffleStatusOpen,
+	}
+
+	require.True(t, raffle.SellsTickets(end.Add(-time.Second)))
+	require.False(t, raffle.SellsTickets(end))
+
+	raffle.Status = RaffleStatusDrawn
+	require.False(t, raffle.SellsTickets(end.Add(-time.Hour)))
+}
+
+func TestRaffleTicketLimitError(t *testing.T) {
+	err := error(&RaffleTicketLimitError{Max: 5, Remaining: 1})
+	require.ErrorIs(t, err, ErrRaffleTicketLimit)
+	require.Equal(t, "at most 5 tickets per user, 1 left", err.Error())
+}
+
+func TestRaffleTicketsAndDraw(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+	now := time.Now()
+
+	item := createRandomItem(t)
+	first := createRandomUser(t)
+	second := createRandomUser(t)
+
+	created, err := store.CreateRaffleTx(ctx, CreateRaffleTxParams{
+		ItemID:            item.ID,
+		TicketPrice:       10,
+		MaxTicketsPerUser: 3,
+		Winners:           1,
+		SalesEndAt:        now.Add(time.Hour),
+	})
+	require.NoError(t, err)
+	raffle := created.Raffle
+	require.Equal(t, RaffleStatusOpen, raffle.Status)
+	require.Equal(t, RaffleSeedHash(raffle.Seed), raffle.SeedHash)
+
+	// Оплата билетов списывается на системный счет
+	bought, err := store.BuyRaffleTicketsTx(ctx, BuyRaffleTicketsTxParams{RaffleID: raffle.ID, UserID: first.ID, Quantity: 2, Now: now})
+	require.NoError(t, err)
+	require.Len(t, bought.Tickets, 2)
+	require.Equal(t, int32(980), bought.User.Balance.Int32)
+	require.Equal(t, TransactionKindRaffle, bought.Payment.Kind)
+	require.False(t, bought.Payment.ReceiverID.Valid)
+
+	// Лимит билетов на участника
+	_, err = store.BuyRaffleTicketsTx(ctx, BuyRaffleTicketsTxParams{RaffleID: raffle.ID, UserID: first.ID, Quantity: 2, Now: now})
+	var limitErr *RaffleTicketLimitError
+	require.True(t, errors.As(err, &limitErr))
+	require.Equal(t, int32(1), limitErr.Remaining)
+
+	_, err = store.BuyRaffleTicketsTx(ctx, BuyRaffleTicketsTxParams{RaffleID: raffle.ID, UserID: second.ID, Quantity: 1, Now: now})
+	require.NoError(t, err)
+
+	// После окончания продажи билеты не продаются
+	_, err = store.BuyRaffleTicketsTx(ctx, BuyRaffleTicketsTxParams{RaffleID: raffle.ID, UserID: second.ID, Quantity: 1, Now: now.Add(2 * time.Hour)})
+	require.ErrorIs(t, err, ErrRaffleClosed)
+
+	// До окончания продажи победитель не выбирается
+	drawn, err := store.DrawRaffleTx(ctx, DrawRaffleTxParams{RaffleID: raffle.ID, Now: now})
+	require.NoError(t, err)
+	require.Equal(t, RaffleStatusOpen, drawn.Raffle.Status)
+
+	drawn, err = store.DrawRaffleTx(ctx, DrawRaffleTxParams{RaffleID: raffle.ID, Now: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Equal(t, RaffleStatusDrawn, drawn.Raffle.Status)
+	require.Len(t, drawn.Purchases, 1)
+	require.Zero(t, drawn.Purchases[0].TotalCost)
+
+	// Победитель совпадает с тем, что получается по раскрытому зерну
+	tickets, err := testQueries.ListRaffleTickets(ctx, raffle.ID)
+	require.NoError(t, err)
+	owners := make([]int32, 0, len(tickets))
+	for _, ticket := range tickets {
+		owners = append(owners, ticket.UserID)
+	}
+	positions := DrawRaffleWinners(raffle.Seed, owners, 1)
+	require.Equal(t, owners[positions[0]], drawn.Purchases[0].BuyerID.Int32)
+	require.Equal(t, drawn.Purchases[0].ID, tickets[positions[0]].PurchaseID.Int32)
+
+	// Баланс участников сходится с журналом
+	rows, err := testQueries.GetBalanceReconciliation(ctx, pgtype.Int4{Int32: first.ID, Valid: true})
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int32(20), rows[0].Sent)
+	require.Equal(t, rows[0].Balance, rows[0].Received-rows[0].Sent-rows[0].Spent-rows[0].Reserved)
+
+	// Повторный розыгрыш ничего не меняет
+	drawn, err = store.DrawRaffleTx(ctx, DrawRaffleTxParams{RaffleID: raffle.ID, Now: now.Add(2 * time.Hour)})
+	require.NoError(t, err)
+	require.Empty(t, drawn.Purchases)
+}
+
+func TestCreateRaffleTxSalesEndAtOffset(t *testing.T) {
+	store := NewStore(testDB)
+	item := createRandomItem(t)
+
+	// Конец продаж со смещением хранится в UTC, поэтому продажи закрываются вовремя
+	salesEndAt := time.Now().Add(time.Hour).Truncate(time.Microsecond)
+	created, err := store.CreateRaffleTx(context.Background(), CreateRaffleTxParams{
+		ItemID:      item.ID,
+		TicketPrice: 10,
+		Winners:     1,
+		SalesEndAt:  salesEndAt.In(time.FixedZone("MSK", 3*60*60)),
+	})
+	require.NoError(t, err)
+	require.True(t, created.Raffle.SalesEndAt.Time.Equal(salesEndAt))
+	require.False(t, created.Raffle.SellsTickets(salesEndAt))
+	require.True(t, created.Raffle.SellsTickets(salesEndAt.Add(-time.Minute)))
+}
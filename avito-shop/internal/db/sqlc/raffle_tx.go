@@ -0,0 +1,380 @@
+package db
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Статусы розыгрыша
+const (
+	RaffleStatusOpen  = "open"
+	RaffleStatusDrawn = "drawn"
+)
+
+// NotificationKindRaffleWon - уведомление победителю розыгрыша
+const NotificationKindRaffleWon = "raffle_won"
+
+// ReasonCodeRaffleTicket - причина списания монет за билеты розыгрыша
+const ReasonCodeRaffleTicket = "raffle_ticket"
+
+// Ошибки розыгрышей
+var (
+	ErrRaffleNotFound    = errors.New("raffle not found")
+	ErrRaffleClosed      = errors.New("raffle is not selling tickets")
+	ErrRaffleTicketLimit = errors.New("raffle ticket limit exceeded")
+)
+
+// RaffleTicketLimitError - покупка превышает лимит билетов на участника
+type RaffleTicketLimitError struct {
+	Max       int32
+	Remaining int32
+}
+
+func (e *RaffleTicketLimitError) Error() string {
+	return fmt.Sprintf("at most %d tickets per user, %d left", e.Max, e.Remaining)
+}
+
+func (e *RaffleTicketLimitError) Unwrap() error {
+	return ErrRaffleTicketLimit
+}
+
+// SellsTickets сообщает, продаются ли билеты розыгрыша в момент now
+func (raffle Raffle) SellsTickets(now time.Time) bool {
+	return raffle.Status == RaffleStatusOpen && now.Before(raffle.SalesEndAt.Time)
+}
+
+// NewRaffleSeed создает секретное зерно розыгрыша и его хеш, который публикуется
+// до начала продажи билетов
+func NewRaffleSeed() (seed string, seedHash string, err error) {
+	buf := make([]byte, 32)
+	if _, err = rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("error generating raffle seed: %v", err)
+	}
+	seed = hex.EncodeToString(buf)
+	return seed, RaffleSeedHash(seed), nil
+}
+
+// RaffleSeedHash - опубликованный хеш зерна: sha256 от его hex-строки
+func RaffleSeedHash(seed string) string {
+	sum := sha256.Sum256([]byte(seed))
+	return hex.EncodeToString(sum[:])
+}
+
+// DrawRaffleWinners выбирает выигравшие билеты. owners - владельцы билетов в порядке
+// номеров. В раунде k из билетов участников, которые еще не выиграли, берется билет
+// с номером sha256(seed + ":" + k) mod их числа: первые 8 байт хеша как big-endian число.
+// Каждый участник выигрывает не больше одного раза, поэтому победителей может оказаться
+// меньше winners. Возвращает позиции выигравших билетов в owners.
+func DrawRaffleWinners(seed string, owners []int32, winners int32) []int {
+	won := make(map[int32]bool)
+	var positions []int
+	for round := 0; len(positions) < int(winners); round++ {
+		pool := make([]int, 0, len(owners))
+		for i, owner := range owners {
+			if !won[owner] {
+				pool = append(pool, i)
+			}
+		}
+		if len(pool) == 0 {
+			break
+		}
+
+		sum := sha256.Sum256([]byte(seed + ":" + strconv.Itoa(round)))
+		pick := pool[binary.BigEndian.Uint64(sum[:8])%uint64(len(pool))]
+		positions = append(positions, pick)
+		won[owners[pick]] = true
+	}
+	return positions
+}
+
+type CreateRaffleTxParams struct {
+	ItemID      int32 `json:"item_id"`
+	TicketPrice int32 `json:"ticket_price"`
+	// MaxTicketsPerUser - лимит билетов на участника, 0 - без ограничения
+	MaxTicketsPerUser int32 `json:"max_tickets_per_user"`
+	Winners           int32 `json:"winners"`
+	// SalesEndAt сохраняется в UTC: колонка timestamp не хранит смещение
+	SalesEndAt time.Time `json:"sales_end_at"`
+	CreatedBy  int32     `json:"created_by"`
+}
+
+type CreateRaffleTxResult struct {
+	Raffle Raffle `json:"raffle"`
+	Item   Item   `json:"item"`
+}
+
+// CreateRaffleTx создает розыгрыш и резервирует на складе по единице приза
+// на каждого победителя. Хеш зерна сохраняется вместе с розыгрышем,
+// само зерно раскрывается только при выборе победителей.
+func (store *SQLStore) CreateRaffleTx(ctx context.Context, arg CreateRaffleTxParams) (CreateRaffleTxResult, error) {
+	var result CreateRaffleTxResult
+
+	seed, seedHash, err := NewRaffleSeed()
+	if err != nil {
+		return CreateRaffleTxResult{}, fmt.Errorf("create raffle tx error: %w", err)
+	}
+
+	err = store.execTx(ctx, func(q *Queries) error {
+		item, err := q.GetItemByID(ctx, arg.ItemID)
+		if err != nil {
+			return fmt.Errorf("error getting item: %v", err)
+		}
+		result.Item = item
+
+		// 1. Призы не должны уйти покупателям, пока идет продажа билетов
+		err = q.decrementStock(ctx, item.ID, pgtype.Int4{}, item.Stock.Valid, arg.Winners, 0, time.Now())
+		if err != nil {
+			return err
+		}
+
+		// 2. Публикуем хеш зерна вместе с розыгрышем
+		result.Raffle, err = q.CreateRaffle(ctx, CreateRaffleParams{
+			ItemID:            item.ID,
+			TicketPrice:       arg.TicketPrice,
+			MaxTicketsPerUser: pgtype.Int4{Int32: arg.MaxTicketsPerUser, Valid: arg.MaxTicketsPerUser > 0},
+			Winners:           arg.Winners,
+			SalesEndAt:        pgtype.Timestamp{Time: arg.SalesEndAt.UTC(), Valid: true},
+			SeedHash:          seedHash,
+			Seed:              seed,
+			CreatedBy:         pgtype.Int4{Int32: arg.CreatedBy, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error creating raffle: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return CreateRaffleTxResult{}, fmt.Errorf("create raffle tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+type BuyRaffleTicketsTxParams struct {
+	RaffleID int32     `json:"raffle_id"`
+	UserID   int32     `json:"user_id"`
+	Quantity int32     `json:"quantity"`
+	Now      time.Time `json:"now"`
+}
+
+type BuyRaffleTicketsTxResult struct {
+	Raffle Raffle `json:"raffle"`
+	// Payment - списание монет за билеты на системный счет
+	Payment Transaction    `json:"payment"`
+	Tickets []RaffleTicket `json:"tickets"`
+	User    User           `json:"user"`
+}
+
+// BuyRaffleTicketsTx продает участнику билеты розыгрыша. Оплата проходит как обычная
+// операция журнала вида raffle: монеты списываются из лотов и уходят на системный счет.
+func (store *SQLStore) BuyRaffleTicketsTx(ctx context.Context, arg BuyRaffleTicketsTxParams) (BuyRaffleTicketsTxResult, error) {
+	var result BuyRaffleTicketsTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем розыгрыш: билеты не продаются во время выбора победителей
+		raffle, err := q.getRaffleForUpdate(ctx, arg.RaffleID)
+		if err != nil {
+			return err
+		}
+		if !raffle.SellsTickets(arg.Now) {
+			return ErrRaffleClosed
+		}
+		result.Raffle = raffle
+
+		// 2. Проверяем лимит билетов участника
+		if raffle.MaxTicketsPerUser.Valid {
+			bought, err := q.CountUserRaffleTickets(ctx, CountUserRaffleTicketsParams{
+				RaffleID: raffle.ID,
+				UserID:   arg.UserID,
+			})
+			if err != nil {
+				return fmt.Errorf("error counting tickets: %v", err)
+			}
+			if bought+arg.Quantity > raffle.MaxTicketsPerUser.Int32 {
+				return &RaffleTicketLimitError{
+					Max:       raffle.MaxTicketsPerUser.Int32,
+					Remaining: max(raffle.MaxTicketsPerUser.Int32-bought, 0),
+				}
+			}
+		}
+
+		err = q.LockUsers(ctx, []int32{arg.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		// 3. Списываем оплату на системный счет
+		cost := raffle.TicketPrice * arg.Quantity
+		_, err = q.debitCoins(ctx, arg.UserID, cost, arg.Now)
+		if err != nil {
+			return err
+		}
+		result.Payment, err = q.CreateAdjustment(ctx, CreateAdjustmentParams{
+			SenderID:   pgtype.Int4{Int32: arg.UserID, Valid: true},
+			Amount:     cost,
+			Kind:       TransactionKindRaffle,
+			ReasonCode: pgtype.Text{String: ReasonCodeRaffleTicket, Valid: true},
+			Comment:    pgtype.Text{String: fmt.Sprintf("raffle #%d: %d tickets", raffle.ID, arg.Quantity), Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error creating raffle payment: %v", err)
+		}
+
+		// 4. Выпускаем билеты: их номера определяются порядком создания
+		for range arg.Quantity {
+			ticket, err := q.CreateRaffleTicket(ctx, CreateRaffleTicketParams{
+				RaffleID:      raffle.ID,
+				UserID:        arg.UserID,
+				TransactionID: result.Payment.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("error creating ticket: %v", err)
+			}
+			result.Tickets = append(result.Tickets, ticket)
+		}
+
+		result.User, err = q.GetUserByID(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return BuyRaffleTicketsTxResult{}, fmt.Errorf("buy raffle tickets tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+type DrawRaffleTxParams struct {
+	RaffleID int32     `json:"raffle_id"`
+	Now      time.Time `json:"now"`
+}
+
+type DrawRaffleTxResult struct {
+	// Raffle - розыгрыш после выбора победителей; статус open означает,
+	// что продажа билетов еще не закончилась
+	Raffle    Raffle     `json:"raffle"`
+	Purchases []Purchase `json:"purchases"`
+}
+
+// DrawRaffleTx выбирает победителей закончившегося розыгрыша по раскрываемому зерну.
+// Каждый победитель получает приз как покупку за 0 монет, неразыгранные единицы приза
+// возвращаются на склад. Уже разыгранный розыгрыш не меняется.
+func (store *SQLStore) DrawRaffleTx(ctx context.Context, arg DrawRaffleTxParams) (DrawRaffleTxResult, error) {
+	var result DrawRaffleTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем розыгрыш, чтобы победителей не выбрали дважды
+		raffle, err := q.getRaffleForUpdate(ctx, arg.RaffleID)
+		if err != nil {
+			return err
+		}
+		result.Raffle = raffle
+		if raffle.Status != RaffleStatusOpen || arg.Now.Before(raffle.SalesEndAt.Time) {
+			return nil
+		}
+
+		item, err := q.GetItemByID(ctx, raffle.ItemID)
+		if err != nil {
+			return fmt.Errorf("error getting item: %v", err)
+		}
+
+		// 2. Выбираем выигравшие билеты
+		tickets, err := q.ListRaffleTickets(ctx, raffle.ID)
+		if err != nil {
+			return fmt.Errorf("error listing tickets: %v", err)
+		}
+		owners := make([]int32, 0, len(tickets))
+		for _, ticket := range tickets {
+			owners = append(owners, ticket.UserID)
+		}
+		positions := DrawRaffleWinners(raffle.Seed, owners, raffle.Winners)
+
+		// 3. Оформляем приз на каждого победителя: единица приза уже списана со склада
+		for _, position := range positions {
+			ticket := tickets[position]
+			order, err := q.placeOrder(ctx, ticket.UserID, 0)
+			if err != nil {
+				return err
+			}
+			purchase, err := q.createPurchase(ctx, CreatePurchaseParams{
+				BuyerID:  pgtype.Int4{Int32: ticket.UserID, Valid: true},
+				ItemID:   pgtype.Int4{Int32: item.ID, Valid: true},
+				Quantity: 1,
+				OrderID:  pgtype.Int4{Int32: order.ID, Valid: true},
+			})
+			if err != nil {
+				return err
+			}
+			result.Purchases = append(result.Purchases, purchase)
+
+			err = q.SetRaffleTicketPurchase(ctx, SetRaffleTicketPurchaseParams{
+				ID:         ticket.ID,
+				PurchaseID: pgtype.Int4{Int32: purchase.ID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error marking winning ticket: %v", err)
+			}
+
+			err = q.CreateNotification(ctx, CreateNotificationParams{
+				UserID:  ticket.UserID,
+				Kind:    NotificationKindRaffleWon,
+				ItemID:  pgtype.Int4{Int32: item.ID, Valid: true},
+				Message: fmt.Sprintf("Your ticket #%d won %s in raffle #%d", ticket.ID, item.Name, raffle.ID),
+			})
+			if err != nil {
+				return fmt.Errorf("error creating notification: %v", err)
+			}
+		}
+
+		// 4. Возвращаем на склад призы, которым не хватило участников
+		if unclaimed := raffle.Winners - int32(len(positions)); unclaimed > 0 {
+			err = q.restoreStock(ctx, item.ID, pgtype.Int4{}, unclaimed)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = q.DrawRaffle(ctx, raffle.ID)
+		if err != nil {
+			return fmt.Errorf("error closing raffle: %v", err)
+		}
+		result.Raffle.Status = RaffleStatusDrawn
+		result.Raffle.DrawnAt = pgtype.Timestamp{Time: arg.Now, Valid: true}
+
+		return nil
+	})
+
+	if err != nil {
+		return DrawRaffleTxResult{}, fmt.Errorf("draw raffle tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// getRaffleForUpdate блокирует розыгрыш до конца транзакции
+func (q *Queries) getRaffleForUpdate(ctx context.Context, raffleID int32) (Raffle, error) {
+	raffle, err := q.GetRaffleForUpdate(ctx, raffleID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Raffle{}, ErrRaffleNotFound
+		}
+		return Raffle{}, fmt.Errorf("error getting raffle: %v", err)
+	}
+	return raffle, nil
+}
@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Причина и комментарий корректировок, которые записывает сверка
+const (
+	ReasonCodeCorrection  = "correction"
+	reconciliationComment = "ledger reconciliation"
+)
+
+type ReconcileBalancesTxParams struct {
+	// Fix - записать корректирующие операции для расхождений с журналом
+	Fix bool `json:"fix"`
+	// AdminID - кто запустил исправление, 0 - запуск из командной строки
+	AdminID int32 `json:"admin_id"`
+}
+
+// BalanceDrift - расхождение баланса пользователя с журналом операций и с лотами монет
+type BalanceDrift struct {
+	UserID     int32  `json:"user_id"`
+	Username   string `json:"username"`
+	Balance    int32  `json:"balance"`
+	Expected   int32  `json:"expected"`
+	Drift      int32  `json:"drift"`
+	LotBalance int32  `json:"lot_balance"`
+	LotDrift   int32  `json:"lot_drift"`
+}
+
+// LedgerConservation - глобальный баланс монет системы
+type LedgerConservation struct {
+	GetLedgerTotalsRow
+	// Expected = Issued - Burned - Spent - InEscrow - Reserved, Drift = TotalBalance - Expected
+	Expected int32 `json:"expected"`
+	Drift    int32 `json:"drift"`
+}
+
+type ReconcileBalancesTxResult struct {
+	CheckedUsers int                `json:"checked_users"`
+	Drifts       []BalanceDrift     `json:"drifts"`
+	Conservation LedgerConservation `json:"conservation"`
+	Corrections  []Transaction      `json:"corrections"`
+}
+
+// Consistent сообщает, сходятся ли все проверенные инварианты
+func (result ReconcileBalancesTxResult) Consistent() bool {
+	return len(result.Drifts) == 0 && result.Conservation.Drift == 0
+}
+
+// newBalanceDrift сравнивает баланс пользователя с ожидаемым по журналу
+func newBalanceDrift(row GetBalanceReconciliationRow) BalanceDrift {
+	expected := row.Received - row.Sent - row.Spent - row.Reserved
+	return BalanceDrift{
+		UserID:     row.ID,
+		Username:   row.Username,
+		Balance:    row.Balance,
+		Expected:   expected,
+		Drift:      row.Balance - expected,
+		LotBalance: row.LotBalance,
+		LotDrift:   row.Balance - row.LotBalance,
+	}
+}
+
+// newLedgerConservation проверяет, что монеты не появляются и не исчезают вне журнала
+func newLedgerConservation(totals GetLedgerTotalsRow) LedgerConservation {
+	expected := totals.Issued - totals.Burned - totals.Spent - totals.InEscrow - totals.Reserved
+	return LedgerConservation{
+		GetLedgerTotalsRow: totals,
+		Expected:           expected,
+		Drift:              totals.TotalBalance - expected,
+	}
+}
+
+// ReconcileBalancesTx пересчитывает баланс каждого пользователя по журналу операций
+// и покупкам и проверяет глобальное сохранение монет. Отчет строится без блокировок;
+// при Fix каждое расхождение перепроверяется и исправляется в отдельной транзакции
+// под блокировкой пользователя. Корректировка записывается в журнал от системного
+// счета и не меняет баланс: журнал приводится к фактическому состоянию счета.
+func (store *SQLStore) ReconcileBalancesTx(ctx context.Context, arg ReconcileBalancesTxParams) (ReconcileBalancesTxResult, error) {
+	var result ReconcileBalancesTxResult
+
+	rows, err := store.GetBalanceReconciliation(ctx, pgtype.Int4{})
+	if err != nil {
+		return result, fmt.Errorf("error getting balance reconciliation: %v", err)
+	}
+	result.CheckedUsers = len(rows)
+
+	for _, row := range rows {
+		drift := newBalanceDrift(row)
+		if drift.Drift != 0 || drift.LotDrift != 0 {
+			result.Drifts = append(result.Drifts, drift)
+		}
+	}
+
+	totals, err := store.GetLedgerTotals(ctx)
+	if err != nil {
+		return result, fmt.Errorf("error getting ledger totals: %v", err)
+	}
+	result.Conservation = newLedgerConservation(totals)
+
+	if !arg.Fix {
+		return result, nil
+	}
+
+	for _, drift := range result.Drifts {
+		if drift.Drift == 0 {
+			continue
+		}
+
+		err = store.execTx(ctx, func(q *Queries) error {
+			correction, ok, err := q.correctBalanceDrift(ctx, drift.UserID, arg.AdminID)
+			if err != nil {
+				return err
+			}
+			if ok {
+				result.Corrections = append(result.Corrections, correction)
+			}
+			return nil
+		})
+		if err != nil {
+			return result, fmt.Errorf("reconcile balances tx error: %w", err)
+		}
+	}
+
+	return result, nil
+}
+
+// correctBalanceDrift записывает операцию, которая объясняет расхождение баланса
+// пользователя с журналом. Возвращает false, если расхождения уже нет.
+func (q *Queries) correctBalanceDrift(ctx context.Context, userID, adminID int32) (Transaction, bool, error) {
+	// 1. Блокируем пользователя и пересчитываем расхождение
+	err := q.LockUsers(ctx, []int32{userID})
+	if err != nil {
+		return Transaction{}, false, fmt.Errorf("error locking user: %v", err)
+	}
+
+	rows, err := q.GetBalanceReconciliation(ctx, pgtype.Int4{Int32: userID, Valid: true})
+	if err != nil {
+		return Transaction{}, false, fmt.Errorf("error getting balance reconciliation: %v", err)
+	}
+	if len(rows) == 0 {
+		return Transaction{}, false, nil
+	}
+
+	drift := newBalanceDrift(rows[0])
+	if drift.Drift == 0 {
+		return Transaction{}, false, nil
+	}
+
+	// 2. Баланс больше ожидаемого - недостает начисления, меньше - списания
+	user := pgtype.Int4{Int32: userID, Valid: true}
+	params := CreateAdjustmentParams{
+		Amount:     drift.Drift,
+		Kind:       TransactionKindGrant,
+		ReceiverID: user,
+		ReasonCode: pgtype.Text{String: ReasonCodeCorrection, Valid: true},
+		Comment:    pgtype.Text{String: reconciliationComment, Valid: true},
+		CreatedBy:  pgtype.Int4{Int32: adminID, Valid: adminID != 0},
+	}
+	if drift.Drift < 0 {
+		params.Amount = -drift.Drift
+		params.Kind = TransactionKindDeduction
+		params.ReceiverID = pgtype.Int4{}
+		params.SenderID = user
+	}
+
+	correction, err := q.CreateAdjustment(ctx, params)
+	if err != nil {
+		return Transaction{}, false, fmt.Errorf("error creating correction: %v", err)
+	}
+
+	return correction, true, nil
+}
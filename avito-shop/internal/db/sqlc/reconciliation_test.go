@@ -0,0 +1,72 @@
+package db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewBalanceDrift(t *testing.T) {
+	testCases := []struct {
+		name     string
+		row      GetBalanceReconciliationRow
+		expected BalanceDrift
+	}{
+		{
+			name: "Consistent",
+			row:  GetBalanceReconciliationRow{ID: 1, Username: "user", Balance: 700, Received: 1200, Sent: 300, Spent: 200, LotBalance: 700},
+			expected: BalanceDrift{
+				UserID: 1, Username: "user", Balance: 700, Expected: 700, LotBalance: 700,
+			},
+		},
+		{
+			name: "Consistent_ReservedByBid",
+			row:  GetBalanceReconciliationRow{ID: 4, Username: "user", Balance: 400, Received: 1000, Spent: 100, Reserved: 500, LotBalance: 400},
+			expected: BalanceDrift{
+				UserID: 4, Username: "user", Balance: 400, Expected: 400, LotBalance: 400,
+			},
+		},
+		{
+			name: "BalanceAboveLedger",
+			row:  GetBalanceReconciliationRow{ID: 2, Username: "user", Balance: 1050, Received: 1000, LotBalance: 1000},
+			expected: BalanceDrift{
+				UserID: 2, Username: "user", Balance: 1050, Expected: 1000, Drift: 50, LotBalance: 1000, LotDrift: 50,
+			},
+		},
+		{
+			name: "BalanceBelowLedger",
+			row:  GetBalanceReconciliationRow{ID: 3, Username: "user", Balance: 900, Received: 1000, LotBalance: 900},
+			expected: BalanceDrift{
+				UserID: 3, Username: "user", Balance: 900, Expected: 1000, Drift: -100, LotBalance: 900,
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, newBalanceDrift(tc.row))
+		})
+	}
+}
+
+func TestNewLedgerConservation(t *testing.T) {
+	totals := GetLedgerTotalsRow{
+		TotalBalance: 2500,
+		Issued:       3000,
+		Burned:       100,
+		Spent:        300,
+		InEscrow:     60,
+		Reserved:     40,
+	}
+
+	conservation := newLedgerConservation(totals)
+	require.Equal(t, int32(2500), conservation.Expected)
+	require.Zero(t, conservation.Drift)
+
+	// Монеты, появившиеся на счетах мимо журнала, видны как положительное расхождение
+	totals.TotalBalance += 40
+	conservation = newLedgerConservation(totals)
+	require.Equal(t, int32(40), conservation.Drift)
+}
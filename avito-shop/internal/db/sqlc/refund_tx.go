@@ -0,0 +1,225 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Причины возврата монет за покупку
+const (
+	ReasonCodeRefund = "refund"
+	ReasonCodeReturn = "return"
+)
+
+// Ошибки возврата покупок
+var (
+	ErrPurchaseNotFound    = errors.New("purchase not found")
+	ErrRefundQuantity      = errors.New("refund quantity exceeds the quantity left to refund")
+	ErrReturnWindowExpired = errors.New("return window has expired")
+)
+
+type RefundTxParams struct {
+	PurchaseID int32 `json:"purchase_id"`
+	// BuyerID - если задан, покупка должна принадлежать этому пользователю (самостоятельный возврат)
+	BuyerID int32 `json:"buyer_id"`
+	// Quantity - сколько единиц вернуть, 0 - все невозвращенные
+	Quantity int32 `json:"quantity"`
+	// ReturnWindow - если больше нуля, возврат возможен только в течение этого срока после покупки
+	ReturnWindow time.Duration `json:"return_window"`
+	// AdminID - кто оформил возврат, 0 - сам покупатель
+	AdminID    int32  `json:"admin_id"`
+	ReasonCode string `json:"reason_code"`
+	Comment    string `json:"comment"`
+	// CoinTTL - срок действия возвращенных монет, 0 - бессрочно
+	CoinTTL time.Duration `json:"coin_ttl"`
+}
+
+type RefundTxResult struct {
+	Purchase Purchase    `json:"purchase"`
+	Refund   Transaction `json:"refund"`
+	User     User        `json:"user"`
+}
+
+// refundAmount считает, сколько монет вернуть за quantity единиц покупки, из которых
+// refunded уже возвращены. Суммы считаются от общей стоимости, поэтому несколько
+// частичных возвратов в сумме дают ровно total_cost.
+func refundAmount(purchase Purchase, quantity int32) int32 {
+	refunded := int64(purchase.RefundedQuantity)
+	total := int64(purchase.TotalCost)
+	bought := int64(purchase.Quantity)
+	return int32(total*(refunded+int64(quantity))/bought - total*refunded/bought)
+}
+
+// RefundTx возвращает покупку целиком или частично: начисляет монеты покупателю
+// от системного счета, отмечает возвращенные единицы в покупке и возвращает их на склад,
+// если остаток товара отслеживается
+func (store *SQLStore) RefundTx(ctx context.Context, arg RefundTxParams) (RefundTxResult, error) {
+	var result RefundTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Находим покупателя, чтобы заблокировать его раньше покупки, как и остальные транзакции
+		purchase, err := q.GetPurchaseByID(ctx, arg.PurchaseID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrPurchaseNotFound
+			}
+			return fmt.Errorf("error getting purchase: %v", err)
+		}
+		if !purchase.BuyerID.Valid || (arg.BuyerID != 0 && purchase.BuyerID.Int32 != arg.BuyerID) {
+			return ErrPurchaseNotFound
+		}
+		buyerID := purchase.BuyerID.Int32
+
+		err = q.LockUsers(ctx, []int32{buyerID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		// 2. Блокируем покупку, чтобы параллельные возвраты не превысили количество
+		purchase, err = q.GetPurchaseForUpdate(ctx, arg.PurchaseID)
+		if err != nil {
+			return fmt.Errorf("error getting purchase: %v", err)
+		}
+
+		if arg.ReturnWindow > 0 && time.Now().After(purchase.PurchaseDate.Time.Add(arg.ReturnWindow)) {
+			return ErrReturnWindowExpired
+		}
+
+		left := purchase.Quantity - purchase.RefundedQuantity
+		quantity := arg.Quantity
+		if quantity == 0 {
+			quantity = left
+		}
+		if quantity <= 0 || quantity > left {
+			return ErrRefundQuantity
+		}
+
+		// 3. Возвращаем единицы и монеты
+		result.Purchase, result.Refund, err = q.refundUnits(ctx, purchase, quantity, arg)
+		if err != nil {
+			return err
+		}
+
+		// 4. Заказ, в котором не осталось невозвращенных единиц, отменяется
+		err = q.cancelRefundedOrder(ctx, result.Purchase.OrderID, arg.AdminID)
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.GetUserByID(ctx, buyerID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return RefundTxResult{}, fmt.Errorf("refund tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// refundUnits возвращает quantity единиц заблокированной покупки: отмечает их в покупке,
+// изымает из инвентаря владельца, возвращает на склад и начисляет покупателю монеты
+// от системного счета.
+// Причина, комментарий и срок действия монет берутся из arg.
+func (q *Queries) refundUnits(ctx context.Context, purchase Purchase, quantity int32, arg RefundTxParams) (Purchase, Transaction, error) {
+	var refund Transaction
+	buyerID := purchase.BuyerID.Int32
+	amount := refundAmount(purchase, quantity)
+
+	// 1. Отмечаем возвращенные единицы
+	updated, err := q.RefundPurchase(ctx, RefundPurchaseParams{
+		ID:       purchase.ID,
+		Quantity: quantity,
+	})
+	if err != nil {
+		return Purchase{}, refund, fmt.Errorf("error updating purchase: %v", err)
+	}
+
+	// 2. Изымаем единицы из инвентаря и возвращаем товар на склад
+	err = q.removePurchaseUnits(ctx, purchase.ID, quantity)
+	if err != nil {
+		return Purchase{}, refund, err
+	}
+	err = q.restoreStock(ctx, purchase.ItemID.Int32, purchase.VariantID, quantity)
+	if err != nil {
+		return Purchase{}, refund, err
+	}
+
+	// 3. Возвращаем монеты новым лотом
+	if amount > 0 {
+		refund, err = q.CreateRefund(ctx, CreateRefundParams{
+			ReceiverID: pgtype.Int4{Int32: buyerID, Valid: true},
+			Amount:     amount,
+			ReasonCode: pgtype.Text{String: arg.ReasonCode, Valid: arg.ReasonCode != ""},
+			Comment:    pgtype.Text{String: arg.Comment, Valid: arg.Comment != ""},
+			CreatedBy:  pgtype.Int4{Int32: arg.AdminID, Valid: arg.AdminID != 0},
+			PurchaseID: pgtype.Int4{Int32: purchase.ID, Valid: true},
+		})
+		if err != nil {
+			return Purchase{}, refund, fmt.Errorf("error creating refund: %v", err)
+		}
+
+		err = q.creditCoins(ctx, buyerID, refund.ID, []lotSlice{
+			{Amount: amount, ExpiresAt: CoinExpiresAt(time.Now(), arg.CoinTTL)},
+		})
+		if err != nil {
+			return Purchase{}, refund, err
+		}
+	}
+
+	return updated, refund, nil
+}
+
+// cancelRefundedOrder переводит заказ в cancelled, если все его единицы возвращены.
+// Статус меняется независимо от стадии выдачи: вернуть больше нечего
+func (q *Queries) cancelRefundedOrder(ctx context.Context, orderID pgtype.Int4, adminID int32) error {
+	if !orderID.Valid {
+		return nil
+	}
+
+	order, err := q.GetOrderForUpdate(ctx, orderID.Int32)
+	if err != nil {
+		return fmt.Errorf("error getting order: %v", err)
+	}
+	if order.Status == OrderStatusCancelled {
+		return nil
+	}
+
+	purchases, err := q.ListOrderPurchasesForUpdate(ctx, orderID)
+	if err != nil {
+		return fmt.Errorf("error listing order purchases: %v", err)
+	}
+	for _, purchase := range purchases {
+		if purchase.RefundedQuantity < purchase.Quantity {
+			return nil
+		}
+	}
+
+	_, err = q.UpdateOrderStatus(ctx, UpdateOrderStatusParams{
+		ID:     order.ID,
+		Status: OrderStatusCancelled,
+	})
+	if err != nil {
+		return fmt.Errorf("error updating order status: %v", err)
+	}
+
+	_, err = q.CreateOrderStatusChange(ctx, CreateOrderStatusChangeParams{
+		OrderID:   order.ID,
+		Status:    OrderStatusCancelled,
+		Note:      pgtype.Text{String: "all items refunded", Valid: true},
+		ChangedBy: pgtype.Int4{Int32: adminID, Valid: adminID != 0},
+	})
+	if err != nil {
+		return fmt.Errorf("error recording status change: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	util "avito-shop/internal/util"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundAmount(t *testing.T) {
+	purchase := Purchase{Quantity: 3, TotalCost: 100}
+
+	// Частичные возвраты в сумме дают полную стоимость без потерь на округлении
+	var total int32
+	for i := 0; i < 3; i++ {
+		amount := refundAmount(purchase, 1)
+		require.Contains(t, []int32{33, 34}, amount)
+		total += amount
+		purchase.RefundedQuantity++
+	}
+	require.Equal(t, int32(100), total)
+
+	purchase.RefundedQuantity = 0
+	require.Equal(t, int32(100), refundAmount(purchase, 3))
+}
+
+func TestRefundTxCancelsOrder(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	item, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 10})
+	require.NoError(t, err)
+	user := createRandomUser(t)
+
+	bought, err := store.PurchaseTx(ctx, PurchaseTxParams{UserID: user.ID, ItemID: item.ID, Quantity: 2})
+	require.NoError(t, err)
+	orderID := bought.Purchase.OrderID.Int32
+
+	// Частичный возврат не меняет статус заказа
+	_, err = store.RefundTx(ctx, RefundTxParams{PurchaseID: bought.Purchase.ID, Quantity: 1, ReasonCode: ReasonCodeRefund})
+	require.NoError(t, err)
+
+	order, err := testQueries.GetOrderByID(ctx, orderID)
+	require.NoError(t, err)
+	require.Equal(t, OrderStatusPlaced, order.Status)
+
+	// Возврат последней единицы отменяет заказ
+	_, err = store.RefundTx(ctx, RefundTxParams{PurchaseID: bought.Purchase.ID, ReasonCode: ReasonCodeRefund})
+	require.NoError(t, err)
+
+	order, err = testQueries.GetOrderByID(ctx, orderID)
+	require.NoError(t, err)
+	require.Equal(t, OrderStatusCancelled, order.Status)
+}
@@ -0,0 +1,236 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrCartItemNotFound возвращается при изменении позиции, которой нет в корзине
+var ErrCartItemNotFound = errors.New("item not in cart")
+
+// NotEnoughStockError - свободного остатка, за вычетом резервов других корзин,
+// не хватает на резерв позиции корзины
+type NotEnoughStockError struct {
+	Available int32
+}
+
+func (e *NotEnoughStockError) Error() string {
+	return fmt.Sprintf("%s: only %d available", ErrItemOutOfStock, e.Available)
+}
+
+func (e *NotEnoughStockError) Unwrap() error {
+	return ErrItemOutOfStock
+}
+
+// AvailableStock - остаток за вычетом резервов корзин; неотслеживаемый остаток не меняется
+func AvailableStock(stock pgtype.Int4, reserved int32) pgtype.Int4 {
+	if !stock.Valid {
+		return stock
+	}
+	return pgtype.Int4{Int32: max(stock.Int32-reserved, 0), Valid: true}
+}
+
+// lockStock блокирует строку остатка варианта, если он задан, иначе товара:
+// резервы и списания одного остатка выполняются по очереди
+func (q *Queries) lockStock(ctx context.Context, itemID int32, variantID pgtype.Int4) (pgtype.Int4, error) {
+	var stock pgtype.Int4
+	var err error
+	if variantID.Valid {
+		stock, err = q.GetVariantStockForUpdate(ctx, variantID.Int32)
+	} else {
+		stock, err = q.GetItemStockForUpdate(ctx, itemID)
+	}
+	if err != nil {
+		return pgtype.Int4{}, fmt.Errorf("error locking stock: %v", err)
+	}
+	return stock, nil
+}
+
+// freeStock блокирует остаток и возвращает, сколько единиц из него доступно userID:
+// единицы в активных резервах чужих корзин недоступны, userID = 0 - недоступны все резервы
+func (q *Queries) freeStock(ctx context.Context, itemID int32, variantID pgtype.Int4, userID int32, now time.Time) (pgtype.Int4, error) {
+	stock, err := q.lockStock(ctx, itemID, variantID)
+	if err != nil || !stock.Valid {
+		return stock, err
+	}
+
+	reserved, err := q.GetReservedStock(ctx, GetReservedStockParams{
+		ItemID:    itemID,
+		VariantID: variantID,
+		UserID:    userID,
+		Now:       pgtype.Timestamp{Time: now, Valid: true},
+	})
+	if err != nil {
+		return pgtype.Int4{}, fmt.Errorf("error getting reserved stock: %v", err)
+	}
+	return AvailableStock(stock, reserved), nil
+}
+
+type CartItemTxParams struct {
+	UserID    int32       `json:"user_id"`
+	ItemID    int32       `json:"item_id"`
+	VariantID pgtype.Int4 `json:"variant_id"`
+	Quantity  int32       `json:"quantity"`
+	// ReservationTTL - срок резерва остатка под позицию, 0 - остаток не резервируется
+	ReservationTTL time.Duration `json:"reservation_ttl"`
+	Now            time.Time     `json:"now"`
+}
+
+type CartItemTxResult struct {
+	// Quantity - количество товара в корзине после изменения
+	Quantity int32 `json:"quantity"`
+	// ReservedQuantity - сколько единиц зарезервировано; меньше Quantity,
+	// если пользователю больше не позволяют ограничения товара
+	ReservedQuantity int32 `json:"reserved_quantity"`
+	// ReservedUntil - до какого момента остаток зарезервирован; не задан - резерва нет
+	ReservedUntil pgtype.Timestamp `json:"reserved_until"`
+}
+
+// AddCartItemTx добавляет товар в корзину или увеличивает его количество
+func (store *SQLStore) AddCartItemTx(ctx context.Context, arg CartItemTxParams) (CartItemTxResult, error) {
+	var result CartItemTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// Строка пользователя блокируется до остатка, как при покупке: ограничения товара
+		// считаются по его покупкам и резервам
+		err := q.LockUsers(ctx, []int32{arg.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		cartItem, err := q.AddCartItem(ctx, AddCartItemParams{
+			UserID:    arg.UserID,
+			ItemID:    arg.ItemID,
+			VariantID: arg.VariantID,
+			Quantity:  arg.Quantity,
+		})
+		if err != nil {
+			return fmt.Errorf("error adding cart item: %v", err)
+		}
+		result, err = q.reserveCartItem(ctx, arg, cartItem.Quantity)
+		return err
+	})
+
+	if err != nil {
+		return CartItemTxResult{}, fmt.Errorf("add cart item tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// UpdateCartItemTx меняет количество товара в корзине
+func (store *SQLStore) UpdateCartItemTx(ctx context.Context, arg CartItemTxParams) (CartItemTxResult, error) {
+	var result CartItemTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		err := q.LockUsers(ctx, []int32{arg.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		updated, err := q.UpdateCartItemQuantity(ctx, UpdateCartItemQuantityParams{
+			Quantity:  arg.Quantity,
+			UserID:    arg.UserID,
+			ItemID:    arg.ItemID,
+			VariantID: arg.VariantID,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating cart item: %v", err)
+		}
+		if updated == 0 {
+			return ErrCartItemNotFound
+		}
+		result, err = q.reserveCartItem(ctx, arg, arg.Quantity)
+		return err
+	})
+
+	if err != nil {
+		return CartItemTxResult{}, fmt.Errorf("update cart item tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// reserveCartItem резервирует остаток под позицию корзины на arg.ReservationTTL.
+// Резерв подчиняется тем же правилам, что и покупка: товар должен продаваться,
+// покупатель - пройти очередь дропа, а резерв не превышает того, что пользователь
+// еще может купить. Неотслеживаемый остаток не резервируется; если свободного
+// остатка не хватает, возвращается NotEnoughStockError и корзина не меняется
+func (q *Queries) reserveCartItem(ctx context.Context, arg CartItemTxParams, quantity int32) (CartItemTxResult, error) {
+	result := CartItemTxResult{Quantity: quantity}
+	if arg.ReservationTTL <= 0 {
+		return result, nil
+	}
+
+	item, err := q.GetItemByID(ctx, arg.ItemID)
+	if err != nil {
+		return CartItemTxResult{}, fmt.Errorf("error getting item: %v", err)
+	}
+
+	// 1. Окно продаж и допуск из очереди дропа
+	err = q.checkAvailability(ctx, item.ID, arg.UserID, item.Name, item.Availability(), arg.Now)
+	if err != nil {
+		return CartItemTxResult{}, err
+	}
+
+	// 2. Резерв не больше остатка ограничений товара, включая резервы других вариантов
+	reserve := quantity
+	limits := item.Limits()
+	if limits.Enabled() {
+		stats, err := q.GetUserItemPurchaseStats(ctx, GetUserItemPurchaseStatsParams{
+			ItemID: pgtype.Int4{Int32: item.ID, Valid: true},
+			UserID: arg.UserID,
+		})
+		if err != nil {
+			return CartItemTxResult{}, fmt.Errorf("error getting purchase stats: %v", err)
+		}
+		reserved, err := q.GetUserReservedQuantity(ctx, GetUserReservedQuantityParams{
+			UserID:    arg.UserID,
+			ItemID:    item.ID,
+			VariantID: arg.VariantID,
+			Now:       pgtype.Timestamp{Time: arg.Now, Valid: true},
+		})
+		if err != nil {
+			return CartItemTxResult{}, fmt.Errorf("error getting reserved quantity: %v", err)
+		}
+
+		allowance := limits.Allowance(stats, arg.Now) - reserved
+		if allowance <= 0 {
+			if err := limits.Check(item.Name, stats, reserved+1, arg.Now); err != nil {
+				return CartItemTxResult{}, err
+			}
+			return CartItemTxResult{}, &PurchaseLimitError{Item: item.Name, Limit: PurchaseLimitMaxPerUser}
+		}
+		reserve = min(reserve, allowance)
+	}
+
+	// 3. Свободный остаток за вычетом чужих резервов
+	free, err := q.freeStock(ctx, arg.ItemID, arg.VariantID, arg.UserID, arg.Now)
+	if err != nil {
+		return CartItemTxResult{}, err
+	}
+	if !free.Valid {
+		return result, nil
+	}
+	if free.Int32 < reserve {
+		return CartItemTxResult{}, &NotEnoughStockError{Available: free.Int32}
+	}
+
+	result.ReservedQuantity = reserve
+	result.ReservedUntil, err = q.ReserveCartItem(ctx, ReserveCartItemParams{
+		ReservedQuantity: reserve,
+		Now:              pgtype.Timestamp{Time: arg.Now, Valid: true},
+		ReservedUntil:    pgtype.Timestamp{Time: arg.Now.Add(arg.ReservationTTL), Valid: true},
+		UserID:           arg.UserID,
+		ItemID:           arg.ItemID,
+		VariantID:        arg.VariantID,
+	})
+	if err != nil {
+		return CartItemTxResult{}, fmt.Errorf("error reserving stock: %v", err)
+	}
+	return result, nil
+}
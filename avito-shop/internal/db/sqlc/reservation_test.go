@@ -0,0 +1,158 @@
+package db
+
+import (
+	util "avito-shop/internal/util"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvailableStock(t *testing.T) {
+	require.Equal(t, pgtype.Int4{Int32: 3, Valid: true}, AvailableStock(pgtype.Int4{Int32: 5, Valid: true}, 2))
+	require.Equal(t, pgtype.Int4{Int32: 0, Valid: true}, AvailableStock(pgtype.Int4{Int32: 1, Valid: true}, 2))
+	require.Equal(t, pgtype.Int4{}, AvailableStock(pgtype.Int4{}, 2))
+}
+
+func TestCartReservationTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	item, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 10})
+	require.NoError(t, err)
+	variant := createRandomVariant(t, item, pgtype.Int4{}, pgtype.Int4{Int32: 3, Valid: true})
+	variantID := pgtype.Int4{Int32: variant.ID, Valid: true}
+	first := createRandomUser(t)
+	second := createRandomUser(t)
+	now := time.Now()
+
+	added, err := store.AddCartItemTx(ctx, CartItemTxParams{
+		UserID:         first.ID,
+		ItemID:         item.ID,
+		VariantID:      variantID,
+		Quantity:       2,
+		ReservationTTL: 15 * time.Minute,
+		Now:            now,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), added.Quantity)
+	require.True(t, added.ReservedUntil.Valid)
+
+	// Повторное изменение позиции не продлевает действующий резерв
+	readded, err := store.UpdateCartItemTx(ctx, CartItemTxParams{
+		UserID:         first.ID,
+		ItemID:         item.ID,
+		VariantID:      variantID,
+		Quantity:       2,
+		ReservationTTL: 15 * time.Minute,
+		Now:            now.Add(10 * time.Minute),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), readded.ReservedQuantity)
+	require.WithinDuration(t, added.ReservedUntil.Time, readded.ReservedUntil.Time, time.Millisecond)
+
+	// Другому покупателю остается одна единица, и резерв на две не проходит
+	_, err = store.AddCartItemTx(ctx, CartItemTxParams{
+		UserID:         second.ID,
+		ItemID:         item.ID,
+		VariantID:      variantID,
+		Quantity:       2,
+		ReservationTTL: 15 * time.Minute,
+		Now:            now,
+	})
+	var stockErr *NotEnoughStockError
+	require.ErrorAs(t, err, &stockErr)
+	require.Equal(t, int32(1), stockErr.Available)
+	require.ErrorIs(t, err, ErrItemOutOfStock)
+
+	// Изменение количества отсутствующей позиции
+	_, err = store.UpdateCartItemTx(ctx, CartItemTxParams{
+		UserID:    second.ID,
+		ItemID:    item.ID,
+		VariantID: variantID,
+		Quantity:  1,
+		Now:       now,
+	})
+	require.ErrorIs(t, err, ErrCartItemNotFound)
+
+	// Оформление заказа забирает собственный резерв
+	checkout, err := store.CheckoutTx(ctx, CheckoutTxParams{UserID: first.ID})
+	require.NoError(t, err)
+	require.Len(t, checkout.Purchases, 1)
+
+	stock, err := testQueries.GetItemVariantByID(ctx, variant.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), stock.Stock.Int32)
+
+	// Просроченный резерв снимается и не мешает другим
+	_, err = store.AddCartItemTx(ctx, CartItemTxParams{
+		UserID:         second.ID,
+		ItemID:         item.ID,
+		VariantID:      variantID,
+		Quantity:       1,
+		ReservationTTL: time.Minute,
+		Now:            now.Add(-time.Hour),
+	})
+	require.NoError(t, err)
+
+	released, err := testQueries.ReleaseExpiredReservations(ctx, pgtype.Timestamp{Time: now, Valid: true})
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, released, int64(1))
+
+	reserved, err := testQueries.GetReservedStock(ctx, GetReservedStockParams{
+		ItemID:    item.ID,
+		VariantID: variantID,
+		Now:       pgtype.Timestamp{Time: now, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Zero(t, reserved)
+}
+
+func TestCartReservationLimits(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	item, err := testQueries.CreateItem(ctx, CreateItemParams{Name: util.RandomString(6), Price: 10})
+	require.NoError(t, err)
+	_, err = testQueries.UpdateItemLimits(ctx, UpdateItemLimitsParams{
+		MaxPerUser: pgtype.Int4{Int32: 2, Valid: true},
+		ID:         item.ID,
+	})
+	require.NoError(t, err)
+	variant := createRandomVariant(t, item, pgtype.Int4{}, pgtype.Int4{Int32: 10, Valid: true})
+	variantID := pgtype.Int4{Int32: variant.ID, Valid: true}
+	user := createRandomUser(t)
+	now := time.Now()
+
+	// Резерв не больше того, что пользователь может купить
+	added, err := store.AddCartItemTx(ctx, CartItemTxParams{
+		UserID:         user.ID,
+		ItemID:         item.ID,
+		VariantID:      variantID,
+		Quantity:       5,
+		ReservationTTL: 15 * time.Minute,
+		Now:            now,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(5), added.Quantity)
+	require.Equal(t, int32(2), added.ReservedQuantity)
+
+	// Товар вне окна продаж не резервируется
+	_, err = testQueries.UpdateItemAvailability(ctx, UpdateItemAvailabilityParams{
+		AvailableFrom: pgtype.Timestamp{Time: now.Add(time.Hour), Valid: true},
+		ID:            item.ID,
+	})
+	require.NoError(t, err)
+
+	_, err = store.UpdateCartItemTx(ctx, CartItemTxParams{
+		UserID:         user.ID,
+		ItemID:         item.ID,
+		VariantID:      variantID,
+		Quantity:       1,
+		ReservationTTL: 15 * time.Minute,
+		Now:            now,
+	})
+	require.ErrorIs(t, err, ErrItemUnavailable)
+}
@@ -2,18 +2,75 @@ package db
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Store interface {
 	Querier
+	CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error)
+	AssignDepartmentTx(ctx context.Context, arg AssignDepartmentTxParams) (AssignDepartmentTxResult, error)
 	TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error)
+	CancelTransferTx(ctx context.Context, arg CancelTransferTxParams) (CancelTransferTxResult, error)
+	SettleTransfersTx(ctx context.Context, arg SettleTransfersTxParams) (SettleTransfersTxResult, error)
 	PurchaseTx(ctx context.Context, arg PurchaseTxParams) (PurchaseTxResult, error)
+	AdjustBalanceTx(ctx context.Context, arg AdjustBalanceTxParams) (AdjustBalanceTxResult, error)
+	ExpireCoinsTx(ctx context.Context, arg ExpireCoinsTxParams) (ExpireCoinsTxResult, error)
+	ReconcileBalancesTx(ctx context.Context, arg ReconcileBalancesTxParams) (ReconcileBalancesTxResult, error)
+	RefundTx(ctx context.Context, arg RefundTxParams) (RefundTxResult, error)
+	CheckoutTx(ctx context.Context, arg CheckoutTxParams) (CheckoutTxResult, error)
+	UpdateOrderStatusTx(ctx context.Context, arg UpdateOrderStatusTxParams) (UpdateOrderStatusTxResult, error)
+	ChangeItemPriceTx(ctx context.Context, arg ChangeItemPriceTxParams) (ChangeItemPriceTxResult, error)
+	UpdateItemTx(ctx context.Context, arg UpdateItemTxParams) (UpdateItemTxResult, error)
+	PlaceBidTx(ctx context.Context, arg PlaceBidTxParams) (PlaceBidTxResult, error)
+	SettleAuctionTx(ctx context.Context, arg SettleAuctionTxParams) (SettleAuctionTxResult, error)
+	CancelAuctionTx(ctx context.Context, arg CancelAuctionTxParams) (CancelAuctionTxResult, error)
+	CreateRaffleTx(ctx context.Context, arg CreateRaffleTxParams) (CreateRaffleTxResult, error)
+	BuyRaffleTicketsTx(ctx context.Context, arg BuyRaffleTicketsTxParams) (BuyRaffleTicketsTxResult, error)
+	DrawRaffleTx(ctx context.Context, arg DrawRaffleTxParams) (DrawRaffleTxResult, error)
+	GiveItemTx(ctx context.Context, arg GiveItemTxParams) (GiveItemTxResult, error)
+	CreateListingTx(ctx context.Context, arg CreateListingTxParams) (CreateListingTxResult, error)
+	MarketplaceTx(ctx context.Context, arg MarketplaceTxParams) (MarketplaceTxResult, error)
+	CreateBundleTx(ctx context.Context, arg CreateBundleTxParams) (CreateBundleTxResult, error)
+	BuyBundleTx(ctx context.Context, arg BuyBundleTxParams) (BuyBundleTxResult, error)
+	AddCartItemTx(ctx context.Context, arg CartItemTxParams) (CartItemTxResult, error)
+	UpdateCartItemTx(ctx context.Context, arg CartItemTxParams) (CartItemTxResult, error)
+	QuotePurchaseTx(ctx context.Context, arg PurchaseTxParams) (QuoteTxResult, error)
+	QuoteTransferTx(ctx context.Context, arg TransferTxParams) (QuoteTxResult, error)
 }
 
+// Статусы перевода в таблице transactions
+const (
+	TransferStatusCompleted = "completed"
+	TransferStatusPending   = "pending"
+	TransferStatusCancelled = "cancelled"
+)
+
+// Виды операций в таблице transactions
+const (
+	TransactionKindTransfer   = "transfer"
+	TransactionKindGrant      = "grant"
+	TransactionKindDeduction  = "deduction"
+	TransactionKindExpiry     = "expiry"
+	TransactionKindRefund     = "refund"
+	TransactionKindRaffle     = "raffle"
+	TransactionKindSale       = "sale"
+	TransactionKindCommission = "commission"
+)
+
+// Ошибки транзакций
+var (
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	ErrTransferNotFound    = errors.New("transfer not found")
+	ErrTransferNotPending  = errors.New("transfer is not pending")
+	ErrItemOutOfStock      = errors.New("item is out of stock")
+)
+
 type SQLStore struct {
 	connPool *pgxpool.Pool
 	*Queries
@@ -30,6 +87,11 @@ type TransferTxParams struct {
 	FromUserID int32 `json:"from_user_id"`
 	ToUserID   int32 `json:"to_user_id"`
 	Amount     int32 `json:"amount"`
+	// HoldPeriod - окно отмены: если больше нуля, монеты списываются у отправителя,
+	// но зачисляются получателю только после его истечения
+	HoldPeriod time.Duration `json:"hold_period"`
+	// Limits проверяются внутри транзакции при заблокированной строке отправителя
+	Limits TransferLimits `json:"limits"`
 }
 
 type TransferTxResult struct {
@@ -38,10 +100,37 @@ type TransferTxResult struct {
 	ToUser   User        `json:"to_user"`
 }
 
+type CancelTransferTxParams struct {
+	TransferID int32 `json:"transfer_id"`
+	UserID     int32 `json:"user_id"`
+}
+
+type CancelTransferTxResult struct {
+	Transfer Transaction `json:"transfer"`
+	FromUser User        `json:"from_user"`
+}
+
+type SettleTransfersTxParams struct {
+	Now   time.Time `json:"now"`
+	Limit int32     `json:"limit"`
+}
+
+type SettleTransfersTxResult struct {
+	Settled []Transaction `json:"settled"`
+}
+
 type PurchaseTxParams struct {
 	UserID   int32 `json:"user_id"`
 	ItemID   int32 `json:"item_id"`
 	Quantity int32 `json:"quantity"`
+	// RecipientID - кому подарить товар, 0 - покупка для себя.
+	// Монеты списываются у UserID, товар попадает в инвентарь получателя.
+	RecipientID int32  `json:"recipient_id"`
+	GiftMessage string `json:"gift_message"`
+	// VariantID - выбранный вариант товара, 0 - товар без вариантов
+	VariantID int32 `json:"variant_id"`
+	// PromoCode - промокод покупателя, пустая строка - без скидки
+	PromoCode string `json:"promo_code"`
 }
 
 type PurchaseTxResult struct {
@@ -66,30 +155,87 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	return tx.Commit(ctx)
 }
 
+// execReadTx выполняет fn в транзакции только для чтения: все запросы видят один
+// снимок данных, а попытка записи отклоняется базой
+func (store *SQLStore) execReadTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.connPool.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	return fn(New(tx))
+}
+
 func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (TransferTxResult, error) {
 	var result TransferTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
 
-		// 1. Создаем запись о транзакции
-		result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
-			SenderID:   pgtype.Int4{Int32: arg.FromUserID, Valid: true},
-			ReceiverID: pgtype.Int4{Int32: arg.ToUserID, Valid: true},
-			Amount:     arg.Amount,
-		})
+		// Блокируем обоих пользователей в порядке id: параллельные переводы одного
+		// отправителя выполняются последовательно и видят суммы друг друга
+		err = q.LockUsers(ctx, []int32{arg.FromUserID, arg.ToUserID})
 		if err != nil {
-			return fmt.Errorf("error creating transfer: %v", err)
+			return fmt.Errorf("error locking users: %v", err)
 		}
 
-		// 2. Обновляем балансы
-		err = q.UpdateBalanceForTransfer(ctx, UpdateBalanceForTransferParams{
-			ID:      arg.FromUserID,
-			ID_2:    arg.ToUserID,
-			Balance: pgtype.Int4{Int32: arg.Amount, Valid: true},
-		})
-		if err != nil {
-			return fmt.Errorf("error updating balances: %v", err)
+		// Проверяем лимиты по уже совершенным и отложенным переводам
+		var stats GetOutgoingTransferStatsRow
+		if arg.Limits.Enabled() {
+			stats, err = q.GetOutgoingTransferStats(ctx, NewTransferStatsParams(arg.FromUserID, time.Now()))
+			if err != nil {
+				return fmt.Errorf("error getting transfer stats: %v", err)
+			}
+		}
+		if err = arg.Limits.Check(stats, arg.Amount); err != nil {
+			return err
+		}
+
+		if arg.HoldPeriod > 0 {
+			// 1. Создаем отложенный перевод и удерживаем монеты отправителя
+			result.Transfer, err = q.CreatePendingTransfer(ctx, CreatePendingTransferParams{
+				SenderID:   pgtype.Int4{Int32: arg.FromUserID, Valid: true},
+				ReceiverID: pgtype.Int4{Int32: arg.ToUserID, Valid: true},
+				Amount:     arg.Amount,
+				SettlesAt:  pgtype.Timestamp{Time: time.Now().Add(arg.HoldPeriod), Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error creating pending transfer: %v", err)
+			}
+
+			// 2. Списываем монеты только у отправителя и запоминаем удержанные лоты
+			slices, err := q.debitCoins(ctx, arg.FromUserID, arg.Amount, time.Now())
+			if err != nil {
+				return err
+			}
+			err = q.holdCoins(ctx, result.Transfer.ID, slices)
+			if err != nil {
+				return err
+			}
+		} else {
+			// 1. Создаем запись о транзакции
+			result.Transfer, err = q.CreateTransfer(ctx, CreateTransferParams{
+				SenderID:   pgtype.Int4{Int32: arg.FromUserID, Valid: true},
+				ReceiverID: pgtype.Int4{Int32: arg.ToUserID, Valid: true},
+				Amount:     arg.Amount,
+			})
+			if err != nil {
+				return fmt.Errorf("error creating transfer: %v", err)
+			}
+
+			// 2. Переносим лоты отправителя получателю вместе со сроком действия
+			slices, err := q.debitCoins(ctx, arg.FromUserID, arg.Amount, time.Now())
+			if err != nil {
+				return err
+			}
+			err = q.creditCoins(ctx, arg.ToUserID, result.Transfer.ID, slices)
+			if err != nil {
+				return err
+			}
 		}
 
 		// 3. Получаем обновленные данные отправителя
@@ -108,7 +254,115 @@ func (store *SQLStore) TransferTx(ctx context.Context, arg TransferTxParams) (Tr
 	})
 
 	if err != nil {
-		return TransferTxResult{}, fmt.Errorf("transfer tx error: %v", err)
+		return TransferTxResult{}, fmt.Errorf("transfer tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// CancelTransferTx отменяет отложенный перевод и возвращает удержанные монеты отправителю.
+// Отменить можно только собственный перевод, пока не истекло окно отмены.
+func (store *SQLStore) CancelTransferTx(ctx context.Context, arg CancelTransferTxParams) (CancelTransferTxResult, error) {
+	var result CancelTransferTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем перевод, чтобы отмена не пересеклась с зачислением
+		transfer, err := q.GetTransferForUpdate(ctx, arg.TransferID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTransferNotFound
+			}
+			return fmt.Errorf("error getting transfer: %v", err)
+		}
+
+		if transfer.SenderID.Int32 != arg.UserID {
+			return ErrTransferNotFound
+		}
+		if transfer.Status != TransferStatusPending || !time.Now().Before(transfer.SettlesAt.Time) {
+			return ErrTransferNotPending
+		}
+
+		// 2. Помечаем перевод отмененным
+		err = q.UpdateTransferStatus(ctx, UpdateTransferStatusParams{
+			ID:     transfer.ID,
+			Status: TransferStatusCancelled,
+		})
+		if err != nil {
+			return fmt.Errorf("error updating transfer status: %v", err)
+		}
+
+		// 3. Возвращаем монеты отправителю в лоты с исходным сроком действия
+		slices, err := q.heldCoins(ctx, transfer.ID)
+		if err != nil {
+			return err
+		}
+		err = q.creditCoins(ctx, arg.UserID, transfer.ID, slices)
+		if err != nil {
+			return err
+		}
+
+		result.Transfer = transfer
+		result.Transfer.Status = TransferStatusCancelled
+
+		result.FromUser, err = q.GetUserByID(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("error getting sender: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return CancelTransferTxResult{}, fmt.Errorf("cancel transfer tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+// SettleTransfersTx зачисляет получателям отложенные переводы, у которых истекло окно отмены.
+// Строки, заблокированные другой транзакцией, пропускаются и будут обработаны позже.
+func (store *SQLStore) SettleTransfersTx(ctx context.Context, arg SettleTransfersTxParams) (SettleTransfersTxResult, error) {
+	var result SettleTransfersTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Выбираем и блокируем переводы, готовые к зачислению
+		transfers, err := q.ListDuePendingTransfers(ctx, ListDuePendingTransfersParams{
+			SettlesAt: pgtype.Timestamp{Time: arg.Now, Valid: true},
+			Limit:     arg.Limit,
+		})
+		if err != nil {
+			return fmt.Errorf("error listing pending transfers: %v", err)
+		}
+
+		for _, transfer := range transfers {
+			// 2. Зачисляем получателю удержанные лоты
+			slices, err := q.heldCoins(ctx, transfer.ID)
+			if err != nil {
+				return err
+			}
+			err = q.creditCoins(ctx, transfer.ReceiverID.Int32, transfer.ID, slices)
+			if err != nil {
+				return err
+			}
+
+			// 3. Завершаем перевод
+			err = q.UpdateTransferStatus(ctx, UpdateTransferStatusParams{
+				ID:     transfer.ID,
+				Status: TransferStatusCompleted,
+			})
+			if err != nil {
+				return fmt.Errorf("error updating transfer status: %v", err)
+			}
+
+			transfer.Status = TransferStatusCompleted
+			result.Settled = append(result.Settled, transfer)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return SettleTransfersTxResult{}, fmt.Errorf("settle transfers tx error: %w", err)
 	}
 
 	return result, nil
@@ -120,59 +374,121 @@ func (store *SQLStore) PurchaseTx(ctx context.Context, arg PurchaseTxParams) (Pu
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
 
-		// 1. Получаем информацию о товаре
+		// 1. Блокируем покупателя, чтобы параллельные покупки не расходовали одни и те же лоты,
+		// и получателя подарка, чтобы параллельные покупки не обошли ограничения товара
+		userIDs := []int32{arg.UserID}
+		if arg.RecipientID != 0 {
+			userIDs = append(userIDs, arg.RecipientID)
+		}
+		err = q.LockUsers(ctx, userIDs)
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		// 2. Получаем информацию о товаре и проверяем ограничения и окно продаж.
+		// Подарок учитывается и у покупателя, и у получателя: иначе ограничение
+		// обходится покупками в подарок
 		item, err := q.GetItemByID(ctx, arg.ItemID)
 		if err != nil {
 			return fmt.Errorf("error getting item: %v", err)
 		}
 
-		// 2. Проверяем текущий баланс пользователя
-		balance, err := q.GetCurrentBalance(ctx, arg.UserID)
+		for _, userID := range userIDs {
+			err = q.checkItemLimits(ctx, item.ID, userID, item.Name, item.Limits(), 1)
+			if err != nil {
+				return err
+			}
+		}
+
+		// Окно продаж и очередь дропа проверяются для покупателя: в очереди стоит он
+		err = q.checkAvailability(ctx, item.ID, arg.UserID, item.Name, item.Availability(), time.Now())
 		if err != nil {
-			return fmt.Errorf("error getting balance: %v", err)
+			return err
+		}
+
+		// 3. Определяем цену по варианту и уменьшаем остаток, если он отслеживается
+		price := item.Price
+		variantID := pgtype.Int4{Int32: arg.VariantID, Valid: arg.VariantID != 0}
+		tracked := item.Stock.Valid
+		if variantID.Valid {
+			variant, err := q.GetItemVariantByID(ctx, arg.VariantID)
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return ErrVariantNotFound
+				}
+				return fmt.Errorf("error getting variant: %v", err)
+			}
+			if variant.ItemID != item.ID {
+				return ErrVariantNotFound
+			}
+			price = variant.UnitPrice(item.Price)
+			tracked = variant.Stock.Valid
 		}
 
-		// Проверяем, достаточно ли денег
-		if balance.Int32 < item.Price {
-			return fmt.Errorf("insufficient balance")
+		err = q.decrementStock(ctx, item.ID, variantID, tracked, 1, arg.UserID, time.Now())
+		if err != nil {
+			return err
 		}
 
-		// 3. Создаем запись о покупке
-		createdPurchase, err := q.CreatePurchase(ctx, CreatePurchaseParams{
-			BuyerID:   pgtype.Int4{Int32: arg.UserID, Valid: true},
-			ItemID:    pgtype.Int4{Int32: arg.ItemID, Valid: true},
-			Quantity:  1,
-			TotalCost: item.Price,
+		// 4. Применяем промокод: он блокируется до конца транзакции,
+		// поэтому лимиты использований не превышаются параллельными покупками
+		var promoCodeID pgtype.Int4
+		var discount int32
+		if arg.PromoCode != "" {
+			var promo PromoCode
+			promo, discount, err = q.applyPromoCode(ctx, arg.PromoCode, arg.UserID, item, price)
+			if err != nil {
+				return err
+			}
+			promoCodeID = pgtype.Int4{Int32: promo.ID, Valid: true}
+		}
+		cost := price - discount
+
+		// 5. Создаем заказ и запись о покупке с ценой на момент покупки
+		order, err := q.placeOrder(ctx, arg.UserID, cost)
+		if err != nil {
+			return err
+		}
+
+		createdPurchase, err := q.createPurchase(ctx, CreatePurchaseParams{
+			BuyerID:     pgtype.Int4{Int32: arg.UserID, Valid: true},
+			ItemID:      pgtype.Int4{Int32: arg.ItemID, Valid: true},
+			Quantity:    1,
+			TotalCost:   cost,
+			RecipientID: pgtype.Int4{Int32: arg.RecipientID, Valid: arg.RecipientID != 0},
+			GiftMessage: pgtype.Text{String: arg.GiftMessage, Valid: arg.GiftMessage != ""},
+			OrderID:     pgtype.Int4{Int32: order.ID, Valid: true},
+			VariantID:   variantID,
+			PromoCodeID: promoCodeID,
+			Discount:    discount,
+			UnitPrice:   price,
 		})
 		if err != nil {
-			return fmt.Errorf("error creating purchase: %v", err)
+			return err
 		}
 		result.Purchase = createdPurchase
 
-		// 4. Списываем деньги
-		err = q.UpdateBalanceForPurchase(ctx, UpdateBalanceForPurchaseParams{
-			ID:      arg.UserID,
-			Balance: pgtype.Int4{Int32: item.Price, Valid: true},
-		})
+		// 6. Списываем монеты, начиная с тех, что сгорят раньше
+		_, err = q.debitCoins(ctx, arg.UserID, cost, time.Now())
 		if err != nil {
-			return fmt.Errorf("error updating balance: %v", err)
+			return err
 		}
 
-		// 5. Получаем обновленные данные пользователя
+		// 7. Получаем обновленные данные пользователя
 		updatedUser, err := q.GetUserByID(ctx, arg.UserID)
 		if err != nil {
 			return fmt.Errorf("error getting updated user: %v", err)
 		}
 		result.User = updatedUser
 
-		// 6. Сохраняем информацию о товаре в результате
+		// 8. Сохраняем информацию о товаре в результате
 		result.Item = item
 
 		return nil
 	})
 
 	if err != nil {
-		return PurchaseTxResult{}, fmt.Errorf("purchase tx error: %v", err)
+		return PurchaseTxResult{}, fmt.Errorf("purchase tx error: %w", err)
 	}
 
 	return result, nil
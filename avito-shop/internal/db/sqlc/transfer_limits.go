@@ -0,0 +1,126 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Скользящие окна, в которых считаются лимиты исходящих переводов
+const (
+	transferDailyWindow  = 24 * time.Hour
+	transferWeeklyWindow = 7 * 24 * time.Hour
+	transferRateWindow   = time.Minute
+)
+
+// Названия лимитов, которые возвращаются в TransferLimitError
+const (
+	TransferLimitMaxAmount = "max_amount"
+	TransferLimitDaily     = "daily"
+	TransferLimitWeekly    = "weekly"
+	TransferLimitRate      = "rate"
+)
+
+// ErrTransferLimitExceeded позволяет проверить любую ошибку лимита через errors.Is
+var ErrTransferLimitExceeded = errors.New("transfer limit exceeded")
+
+// TransferLimits - ограничения на исходящие переводы пользователя.
+// Нулевое значение поля отключает соответствующее ограничение.
+type TransferLimits struct {
+	MaxAmount    int32 `json:"max_amount"`
+	DailyLimit   int32 `json:"daily_limit"`
+	WeeklyLimit  int32 `json:"weekly_limit"`
+	MaxPerMinute int32 `json:"max_per_minute"`
+}
+
+// TransferLimitError описывает, какой лимит был превышен и сколько еще доступно
+type TransferLimitError struct {
+	Limit     string
+	Allowed   int32
+	Remaining int32
+}
+
+func (e *TransferLimitError) Error() string {
+	switch e.Limit {
+	case TransferLimitMaxAmount:
+		return fmt.Sprintf("transfer amount exceeds the per-transfer maximum of %d", e.Allowed)
+	case TransferLimitRate:
+		return fmt.Sprintf("too many transfers: at most %d per minute allowed", e.Allowed)
+	default:
+		return fmt.Sprintf("%s transfer limit of %d exceeded, %d remaining", e.Limit, e.Allowed, e.Remaining)
+	}
+}
+
+func (e *TransferLimitError) Unwrap() error {
+	return ErrTransferLimitExceeded
+}
+
+// TransferAllowance - остаток лимитов; nil означает отсутствие ограничения
+type TransferAllowance struct {
+	MaxAmount       *int32 `json:"max_amount"`
+	DailyRemaining  *int32 `json:"daily_remaining"`
+	WeeklyRemaining *int32 `json:"weekly_remaining"`
+	MinuteRemaining *int32 `json:"minute_remaining"`
+}
+
+// Enabled сообщает, задан ли хотя бы один лимит, требующий подсчета по истории
+func (limits TransferLimits) Enabled() bool {
+	return limits.DailyLimit > 0 || limits.WeeklyLimit > 0 || limits.MaxPerMinute > 0
+}
+
+// Check проверяет, укладывается ли перевод amount в лимиты с учетом уже отправленного
+func (limits TransferLimits) Check(stats GetOutgoingTransferStatsRow, amount int32) error {
+	if limits.MaxAmount > 0 && amount > limits.MaxAmount {
+		return &TransferLimitError{Limit: TransferLimitMaxAmount, Allowed: limits.MaxAmount, Remaining: limits.MaxAmount}
+	}
+	if limits.MaxPerMinute > 0 && stats.MinuteCount >= limits.MaxPerMinute {
+		return &TransferLimitError{Limit: TransferLimitRate, Allowed: limits.MaxPerMinute}
+	}
+	if limits.DailyLimit > 0 && stats.DailyAmount+amount > limits.DailyLimit {
+		return &TransferLimitError{Limit: TransferLimitDaily, Allowed: limits.DailyLimit, Remaining: remaining(limits.DailyLimit, stats.DailyAmount)}
+	}
+	if limits.WeeklyLimit > 0 && stats.WeeklyAmount+amount > limits.WeeklyLimit {
+		return &TransferLimitError{Limit: TransferLimitWeekly, Allowed: limits.WeeklyLimit, Remaining: remaining(limits.WeeklyLimit, stats.WeeklyAmount)}
+	}
+	return nil
+}
+
+// Allowance считает остаток каждого заданного лимита
+func (limits TransferLimits) Allowance(stats GetOutgoingTransferStatsRow) TransferAllowance {
+	var allowance TransferAllowance
+	if limits.MaxAmount > 0 {
+		allowance.MaxAmount = &limits.MaxAmount
+	}
+	if limits.DailyLimit > 0 {
+		left := remaining(limits.DailyLimit, stats.DailyAmount)
+		allowance.DailyRemaining = &left
+	}
+	if limits.WeeklyLimit > 0 {
+		left := remaining(limits.WeeklyLimit, stats.WeeklyAmount)
+		allowance.WeeklyRemaining = &left
+	}
+	if limits.MaxPerMinute > 0 {
+		left := remaining(limits.MaxPerMinute, stats.MinuteCount)
+		allowance.MinuteRemaining = &left
+	}
+	return allowance
+}
+
+// NewTransferStatsParams задает границы окон для подсчета исходящих переводов на момент now
+func NewTransferStatsParams(userID int32, now time.Time) GetOutgoingTransferStatsParams {
+	return GetOutgoingTransferStatsParams{
+		DayStart:    pgtype.Timestamp{Time: now.Add(-transferDailyWindow), Valid: true},
+		WeekStart:   pgtype.Timestamp{Time: now.Add(-transferWeeklyWindow), Valid: true},
+		MinuteStart: pgtype.Timestamp{Time: now.Add(-transferRateWindow), Valid: true},
+		SenderID:    pgtype.Int4{Int32: userID, Valid: true},
+	}
+}
+
+func remaining(limit, used int32) int32 {
+	if used >= limit {
+		return 0
+	}
+	return limit - used
+}
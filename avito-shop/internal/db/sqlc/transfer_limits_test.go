@@ -0,0 +1,94 @@
+package db
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransferLimitsCheck(t *testing.T) {
+	limits := TransferLimits{
+		MaxAmount:    200,
+		DailyLimit:   500,
+		WeeklyLimit:  1000,
+		MaxPerMinute: 3,
+	}
+
+	testCases := []struct {
+		name      string
+		limits    TransferLimits
+		stats     GetOutgoingTransferStatsRow
+		amount    int32
+		wantLimit string
+	}{
+		{
+			name:   "OK_NoLimits",
+			limits: TransferLimits{},
+			stats:  GetOutgoingTransferStatsRow{DailyAmount: 10000, WeeklyAmount: 10000, MinuteCount: 100},
+			amount: 1000,
+		},
+		{
+			name:   "OK_WithinLimits",
+			limits: limits,
+			stats:  GetOutgoingTransferStatsRow{DailyAmount: 300, WeeklyAmount: 800, MinuteCount: 2},
+			amount: 200,
+		},
+		{
+			name:      "MaxAmount",
+			limits:    limits,
+			amount:    201,
+			wantLimit: TransferLimitMaxAmount,
+		},
+		{
+			name:      "Rate",
+			limits:    limits,
+			stats:     GetOutgoingTransferStatsRow{MinuteCount: 3},
+			amount:    10,
+			wantLimit: TransferLimitRate,
+		},
+		{
+			name:      "Daily",
+			limits:    limits,
+			stats:     GetOutgoingTransferStatsRow{DailyAmount: 450, WeeklyAmount: 450},
+			amount:    100,
+			wantLimit: TransferLimitDaily,
+		},
+		{
+			name:      "Weekly",
+			limits:    limits,
+			stats:     GetOutgoingTransferStatsRow{DailyAmount: 0, WeeklyAmount: 950},
+			amount:    100,
+			wantLimit: TransferLimitWeekly,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.limits.Check(tc.stats, tc.amount)
+			if tc.wantLimit == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.ErrorIs(t, err, ErrTransferLimitExceeded)
+
+			var limitErr *TransferLimitError
+			require.True(t, errors.As(err, &limitErr))
+			require.Equal(t, tc.wantLimit, limitErr.Limit)
+		})
+	}
+}
+
+func TestTransferLimitsAllowance(t *testing.T) {
+	limits := TransferLimits{DailyLimit: 500, WeeklyLimit: 1000}
+	stats := GetOutgoingTransferStatsRow{DailyAmount: 600, WeeklyAmount: 600}
+
+	allowance := limits.Allowance(stats)
+	require.Nil(t, allowance.MaxAmount)
+	require.Nil(t, allowance.MinuteRemaining)
+	require.Equal(t, int32(0), *allowance.DailyRemaining)
+	require.Equal(t, int32(400), *allowance.WeeklyRemaining)
+}
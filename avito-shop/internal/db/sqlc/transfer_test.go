@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomPendingTransfer(t *testing.T, sender, receiver User) Transaction {
+	arg := CreatePendingTransferParams{
+		SenderID:   pgtype.Int4{Int32: sender.ID, Valid: true},
+		ReceiverID: pgtype.Int4{Int32: receiver.ID, Valid: true},
+		Amount:     50,
+		SettlesAt:  pgtype.Timestamp{Time: time.Now().Add(time.Hour), Valid: true},
+	}
+
+	transfer, err := testQueries.CreatePendingTransfer(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotEmpty(t, transfer)
+
+	require.Equal(t, arg.SenderID, transfer.SenderID)
+	require.Equal(t, arg.ReceiverID, transfer.ReceiverID)
+	require.Equal(t, arg.Amount, transfer.Amount)
+	require.Equal(t, "pending", transfer.Status)
+	require.True(t, transfer.SettlesAt.Valid)
+	require.False(t, transfer.ResolvedAt.Valid)
+
+	return transfer
+}
+
+func TestCreatePendingTransfer(t *testing.T) {
+	createRandomPendingTransfer(t, createRandomUser(t), createRandomUser(t))
+}
+
+func TestGetPendingTransfers(t *testing.T) {
+	sender := createRandomUser(t)
+	receiver := createRandomUser(t)
+	transfer := createRandomPendingTransfer(t, sender, receiver)
+
+	// Отложенный перевод виден обеим сторонам, но не попадает в историю
+	for _, user := range []User{sender, receiver} {
+		pending, err := testQueries.GetPendingTransfers(context.Background(), pgtype.Int4{Int32: user.ID, Valid: true})
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+		require.Equal(t, transfer.ID, pending[0].ID)
+		require.Equal(t, sender.Username, pending[0].SenderUsername)
+		require.Equal(t, receiver.Username, pending[0].ReceiverUsername)
+
+		transactions, err := testQueries.GetTransactions(context.Background(), pgtype.Int4{Int32: user.ID, Valid: true})
+		require.NoError(t, err)
+		require.Empty(t, transactions)
+	}
+}
+
+func TestUpdateTransferStatus(t *testing.T) {
+	transfer := createRandomPendingTransfer(t, createRandomUser(t), createRandomUser(t))
+
+	err := testQueries.UpdateTransferStatus(context.Background(), UpdateTransferStatusParams{
+		ID:     transfer.ID,
+		Status: "cancelled",
+	})
+	require.NoError(t, err)
+
+	updated, err := testQueries.GetTransferForUpdate(context.Background(), transfer.ID)
+	require.NoError(t, err)
+	require.Equal(t, "cancelled", updated.Status)
+	require.True(t, updated.ResolvedAt.Valid)
+}
@@ -0,0 +1,171 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ReasonCodeWelcome - причина приветственного начисления новому пользователю
+const ReasonCodeWelcome = "welcome"
+
+// ErrUserNotFound возвращается, если пользователя с таким id нет
+var ErrUserNotFound = errors.New("user not found")
+
+type CreateUserTxParams struct {
+	Username     string `json:"username"`
+	PasswordHash string `json:"password_hash"`
+	// WelcomeGrant - сколько монет начислить при регистрации, 0 - без начисления
+	WelcomeGrant int32 `json:"welcome_grant"`
+	// CoinTTL - срок действия приветственных монет, 0 - бессрочно
+	CoinTTL time.Duration `json:"coin_ttl"`
+}
+
+type CreateUserTxResult struct {
+	User  User        `json:"user"`
+	Grant Transaction `json:"grant"`
+}
+
+// CreateUserTx создает пользователя с нулевым балансом и записывает приветственное
+// начисление от системного счета, чтобы стартовый баланс был виден в истории операций
+func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUserTxParams) (CreateUserTxResult, error) {
+	var result CreateUserTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Создаем пользователя
+		// Отдел назначает администратор, пользователь не может выбрать его сам
+		user, err := q.CreateUser(ctx, CreateUserParams{
+			Username:     arg.Username,
+			PasswordHash: arg.PasswordHash,
+		})
+		if err != nil {
+			return fmt.Errorf("error creating user: %w", err)
+		}
+		result.User = user
+
+		if arg.WelcomeGrant <= 0 {
+			return nil
+		}
+
+		// 2. Записываем приветственное начисление
+		result.Grant, err = q.CreateAdjustment(ctx, CreateAdjustmentParams{
+			ReceiverID: pgtype.Int4{Int32: user.ID, Valid: true},
+			Amount:     arg.WelcomeGrant,
+			Kind:       TransactionKindGrant,
+			ReasonCode: pgtype.Text{String: ReasonCodeWelcome, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("error creating welcome grant: %v", err)
+		}
+
+		// 3. Зачисляем монеты первым лотом пользователя
+		err = q.creditCoins(ctx, user.ID, result.Grant.ID, []lotSlice{
+			{Amount: arg.WelcomeGrant, ExpiresAt: CoinExpiresAt(time.Now(), arg.CoinTTL)},
+		})
+		if err != nil {
+			return err
+		}
+
+		result.User, err = q.GetUserByID(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return CreateUserTxResult{}, fmt.Errorf("create user tx error: %w", err)
+	}
+
+	return result, nil
+}
+
+type AssignDepartmentTxParams struct {
+	AdminID    int32  `json:"admin_id"`
+	UserID     int32  `json:"user_id"`
+	Department string `json:"department"`
+	// WelcomeTopUp - доплата к стандартному приветственному начислению для отдела;
+	// выдается только при первом назначении отдела
+	WelcomeTopUp int32 `json:"welcome_top_up"`
+	// CoinTTL - срок действия доплаченных монет, 0 - бессрочно
+	CoinTTL time.Duration `json:"coin_ttl"`
+}
+
+type AssignDepartmentTxResult struct {
+	User User `json:"user"`
+	// Grant - доплата приветственного начисления; не задана, если доплаты не было
+	Grant Transaction `json:"grant"`
+}
+
+// AssignDepartmentTx назначает пользователю отдел. При регистрации пользователь получает
+// стандартное приветственное начисление; если для отдела оно больше, разница доплачивается
+// при первом назначении. Смена уже назначенного отдела монет не начисляет
+func (store *SQLStore) AssignDepartmentTx(ctx context.Context, arg AssignDepartmentTxParams) (AssignDepartmentTxResult, error) {
+	var result AssignDepartmentTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		// 1. Блокируем пользователя, чтобы параллельные назначения не доплатили дважды
+		err := q.LockUsers(ctx, []int32{arg.UserID})
+		if err != nil {
+			return fmt.Errorf("error locking user: %v", err)
+		}
+
+		user, err := q.GetUserByID(ctx, arg.UserID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrUserNotFound
+			}
+			return fmt.Errorf("error getting user: %v", err)
+		}
+		firstAssignment := !user.Department.Valid
+
+		// 2. Записываем отдел
+		err = q.SetUserDepartment(ctx, SetUserDepartmentParams{
+			Department: pgtype.Text{String: arg.Department, Valid: true},
+			ID:         arg.UserID,
+		})
+		if err != nil {
+			return fmt.Errorf("error setting department: %v", err)
+		}
+
+		// 3. Доплачиваем приветственное начисление отдела
+		if firstAssignment && arg.WelcomeTopUp > 0 {
+			result.Grant, err = q.CreateAdjustment(ctx, CreateAdjustmentParams{
+				ReceiverID: pgtype.Int4{Int32: arg.UserID, Valid: true},
+				Amount:     arg.WelcomeTopUp,
+				Kind:       TransactionKindGrant,
+				ReasonCode: pgtype.Text{String: ReasonCodeWelcome, Valid: true},
+				Comment:    pgtype.Text{String: "department: " + arg.Department, Valid: true},
+				CreatedBy:  pgtype.Int4{Int32: arg.AdminID, Valid: true},
+			})
+			if err != nil {
+				return fmt.Errorf("error creating welcome grant: %v", err)
+			}
+
+			err = q.creditCoins(ctx, arg.UserID, result.Grant.ID, []lotSlice{
+				{Amount: arg.WelcomeTopUp, ExpiresAt: CoinExpiresAt(time.Now(), arg.CoinTTL)},
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		result.User, err = q.GetUserByID(ctx, arg.UserID)
+		if err != nil {
+			return fmt.Errorf("error getting user: %v", err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return AssignDepartmentTxResult{}, fmt.Errorf("assign department tx error: %w", err)
+	}
+
+	return result, nil
+}
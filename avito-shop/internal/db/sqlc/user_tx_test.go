@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssignDepartmentTx(t *testing.T) {
+	store := NewStore(testDB)
+	ctx := context.Background()
+
+	admin := createRandomUser(t)
+	user := createRandomUser(t)
+
+	// Первое назначение доплачивает приветственное начисление отдела
+	result, err := store.AssignDepartmentTx(ctx, AssignDepartmentTxParams{
+		AdminID:      admin.ID,
+		UserID:       user.ID,
+		Department:   "engineering",
+		WelcomeTopUp: 500,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "engineering", result.User.Department.String)
+	require.Equal(t, int32(500), result.Grant.Amount)
+	require.Equal(t, ReasonCodeWelcome, result.Grant.ReasonCode.String)
+	require.Equal(t, user.Balance.Int32+500, result.User.Balance.Int32)
+
+	// Смена отдела монет не начисляет
+	result, err = store.AssignDepartmentTx(ctx, AssignDepartmentTxParams{
+		AdminID:      admin.ID,
+		UserID:       user.ID,
+		Department:   "sales",
+		WelcomeTopUp: 500,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "sales", result.User.Department.String)
+	require.Zero(t, result.Grant.ID)
+	require.Equal(t, user.Balance.Int32+500, result.User.Balance.Int32)
+
+	_, err = store.AssignDepartmentTx(ctx, AssignDepartmentTxParams{
+		AdminID:    admin.ID,
+		UserID:     user.ID + 1000000,
+		Department: "sales",
+	})
+	require.ErrorIs(t, err, ErrUserNotFound)
+}
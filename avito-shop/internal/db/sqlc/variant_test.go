@@ -0,0 +1,116 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"avito-shop/internal/util"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func createRandomVariant(t *testing.T, item Item, price, stock pgtype.Int4) ItemVariant {
+	arg := CreateItemVariantParams{
+		ItemID: item.ID,
+		Sku:    util.RandomString(10),
+		Size:   pgtype.Text{String: "L", Valid: true},
+		Colour: pgtype.Text{String: "pink", Valid: true},
+		Price:  price,
+		Stock:  stock,
+	}
+
+	variant, err := testQueries.CreateItemVariant(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Sku, variant.Sku)
+	require.Equal(t, arg.Price, variant.Price)
+	require.Equal(t, arg.Stock, variant.Stock)
+
+	return variant
+}
+
+func TestGetItemByNameResolvesVariant(t *testing.T) {
+	item := createRandomItem(t)
+	variant := createRandomVariant(t, item, pgtype.Int4{Int32: item.Price + 100, Valid: true}, pgtype.Int4{Int32: 5, Valid: true})
+
+	row, err := testQueries.GetItemByName(context.Background(), GetItemByNameParams{
+		Name: item.Name,
+		Sku:  pgtype.Text{String: variant.Sku, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, row.HasVariants)
+	require.Equal(t, variant.ID, row.VariantID.Int32)
+	require.Equal(t, item.Price+100, row.UnitPrice())
+	require.Equal(t, int32(5), row.VariantStock.Int32)
+
+	// Без артикула товар находится, но вариант не выбран
+	row, err = testQueries.GetItemByName(context.Background(), GetItemByNameParams{Name: item.Name})
+	require.NoError(t, err)
+	require.True(t, row.HasVariants)
+	require.False(t, row.VariantID.Valid)
+	require.Equal(t, item.Price, row.UnitPrice())
+}
+
+func TestDecrementVariantStock(t *testing.T) {
+	item := createRandomItem(t)
+	variant := createRandomVariant(t, item, pgtype.Int4{}, pgtype.Int4{Int32: 1, Valid: true})
+
+	updated, err := testQueries.DecrementVariantStock(context.Background(), DecrementVariantStockParams{ID: variant.ID, Quantity: 1})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), updated)
+
+	// Остаток закончился
+	updated, err = testQueries.DecrementVariantStock(context.Background(), DecrementVariantStockParams{ID: variant.ID, Quantity: 1})
+	require.NoError(t, err)
+	require.Zero(t, updated)
+
+	err = testQueries.RestoreVariantStock(context.Background(), RestoreVariantStockParams{ID: variant.ID, Quantity: 1})
+	require.NoError(t, err)
+
+	restored, err := testQueries.GetItemVariantByID(context.Background(), variant.ID)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), restored.Stock.Int32)
+}
+
+func TestCartKeepsVariantsSeparate(t *testing.T) {
+	user := createRandomUser(t)
+	item := createRandomItem(t)
+	pink := createRandomVariant(t, item, pgtype.Int4{Int32: 700, Valid: true}, pgtype.Int4{})
+	grey := createRandomVariant(t, item, pgtype.Int4{}, pgtype.Int4{})
+
+	for _, variant := range []ItemVariant{pink, grey, pink} {
+		_, err := testQueries.AddCartItem(context.Background(), AddCartItemParams{
+			UserID:    user.ID,
+			ItemID:    item.ID,
+			VariantID: pgtype.Int4{Int32: variant.ID, Valid: true},
+			Quantity:  1,
+		})
+		require.NoError(t, err)
+	}
+
+	lines, err := testQueries.ListCartItems(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, lines, 2)
+	require.Equal(t, pink.Sku, lines[0].Sku.String)
+	require.Equal(t, int32(700), lines[0].Price)
+	require.Equal(t, int32(2), lines[0].Quantity)
+	require.Equal(t, grey.Sku, lines[1].Sku.String)
+	require.Equal(t, item.Price, lines[1].Price)
+}
+
+func TestVariantUnitPrice(t *testing.T) {
+	require.Equal(t, int32(300), ItemVariant{}.UnitPrice(300))
+	require.Equal(t, int32(500), ItemVariant{Price: pgtype.Int4{Int32: 500, Valid: true}}.UnitPrice(300))
+}
+
+func TestStockLockLess(t *testing.T) {
+	noVariant := pgtype.Int4{}
+	first := pgtype.Int4{Int32: 1, Valid: true}
+	second := pgtype.Int4{Int32: 2, Valid: true}
+
+	require.True(t, stockLockLess(1, second, 2, noVariant))
+	require.True(t, stockLockLess(1, noVariant, 1, first))
+	require.True(t, stockLockLess(1, first, 1, second))
+	require.False(t, stockLockLess(1, second, 1, first))
+	require.False(t, stockLockLess(1, first, 1, first))
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Ошибки выбора варианта товара
+var (
+	ErrVariantNotFound = errors.New("variant not found")
+	ErrVariantRequired = errors.New("item has variants, choose one")
+)
+
+// UnitPrice - цена варианта, если она переопределена, иначе цена товара
+func (variant ItemVariant) UnitPrice(itemPrice int32) int32 {
+	if variant.Price.Valid {
+		return variant.Price.Int32
+	}
+	return itemPrice
+}
+
+// UnitPrice - цена найденного варианта или товара
+func (row GetItemByNameRow) UnitPrice() int32 {
+	if row.VariantPrice.Valid {
+		return row.VariantPrice.Int32
+	}
+	return row.Price
+}
+
+// stockLockLess задает порядок блокировки остатков: по товару, затем по варианту,
+// остаток самого товара раньше вариантов. Транзакции, которые списывают несколько
+// остатков, блокируют их в этом порядке и не ждут друг друга по кругу
+func stockLockLess(itemA int32, variantA pgtype.Int4, itemB int32, variantB pgtype.Int4) bool {
+	if itemA != itemB {
+		return itemA < itemB
+	}
+	if variantA.Valid != variantB.Valid {
+		return !variantA.Valid
+	}
+	return variantA.Int32 < variantB.Int32
+}
+
+// decrementStock уменьшает остаток варианта, если он задан, иначе остаток товара.
+// tracked - отслеживается ли этот остаток; неотслеживаемый не меняется.
+// Единицы в резервах чужих корзин не списываются: buyerID расходует только собственный
+// резерв, buyerID = 0 - списание не для покупателя, недоступны все резервы
+func (q *Queries) decrementStock(ctx context.Context, itemID int32, variantID pgtype.Int4, tracked bool, quantity, buyerID int32, now time.Time) error {
+	if !tracked {
+		return nil
+	}
+
+	free, err := q.freeStock(ctx, itemID, variantID, buyerID, now)
+	if err != nil {
+		return err
+	}
+	if free.Valid && free.Int32 < quantity {
+		return ErrItemOutOfStock
+	}
+
+	var updated int64
+	if variantID.Valid {
+		updated, err = q.DecrementVariantStock(ctx, DecrementVariantStockParams{ID: variantID.Int32, Quantity: quantity})
+	} else {
+		updated, err = q.DecrementItemStock(ctx, DecrementItemStockParams{ID: itemID, Quantity: quantity})
+	}
+	if err != nil {
+		return fmt.Errorf("error updating stock: %v", err)
+	}
+	if updated == 0 {
+		return ErrItemOutOfStock
+	}
+	return nil
+}
+
+// restoreStock возвращает единицы на остаток варианта или товара
+func (q *Queries) restoreStock(ctx context.Context, itemID int32, variantID pgtype.Int4, quantity int32) error {
+	var err error
+	if variantID.Valid {
+		err = q.RestoreVariantStock(ctx, RestoreVariantStockParams{ID: variantID.Int32, Quantity: quantity})
+	} else {
+		err = q.RestoreItemStock(ctx, RestoreItemStockParams{ID: itemID, Quantity: quantity})
+	}
+	if err != nil {
+		return fmt.Errorf("error restoring stock: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,7 @@
+package db
+
+// Виды уведомлений; значения записываются запросами из wishlist.sql
+const (
+	NotificationKindPriceDrop  = "price_drop"
+	NotificationKindAffordable = "affordable"
+)
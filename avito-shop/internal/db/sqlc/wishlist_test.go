@@ -0,0 +1,93 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	util "avito-shop/internal/util"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWishlistNotifications(t *testing.T) {
+	user := createRandomUser(t)
+	// Дороже стартового баланса пользователя
+	item, err := testQueries.CreateItem(context.Background(), CreateItemParams{
+		Name:  util.RandomString(6),
+		Price: 1500,
+	})
+	require.NoError(t, err)
+	now := pgtype.Timestamp{Time: time.Now(), Valid: true}
+
+	added, err := testQueries.AddWishlistItem(context.Background(), AddWishlistItemParams{Now: now, UserID: user.ID, ItemID: item.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), added)
+
+	added, err = testQueries.AddWishlistItem(context.Background(), AddWishlistItemParams{Now: now, UserID: user.ID, ItemID: item.ID})
+	require.NoError(t, err)
+	require.Zero(t, added)
+
+	listUnread := func() []Notification {
+		notifications, err := testQueries.ListNotifications(context.Background(), ListNotificationsParams{
+			UserID:     user.ID,
+			UnreadOnly: true,
+			RowLimit:   10,
+		})
+		require.NoError(t, err)
+		return notifications
+	}
+
+	_, err = testQueries.CreateAffordableNotifications(context.Background(), now)
+	require.NoError(t, err)
+	require.Empty(t, listUnread())
+
+	// Просроченный лот, который воркер сгорания еще не списал, цену не покрывает
+	expired := createRandomCoinLot(t, user.ID, 600, pgtype.Timestamp{Time: now.Time.Add(-time.Hour), Valid: true})
+	err = testQueries.UpdateBalance(context.Background(), UpdateBalanceParams{ID: user.ID, Amount: expired.Amount})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateAffordableNotifications(context.Background(), now)
+	require.NoError(t, err)
+	require.Empty(t, listUnread())
+
+	// Баланс стал покрывать цену - одно уведомление, повторный проход ничего не добавляет
+	createRandomCoinLot(t, user.ID, 600, pgtype.Timestamp{})
+	err = testQueries.UpdateBalance(context.Background(), UpdateBalanceParams{ID: user.ID, Amount: 600})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateAffordableNotifications(context.Background(), now)
+	require.NoError(t, err)
+	_, err = testQueries.CreateAffordableNotifications(context.Background(), now)
+	require.NoError(t, err)
+
+	notifications := listUnread()
+	require.Len(t, notifications, 1)
+	require.Equal(t, NotificationKindAffordable, notifications[0].Kind)
+	require.Equal(t, item.ID, notifications[0].ItemID.Int32)
+
+	_, err = testQueries.CreatePriceDropNotifications(context.Background(), CreatePriceDropNotificationsParams{
+		OldPrice: 1500,
+		Price:    1200,
+		ItemID:   item.ID,
+	})
+	require.NoError(t, err)
+
+	notifications = listUnread()
+	require.Len(t, notifications, 2)
+	require.Equal(t, NotificationKindPriceDrop, notifications[0].Kind)
+	require.Contains(t, notifications[0].Message, "from 1500 to 1200")
+
+	marked, err := testQueries.MarkAllNotificationsRead(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), marked)
+
+	unread, err := testQueries.CountUnreadNotifications(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Zero(t, unread)
+
+	removed, err := testQueries.RemoveWishlistItem(context.Background(), RemoveWishlistItemParams{UserID: user.ID, ItemID: item.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), removed)
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const roleAdmin = "admin"
+
+// AdminMiddleware пропускает только администраторов.
+// Должен стоять после AuthMiddleware, который кладет username в контекст.
+func AdminMiddleware(store db.Store) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		role, err := store.GetUserRole(c, c.GetString("username"))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+
+		if role != roleAdmin {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "admin access required"})
+			return
+		}
+
+		c.Next()
+	}
+}
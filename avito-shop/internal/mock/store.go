@@ -36,184 +36,2667 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddCartItem mocks base method.
+func (m *MockStore) AddCartItem(arg0 context.Context, arg1 db.AddCartItemParams) (db.CartItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCartItem", arg0, arg1)
+	ret0, _ := ret[0].(db.CartItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCartItem indicates an expected call of AddCartItem.
+func (mr *MockStoreMockRecorder) AddCartItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCartItem", reflect.TypeOf((*MockStore)(nil).AddCartItem), arg0, arg1)
+}
+
+// AddCartItemTx mocks base method.
+func (m *MockStore) AddCartItemTx(arg0 context.Context, arg1 db.CartItemTxParams) (db.CartItemTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCartItemTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CartItemTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCartItemTx indicates an expected call of AddCartItemTx.
+func (mr *MockStoreMockRecorder) AddCartItemTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCartItemTx", reflect.TypeOf((*MockStore)(nil).AddCartItemTx), arg0, arg1)
+}
+
+// AddItemAttributes mocks base method.
+func (m *MockStore) AddItemAttributes(arg0 context.Context, arg1 db.AddItemAttributesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItemAttributes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddItemAttributes indicates an expected call of AddItemAttributes.
+func (mr *MockStoreMockRecorder) AddItemAttributes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItemAttributes", reflect.TypeOf((*MockStore)(nil).AddItemAttributes), arg0, arg1)
+}
+
+// AddItemTags mocks base method.
+func (m *MockStore) AddItemTags(arg0 context.Context, arg1 db.AddItemTagsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddItemTags", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddItemTags indicates an expected call of AddItemTags.
+func (mr *MockStoreMockRecorder) AddItemTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddItemTags", reflect.TypeOf((*MockStore)(nil).AddItemTags), arg0, arg1)
+}
+
+// AddWishlistItem mocks base method.
+func (m *MockStore) AddWishlistItem(arg0 context.Context, arg1 db.AddWishlistItemParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddWishlistItem", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddWishlistItem indicates an expected call of AddWishlistItem.
+func (mr *MockStoreMockRecorder) AddWishlistItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddWishlistItem", reflect.TypeOf((*MockStore)(nil).AddWishlistItem), arg0, arg1)
+}
+
+// AdjustBalanceTx mocks base method.
+func (m *MockStore) AdjustBalanceTx(arg0 context.Context, arg1 db.AdjustBalanceTxParams) (db.AdjustBalanceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AdjustBalanceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AdjustBalanceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AdjustBalanceTx indicates an expected call of AdjustBalanceTx.
+func (mr *MockStoreMockRecorder) AdjustBalanceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AdjustBalanceTx", reflect.TypeOf((*MockStore)(nil).AdjustBalanceTx), arg0, arg1)
+}
+
+// ApplyDueItemPrices mocks base method.
+func (m *MockStore) ApplyDueItemPrices(arg0 context.Context, arg1 pgtype.Timestamp) ([]db.ApplyDueItemPricesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyDueItemPrices", arg0, arg1)
+	ret0, _ := ret[0].([]db.ApplyDueItemPricesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyDueItemPrices indicates an expected call of ApplyDueItemPrices.
+func (mr *MockStoreMockRecorder) ApplyDueItemPrices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyDueItemPrices", reflect.TypeOf((*MockStore)(nil).ApplyDueItemPrices), arg0, arg1)
+}
+
+// AssignDepartmentTx mocks base method.
+func (m *MockStore) AssignDepartmentTx(arg0 context.Context, arg1 db.AssignDepartmentTxParams) (db.AssignDepartmentTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignDepartmentTx", arg0, arg1)
+	ret0, _ := ret[0].(db.AssignDepartmentTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssignDepartmentTx indicates an expected call of AssignDepartmentTx.
+func (mr *MockStoreMockRecorder) AssignDepartmentTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignDepartmentTx", reflect.TypeOf((*MockStore)(nil).AssignDepartmentTx), arg0, arg1)
+}
+
+// BuyBundleTx mocks base method.
+func (m *MockStore) BuyBundleTx(arg0 context.Context, arg1 db.BuyBundleTxParams) (db.BuyBundleTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyBundleTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BuyBundleTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuyBundleTx indicates an expected call of BuyBundleTx.
+func (mr *MockStoreMockRecorder) BuyBundleTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyBundleTx", reflect.TypeOf((*MockStore)(nil).BuyBundleTx), arg0, arg1)
+}
+
+// BuyRaffleTicketsTx mocks base method.
+func (m *MockStore) BuyRaffleTicketsTx(arg0 context.Context, arg1 db.BuyRaffleTicketsTxParams) (db.BuyRaffleTicketsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuyRaffleTicketsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.BuyRaffleTicketsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuyRaffleTicketsTx indicates an expected call of BuyRaffleTicketsTx.
+func (mr *MockStoreMockRecorder) BuyRaffleTicketsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuyRaffleTicketsTx", reflect.TypeOf((*MockStore)(nil).BuyRaffleTicketsTx), arg0, arg1)
+}
+
+// CancelAuctionTx mocks base method.
+func (m *MockStore) CancelAuctionTx(arg0 context.Context, arg1 db.CancelAuctionTxParams) (db.CancelAuctionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelAuctionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CancelAuctionTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelAuctionTx indicates an expected call of CancelAuctionTx.
+func (mr *MockStoreMockRecorder) CancelAuctionTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelAuctionTx", reflect.TypeOf((*MockStore)(nil).CancelAuctionTx), arg0, arg1)
+}
+
+// CancelListing mocks base method.
+func (m *MockStore) CancelListing(arg0 context.Context, arg1 db.CancelListingParams) (db.MarketplaceListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelListing", arg0, arg1)
+	ret0, _ := ret[0].(db.MarketplaceListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelListing indicates an expected call of CancelListing.
+func (mr *MockStoreMockRecorder) CancelListing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelListing", reflect.TypeOf((*MockStore)(nil).CancelListing), arg0, arg1)
+}
+
+// CancelTransferTx mocks base method.
+func (m *MockStore) CancelTransferTx(arg0 context.Context, arg1 db.CancelTransferTxParams) (db.CancelTransferTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CancelTransferTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelTransferTx indicates an expected call of CancelTransferTx.
+func (mr *MockStoreMockRecorder) CancelTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelTransferTx", reflect.TypeOf((*MockStore)(nil).CancelTransferTx), arg0, arg1)
+}
+
+// ChangeItemPriceTx mocks base method.
+func (m *MockStore) ChangeItemPriceTx(arg0 context.Context, arg1 db.ChangeItemPriceTxParams) (db.ChangeItemPriceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeItemPriceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ChangeItemPriceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeItemPriceTx indicates an expected call of ChangeItemPriceTx.
+func (mr *MockStoreMockRecorder) ChangeItemPriceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeItemPriceTx", reflect.TypeOf((*MockStore)(nil).ChangeItemPriceTx), arg0, arg1)
+}
+
+// CheckoutTx mocks base method.
+func (m *MockStore) CheckoutTx(arg0 context.Context, arg1 db.CheckoutTxParams) (db.CheckoutTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CheckoutTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CheckoutTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CheckoutTx indicates an expected call of CheckoutTx.
+func (mr *MockStoreMockRecorder) CheckoutTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckoutTx", reflect.TypeOf((*MockStore)(nil).CheckoutTx), arg0, arg1)
+}
+
+// CloseAuction mocks base method.
+func (m *MockStore) CloseAuction(arg0 context.Context, arg1 db.CloseAuctionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseAuction", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CloseAuction indicates an expected call of CloseAuction.
+func (mr *MockStoreMockRecorder) CloseAuction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseAuction", reflect.TypeOf((*MockStore)(nil).CloseAuction), arg0, arg1)
+}
+
+// ConsumeCoinLot mocks base method.
+func (m *MockStore) ConsumeCoinLot(arg0 context.Context, arg1 db.ConsumeCoinLotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeCoinLot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeCoinLot indicates an expected call of ConsumeCoinLot.
+func (mr *MockStoreMockRecorder) ConsumeCoinLot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeCoinLot", reflect.TypeOf((*MockStore)(nil).ConsumeCoinLot), arg0, arg1)
+}
+
+// CountDropQueue mocks base method.
+func (m *MockStore) CountDropQueue(arg0 context.Context, arg1 int32) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountDropQueue", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountDropQueue indicates an expected call of CountDropQueue.
+func (mr *MockStoreMockRecorder) CountDropQueue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountDropQueue", reflect.TypeOf((*MockStore)(nil).CountDropQueue), arg0, arg1)
+}
+
+// CountSearchItemsByCategory mocks base method.
+func (m *MockStore) CountSearchItemsByCategory(arg0 context.Context, arg1 db.CountSearchItemsByCategoryParams) ([]db.CountSearchItemsByCategoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchItemsByCategory", arg0, arg1)
+	ret0, _ := ret[0].([]db.CountSearchItemsByCategoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchItemsByCategory indicates an expected call of CountSearchItemsByCategory.
+func (mr *MockStoreMockRecorder) CountSearchItemsByCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchItemsByCategory", reflect.TypeOf((*MockStore)(nil).CountSearchItemsByCategory), arg0, arg1)
+}
+
+// CountSearchItemsByTag mocks base method.
+func (m *MockStore) CountSearchItemsByTag(arg0 context.Context, arg1 db.CountSearchItemsByTagParams) ([]db.CountSearchItemsByTagRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSearchItemsByTag", arg0, arg1)
+	ret0, _ := ret[0].([]db.CountSearchItemsByTagRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSearchItemsByTag indicates an expected call of CountSearchItemsByTag.
+func (mr *MockStoreMockRecorder) CountSearchItemsByTag(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSearchItemsByTag", reflect.TypeOf((*MockStore)(nil).CountSearchItemsByTag), arg0, arg1)
+}
+
+// CountUnreadNotifications mocks base method.
+func (m *MockStore) CountUnreadNotifications(arg0 context.Context, arg1 int32) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUnreadNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUnreadNotifications indicates an expected call of CountUnreadNotifications.
+func (mr *MockStoreMockRecorder) CountUnreadNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUnreadNotifications", reflect.TypeOf((*MockStore)(nil).CountUnreadNotifications), arg0, arg1)
+}
+
+// CountUserPromoCodeUses mocks base method.
+func (m *MockStore) CountUserPromoCodeUses(arg0 context.Context, arg1 db.CountUserPromoCodeUsesParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserPromoCodeUses", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserPromoCodeUses indicates an expected call of CountUserPromoCodeUses.
+func (mr *MockStoreMockRecorder) CountUserPromoCodeUses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserPromoCodeUses", reflect.TypeOf((*MockStore)(nil).CountUserPromoCodeUses), arg0, arg1)
+}
+
+// CountUserRaffleTickets mocks base method.
+func (m *MockStore) CountUserRaffleTickets(arg0 context.Context, arg1 db.CountUserRaffleTicketsParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountUserRaffleTickets", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountUserRaffleTickets indicates an expected call of CountUserRaffleTickets.
+func (mr *MockStoreMockRecorder) CountUserRaffleTickets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountUserRaffleTickets", reflect.TypeOf((*MockStore)(nil).CountUserRaffleTickets), arg0, arg1)
+}
+
+// CreateAdjustment mocks base method.
+func (m *MockStore) CreateAdjustment(arg0 context.Context, arg1 db.CreateAdjustmentParams) (db.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAdjustment", arg0, arg1)
+	ret0, _ := ret[0].(db.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAdjustment indicates an expected call of CreateAdjustment.
+func (mr *MockStoreMockRecorder) CreateAdjustment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAdjustment", reflect.TypeOf((*MockStore)(nil).CreateAdjustment), arg0, arg1)
+}
+
+// CreateAffordableNotifications mocks base method.
+func (m *MockStore) CreateAffordableNotifications(arg0 context.Context, arg1 pgtype.Timestamp) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAffordableNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAffordableNotifications indicates an expected call of CreateAffordableNotifications.
+func (mr *MockStoreMockRecorder) CreateAffordableNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAffordableNotifications", reflect.TypeOf((*MockStore)(nil).CreateAffordableNotifications), arg0, arg1)
+}
+
+// CreateAuction mocks base method.
+func (m *MockStore) CreateAuction(arg0 context.Context, arg1 db.CreateAuctionParams) (db.Auction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuction", arg0, arg1)
+	ret0, _ := ret[0].(db.Auction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuction indicates an expected call of CreateAuction.
+func (mr *MockStoreMockRecorder) CreateAuction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuction", reflect.TypeOf((*MockStore)(nil).CreateAuction), arg0, arg1)
+}
+
+// CreateAuctionBid mocks base method.
+func (m *MockStore) CreateAuctionBid(arg0 context.Context, arg1 db.CreateAuctionBidParams) (db.AuctionBid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuctionBid", arg0, arg1)
+	ret0, _ := ret[0].(db.AuctionBid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAuctionBid indicates an expected call of CreateAuctionBid.
+func (mr *MockStoreMockRecorder) CreateAuctionBid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuctionBid", reflect.TypeOf((*MockStore)(nil).CreateAuctionBid), arg0, arg1)
+}
+
+// CreateAuctionBidLot mocks base method.
+func (m *MockStore) CreateAuctionBidLot(arg0 context.Context, arg1 db.CreateAuctionBidLotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuctionBidLot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuctionBidLot indicates an expected call of CreateAuctionBidLot.
+func (mr *MockStoreMockRecorder) CreateAuctionBidLot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuctionBidLot", reflect.TypeOf((*MockStore)(nil).CreateAuctionBidLot), arg0, arg1)
+}
+
+// CreateBundle mocks base method.
+func (m *MockStore) CreateBundle(arg0 context.Context, arg1 db.CreateBundleParams) (db.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBundle", arg0, arg1)
+	ret0, _ := ret[0].(db.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBundle indicates an expected call of CreateBundle.
+func (mr *MockStoreMockRecorder) CreateBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBundle", reflect.TypeOf((*MockStore)(nil).CreateBundle), arg0, arg1)
+}
+
+// CreateBundleItem mocks base method.
+func (m *MockStore) CreateBundleItem(arg0 context.Context, arg1 db.CreateBundleItemParams) (db.BundleItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBundleItem", arg0, arg1)
+	ret0, _ := ret[0].(db.BundleItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBundleItem indicates an expected call of CreateBundleItem.
+func (mr *MockStoreMockRecorder) CreateBundleItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBundleItem", reflect.TypeOf((*MockStore)(nil).CreateBundleItem), arg0, arg1)
+}
+
+// CreateBundleTx mocks base method.
+func (m *MockStore) CreateBundleTx(arg0 context.Context, arg1 db.CreateBundleTxParams) (db.CreateBundleTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBundleTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateBundleTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBundleTx indicates an expected call of CreateBundleTx.
+func (mr *MockStoreMockRecorder) CreateBundleTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBundleTx", reflect.TypeOf((*MockStore)(nil).CreateBundleTx), arg0, arg1)
+}
+
+// CreateCategory mocks base method.
+func (m *MockStore) CreateCategory(arg0 context.Context, arg1 string) (db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCategory", arg0, arg1)
+	ret0, _ := ret[0].(db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCategory indicates an expected call of CreateCategory.
+func (mr *MockStoreMockRecorder) CreateCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCategory", reflect.TypeOf((*MockStore)(nil).CreateCategory), arg0, arg1)
+}
+
+// CreateCoinLot mocks base method.
+func (m *MockStore) CreateCoinLot(arg0 context.Context, arg1 db.CreateCoinLotParams) (db.CoinLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCoinLot", arg0, arg1)
+	ret0, _ := ret[0].(db.CoinLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCoinLot indicates an expected call of CreateCoinLot.
+func (mr *MockStoreMockRecorder) CreateCoinLot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCoinLot", reflect.TypeOf((*MockStore)(nil).CreateCoinLot), arg0, arg1)
+}
+
+// CreateInventoryTransfers mocks base method.
+func (m *MockStore) CreateInventoryTransfers(arg0 context.Context, arg1 db.CreateInventoryTransfersParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInventoryTransfers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInventoryTransfers indicates an expected call of CreateInventoryTransfers.
+func (mr *MockStoreMockRecorder) CreateInventoryTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInventoryTransfers", reflect.TypeOf((*MockStore)(nil).CreateInventoryTransfers), arg0, arg1)
+}
+
 // CreateItem mocks base method.
 func (m *MockStore) CreateItem(arg0 context.Context, arg1 db.CreateItemParams) (db.Item, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateItem", arg0, arg1)
-	ret0, _ := ret[0].(db.Item)
+	ret := m.ctrl.Call(m, "CreateItem", arg0, arg1)
+	ret0, _ := ret[0].(db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItem indicates an expected call of CreateItem.
+func (mr *MockStoreMockRecorder) CreateItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockStore)(nil).CreateItem), arg0, arg1)
+}
+
+// CreateItemImage mocks base method.
+func (m *MockStore) CreateItemImage(arg0 context.Context, arg1 db.CreateItemImageParams) (db.ItemImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItemImage", arg0, arg1)
+	ret0, _ := ret[0].(db.ItemImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItemImage indicates an expected call of CreateItemImage.
+func (mr *MockStoreMockRecorder) CreateItemImage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItemImage", reflect.TypeOf((*MockStore)(nil).CreateItemImage), arg0, arg1)
+}
+
+// CreateItemPrice mocks base method.
+func (m *MockStore) CreateItemPrice(arg0 context.Context, arg1 db.CreateItemPriceParams) (db.ItemPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItemPrice", arg0, arg1)
+	ret0, _ := ret[0].(db.ItemPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItemPrice indicates an expected call of CreateItemPrice.
+func (mr *MockStoreMockRecorder) CreateItemPrice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItemPrice", reflect.TypeOf((*MockStore)(nil).CreateItemPrice), arg0, arg1)
+}
+
+// CreateItemVariant mocks base method.
+func (m *MockStore) CreateItemVariant(arg0 context.Context, arg1 db.CreateItemVariantParams) (db.ItemVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateItemVariant", arg0, arg1)
+	ret0, _ := ret[0].(db.ItemVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateItemVariant indicates an expected call of CreateItemVariant.
+func (mr *MockStoreMockRecorder) CreateItemVariant(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItemVariant", reflect.TypeOf((*MockStore)(nil).CreateItemVariant), arg0, arg1)
+}
+
+// CreateListing mocks base method.
+func (m *MockStore) CreateListing(arg0 context.Context, arg1 db.CreateListingParams) (db.MarketplaceListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateListing", arg0, arg1)
+	ret0, _ := ret[0].(db.MarketplaceListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateListing indicates an expected call of CreateListing.
+func (mr *MockStoreMockRecorder) CreateListing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListing", reflect.TypeOf((*MockStore)(nil).CreateListing), arg0, arg1)
+}
+
+// CreateListingTx mocks base method.
+func (m *MockStore) CreateListingTx(arg0 context.Context, arg1 db.CreateListingTxParams) (db.CreateListingTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateListingTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateListingTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateListingTx indicates an expected call of CreateListingTx.
+func (mr *MockStoreMockRecorder) CreateListingTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateListingTx", reflect.TypeOf((*MockStore)(nil).CreateListingTx), arg0, arg1)
+}
+
+// CreateNotification mocks base method.
+func (m *MockStore) CreateNotification(arg0 context.Context, arg1 db.CreateNotificationParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockStoreMockRecorder) CreateNotification(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockStore)(nil).CreateNotification), arg0, arg1)
+}
+
+// CreateOrder mocks base method.
+func (m *MockStore) CreateOrder(arg0 context.Context, arg1 db.CreateOrderParams) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockStoreMockRecorder) CreateOrder(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockStore)(nil).CreateOrder), arg0, arg1)
+}
+
+// CreateOrderStatusChange mocks base method.
+func (m *MockStore) CreateOrderStatusChange(arg0 context.Context, arg1 db.CreateOrderStatusChangeParams) (db.OrderStatusHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrderStatusChange", arg0, arg1)
+	ret0, _ := ret[0].(db.OrderStatusHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateOrderStatusChange indicates an expected call of CreateOrderStatusChange.
+func (mr *MockStoreMockRecorder) CreateOrderStatusChange(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderStatusChange", reflect.TypeOf((*MockStore)(nil).CreateOrderStatusChange), arg0, arg1)
+}
+
+// CreatePendingTransfer mocks base method.
+func (m *MockStore) CreatePendingTransfer(arg0 context.Context, arg1 db.CreatePendingTransferParams) (db.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePendingTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePendingTransfer indicates an expected call of CreatePendingTransfer.
+func (mr *MockStoreMockRecorder) CreatePendingTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePendingTransfer", reflect.TypeOf((*MockStore)(nil).CreatePendingTransfer), arg0, arg1)
+}
+
+// CreatePriceDropNotifications mocks base method.
+func (m *MockStore) CreatePriceDropNotifications(arg0 context.Context, arg1 db.CreatePriceDropNotificationsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePriceDropNotifications", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePriceDropNotifications indicates an expected call of CreatePriceDropNotifications.
+func (mr *MockStoreMockRecorder) CreatePriceDropNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePriceDropNotifications", reflect.TypeOf((*MockStore)(nil).CreatePriceDropNotifications), arg0, arg1)
+}
+
+// CreatePromoCode mocks base method.
+func (m *MockStore) CreatePromoCode(arg0 context.Context, arg1 db.CreatePromoCodeParams) (db.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePromoCode", arg0, arg1)
+	ret0, _ := ret[0].(db.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePromoCode indicates an expected call of CreatePromoCode.
+func (mr *MockStoreMockRecorder) CreatePromoCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePromoCode", reflect.TypeOf((*MockStore)(nil).CreatePromoCode), arg0, arg1)
+}
+
+// CreatePurchase mocks base method.
+func (m *MockStore) CreatePurchase(arg0 context.Context, arg1 db.CreatePurchaseParams) (db.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePurchase", arg0, arg1)
+	ret0, _ := ret[0].(db.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePurchase indicates an expected call of CreatePurchase.
+func (mr *MockStoreMockRecorder) CreatePurchase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePurchase", reflect.TypeOf((*MockStore)(nil).CreatePurchase), arg0, arg1)
+}
+
+// CreatePurchaseUnits mocks base method.
+func (m *MockStore) CreatePurchaseUnits(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePurchaseUnits", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePurchaseUnits indicates an expected call of CreatePurchaseUnits.
+func (mr *MockStoreMockRecorder) CreatePurchaseUnits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePurchaseUnits", reflect.TypeOf((*MockStore)(nil).CreatePurchaseUnits), arg0, arg1)
+}
+
+// CreateRaffle mocks base method.
+func (m *MockStore) CreateRaffle(arg0 context.Context, arg1 db.CreateRaffleParams) (db.Raffle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRaffle", arg0, arg1)
+	ret0, _ := ret[0].(db.Raffle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRaffle indicates an expected call of CreateRaffle.
+func (mr *MockStoreMockRecorder) CreateRaffle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRaffle", reflect.TypeOf((*MockStore)(nil).CreateRaffle), arg0, arg1)
+}
+
+// CreateRaffleTicket mocks base method.
+func (m *MockStore) CreateRaffleTicket(arg0 context.Context, arg1 db.CreateRaffleTicketParams) (db.RaffleTicket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRaffleTicket", arg0, arg1)
+	ret0, _ := ret[0].(db.RaffleTicket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRaffleTicket indicates an expected call of CreateRaffleTicket.
+func (mr *MockStoreMockRecorder) CreateRaffleTicket(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRaffleTicket", reflect.TypeOf((*MockStore)(nil).CreateRaffleTicket), arg0, arg1)
+}
+
+// CreateRaffleTx mocks base method.
+func (m *MockStore) CreateRaffleTx(arg0 context.Context, arg1 db.CreateRaffleTxParams) (db.CreateRaffleTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRaffleTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateRaffleTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRaffleTx indicates an expected call of CreateRaffleTx.
+func (mr *MockStoreMockRecorder) CreateRaffleTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRaffleTx", reflect.TypeOf((*MockStore)(nil).CreateRaffleTx), arg0, arg1)
+}
+
+// CreateRefund mocks base method.
+func (m *MockStore) CreateRefund(arg0 context.Context, arg1 db.CreateRefundParams) (db.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRefund", arg0, arg1)
+	ret0, _ := ret[0].(db.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRefund indicates an expected call of CreateRefund.
+func (mr *MockStoreMockRecorder) CreateRefund(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRefund", reflect.TypeOf((*MockStore)(nil).CreateRefund), arg0, arg1)
+}
+
+// CreateTransactionLot mocks base method.
+func (m *MockStore) CreateTransactionLot(arg0 context.Context, arg1 db.CreateTransactionLotParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransactionLot", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTransactionLot indicates an expected call of CreateTransactionLot.
+func (mr *MockStoreMockRecorder) CreateTransactionLot(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransactionLot", reflect.TypeOf((*MockStore)(nil).CreateTransactionLot), arg0, arg1)
+}
+
+// CreateTransfer mocks base method.
+func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
+	ret0, _ := ret[0].(db.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTransfer indicates an expected call of CreateTransfer.
+func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserTx mocks base method.
+func (m *MockStore) CreateUserTx(arg0 context.Context, arg1 db.CreateUserTxParams) (db.CreateUserTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateUserTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserTx indicates an expected call of CreateUserTx.
+func (mr *MockStoreMockRecorder) CreateUserTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserTx", reflect.TypeOf((*MockStore)(nil).CreateUserTx), arg0, arg1)
+}
+
+// DecrementItemStock mocks base method.
+func (m *MockStore) DecrementItemStock(arg0 context.Context, arg1 db.DecrementItemStockParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementItemStock", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementItemStock indicates an expected call of DecrementItemStock.
+func (mr *MockStoreMockRecorder) DecrementItemStock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementItemStock", reflect.TypeOf((*MockStore)(nil).DecrementItemStock), arg0, arg1)
+}
+
+// DecrementVariantStock mocks base method.
+func (m *MockStore) DecrementVariantStock(arg0 context.Context, arg1 db.DecrementVariantStockParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementVariantStock", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DecrementVariantStock indicates an expected call of DecrementVariantStock.
+func (mr *MockStoreMockRecorder) DecrementVariantStock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementVariantStock", reflect.TypeOf((*MockStore)(nil).DecrementVariantStock), arg0, arg1)
+}
+
+// DeleteCartItem mocks base method.
+func (m *MockStore) DeleteCartItem(arg0 context.Context, arg1 db.DeleteCartItemParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartItem", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCartItem indicates an expected call of DeleteCartItem.
+func (mr *MockStoreMockRecorder) DeleteCartItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartItem", reflect.TypeOf((*MockStore)(nil).DeleteCartItem), arg0, arg1)
+}
+
+// DeleteCartItems mocks base method.
+func (m *MockStore) DeleteCartItems(arg0 context.Context, arg1 db.DeleteCartItemsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCartItems", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCartItems indicates an expected call of DeleteCartItems.
+func (mr *MockStoreMockRecorder) DeleteCartItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCartItems", reflect.TypeOf((*MockStore)(nil).DeleteCartItems), arg0, arg1)
+}
+
+// DeleteItemAttributes mocks base method.
+func (m *MockStore) DeleteItemAttributes(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItemAttributes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItemAttributes indicates an expected call of DeleteItemAttributes.
+func (mr *MockStoreMockRecorder) DeleteItemAttributes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItemAttributes", reflect.TypeOf((*MockStore)(nil).DeleteItemAttributes), arg0, arg1)
+}
+
+// DeleteItemImage mocks base method.
+func (m *MockStore) DeleteItemImage(arg0 context.Context, arg1 db.DeleteItemImageParams) (db.ItemImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItemImage", arg0, arg1)
+	ret0, _ := ret[0].(db.ItemImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteItemImage indicates an expected call of DeleteItemImage.
+func (mr *MockStoreMockRecorder) DeleteItemImage(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItemImage", reflect.TypeOf((*MockStore)(nil).DeleteItemImage), arg0, arg1)
+}
+
+// DeleteItemTags mocks base method.
+func (m *MockStore) DeleteItemTags(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteItemTags", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteItemTags indicates an expected call of DeleteItemTags.
+func (mr *MockStoreMockRecorder) DeleteItemTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteItemTags", reflect.TypeOf((*MockStore)(nil).DeleteItemTags), arg0, arg1)
+}
+
+// DeletePromoCode mocks base method.
+func (m *MockStore) DeletePromoCode(arg0 context.Context, arg1 int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePromoCode", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeletePromoCode indicates an expected call of DeletePromoCode.
+func (mr *MockStoreMockRecorder) DeletePromoCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePromoCode", reflect.TypeOf((*MockStore)(nil).DeletePromoCode), arg0, arg1)
+}
+
+// DeleteScheduledItemPrice mocks base method.
+func (m *MockStore) DeleteScheduledItemPrice(arg0 context.Context, arg1 db.DeleteScheduledItemPriceParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteScheduledItemPrice", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteScheduledItemPrice indicates an expected call of DeleteScheduledItemPrice.
+func (mr *MockStoreMockRecorder) DeleteScheduledItemPrice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScheduledItemPrice", reflect.TypeOf((*MockStore)(nil).DeleteScheduledItemPrice), arg0, arg1)
+}
+
+// DrawRaffle mocks base method.
+func (m *MockStore) DrawRaffle(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrawRaffle", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DrawRaffle indicates an expected call of DrawRaffle.
+func (mr *MockStoreMockRecorder) DrawRaffle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrawRaffle", reflect.TypeOf((*MockStore)(nil).DrawRaffle), arg0, arg1)
+}
+
+// DrawRaffleTx mocks base method.
+func (m *MockStore) DrawRaffleTx(arg0 context.Context, arg1 db.DrawRaffleTxParams) (db.DrawRaffleTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DrawRaffleTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DrawRaffleTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DrawRaffleTx indicates an expected call of DrawRaffleTx.
+func (mr *MockStoreMockRecorder) DrawRaffleTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DrawRaffleTx", reflect.TypeOf((*MockStore)(nil).DrawRaffleTx), arg0, arg1)
+}
+
+// ExpireCoinsTx mocks base method.
+func (m *MockStore) ExpireCoinsTx(arg0 context.Context, arg1 db.ExpireCoinsTxParams) (db.ExpireCoinsTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireCoinsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ExpireCoinsTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireCoinsTx indicates an expected call of ExpireCoinsTx.
+func (mr *MockStoreMockRecorder) ExpireCoinsTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireCoinsTx", reflect.TypeOf((*MockStore)(nil).ExpireCoinsTx), arg0, arg1)
+}
+
+// GetAuctionBidLots mocks base method.
+func (m *MockStore) GetAuctionBidLots(arg0 context.Context, arg1 int32) ([]db.AuctionBidLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuctionBidLots", arg0, arg1)
+	ret0, _ := ret[0].([]db.AuctionBidLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuctionBidLots indicates an expected call of GetAuctionBidLots.
+func (mr *MockStoreMockRecorder) GetAuctionBidLots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuctionBidLots", reflect.TypeOf((*MockStore)(nil).GetAuctionBidLots), arg0, arg1)
+}
+
+// GetAuctionForUpdate mocks base method.
+func (m *MockStore) GetAuctionForUpdate(arg0 context.Context, arg1 int32) (db.Auction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuctionForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Auction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuctionForUpdate indicates an expected call of GetAuctionForUpdate.
+func (mr *MockStoreMockRecorder) GetAuctionForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuctionForUpdate", reflect.TypeOf((*MockStore)(nil).GetAuctionForUpdate), arg0, arg1)
+}
+
+// GetAuctionSummary mocks base method.
+func (m *MockStore) GetAuctionSummary(arg0 context.Context, arg1 int32) (db.GetAuctionSummaryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuctionSummary", arg0, arg1)
+	ret0, _ := ret[0].(db.GetAuctionSummaryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuctionSummary indicates an expected call of GetAuctionSummary.
+func (mr *MockStoreMockRecorder) GetAuctionSummary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuctionSummary", reflect.TypeOf((*MockStore)(nil).GetAuctionSummary), arg0, arg1)
+}
+
+// GetBalanceReconciliation mocks base method.
+func (m *MockStore) GetBalanceReconciliation(arg0 context.Context, arg1 pgtype.Int4) ([]db.GetBalanceReconciliationRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBalanceReconciliation", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetBalanceReconciliationRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBalanceReconciliation indicates an expected call of GetBalanceReconciliation.
+func (mr *MockStoreMockRecorder) GetBalanceReconciliation(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBalanceReconciliation", reflect.TypeOf((*MockStore)(nil).GetBalanceReconciliation), arg0, arg1)
+}
+
+// GetBundle mocks base method.
+func (m *MockStore) GetBundle(arg0 context.Context, arg1 int32) (db.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBundle", arg0, arg1)
+	ret0, _ := ret[0].(db.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBundle indicates an expected call of GetBundle.
+func (mr *MockStoreMockRecorder) GetBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBundle", reflect.TypeOf((*MockStore)(nil).GetBundle), arg0, arg1)
+}
+
+// GetCategoryByID mocks base method.
+func (m *MockStore) GetCategoryByID(arg0 context.Context, arg1 int32) (db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryByID indicates an expected call of GetCategoryByID.
+func (mr *MockStoreMockRecorder) GetCategoryByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByID", reflect.TypeOf((*MockStore)(nil).GetCategoryByID), arg0, arg1)
+}
+
+// GetCategoryByName mocks base method.
+func (m *MockStore) GetCategoryByName(arg0 context.Context, arg1 string) (db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCategoryByName", arg0, arg1)
+	ret0, _ := ret[0].(db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCategoryByName indicates an expected call of GetCategoryByName.
+func (mr *MockStoreMockRecorder) GetCategoryByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCategoryByName", reflect.TypeOf((*MockStore)(nil).GetCategoryByName), arg0, arg1)
+}
+
+// GetCurrentBalance mocks base method.
+func (m *MockStore) GetCurrentBalance(arg0 context.Context, arg1 int32) (pgtype.Int4, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCurrentBalance", arg0, arg1)
+	ret0, _ := ret[0].(pgtype.Int4)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCurrentBalance indicates an expected call of GetCurrentBalance.
+func (mr *MockStoreMockRecorder) GetCurrentBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentBalance", reflect.TypeOf((*MockStore)(nil).GetCurrentBalance), arg0, arg1)
+}
+
+// GetDropQueueRank mocks base method.
+func (m *MockStore) GetDropQueueRank(arg0 context.Context, arg1 db.GetDropQueueRankParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDropQueueRank", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDropQueueRank indicates an expected call of GetDropQueueRank.
+func (mr *MockStoreMockRecorder) GetDropQueueRank(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDropQueueRank", reflect.TypeOf((*MockStore)(nil).GetDropQueueRank), arg0, arg1)
+}
+
+// GetGifts mocks base method.
+func (m *MockStore) GetGifts(arg0 context.Context, arg1 pgtype.Int4) ([]db.GetGiftsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGifts", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetGiftsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGifts indicates an expected call of GetGifts.
+func (mr *MockStoreMockRecorder) GetGifts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGifts", reflect.TypeOf((*MockStore)(nil).GetGifts), arg0, arg1)
+}
+
+// GetInventory mocks base method.
+func (m *MockStore) GetInventory(arg0 context.Context, arg1 int32) ([]db.GetInventoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventory", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetInventoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInventory indicates an expected call of GetInventory.
+func (mr *MockStoreMockRecorder) GetInventory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventory", reflect.TypeOf((*MockStore)(nil).GetInventory), arg0, arg1)
+}
+
+// GetInventoryItem mocks base method.
+func (m *MockStore) GetInventoryItem(arg0 context.Context, arg1 int32) (db.InventoryItem, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInventoryItem", arg0, arg1)
+	ret0, _ := ret[0].(db.InventoryItem)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInventoryItem indicates an expected call of GetInventoryItem.
+func (mr *MockStoreMockRecorder) GetInventoryItem(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInventoryItem", reflect.TypeOf((*MockStore)(nil).GetInventoryItem), arg0, arg1)
+}
+
+// GetItemByID mocks base method.
+func (m *MockStore) GetItemByID(arg0 context.Context, arg1 int32) (db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemByID indicates an expected call of GetItemByID.
+func (mr *MockStoreMockRecorder) GetItemByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByID", reflect.TypeOf((*MockStore)(nil).GetItemByID), arg0, arg1)
+}
+
+// GetItemByName mocks base method.
+func (m *MockStore) GetItemByName(arg0 context.Context, arg1 db.GetItemByNameParams) (db.GetItemByNameRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemByName", arg0, arg1)
+	ret0, _ := ret[0].(db.GetItemByNameRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemByName indicates an expected call of GetItemByName.
+func (mr *MockStoreMockRecorder) GetItemByName(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByName", reflect.TypeOf((*MockStore)(nil).GetItemByName), arg0, arg1)
+}
+
+// GetItemStockForUpdate mocks base method.
+func (m *MockStore) GetItemStockForUpdate(arg0 context.Context, arg1 int32) (pgtype.Int4, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemStockForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(pgtype.Int4)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemStockForUpdate indicates an expected call of GetItemStockForUpdate.
+func (mr *MockStoreMockRecorder) GetItemStockForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemStockForUpdate", reflect.TypeOf((*MockStore)(nil).GetItemStockForUpdate), arg0, arg1)
+}
+
+// GetItemVariantByID mocks base method.
+func (m *MockStore) GetItemVariantByID(arg0 context.Context, arg1 int32) (db.ItemVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetItemVariantByID", arg0, arg1)
+	ret0, _ := ret[0].(db.ItemVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetItemVariantByID indicates an expected call of GetItemVariantByID.
+func (mr *MockStoreMockRecorder) GetItemVariantByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemVariantByID", reflect.TypeOf((*MockStore)(nil).GetItemVariantByID), arg0, arg1)
+}
+
+// GetLeadingAuctionBid mocks base method.
+func (m *MockStore) GetLeadingAuctionBid(arg0 context.Context, arg1 int32) (db.AuctionBid, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLeadingAuctionBid", arg0, arg1)
+	ret0, _ := ret[0].(db.AuctionBid)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLeadingAuctionBid indicates an expected call of GetLeadingAuctionBid.
+func (mr *MockStoreMockRecorder) GetLeadingAuctionBid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLeadingAuctionBid", reflect.TypeOf((*MockStore)(nil).GetLeadingAuctionBid), arg0, arg1)
+}
+
+// GetLedgerTotals mocks base method.
+func (m *MockStore) GetLedgerTotals(arg0 context.Context) (db.GetLedgerTotalsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLedgerTotals", arg0)
+	ret0, _ := ret[0].(db.GetLedgerTotalsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLedgerTotals indicates an expected call of GetLedgerTotals.
+func (mr *MockStoreMockRecorder) GetLedgerTotals(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLedgerTotals", reflect.TypeOf((*MockStore)(nil).GetLedgerTotals), arg0)
+}
+
+// GetListing mocks base method.
+func (m *MockStore) GetListing(arg0 context.Context, arg1 int32) (db.MarketplaceListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListing", arg0, arg1)
+	ret0, _ := ret[0].(db.MarketplaceListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListing indicates an expected call of GetListing.
+func (mr *MockStoreMockRecorder) GetListing(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListing", reflect.TypeOf((*MockStore)(nil).GetListing), arg0, arg1)
+}
+
+// GetListingForUpdate mocks base method.
+func (m *MockStore) GetListingForUpdate(arg0 context.Context, arg1 int32) (db.MarketplaceListing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetListingForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.MarketplaceListing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetListingForUpdate indicates an expected call of GetListingForUpdate.
+func (mr *MockStoreMockRecorder) GetListingForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetListingForUpdate", reflect.TypeOf((*MockStore)(nil).GetListingForUpdate), arg0, arg1)
+}
+
+// GetOrderByID mocks base method.
+func (m *MockStore) GetOrderByID(arg0 context.Context, arg1 int32) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByID indicates an expected call of GetOrderByID.
+func (mr *MockStoreMockRecorder) GetOrderByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockStore)(nil).GetOrderByID), arg0, arg1)
+}
+
+// GetOrderForUpdate mocks base method.
+func (m *MockStore) GetOrderForUpdate(arg0 context.Context, arg1 int32) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderForUpdate indicates an expected call of GetOrderForUpdate.
+func (mr *MockStoreMockRecorder) GetOrderForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderForUpdate", reflect.TypeOf((*MockStore)(nil).GetOrderForUpdate), arg0, arg1)
+}
+
+// GetOrderStatusHistory mocks base method.
+func (m *MockStore) GetOrderStatusHistory(arg0 context.Context, arg1 int32) ([]db.GetOrderStatusHistoryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderStatusHistory", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetOrderStatusHistoryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderStatusHistory indicates an expected call of GetOrderStatusHistory.
+func (mr *MockStoreMockRecorder) GetOrderStatusHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderStatusHistory", reflect.TypeOf((*MockStore)(nil).GetOrderStatusHistory), arg0, arg1)
+}
+
+// GetOutgoingTransferStats mocks base method.
+func (m *MockStore) GetOutgoingTransferStats(arg0 context.Context, arg1 db.GetOutgoingTransferStatsParams) (db.GetOutgoingTransferStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOutgoingTransferStats", arg0, arg1)
+	ret0, _ := ret[0].(db.GetOutgoingTransferStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOutgoingTransferStats indicates an expected call of GetOutgoingTransferStats.
+func (mr *MockStoreMockRecorder) GetOutgoingTransferStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOutgoingTransferStats", reflect.TypeOf((*MockStore)(nil).GetOutgoingTransferStats), arg0, arg1)
+}
+
+// GetPendingTransfers mocks base method.
+func (m *MockStore) GetPendingTransfers(arg0 context.Context, arg1 pgtype.Int4) ([]db.GetPendingTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetPendingTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingTransfers indicates an expected call of GetPendingTransfers.
+func (mr *MockStoreMockRecorder) GetPendingTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingTransfers", reflect.TypeOf((*MockStore)(nil).GetPendingTransfers), arg0, arg1)
+}
+
+// GetPromoCode mocks base method.
+func (m *MockStore) GetPromoCode(arg0 context.Context, arg1 string) (db.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoCode", arg0, arg1)
+	ret0, _ := ret[0].(db.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoCode indicates an expected call of GetPromoCode.
+func (mr *MockStoreMockRecorder) GetPromoCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoCode", reflect.TypeOf((*MockStore)(nil).GetPromoCode), arg0, arg1)
+}
+
+// GetPromoCodeByID mocks base method.
+func (m *MockStore) GetPromoCodeByID(arg0 context.Context, arg1 int32) (db.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoCodeByID", arg0, arg1)
+	ret0, _ := ret[0].(db.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoCodeByID indicates an expected call of GetPromoCodeByID.
+func (mr *MockStoreMockRecorder) GetPromoCodeByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoCodeByID", reflect.TypeOf((*MockStore)(nil).GetPromoCodeByID), arg0, arg1)
+}
+
+// GetPromoCodeForUpdate mocks base method.
+func (m *MockStore) GetPromoCodeForUpdate(arg0 context.Context, arg1 string) (db.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPromoCodeForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPromoCodeForUpdate indicates an expected call of GetPromoCodeForUpdate.
+func (mr *MockStoreMockRecorder) GetPromoCodeForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPromoCodeForUpdate", reflect.TypeOf((*MockStore)(nil).GetPromoCodeForUpdate), arg0, arg1)
+}
+
+// GetPurchaseByID mocks base method.
+func (m *MockStore) GetPurchaseByID(arg0 context.Context, arg1 int32) (db.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseByID", arg0, arg1)
+	ret0, _ := ret[0].(db.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseByID indicates an expected call of GetPurchaseByID.
+func (mr *MockStoreMockRecorder) GetPurchaseByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseByID", reflect.TypeOf((*MockStore)(nil).GetPurchaseByID), arg0, arg1)
+}
+
+// GetPurchaseForUpdate mocks base method.
+func (m *MockStore) GetPurchaseForUpdate(arg0 context.Context, arg1 int32) (db.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchaseForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchaseForUpdate indicates an expected call of GetPurchaseForUpdate.
+func (mr *MockStoreMockRecorder) GetPurchaseForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchaseForUpdate", reflect.TypeOf((*MockStore)(nil).GetPurchaseForUpdate), arg0, arg1)
+}
+
+// GetPurchases mocks base method.
+func (m *MockStore) GetPurchases(arg0 context.Context, arg1 pgtype.Int4) ([]db.GetPurchasesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPurchases", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetPurchasesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPurchases indicates an expected call of GetPurchases.
+func (mr *MockStoreMockRecorder) GetPurchases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchases", reflect.TypeOf((*MockStore)(nil).GetPurchases), arg0, arg1)
+}
+
+// GetRaffleForUpdate mocks base method.
+func (m *MockStore) GetRaffleForUpdate(arg0 context.Context, arg1 int32) (db.Raffle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRaffleForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Raffle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRaffleForUpdate indicates an expected call of GetRaffleForUpdate.
+func (mr *MockStoreMockRecorder) GetRaffleForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRaffleForUpdate", reflect.TypeOf((*MockStore)(nil).GetRaffleForUpdate), arg0, arg1)
+}
+
+// GetRaffleSummary mocks base method.
+func (m *MockStore) GetRaffleSummary(arg0 context.Context, arg1 db.GetRaffleSummaryParams) (db.GetRaffleSummaryRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRaffleSummary", arg0, arg1)
+	ret0, _ := ret[0].(db.GetRaffleSummaryRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRaffleSummary indicates an expected call of GetRaffleSummary.
+func (mr *MockStoreMockRecorder) GetRaffleSummary(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRaffleSummary", reflect.TypeOf((*MockStore)(nil).GetRaffleSummary), arg0, arg1)
+}
+
+// GetReservedStock mocks base method.
+func (m *MockStore) GetReservedStock(arg0 context.Context, arg1 db.GetReservedStockParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReservedStock", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReservedStock indicates an expected call of GetReservedStock.
+func (mr *MockStoreMockRecorder) GetReservedStock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReservedStock", reflect.TypeOf((*MockStore)(nil).GetReservedStock), arg0, arg1)
+}
+
+// GetSpendableBalance mocks base method.
+func (m *MockStore) GetSpendableBalance(arg0 context.Context, arg1 db.GetSpendableBalanceParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSpendableBalance", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSpendableBalance indicates an expected call of GetSpendableBalance.
+func (mr *MockStoreMockRecorder) GetSpendableBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSpendableBalance", reflect.TypeOf((*MockStore)(nil).GetSpendableBalance), arg0, arg1)
+}
+
+// GetTransactionLots mocks base method.
+func (m *MockStore) GetTransactionLots(arg0 context.Context, arg1 int32) ([]db.TransactionLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionLots", arg0, arg1)
+	ret0, _ := ret[0].([]db.TransactionLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionLots indicates an expected call of GetTransactionLots.
+func (mr *MockStoreMockRecorder) GetTransactionLots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionLots", reflect.TypeOf((*MockStore)(nil).GetTransactionLots), arg0, arg1)
+}
+
+// GetTransactions mocks base method.
+func (m *MockStore) GetTransactions(arg0 context.Context, arg1 pgtype.Int4) ([]db.GetTransactionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactions", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetTransactionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactions indicates an expected call of GetTransactions.
+func (mr *MockStoreMockRecorder) GetTransactions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockStore)(nil).GetTransactions), arg0, arg1)
+}
+
+// GetTransferForUpdate mocks base method.
+func (m *MockStore) GetTransferForUpdate(arg0 context.Context, arg1 int32) (db.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransferForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(db.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransferForUpdate indicates an expected call of GetTransferForUpdate.
+func (mr *MockStoreMockRecorder) GetTransferForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransferForUpdate", reflect.TypeOf((*MockStore)(nil).GetTransferForUpdate), arg0, arg1)
+}
+
+// GetUpcomingExpirations mocks base method.
+func (m *MockStore) GetUpcomingExpirations(arg0 context.Context, arg1 db.GetUpcomingExpirationsParams) ([]db.GetUpcomingExpirationsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUpcomingExpirations", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetUpcomingExpirationsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUpcomingExpirations indicates an expected call of GetUpcomingExpirations.
+func (mr *MockStoreMockRecorder) GetUpcomingExpirations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUpcomingExpirations", reflect.TypeOf((*MockStore)(nil).GetUpcomingExpirations), arg0, arg1)
+}
+
+// GetUserByID mocks base method.
+func (m *MockStore) GetUserByID(arg0 context.Context, arg1 int32) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByID indicates an expected call of GetUserByID.
+func (mr *MockStoreMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStore)(nil).GetUserByID), arg0, arg1)
+}
+
+// GetUserByUsername mocks base method.
+func (m *MockStore) GetUserByUsername(arg0 context.Context, arg1 string) (db.GetUserByUsernameRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByUsername", arg0, arg1)
+	ret0, _ := ret[0].(db.GetUserByUsernameRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByUsername indicates an expected call of GetUserByUsername.
+func (mr *MockStoreMockRecorder) GetUserByUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockStore)(nil).GetUserByUsername), arg0, arg1)
+}
+
+// GetUserItemPurchaseStats mocks base method.
+func (m *MockStore) GetUserItemPurchaseStats(arg0 context.Context, arg1 db.GetUserItemPurchaseStatsParams) (db.GetUserItemPurchaseStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserItemPurchaseStats", arg0, arg1)
+	ret0, _ := ret[0].(db.GetUserItemPurchaseStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserItemPurchaseStats indicates an expected call of GetUserItemPurchaseStats.
+func (mr *MockStoreMockRecorder) GetUserItemPurchaseStats(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserItemPurchaseStats", reflect.TypeOf((*MockStore)(nil).GetUserItemPurchaseStats), arg0, arg1)
+}
+
+// GetUserReservedQuantity mocks base method.
+func (m *MockStore) GetUserReservedQuantity(arg0 context.Context, arg1 db.GetUserReservedQuantityParams) (int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserReservedQuantity", arg0, arg1)
+	ret0, _ := ret[0].(int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserReservedQuantity indicates an expected call of GetUserReservedQuantity.
+func (mr *MockStoreMockRecorder) GetUserReservedQuantity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserReservedQuantity", reflect.TypeOf((*MockStore)(nil).GetUserReservedQuantity), arg0, arg1)
+}
+
+// GetUserRole mocks base method.
+func (m *MockStore) GetUserRole(arg0 context.Context, arg1 string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserRole", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserRole indicates an expected call of GetUserRole.
+func (mr *MockStoreMockRecorder) GetUserRole(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserRole", reflect.TypeOf((*MockStore)(nil).GetUserRole), arg0, arg1)
+}
+
+// GetUsersByUsernames mocks base method.
+func (m *MockStore) GetUsersByUsernames(arg0 context.Context, arg1 []string) ([]db.GetUsersByUsernamesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByUsernames", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetUsersByUsernamesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByUsernames indicates an expected call of GetUsersByUsernames.
+func (mr *MockStoreMockRecorder) GetUsersByUsernames(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByUsernames", reflect.TypeOf((*MockStore)(nil).GetUsersByUsernames), arg0, arg1)
+}
+
+// GetVariantStockForUpdate mocks base method.
+func (m *MockStore) GetVariantStockForUpdate(arg0 context.Context, arg1 int32) (pgtype.Int4, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariantStockForUpdate", arg0, arg1)
+	ret0, _ := ret[0].(pgtype.Int4)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariantStockForUpdate indicates an expected call of GetVariantStockForUpdate.
+func (mr *MockStoreMockRecorder) GetVariantStockForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariantStockForUpdate", reflect.TypeOf((*MockStore)(nil).GetVariantStockForUpdate), arg0, arg1)
+}
+
+// GiveItemTx mocks base method.
+func (m *MockStore) GiveItemTx(arg0 context.Context, arg1 db.GiveItemTxParams) (db.GiveItemTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GiveItemTx", arg0, arg1)
+	ret0, _ := ret[0].(db.GiveItemTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GiveItemTx indicates an expected call of GiveItemTx.
+func (mr *MockStoreMockRecorder) GiveItemTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GiveItemTx", reflect.TypeOf((*MockStore)(nil).GiveItemTx), arg0, arg1)
+}
+
+// IncrementPromoCodeUses mocks base method.
+func (m *MockStore) IncrementPromoCodeUses(arg0 context.Context, arg1 int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementPromoCodeUses", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementPromoCodeUses indicates an expected call of IncrementPromoCodeUses.
+func (mr *MockStoreMockRecorder) IncrementPromoCodeUses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementPromoCodeUses", reflect.TypeOf((*MockStore)(nil).IncrementPromoCodeUses), arg0, arg1)
+}
+
+// JoinDropQueue mocks base method.
+func (m *MockStore) JoinDropQueue(arg0 context.Context, arg1 db.JoinDropQueueParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JoinDropQueue", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// JoinDropQueue indicates an expected call of JoinDropQueue.
+func (mr *MockStoreMockRecorder) JoinDropQueue(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JoinDropQueue", reflect.TypeOf((*MockStore)(nil).JoinDropQueue), arg0, arg1)
+}
+
+// ListAttributesForItems mocks base method.
+func (m *MockStore) ListAttributesForItems(arg0 context.Context, arg1 []int32) ([]db.ItemAttribute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttributesForItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.ItemAttribute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttributesForItems indicates an expected call of ListAttributesForItems.
+func (mr *MockStoreMockRecorder) ListAttributesForItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttributesForItems", reflect.TypeOf((*MockStore)(nil).ListAttributesForItems), arg0, arg1)
+}
+
+// ListAuctions mocks base method.
+func (m *MockStore) ListAuctions(arg0 context.Context, arg1 db.ListAuctionsParams) ([]db.ListAuctionsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAuctions", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListAuctionsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAuctions indicates an expected call of ListAuctions.
+func (mr *MockStoreMockRecorder) ListAuctions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAuctions", reflect.TypeOf((*MockStore)(nil).ListAuctions), arg0, arg1)
+}
+
+// ListBundleComponents mocks base method.
+func (m *MockStore) ListBundleComponents(arg0 context.Context, arg1 int32) ([]db.ListBundleComponentsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBundleComponents", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListBundleComponentsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBundleComponents indicates an expected call of ListBundleComponents.
+func (mr *MockStoreMockRecorder) ListBundleComponents(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBundleComponents", reflect.TypeOf((*MockStore)(nil).ListBundleComponents), arg0, arg1)
+}
+
+// ListBundleItems mocks base method.
+func (m *MockStore) ListBundleItems(arg0 context.Context, arg1 []int32) ([]db.ListBundleItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBundleItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListBundleItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBundleItems indicates an expected call of ListBundleItems.
+func (mr *MockStoreMockRecorder) ListBundleItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBundleItems", reflect.TypeOf((*MockStore)(nil).ListBundleItems), arg0, arg1)
+}
+
+// ListBundles mocks base method.
+func (m *MockStore) ListBundles(arg0 context.Context, arg1 pgtype.Bool) ([]db.Bundle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBundles", arg0, arg1)
+	ret0, _ := ret[0].([]db.Bundle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBundles indicates an expected call of ListBundles.
+func (mr *MockStoreMockRecorder) ListBundles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBundles", reflect.TypeOf((*MockStore)(nil).ListBundles), arg0, arg1)
+}
+
+// ListCartItems mocks base method.
+func (m *MockStore) ListCartItems(arg0 context.Context, arg1 int32) ([]db.ListCartItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCartItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCartItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCartItems indicates an expected call of ListCartItems.
+func (mr *MockStoreMockRecorder) ListCartItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCartItems", reflect.TypeOf((*MockStore)(nil).ListCartItems), arg0, arg1)
+}
+
+// ListCartItemsForUpdate mocks base method.
+func (m *MockStore) ListCartItemsForUpdate(arg0 context.Context, arg1 int32) ([]db.ListCartItemsForUpdateRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCartItemsForUpdate", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCartItemsForUpdateRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCartItemsForUpdate indicates an expected call of ListCartItemsForUpdate.
+func (mr *MockStoreMockRecorder) ListCartItemsForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCartItemsForUpdate", reflect.TypeOf((*MockStore)(nil).ListCartItemsForUpdate), arg0, arg1)
+}
+
+// ListCategories mocks base method.
+func (m *MockStore) ListCategories(arg0 context.Context) ([]db.Category, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCategories", arg0)
+	ret0, _ := ret[0].([]db.Category)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCategories indicates an expected call of ListCategories.
+func (mr *MockStoreMockRecorder) ListCategories(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCategories", reflect.TypeOf((*MockStore)(nil).ListCategories), arg0)
+}
+
+// ListDueAuctions mocks base method.
+func (m *MockStore) ListDueAuctions(arg0 context.Context, arg1 db.ListDueAuctionsParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueAuctions", arg0, arg1)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueAuctions indicates an expected call of ListDueAuctions.
+func (mr *MockStoreMockRecorder) ListDueAuctions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueAuctions", reflect.TypeOf((*MockStore)(nil).ListDueAuctions), arg0, arg1)
+}
+
+// ListDuePendingTransfers mocks base method.
+func (m *MockStore) ListDuePendingTransfers(arg0 context.Context, arg1 db.ListDuePendingTransfersParams) ([]db.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDuePendingTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDuePendingTransfers indicates an expected call of ListDuePendingTransfers.
+func (mr *MockStoreMockRecorder) ListDuePendingTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDuePendingTransfers", reflect.TypeOf((*MockStore)(nil).ListDuePendingTransfers), arg0, arg1)
+}
+
+// ListDueRaffles mocks base method.
+func (m *MockStore) ListDueRaffles(arg0 context.Context, arg1 db.ListDueRafflesParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDueRaffles", arg0, arg1)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDueRaffles indicates an expected call of ListDueRaffles.
+func (mr *MockStoreMockRecorder) ListDueRaffles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDueRaffles", reflect.TypeOf((*MockStore)(nil).ListDueRaffles), arg0, arg1)
+}
+
+// ListExpiredCoinLots mocks base method.
+func (m *MockStore) ListExpiredCoinLots(arg0 context.Context, arg1 db.ListExpiredCoinLotsParams) ([]db.CoinLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiredCoinLots", arg0, arg1)
+	ret0, _ := ret[0].([]db.CoinLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiredCoinLots indicates an expected call of ListExpiredCoinLots.
+func (mr *MockStoreMockRecorder) ListExpiredCoinLots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiredCoinLots", reflect.TypeOf((*MockStore)(nil).ListExpiredCoinLots), arg0, arg1)
+}
+
+// ListImagesForItems mocks base method.
+func (m *MockStore) ListImagesForItems(arg0 context.Context, arg1 []int32) ([]db.ItemImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListImagesForItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.ItemImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListImagesForItems indicates an expected call of ListImagesForItems.
+func (mr *MockStoreMockRecorder) ListImagesForItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListImagesForItems", reflect.TypeOf((*MockStore)(nil).ListImagesForItems), arg0, arg1)
+}
+
+// ListInventoryTransfers mocks base method.
+func (m *MockStore) ListInventoryTransfers(arg0 context.Context, arg1 int32) ([]db.ListInventoryTransfersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInventoryTransfers", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListInventoryTransfersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInventoryTransfers indicates an expected call of ListInventoryTransfers.
+func (mr *MockStoreMockRecorder) ListInventoryTransfers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInventoryTransfers", reflect.TypeOf((*MockStore)(nil).ListInventoryTransfers), arg0, arg1)
+}
+
+// ListInventoryUnits mocks base method.
+func (m *MockStore) ListInventoryUnits(arg0 context.Context, arg1 int32) ([]db.ListInventoryUnitsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListInventoryUnits", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListInventoryUnitsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListInventoryUnits indicates an expected call of ListInventoryUnits.
+func (mr *MockStoreMockRecorder) ListInventoryUnits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListInventoryUnits", reflect.TypeOf((*MockStore)(nil).ListInventoryUnits), arg0, arg1)
+}
+
+// ListItemAttributes mocks base method.
+func (m *MockStore) ListItemAttributes(arg0 context.Context, arg1 int32) ([]db.ItemAttribute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemAttributes", arg0, arg1)
+	ret0, _ := ret[0].([]db.ItemAttribute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemAttributes indicates an expected call of ListItemAttributes.
+func (mr *MockStoreMockRecorder) ListItemAttributes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemAttributes", reflect.TypeOf((*MockStore)(nil).ListItemAttributes), arg0, arg1)
+}
+
+// ListItemImages mocks base method.
+func (m *MockStore) ListItemImages(arg0 context.Context, arg1 int32) ([]db.ItemImage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemImages", arg0, arg1)
+	ret0, _ := ret[0].([]db.ItemImage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemImages indicates an expected call of ListItemImages.
+func (mr *MockStoreMockRecorder) ListItemImages(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemImages", reflect.TypeOf((*MockStore)(nil).ListItemImages), arg0, arg1)
+}
+
+// ListItemPrices mocks base method.
+func (m *MockStore) ListItemPrices(arg0 context.Context, arg1 int32) ([]db.ItemPrice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemPrices", arg0, arg1)
+	ret0, _ := ret[0].([]db.ItemPrice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemPrices indicates an expected call of ListItemPrices.
+func (mr *MockStoreMockRecorder) ListItemPrices(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemPrices", reflect.TypeOf((*MockStore)(nil).ListItemPrices), arg0, arg1)
+}
+
+// ListItemTags mocks base method.
+func (m *MockStore) ListItemTags(arg0 context.Context, arg1 int32) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemTags", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemTags indicates an expected call of ListItemTags.
+func (mr *MockStoreMockRecorder) ListItemTags(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemTags", reflect.TypeOf((*MockStore)(nil).ListItemTags), arg0, arg1)
+}
+
+// ListItemVariants mocks base method.
+func (m *MockStore) ListItemVariants(arg0 context.Context, arg1 int32) ([]db.ItemVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListItemVariants", arg0, arg1)
+	ret0, _ := ret[0].([]db.ItemVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListItemVariants indicates an expected call of ListItemVariants.
+func (mr *MockStoreMockRecorder) ListItemVariants(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListItemVariants", reflect.TypeOf((*MockStore)(nil).ListItemVariants), arg0, arg1)
+}
+
+// ListListings mocks base method.
+func (m *MockStore) ListListings(arg0 context.Context, arg1 db.ListListingsParams) ([]db.ListListingsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListListings", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListListingsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListListings indicates an expected call of ListListings.
+func (mr *MockStoreMockRecorder) ListListings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListListings", reflect.TypeOf((*MockStore)(nil).ListListings), arg0, arg1)
+}
+
+// ListNotifications mocks base method.
+func (m *MockStore) ListNotifications(arg0 context.Context, arg1 db.ListNotificationsParams) ([]db.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListNotifications", arg0, arg1)
+	ret0, _ := ret[0].([]db.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListNotifications indicates an expected call of ListNotifications.
+func (mr *MockStoreMockRecorder) ListNotifications(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListNotifications", reflect.TypeOf((*MockStore)(nil).ListNotifications), arg0, arg1)
+}
+
+// ListOrderLines mocks base method.
+func (m *MockStore) ListOrderLines(arg0 context.Context, arg1 []int32) ([]db.ListOrderLinesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrderLines", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListOrderLinesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrderLines indicates an expected call of ListOrderLines.
+func (mr *MockStoreMockRecorder) ListOrderLines(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrderLines", reflect.TypeOf((*MockStore)(nil).ListOrderLines), arg0, arg1)
+}
+
+// ListOrderPurchasesForUpdate mocks base method.
+func (m *MockStore) ListOrderPurchasesForUpdate(arg0 context.Context, arg1 pgtype.Int4) ([]db.Purchase, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrderPurchasesForUpdate", arg0, arg1)
+	ret0, _ := ret[0].([]db.Purchase)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrderPurchasesForUpdate indicates an expected call of ListOrderPurchasesForUpdate.
+func (mr *MockStoreMockRecorder) ListOrderPurchasesForUpdate(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrderPurchasesForUpdate", reflect.TypeOf((*MockStore)(nil).ListOrderPurchasesForUpdate), arg0, arg1)
+}
+
+// ListOrders mocks base method.
+func (m *MockStore) ListOrders(arg0 context.Context, arg1 db.ListOrdersParams) ([]db.ListOrdersRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListOrdersRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockStoreMockRecorder) ListOrders(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockStore)(nil).ListOrders), arg0, arg1)
+}
+
+// ListPromoCodes mocks base method.
+func (m *MockStore) ListPromoCodes(arg0 context.Context) ([]db.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPromoCodes", arg0)
+	ret0, _ := ret[0].([]db.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPromoCodes indicates an expected call of ListPromoCodes.
+func (mr *MockStoreMockRecorder) ListPromoCodes(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPromoCodes", reflect.TypeOf((*MockStore)(nil).ListPromoCodes), arg0)
+}
+
+// ListRaffleTickets mocks base method.
+func (m *MockStore) ListRaffleTickets(arg0 context.Context, arg1 int32) ([]db.ListRaffleTicketsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRaffleTickets", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListRaffleTicketsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRaffleTickets indicates an expected call of ListRaffleTickets.
+func (mr *MockStoreMockRecorder) ListRaffleTickets(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRaffleTickets", reflect.TypeOf((*MockStore)(nil).ListRaffleTickets), arg0, arg1)
+}
+
+// ListRaffles mocks base method.
+func (m *MockStore) ListRaffles(arg0 context.Context, arg1 db.ListRafflesParams) ([]db.ListRafflesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRaffles", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListRafflesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRaffles indicates an expected call of ListRaffles.
+func (mr *MockStoreMockRecorder) ListRaffles(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRaffles", reflect.TypeOf((*MockStore)(nil).ListRaffles), arg0, arg1)
+}
+
+// ListSpendableCoinLots mocks base method.
+func (m *MockStore) ListSpendableCoinLots(arg0 context.Context, arg1 db.ListSpendableCoinLotsParams) ([]db.CoinLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSpendableCoinLots", arg0, arg1)
+	ret0, _ := ret[0].([]db.CoinLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSpendableCoinLots indicates an expected call of ListSpendableCoinLots.
+func (mr *MockStoreMockRecorder) ListSpendableCoinLots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSpendableCoinLots", reflect.TypeOf((*MockStore)(nil).ListSpendableCoinLots), arg0, arg1)
+}
+
+// ListTransferableUnits mocks base method.
+func (m *MockStore) ListTransferableUnits(arg0 context.Context, arg1 db.ListTransferableUnitsParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTransferableUnits", arg0, arg1)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTransferableUnits indicates an expected call of ListTransferableUnits.
+func (mr *MockStoreMockRecorder) ListTransferableUnits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTransferableUnits", reflect.TypeOf((*MockStore)(nil).ListTransferableUnits), arg0, arg1)
+}
+
+// ListUpcomingDrops mocks base method.
+func (m *MockStore) ListUpcomingDrops(arg0 context.Context, arg1 pgtype.Timestamp) ([]db.ListUpcomingDropsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUpcomingDrops", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUpcomingDropsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUpcomingDrops indicates an expected call of ListUpcomingDrops.
+func (mr *MockStoreMockRecorder) ListUpcomingDrops(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUpcomingDrops", reflect.TypeOf((*MockStore)(nil).ListUpcomingDrops), arg0, arg1)
+}
+
+// ListUserPurchases mocks base method.
+func (m *MockStore) ListUserPurchases(arg0 context.Context, arg1 pgtype.Int4) ([]db.ListUserPurchasesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUserPurchases", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListUserPurchasesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUserPurchases indicates an expected call of ListUserPurchases.
+func (mr *MockStoreMockRecorder) ListUserPurchases(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUserPurchases", reflect.TypeOf((*MockStore)(nil).ListUserPurchases), arg0, arg1)
+}
+
+// ListUsersWithExpiredLots mocks base method.
+func (m *MockStore) ListUsersWithExpiredLots(arg0 context.Context, arg1 db.ListUsersWithExpiredLotsParams) ([]int32, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsersWithExpiredLots", arg0, arg1)
+	ret0, _ := ret[0].([]int32)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsersWithExpiredLots indicates an expected call of ListUsersWithExpiredLots.
+func (mr *MockStoreMockRecorder) ListUsersWithExpiredLots(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsersWithExpiredLots", reflect.TypeOf((*MockStore)(nil).ListUsersWithExpiredLots), arg0, arg1)
+}
+
+// ListWishlistItems mocks base method.
+func (m *MockStore) ListWishlistItems(arg0 context.Context, arg1 int32) ([]db.ListWishlistItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListWishlistItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListWishlistItemsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListWishlistItems indicates an expected call of ListWishlistItems.
+func (mr *MockStoreMockRecorder) ListWishlistItems(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListWishlistItems", reflect.TypeOf((*MockStore)(nil).ListWishlistItems), arg0, arg1)
+}
+
+// LockUsers mocks base method.
+func (m *MockStore) LockUsers(arg0 context.Context, arg1 []int32) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUsers", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUsers indicates an expected call of LockUsers.
+func (mr *MockStoreMockRecorder) LockUsers(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUsers", reflect.TypeOf((*MockStore)(nil).LockUsers), arg0, arg1)
+}
+
+// MarkAllNotificationsRead mocks base method.
+func (m *MockStore) MarkAllNotificationsRead(arg0 context.Context, arg1 int32) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAllNotificationsRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAllNotificationsRead indicates an expected call of MarkAllNotificationsRead.
+func (mr *MockStoreMockRecorder) MarkAllNotificationsRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAllNotificationsRead", reflect.TypeOf((*MockStore)(nil).MarkAllNotificationsRead), arg0, arg1)
+}
+
+// MarkNotificationRead mocks base method.
+func (m *MockStore) MarkNotificationRead(arg0 context.Context, arg1 db.MarkNotificationReadParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkNotificationRead", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkNotificationRead indicates an expected call of MarkNotificationRead.
+func (mr *MockStoreMockRecorder) MarkNotificationRead(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkNotificationRead", reflect.TypeOf((*MockStore)(nil).MarkNotificationRead), arg0, arg1)
+}
+
+// MarketplaceTx mocks base method.
+func (m *MockStore) MarketplaceTx(arg0 context.Context, arg1 db.MarketplaceTxParams) (db.MarketplaceTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarketplaceTx", arg0, arg1)
+	ret0, _ := ret[0].(db.MarketplaceTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarketplaceTx indicates an expected call of MarketplaceTx.
+func (mr *MockStoreMockRecorder) MarketplaceTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarketplaceTx", reflect.TypeOf((*MockStore)(nil).MarketplaceTx), arg0, arg1)
+}
+
+// PlaceBidTx mocks base method.
+func (m *MockStore) PlaceBidTx(arg0 context.Context, arg1 db.PlaceBidTxParams) (db.PlaceBidTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceBidTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PlaceBidTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceBidTx indicates an expected call of PlaceBidTx.
+func (mr *MockStoreMockRecorder) PlaceBidTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceBidTx", reflect.TypeOf((*MockStore)(nil).PlaceBidTx), arg0, arg1)
+}
+
+// PurchaseTx mocks base method.
+func (m *MockStore) PurchaseTx(arg0 context.Context, arg1 db.PurchaseTxParams) (db.PurchaseTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurchaseTx", arg0, arg1)
+	ret0, _ := ret[0].(db.PurchaseTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurchaseTx indicates an expected call of PurchaseTx.
+func (mr *MockStoreMockRecorder) PurchaseTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurchaseTx", reflect.TypeOf((*MockStore)(nil).PurchaseTx), arg0, arg1)
+}
+
+// QuotePurchaseTx mocks base method.
+func (m *MockStore) QuotePurchaseTx(arg0 context.Context, arg1 db.PurchaseTxParams) (db.QuoteTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuotePurchaseTx", arg0, arg1)
+	ret0, _ := ret[0].(db.QuoteTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuotePurchaseTx indicates an expected call of QuotePurchaseTx.
+func (mr *MockStoreMockRecorder) QuotePurchaseTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuotePurchaseTx", reflect.TypeOf((*MockStore)(nil).QuotePurchaseTx), arg0, arg1)
+}
+
+// QuoteTransferTx mocks base method.
+func (m *MockStore) QuoteTransferTx(arg0 context.Context, arg1 db.TransferTxParams) (db.QuoteTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuoteTransferTx", arg0, arg1)
+	ret0, _ := ret[0].(db.QuoteTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuoteTransferTx indicates an expected call of QuoteTransferTx.
+func (mr *MockStoreMockRecorder) QuoteTransferTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuoteTransferTx", reflect.TypeOf((*MockStore)(nil).QuoteTransferTx), arg0, arg1)
+}
+
+// ReconcileBalancesTx mocks base method.
+func (m *MockStore) ReconcileBalancesTx(arg0 context.Context, arg1 db.ReconcileBalancesTxParams) (db.ReconcileBalancesTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReconcileBalancesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ReconcileBalancesTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateItem indicates an expected call of CreateItem.
-func (mr *MockStoreMockRecorder) CreateItem(arg0, arg1 interface{}) *gomock.Call {
+// ReconcileBalancesTx indicates an expected call of ReconcileBalancesTx.
+func (mr *MockStoreMockRecorder) ReconcileBalancesTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateItem", reflect.TypeOf((*MockStore)(nil).CreateItem), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReconcileBalancesTx", reflect.TypeOf((*MockStore)(nil).ReconcileBalancesTx), arg0, arg1)
 }
 
-// CreatePurchase mocks base method.
-func (m *MockStore) CreatePurchase(arg0 context.Context, arg1 db.CreatePurchaseParams) (db.Purchase, error) {
+// RefundPurchase mocks base method.
+func (m *MockStore) RefundPurchase(arg0 context.Context, arg1 db.RefundPurchaseParams) (db.Purchase, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreatePurchase", arg0, arg1)
+	ret := m.ctrl.Call(m, "RefundPurchase", arg0, arg1)
 	ret0, _ := ret[0].(db.Purchase)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreatePurchase indicates an expected call of CreatePurchase.
-func (mr *MockStoreMockRecorder) CreatePurchase(arg0, arg1 interface{}) *gomock.Call {
+// RefundPurchase indicates an expected call of RefundPurchase.
+func (mr *MockStoreMockRecorder) RefundPurchase(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePurchase", reflect.TypeOf((*MockStore)(nil).CreatePurchase), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundPurchase", reflect.TypeOf((*MockStore)(nil).RefundPurchase), arg0, arg1)
 }
 
-// CreateTransfer mocks base method.
-func (m *MockStore) CreateTransfer(arg0 context.Context, arg1 db.CreateTransferParams) (db.Transaction, error) {
+// RefundTx mocks base method.
+func (m *MockStore) RefundTx(arg0 context.Context, arg1 db.RefundTxParams) (db.RefundTxResult, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateTransfer", arg0, arg1)
-	ret0, _ := ret[0].(db.Transaction)
+	ret := m.ctrl.Call(m, "RefundTx", arg0, arg1)
+	ret0, _ := ret[0].(db.RefundTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateTransfer indicates an expected call of CreateTransfer.
-func (mr *MockStoreMockRecorder) CreateTransfer(arg0, arg1 interface{}) *gomock.Call {
+// RefundTx indicates an expected call of RefundTx.
+func (mr *MockStoreMockRecorder) RefundTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTransfer", reflect.TypeOf((*MockStore)(nil).CreateTransfer), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RefundTx", reflect.TypeOf((*MockStore)(nil).RefundTx), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// ReleaseExpiredReservations mocks base method.
+func (m *MockStore) ReleaseExpiredReservations(arg0 context.Context, arg1 pgtype.Timestamp) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "ReleaseExpiredReservations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+// ReleaseExpiredReservations indicates an expected call of ReleaseExpiredReservations.
+func (mr *MockStoreMockRecorder) ReleaseExpiredReservations(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseExpiredReservations", reflect.TypeOf((*MockStore)(nil).ReleaseExpiredReservations), arg0, arg1)
 }
 
-// GetCurrentBalance mocks base method.
-func (m *MockStore) GetCurrentBalance(arg0 context.Context, arg1 int32) (pgtype.Int4, error) {
+// RemovePurchaseUnits mocks base method.
+func (m *MockStore) RemovePurchaseUnits(arg0 context.Context, arg1 db.RemovePurchaseUnitsParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetCurrentBalance", arg0, arg1)
-	ret0, _ := ret[0].(pgtype.Int4)
+	ret := m.ctrl.Call(m, "RemovePurchaseUnits", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetCurrentBalance indicates an expected call of GetCurrentBalance.
-func (mr *MockStoreMockRecorder) GetCurrentBalance(arg0, arg1 interface{}) *gomock.Call {
+// RemovePurchaseUnits indicates an expected call of RemovePurchaseUnits.
+func (mr *MockStoreMockRecorder) RemovePurchaseUnits(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCurrentBalance", reflect.TypeOf((*MockStore)(nil).GetCurrentBalance), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemovePurchaseUnits", reflect.TypeOf((*MockStore)(nil).RemovePurchaseUnits), arg0, arg1)
 }
 
-// GetItemByID mocks base method.
-func (m *MockStore) GetItemByID(arg0 context.Context, arg1 int32) (db.Item, error) {
+// RemoveWishlistItem mocks base method.
+func (m *MockStore) RemoveWishlistItem(arg0 context.Context, arg1 db.RemoveWishlistItemParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetItemByID", arg0, arg1)
-	ret0, _ := ret[0].(db.Item)
+	ret := m.ctrl.Call(m, "RemoveWishlistItem", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetItemByID indicates an expected call of GetItemByID.
-func (mr *MockStoreMockRecorder) GetItemByID(arg0, arg1 interface{}) *gomock.Call {
+// RemoveWishlistItem indicates an expected call of RemoveWishlistItem.
+func (mr *MockStoreMockRecorder) RemoveWishlistItem(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByID", reflect.TypeOf((*MockStore)(nil).GetItemByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveWishlistItem", reflect.TypeOf((*MockStore)(nil).RemoveWishlistItem), arg0, arg1)
 }
 
-// GetItemByName mocks base method.
-func (m *MockStore) GetItemByName(arg0 context.Context, arg1 string) (db.Item, error) {
+// ReserveCartItem mocks base method.
+func (m *MockStore) ReserveCartItem(arg0 context.Context, arg1 db.ReserveCartItemParams) (pgtype.Timestamp, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetItemByName", arg0, arg1)
-	ret0, _ := ret[0].(db.Item)
+	ret := m.ctrl.Call(m, "ReserveCartItem", arg0, arg1)
+	ret0, _ := ret[0].(pgtype.Timestamp)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetItemByName indicates an expected call of GetItemByName.
-func (mr *MockStoreMockRecorder) GetItemByName(arg0, arg1 interface{}) *gomock.Call {
+// ReserveCartItem indicates an expected call of ReserveCartItem.
+func (mr *MockStoreMockRecorder) ReserveCartItem(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetItemByName", reflect.TypeOf((*MockStore)(nil).GetItemByName), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveCartItem", reflect.TypeOf((*MockStore)(nil).ReserveCartItem), arg0, arg1)
 }
 
-// GetPurchases mocks base method.
-func (m *MockStore) GetPurchases(arg0 context.Context, arg1 pgtype.Int4) ([]db.GetPurchasesRow, error) {
+// ResetUncoveredWishlistItems mocks base method.
+func (m *MockStore) ResetUncoveredWishlistItems(arg0 context.Context, arg1 pgtype.Timestamp) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetPurchases", arg0, arg1)
-	ret0, _ := ret[0].([]db.GetPurchasesRow)
+	ret := m.ctrl.Call(m, "ResetUncoveredWishlistItems", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetPurchases indicates an expected call of GetPurchases.
-func (mr *MockStoreMockRecorder) GetPurchases(arg0, arg1 interface{}) *gomock.Call {
+// ResetUncoveredWishlistItems indicates an expected call of ResetUncoveredWishlistItems.
+func (mr *MockStoreMockRecorder) ResetUncoveredWishlistItems(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPurchases", reflect.TypeOf((*MockStore)(nil).GetPurchases), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetUncoveredWishlistItems", reflect.TypeOf((*MockStore)(nil).ResetUncoveredWishlistItems), arg0, arg1)
 }
 
-// GetTransactions mocks base method.
-func (m *MockStore) GetTransactions(arg0 context.Context, arg1 pgtype.Int4) ([]db.GetTransactionsRow, error) {
+// ResolveAuctionBid mocks base method.
+func (m *MockStore) ResolveAuctionBid(arg0 context.Context, arg1 db.ResolveAuctionBidParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactions", arg0, arg1)
-	ret0, _ := ret[0].([]db.GetTransactionsRow)
+	ret := m.ctrl.Call(m, "ResolveAuctionBid", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResolveAuctionBid indicates an expected call of ResolveAuctionBid.
+func (mr *MockStoreMockRecorder) ResolveAuctionBid(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResolveAuctionBid", reflect.TypeOf((*MockStore)(nil).ResolveAuctionBid), arg0, arg1)
+}
+
+// RestoreItemStock mocks base method.
+func (m *MockStore) RestoreItemStock(arg0 context.Context, arg1 db.RestoreItemStockParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreItemStock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreItemStock indicates an expected call of RestoreItemStock.
+func (mr *MockStoreMockRecorder) RestoreItemStock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreItemStock", reflect.TypeOf((*MockStore)(nil).RestoreItemStock), arg0, arg1)
+}
+
+// RestoreVariantStock mocks base method.
+func (m *MockStore) RestoreVariantStock(arg0 context.Context, arg1 db.RestoreVariantStockParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreVariantStock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreVariantStock indicates an expected call of RestoreVariantStock.
+func (mr *MockStoreMockRecorder) RestoreVariantStock(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreVariantStock", reflect.TypeOf((*MockStore)(nil).RestoreVariantStock), arg0, arg1)
+}
+
+// SearchItems mocks base method.
+func (m *MockStore) SearchItems(arg0 context.Context, arg1 db.SearchItemsParams) ([]db.SearchItemsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchItems", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchItemsRow)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetTransactions indicates an expected call of GetTransactions.
-func (mr *MockStoreMockRecorder) GetTransactions(arg0, arg1 interface{}) *gomock.Call {
+// SearchItems indicates an expected call of SearchItems.
+func (mr *MockStoreMockRecorder) SearchItems(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockStore)(nil).GetTransactions), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchItems", reflect.TypeOf((*MockStore)(nil).SearchItems), arg0, arg1)
 }
 
-// GetUserByID mocks base method.
-func (m *MockStore) GetUserByID(arg0 context.Context, arg1 int32) (db.User, error) {
+// SellListing mocks base method.
+func (m *MockStore) SellListing(arg0 context.Context, arg1 db.SellListingParams) (db.MarketplaceListing, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByID", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "SellListing", arg0, arg1)
+	ret0, _ := ret[0].(db.MarketplaceListing)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByID indicates an expected call of GetUserByID.
-func (mr *MockStoreMockRecorder) GetUserByID(arg0, arg1 interface{}) *gomock.Call {
+// SellListing indicates an expected call of SellListing.
+func (mr *MockStoreMockRecorder) SellListing(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByID", reflect.TypeOf((*MockStore)(nil).GetUserByID), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SellListing", reflect.TypeOf((*MockStore)(nil).SellListing), arg0, arg1)
 }
 
-// GetUserByUsername mocks base method.
-func (m *MockStore) GetUserByUsername(arg0 context.Context, arg1 string) (db.GetUserByUsernameRow, error) {
+// SetBundleActive mocks base method.
+func (m *MockStore) SetBundleActive(arg0 context.Context, arg1 db.SetBundleActiveParams) (db.Bundle, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserByUsername", arg0, arg1)
-	ret0, _ := ret[0].(db.GetUserByUsernameRow)
+	ret := m.ctrl.Call(m, "SetBundleActive", arg0, arg1)
+	ret0, _ := ret[0].(db.Bundle)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserByUsername indicates an expected call of GetUserByUsername.
-func (mr *MockStoreMockRecorder) GetUserByUsername(arg0, arg1 interface{}) *gomock.Call {
+// SetBundleActive indicates an expected call of SetBundleActive.
+func (mr *MockStoreMockRecorder) SetBundleActive(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByUsername", reflect.TypeOf((*MockStore)(nil).GetUserByUsername), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBundleActive", reflect.TypeOf((*MockStore)(nil).SetBundleActive), arg0, arg1)
 }
 
-// PurchaseTx mocks base method.
-func (m *MockStore) PurchaseTx(arg0 context.Context, arg1 db.PurchaseTxParams) (db.PurchaseTxResult, error) {
+// SetOrderBundle mocks base method.
+func (m *MockStore) SetOrderBundle(arg0 context.Context, arg1 db.SetOrderBundleParams) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "PurchaseTx", arg0, arg1)
-	ret0, _ := ret[0].(db.PurchaseTxResult)
+	ret := m.ctrl.Call(m, "SetOrderBundle", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOrderBundle indicates an expected call of SetOrderBundle.
+func (mr *MockStoreMockRecorder) SetOrderBundle(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOrderBundle", reflect.TypeOf((*MockStore)(nil).SetOrderBundle), arg0, arg1)
+}
+
+// SetRaffleTicketPurchase mocks base method.
+func (m *MockStore) SetRaffleTicketPurchase(arg0 context.Context, arg1 db.SetRaffleTicketPurchaseParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRaffleTicketPurchase", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRaffleTicketPurchase indicates an expected call of SetRaffleTicketPurchase.
+func (mr *MockStoreMockRecorder) SetRaffleTicketPurchase(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRaffleTicketPurchase", reflect.TypeOf((*MockStore)(nil).SetRaffleTicketPurchase), arg0, arg1)
+}
+
+// SetUserDepartment mocks base method.
+func (m *MockStore) SetUserDepartment(arg0 context.Context, arg1 db.SetUserDepartmentParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetUserDepartment", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetUserDepartment indicates an expected call of SetUserDepartment.
+func (mr *MockStoreMockRecorder) SetUserDepartment(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetUserDepartment", reflect.TypeOf((*MockStore)(nil).SetUserDepartment), arg0, arg1)
+}
+
+// SettleAuctionTx mocks base method.
+func (m *MockStore) SettleAuctionTx(arg0 context.Context, arg1 db.SettleAuctionTxParams) (db.SettleAuctionTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleAuctionTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SettleAuctionTxResult)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// PurchaseTx indicates an expected call of PurchaseTx.
-func (mr *MockStoreMockRecorder) PurchaseTx(arg0, arg1 interface{}) *gomock.Call {
+// SettleAuctionTx indicates an expected call of SettleAuctionTx.
+func (mr *MockStoreMockRecorder) SettleAuctionTx(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurchaseTx", reflect.TypeOf((*MockStore)(nil).PurchaseTx), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleAuctionTx", reflect.TypeOf((*MockStore)(nil).SettleAuctionTx), arg0, arg1)
+}
+
+// SettleTransfersTx mocks base method.
+func (m *MockStore) SettleTransfersTx(arg0 context.Context, arg1 db.SettleTransfersTxParams) (db.SettleTransfersTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SettleTransfersTx", arg0, arg1)
+	ret0, _ := ret[0].(db.SettleTransfersTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SettleTransfersTx indicates an expected call of SettleTransfersTx.
+func (mr *MockStoreMockRecorder) SettleTransfersTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SettleTransfersTx", reflect.TypeOf((*MockStore)(nil).SettleTransfersTx), arg0, arg1)
+}
+
+// TransferInventoryUnits mocks base method.
+func (m *MockStore) TransferInventoryUnits(arg0 context.Context, arg1 db.TransferInventoryUnitsParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "TransferInventoryUnits", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// TransferInventoryUnits indicates an expected call of TransferInventoryUnits.
+func (mr *MockStoreMockRecorder) TransferInventoryUnits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferInventoryUnits", reflect.TypeOf((*MockStore)(nil).TransferInventoryUnits), arg0, arg1)
 }
 
 // TransferTx mocks base method.
@@ -231,6 +2714,20 @@ func (mr *MockStoreMockRecorder) TransferTx(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TransferTx", reflect.TypeOf((*MockStore)(nil).TransferTx), arg0, arg1)
 }
 
+// UpdateBalance mocks base method.
+func (m *MockStore) UpdateBalance(arg0 context.Context, arg1 db.UpdateBalanceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBalance", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBalance indicates an expected call of UpdateBalance.
+func (mr *MockStoreMockRecorder) UpdateBalance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalance", reflect.TypeOf((*MockStore)(nil).UpdateBalance), arg0, arg1)
+}
+
 // UpdateBalanceForPurchase mocks base method.
 func (m *MockStore) UpdateBalanceForPurchase(arg0 context.Context, arg1 db.UpdateBalanceForPurchaseParams) error {
 	m.ctrl.T.Helper()
@@ -258,3 +2755,179 @@ func (mr *MockStoreMockRecorder) UpdateBalanceForTransfer(arg0, arg1 interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBalanceForTransfer", reflect.TypeOf((*MockStore)(nil).UpdateBalanceForTransfer), arg0, arg1)
 }
+
+// UpdateCartItemQuantity mocks base method.
+func (m *MockStore) UpdateCartItemQuantity(arg0 context.Context, arg1 db.UpdateCartItemQuantityParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCartItemQuantity", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCartItemQuantity indicates an expected call of UpdateCartItemQuantity.
+func (mr *MockStoreMockRecorder) UpdateCartItemQuantity(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCartItemQuantity", reflect.TypeOf((*MockStore)(nil).UpdateCartItemQuantity), arg0, arg1)
+}
+
+// UpdateCartItemTx mocks base method.
+func (m *MockStore) UpdateCartItemTx(arg0 context.Context, arg1 db.CartItemTxParams) (db.CartItemTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCartItemTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CartItemTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCartItemTx indicates an expected call of UpdateCartItemTx.
+func (mr *MockStoreMockRecorder) UpdateCartItemTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCartItemTx", reflect.TypeOf((*MockStore)(nil).UpdateCartItemTx), arg0, arg1)
+}
+
+// UpdateItemAvailability mocks base method.
+func (m *MockStore) UpdateItemAvailability(arg0 context.Context, arg1 db.UpdateItemAvailabilityParams) (db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemAvailability", arg0, arg1)
+	ret0, _ := ret[0].(db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemAvailability indicates an expected call of UpdateItemAvailability.
+func (mr *MockStoreMockRecorder) UpdateItemAvailability(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemAvailability", reflect.TypeOf((*MockStore)(nil).UpdateItemAvailability), arg0, arg1)
+}
+
+// UpdateItemCategory mocks base method.
+func (m *MockStore) UpdateItemCategory(arg0 context.Context, arg1 db.UpdateItemCategoryParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemCategory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemCategory indicates an expected call of UpdateItemCategory.
+func (mr *MockStoreMockRecorder) UpdateItemCategory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemCategory", reflect.TypeOf((*MockStore)(nil).UpdateItemCategory), arg0, arg1)
+}
+
+// UpdateItemDescription mocks base method.
+func (m *MockStore) UpdateItemDescription(arg0 context.Context, arg1 db.UpdateItemDescriptionParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemDescription", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemDescription indicates an expected call of UpdateItemDescription.
+func (mr *MockStoreMockRecorder) UpdateItemDescription(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemDescription", reflect.TypeOf((*MockStore)(nil).UpdateItemDescription), arg0, arg1)
+}
+
+// UpdateItemLimits mocks base method.
+func (m *MockStore) UpdateItemLimits(arg0 context.Context, arg1 db.UpdateItemLimitsParams) (db.Item, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemLimits", arg0, arg1)
+	ret0, _ := ret[0].(db.Item)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemLimits indicates an expected call of UpdateItemLimits.
+func (mr *MockStoreMockRecorder) UpdateItemLimits(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemLimits", reflect.TypeOf((*MockStore)(nil).UpdateItemLimits), arg0, arg1)
+}
+
+// UpdateItemPrice mocks base method.
+func (m *MockStore) UpdateItemPrice(arg0 context.Context, arg1 db.UpdateItemPriceParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemPrice", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateItemPrice indicates an expected call of UpdateItemPrice.
+func (mr *MockStoreMockRecorder) UpdateItemPrice(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemPrice", reflect.TypeOf((*MockStore)(nil).UpdateItemPrice), arg0, arg1)
+}
+
+// UpdateItemTx mocks base method.
+func (m *MockStore) UpdateItemTx(arg0 context.Context, arg1 db.UpdateItemTxParams) (db.UpdateItemTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateItemTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UpdateItemTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateItemTx indicates an expected call of UpdateItemTx.
+func (mr *MockStoreMockRecorder) UpdateItemTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateItemTx", reflect.TypeOf((*MockStore)(nil).UpdateItemTx), arg0, arg1)
+}
+
+// UpdateOrderStatus mocks base method.
+func (m *MockStore) UpdateOrderStatus(arg0 context.Context, arg1 db.UpdateOrderStatusParams) (db.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatus", arg0, arg1)
+	ret0, _ := ret[0].(db.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatus indicates an expected call of UpdateOrderStatus.
+func (mr *MockStoreMockRecorder) UpdateOrderStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatus", reflect.TypeOf((*MockStore)(nil).UpdateOrderStatus), arg0, arg1)
+}
+
+// UpdateOrderStatusTx mocks base method.
+func (m *MockStore) UpdateOrderStatusTx(arg0 context.Context, arg1 db.UpdateOrderStatusTxParams) (db.UpdateOrderStatusTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateOrderStatusTx", arg0, arg1)
+	ret0, _ := ret[0].(db.UpdateOrderStatusTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateOrderStatusTx indicates an expected call of UpdateOrderStatusTx.
+func (mr *MockStoreMockRecorder) UpdateOrderStatusTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateOrderStatusTx", reflect.TypeOf((*MockStore)(nil).UpdateOrderStatusTx), arg0, arg1)
+}
+
+// UpdatePromoCode mocks base method.
+func (m *MockStore) UpdatePromoCode(arg0 context.Context, arg1 db.UpdatePromoCodeParams) (db.PromoCode, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdatePromoCode", arg0, arg1)
+	ret0, _ := ret[0].(db.PromoCode)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdatePromoCode indicates an expected call of UpdatePromoCode.
+func (mr *MockStoreMockRecorder) UpdatePromoCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePromoCode", reflect.TypeOf((*MockStore)(nil).UpdatePromoCode), arg0, arg1)
+}
+
+// UpdateTransferStatus mocks base method.
+func (m *MockStore) UpdateTransferStatus(arg0 context.Context, arg1 db.UpdateTransferStatusParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTransferStatus", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTransferStatus indicates an expected call of UpdateTransferStatus.
+func (mr *MockStoreMockRecorder) UpdateTransferStatus(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTransferStatus", reflect.TypeOf((*MockStore)(nil).UpdateTransferStatus), arg0, arg1)
+}
@@ -0,0 +1,113 @@
+package storage
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+)
+
+// ThumbnailSize - наибольшая сторона миниатюры в пикселях
+const ThumbnailSize = 256
+
+// maxImagePixels защищает от изображений, которые при распаковке займут гигабайты памяти
+const maxImagePixels = 40_000_000
+
+// ErrUnsupportedImage - файл не является изображением JPEG или PNG
+var ErrUnsupportedImage = errors.New("unsupported image format, expected jpeg or png")
+
+// ProcessedImage - проверенное изображение и его миниатюра
+type ProcessedImage struct {
+	ContentType string
+	Ext         string
+	Width       int
+	Height      int
+
+	Thumbnail            []byte
+	ThumbnailContentType string
+	ThumbnailExt         string
+}
+
+// ProcessImage проверяет загруженный файл и готовит миниатюру.
+// PNG остается PNG, чтобы не потерять прозрачность, остальное сжимается в JPEG
+func ProcessImage(data []byte) (ProcessedImage, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil || (format != "jpeg" && format != "png") {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > maxImagePixels {
+		return ProcessedImage{}, fmt.Errorf("image is too large: %dx%d", config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ProcessedImage{}, ErrUnsupportedImage
+	}
+
+	result := ProcessedImage{
+		Width:  config.Width,
+		Height: config.Height,
+	}
+
+	var thumb bytes.Buffer
+	if format == "png" {
+		result.ContentType, result.Ext = "image/png", ".png"
+		result.ThumbnailContentType, result.ThumbnailExt = "image/png", ".png"
+		err = png.Encode(&thumb, Thumbnail(img, ThumbnailSize))
+	} else {
+		result.ContentType, result.Ext = "image/jpeg", ".jpg"
+		result.ThumbnailContentType, result.ThumbnailExt = "image/jpeg", ".jpg"
+		err = jpeg.Encode(&thumb, Thumbnail(img, ThumbnailSize), &jpeg.Options{Quality: 85})
+	}
+	if err != nil {
+		return ProcessedImage{}, fmt.Errorf("cannot encode thumbnail: %w", err)
+	}
+	result.Thumbnail = thumb.Bytes()
+
+	return result, nil
+}
+
+// Thumbnail уменьшает изображение так, чтобы большая сторона не превышала size,
+// усредняя исходные пиксели. Маленькие изображения возвращаются без изменений
+func Thumbnail(src image.Image, size int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= size && h <= size {
+		return src
+	}
+
+	dw, dh := size, h*size/w
+	if h > w {
+		dw, dh = w*size/h, size
+	}
+	dw, dh = max(dw, 1), max(dh, 1)
+
+	dst := image.NewRGBA64(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		sy0 := bounds.Min.Y + y*h/dh
+		sy1 := max(bounds.Min.Y+(y+1)*h/dh, sy0+1)
+		for x := 0; x < dw; x++ {
+			sx0 := bounds.Min.X + x*w/dw
+			sx1 := max(bounds.Min.X+(x+1)*w/dw, sx0+1)
+
+			var r, g, b, a, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					pr, pg, pb, pa := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(pr), g+uint64(pg), b+uint64(pb), a+uint64(pa)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
@@ -0,0 +1,39 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"path"
+)
+
+// ErrInvalidKey - ключ пустой или выходит за пределы хранилища
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// BlobStorage хранит файлы (изображения товаров) вне базы данных.
+// В базе хранятся только ключи, поэтому хранилище можно заменить
+// S3-совместимым, реализовав этот интерфейс
+type BlobStorage interface {
+	// Put сохраняет файл под ключом, существующий файл перезаписывается
+	Put(ctx context.Context, key string, data io.Reader, contentType string) error
+	// Delete удаляет файл; отсутствие файла ошибкой не считается
+	Delete(ctx context.Context, key string) error
+	// URL - адрес, по которому клиент скачивает файл
+	URL(key string) string
+}
+
+// NewKey собирает случайный ключ вида prefix/<hex>suffix
+func NewKey(prefix, suffix string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return path.Join(prefix, hex.EncodeToString(buf)+suffix), nil
+}
+
+// validKey проверяет, что ключ - относительный путь без переходов наверх
+func validKey(key string) bool {
+	return key != "" && path.Clean("/"+key) == "/"+key
+}
@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStorage(t *testing.T) {
+	root := t.TempDir()
+	blobs, err := NewLocalStorage(root, "media/")
+	require.NoError(t, err)
+
+	key, err := NewKey("items/1", ".jpg")
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(key, "items/1/"))
+
+	err = blobs.Put(context.Background(), key, strings.NewReader("data"), "image/jpeg")
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(root, filepath.FromSlash(key)))
+	require.NoError(t, err)
+	require.Equal(t, "data", string(data))
+	require.Equal(t, "/media/"+key, blobs.URL(key))
+
+	require.NoError(t, blobs.Delete(context.Background(), key))
+	// Повторное удаление не ошибка
+	require.NoError(t, blobs.Delete(context.Background(), key))
+
+	for _, bad := range []string{"", "../secret", "items/../../secret", "/etc/passwd", "items/"} {
+		err = blobs.Put(context.Background(), bad, strings.NewReader("data"), "text/plain")
+		require.ErrorIs(t, err, ErrInvalidKey, bad)
+	}
+}
+
+func TestProcessImage(t *testing.T) {
+	src := image.NewNRGBA(image.Rect(0, 0, 1024, 512))
+	for y := 0; y < 512; y++ {
+		for x := 0; x < 1024; x++ {
+			src.Set(x, y, color.NRGBA{R: 200, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, src))
+
+	processed, err := ProcessImage(buf.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, "image/png", processed.ContentType)
+	require.Equal(t, 1024, processed.Width)
+	require.Equal(t, 512, processed.Height)
+
+	thumb, err := png.Decode(bytes.NewReader(processed.Thumbnail))
+	require.NoError(t, err)
+	require.Equal(t, ThumbnailSize, thumb.Bounds().Dx())
+	require.Equal(t, ThumbnailSize/2, thumb.Bounds().Dy())
+	r, _, _, a := thumb.At(10, 10).RGBA()
+	require.Equal(t, uint32(200), r>>8)
+	require.Equal(t, uint32(255), a>>8)
+
+	_, err = ProcessImage([]byte("not an image"))
+	require.ErrorIs(t, err, ErrUnsupportedImage)
+}
+
+func TestThumbnailKeepsSmallImage(t *testing.T) {
+	src := image.NewRGBA(image.Rect(0, 0, 100, 50))
+	require.Equal(t, image.Image(src), Thumbnail(src, ThumbnailSize))
+}
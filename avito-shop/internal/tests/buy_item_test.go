@@ -28,7 +28,10 @@ func TestE2EMerchandisePurchase(t *testing.T) {
 		AccessTokenDuration: time.Hour * 24,
 	}
 
-	server, err := api.NewServer(testStore, config)
+	server, err := api.NewServer(testStore, api.Config{
+		TokenConfig:      config,
+		OnboardingConfig: api.OnboardingConfig{WelcomeBalance: 1000},
+	})
 	require.NoError(t, err)
 	require.NotNil(t, server)
 	require.NotNil(t, server.Router)
@@ -22,7 +22,10 @@ func TestE2ETransferCoins(t *testing.T) {
 		AccessTokenDuration: time.Hour * 24,
 	}
 
-	server, err := api.NewServer(testStore, config)
+	server, err := api.NewServer(testStore, api.Config{
+		TokenConfig:      config,
+		OnboardingConfig: api.OnboardingConfig{WelcomeBalance: 1000},
+	})
 	require.NoError(t, err)
 
 	// Создаем двух тестовых пользователей
@@ -1,13 +1,51 @@
 package util
 
-import "github.com/spf13/viper"
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
 
 type Config struct {
-	DBDriver     string `mapstructure:"DB_DRIVER"`
-	DBSource     string `mapstructure:"DB_SOURCE"`
-	DBSourceTest string `mapstructure:"DB_SOURCE_TEST"`
-	Address      string `mapstructure:"ADDRESS"`
-	TokenKey     string `mapstructure:"TOKEN_KEY"`
+	DBDriver           string        `mapstructure:"DB_DRIVER"`
+	DBSource           string        `mapstructure:"DB_SOURCE"`
+	DBSourceTest       string        `mapstructure:"DB_SOURCE_TEST"`
+	Address            string        `mapstructure:"ADDRESS"`
+	TokenKey           string        `mapstructure:"TOKEN_KEY"`
+	TransferHoldPeriod time.Duration `mapstructure:"TRANSFER_HOLD_PERIOD"`
+	SettlementInterval time.Duration `mapstructure:"SETTLEMENT_INTERVAL"`
+	// Лимиты исходящих переводов, 0 - без ограничения
+	TransferMaxAmount    int32 `mapstructure:"TRANSFER_MAX_AMOUNT"`
+	TransferDailyLimit   int32 `mapstructure:"TRANSFER_DAILY_LIMIT"`
+	TransferWeeklyLimit  int32 `mapstructure:"TRANSFER_WEEKLY_LIMIT"`
+	TransferMaxPerMinute int32 `mapstructure:"TRANSFER_MAX_PER_MINUTE"`
+	// Приветственное начисление: общее и по отделам в формате "отдел:сумма,отдел:сумма"
+	WelcomeBalance             int32  `mapstructure:"WELCOME_BALANCE"`
+	WelcomeBalanceByDepartment string `mapstructure:"WELCOME_BALANCE_BY_DEPARTMENT"`
+	// Срок действия начисленных монет (0 - бессрочно) и период запуска их сжигания
+	CoinTTL            time.Duration `mapstructure:"COIN_TTL"`
+	CoinExpiryInterval time.Duration `mapstructure:"COIN_EXPIRY_INTERVAL"`
+	// Окно самостоятельного возврата покупок, 0 - возврат только через администратора
+	ReturnWindow time.Duration `mapstructure:"RETURN_WINDOW"`
+	// Период проверки запланированных изменений цен
+	PriceScheduleInterval time.Duration `mapstructure:"PRICE_SCHEDULE_INTERVAL"`
+	// Период проверки списков желаний: на какие товары стало хватать баланса
+	WishlistCheckInterval time.Duration `mapstructure:"WISHLIST_CHECK_INTERVAL"`
+	// Период подведения итогов закончившихся аукционов
+	AuctionCloseInterval time.Duration `mapstructure:"AUCTION_CLOSE_INTERVAL"`
+	// Период выбора победителей розыгрышей, продажа билетов по которым закончилась
+	RaffleDrawInterval time.Duration `mapstructure:"RAFFLE_DRAW_INTERVAL"`
+	// Комиссия магазина с продаж на маркетплейсе в процентах от цены, 0 - без комиссии
+	MarketplaceCommissionPercent int32 `mapstructure:"MARKETPLACE_COMMISSION_PERCENT"`
+	// Срок резерва остатка под позиции корзины (0 - без резерва) и период снятия истекших резервов
+	CartReservationTTL           time.Duration `mapstructure:"CART_RESERVATION_TTL"`
+	CartReservationSweepInterval time.Duration `mapstructure:"CART_RESERVATION_SWEEP_INTERVAL"`
+	// Каталог изображений товаров и путь, по которому сервер их раздает
+	MediaDir       string `mapstructure:"MEDIA_DIR"`
+	MediaURLPrefix string `mapstructure:"MEDIA_URL_PREFIX"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -25,3 +63,26 @@ func LoadConfig(path string) (config Config, err error) {
 	err = viper.Unmarshal(&config)
 	return
 }
+
+// ParseAmounts разбирает строку вида "engineering:1500,sales:1200" в map
+func ParseAmounts(value string) (map[string]int32, error) {
+	amounts := make(map[string]int32)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		key, rawAmount, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid amount pair %q", pair)
+		}
+
+		amount, err := strconv.ParseInt(strings.TrimSpace(rawAmount), 10, 32)
+		if err != nil || amount < 0 {
+			return nil, fmt.Errorf("invalid amount in pair %q", pair)
+		}
+		amounts[strings.TrimSpace(key)] = int32(amount)
+	}
+	return amounts, nil
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// auctionBatchSize - сколько аукционов закрывается за один проход
+const auctionBatchSize = 50
+
+// AuctionWorker периодически подводит итоги аукционов, прием ставок по которым закончился
+type AuctionWorker struct {
+	store    db.Store
+	interval time.Duration
+}
+
+func NewAuctionWorker(store db.Store, interval time.Duration) *AuctionWorker {
+	return &AuctionWorker{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start раз в interval подводит итоги закончившихся аукционов до отмены контекста
+func (worker *AuctionWorker) Start(ctx context.Context) {
+	run(ctx, worker.interval, "auction worker", func(ctx context.Context, now time.Time) error {
+		_, err := worker.closeDue(ctx, now)
+		return err
+	})
+}
+
+// closeDue подводит итог каждого закончившегося аукциона в отдельной транзакции:
+// ошибка одного аукциона не мешает закрыть остальные. Возвращает число проданных лотов
+func (worker *AuctionWorker) closeDue(ctx context.Context, now time.Time) (int, error) {
+	ids, err := worker.store.ListDueAuctions(ctx, db.ListDueAuctionsParams{
+		Now:      pgtype.Timestamp{Time: now, Valid: true},
+		RowLimit: auctionBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sold := 0
+	for _, id := range ids {
+		result, err := worker.store.SettleAuctionTx(ctx, db.SettleAuctionTxParams{AuctionID: id, Now: now})
+		if err != nil {
+			log.Printf("auction worker: auction %d: %v", id, err)
+			continue
+		}
+		if result.Auction.Status == db.AuctionStatusSettled {
+			sold++
+		}
+	}
+	return sold, nil
+}
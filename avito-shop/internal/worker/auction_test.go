@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloseDueAuctions(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name        string
+		buildStubs  func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, sold int, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDueAuctions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int32{1, 2}, nil)
+				store.EXPECT().
+					SettleAuctionTx(gomock.Any(), db.SettleAuctionTxParams{AuctionID: 1, Now: now}).
+					Times(1).
+					Return(db.SettleAuctionTxResult{Auction: db.Auction{ID: 1, Status: db.AuctionStatusSettled}}, nil)
+				store.EXPECT().
+					SettleAuctionTx(gomock.Any(), db.SettleAuctionTxParams{AuctionID: 2, Now: now}).
+					Times(1).
+					Return(db.SettleAuctionTxResult{Auction: db.Auction{ID: 2, Status: db.AuctionStatusUnsold}}, nil)
+			},
+			checkResult: func(t *testing.T, sold int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, sold)
+			},
+		},
+		{
+			name: "SettleErrorDoesNotStopOthers",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDueAuctions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int32{1, 2}, nil)
+				store.EXPECT().
+					SettleAuctionTx(gomock.Any(), db.SettleAuctionTxParams{AuctionID: 1, Now: now}).
+					Times(1).
+					Return(db.SettleAuctionTxResult{}, errors.New("database error"))
+				store.EXPECT().
+					SettleAuctionTx(gomock.Any(), db.SettleAuctionTxParams{AuctionID: 2, Now: now}).
+					Times(1).
+					Return(db.SettleAuctionTxResult{Auction: db.Auction{ID: 2, Status: db.AuctionStatusSettled}}, nil)
+			},
+			checkResult: func(t *testing.T, sold int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, sold)
+			},
+		},
+		{
+			name: "ListError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDueAuctions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("database error"))
+				store.EXPECT().
+					SettleAuctionTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, sold int, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewAuctionWorker(store, time.Minute)
+			sold, err := worker.closeDue(context.Background(), now)
+			tc.checkResult(t, sold, err)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package worker
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// expiryBatchSize - сколько пользователей обрабатывается за один проход
+const expiryBatchSize = 100
+
+// ExpiryWorker периодически сжигает монеты, срок действия которых истек
+type ExpiryWorker struct {
+	store    db.Store
+	interval time.Duration
+}
+
+func NewExpiryWorker(store db.Store, interval time.Duration) *ExpiryWorker {
+	return &ExpiryWorker{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start сразу сжигает монеты, истекшие пока сервис не работал, а затем повторяет
+// это раз в interval до отмены контекста
+func (worker *ExpiryWorker) Start(ctx context.Context) {
+	expire := func(ctx context.Context, now time.Time) error {
+		_, err := worker.expireDue(ctx, now)
+		return err
+	}
+
+	if err := expire(ctx, time.Now()); err != nil {
+		log.Printf("expiry worker: %v", err)
+	}
+	run(ctx, worker.interval, "expiry worker", expire)
+}
+
+// expireDue сжигает просроченные лоты у всех пользователей и возвращает
+// количество записанных операций сгорания. Каждый пользователь обрабатывается
+// в отдельной транзакции, чтобы не держать блокировки на всех сразу.
+func (worker *ExpiryWorker) expireDue(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		userIDs, err := worker.store.ListUsersWithExpiredLots(ctx, db.ListUsersWithExpiredLotsParams{
+			Now:      pgtype.Timestamp{Time: now, Valid: true},
+			MaxUsers: expiryBatchSize,
+		})
+		if err != nil {
+			return total, err
+		}
+
+		for _, userID := range userIDs {
+			result, err := worker.store.ExpireCoinsTx(ctx, db.ExpireCoinsTxParams{
+				UserID: userID,
+				Now:    now,
+			})
+			if err != nil {
+				return total, err
+			}
+			total += len(result.Expired)
+		}
+
+		if len(userIDs) < expiryBatchSize {
+			return total, nil
+		}
+	}
+}
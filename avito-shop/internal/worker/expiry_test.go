@@ -0,0 +1,131 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireDue(t *testing.T) {
+	now := time.Now()
+	listArg := db.ListUsersWithExpiredLotsParams{
+		Now:      pgtype.Timestamp{Time: now, Valid: true},
+		MaxUsers: expiryBatchSize,
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkCount func(t *testing.T, count int, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListUsersWithExpiredLots(gomock.Any(), listArg).
+					Times(1).
+					Return([]int32{1, 2}, nil)
+				store.EXPECT().
+					ExpireCoinsTx(gomock.Any(), db.ExpireCoinsTxParams{UserID: 1, Now: now}).
+					Times(1).
+					Return(db.ExpireCoinsTxResult{Expired: make([]db.Transaction, 2)}, nil)
+				store.EXPECT().
+					ExpireCoinsTx(gomock.Any(), db.ExpireCoinsTxParams{UserID: 2, Now: now}).
+					Times(1).
+					Return(db.ExpireCoinsTxResult{Expired: make([]db.Transaction, 1)}, nil)
+			},
+			checkCount: func(t *testing.T, count int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 3, count)
+			},
+		},
+		{
+			name: "OK_NothingExpired",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListUsersWithExpiredLots(gomock.Any(), listArg).
+					Times(1).
+					Return([]int32{}, nil)
+				store.EXPECT().
+					ExpireCoinsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkCount: func(t *testing.T, count int, err error) {
+				require.NoError(t, err)
+				require.Zero(t, count)
+			},
+		},
+		{
+			name: "StoreError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListUsersWithExpiredLots(gomock.Any(), listArg).
+					Times(1).
+					Return([]int32{1}, nil)
+				store.EXPECT().
+					ExpireCoinsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ExpireCoinsTxResult{}, errors.New("database error"))
+			},
+			checkCount: func(t *testing.T, count int, err error) {
+				require.Error(t, err)
+				require.Zero(t, count)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewExpiryWorker(store, time.Hour)
+			count, err := worker.expireDue(context.Background(), now)
+			tc.checkCount(t, count, err)
+		})
+	}
+}
+
+func TestExpiryWorkerStartSweepsAtOnce(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Интервал больше времени теста: проход может случиться только при запуске
+	swept := make(chan struct{})
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ListUsersWithExpiredLots(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(context.Context, db.ListUsersWithExpiredLotsParams) ([]int32, error) {
+			close(swept)
+			return nil, nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewExpiryWorker(store, time.Hour).Start(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-swept:
+	case <-time.After(time.Second):
+		t.Fatal("expiry worker did not sweep at startup")
+	}
+	cancel()
+	<-done
+}
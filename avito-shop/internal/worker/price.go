@@ -0,0 +1,59 @@
+package worker
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// PriceWorker периодически вводит в действие запланированные цены товаров
+type PriceWorker struct {
+	store    db.Store
+	interval time.Duration
+}
+
+func NewPriceWorker(store db.Store, interval time.Duration) *PriceWorker {
+	return &PriceWorker{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start раз в interval вводит в действие наступившие цены до отмены контекста
+func (worker *PriceWorker) Start(ctx context.Context) {
+	run(ctx, worker.interval, "price worker", func(ctx context.Context, now time.Time) error {
+		_, err := worker.applyDue(ctx, now)
+		return err
+	})
+}
+
+// applyDue переносит наступившие цены в каталог, уведомляет о снижении цен
+// и возвращает измененные товары. Обновление выполняется одним запросом,
+// поэтому отдельная транзакция не нужна.
+func (worker *PriceWorker) applyDue(ctx context.Context, now time.Time) ([]db.ApplyDueItemPricesRow, error) {
+	changed, err := worker.store.ApplyDueItemPrices(ctx, pgtype.Timestamp{Time: now, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range changed {
+		log.Printf("price worker: %s price changed from %d to %d", item.Name, item.OldPrice, item.Price)
+		if item.Price >= item.OldPrice {
+			continue
+		}
+
+		// Цена уже изменена, поэтому ошибка уведомлений не прерывает обработку остальных товаров
+		_, err := worker.store.CreatePriceDropNotifications(ctx, db.CreatePriceDropNotificationsParams{
+			OldPrice: item.OldPrice,
+			Price:    item.Price,
+			ItemID:   item.ID,
+		})
+		if err != nil {
+			log.Printf("price worker: cannot notify about %s price drop: %v", item.Name, err)
+		}
+	}
+	return changed, nil
+}
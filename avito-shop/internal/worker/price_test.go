@@ -0,0 +1,115 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyDuePrices(t *testing.T) {
+	now := time.Now()
+	arg := pgtype.Timestamp{Time: now, Valid: true}
+
+	testCases := []struct {
+		name         string
+		buildStubs   func(store *mockdb.MockStore)
+		checkChanged func(t *testing.T, changed []db.ApplyDueItemPricesRow, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyDueItemPrices(gomock.Any(), arg).
+					Times(1).
+					Return([]db.ApplyDueItemPricesRow{
+						{ID: 1, Name: "t-shirt", OldPrice: 80, Price: 60},
+						{ID: 2, Name: "cup", OldPrice: 20, Price: 25},
+					}, nil)
+				// О повышении цены не уведомляем
+				store.EXPECT().
+					CreatePriceDropNotifications(gomock.Any(), db.CreatePriceDropNotificationsParams{
+						OldPrice: 80,
+						Price:    60,
+						ItemID:   1,
+					}).
+					Times(1).
+					Return(int64(2), nil)
+			},
+			checkChanged: func(t *testing.T, changed []db.ApplyDueItemPricesRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, changed, 2)
+				require.Equal(t, int32(60), changed[0].Price)
+			},
+		},
+		{
+			name: "OK_NotificationError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyDueItemPrices(gomock.Any(), arg).
+					Times(1).
+					Return([]db.ApplyDueItemPricesRow{
+						{ID: 1, Name: "t-shirt", OldPrice: 80, Price: 60},
+						{ID: 3, Name: "pen", OldPrice: 10, Price: 5},
+					}, nil)
+				// Ошибка уведомления по одному товару не мешает остальным
+				store.EXPECT().
+					CreatePriceDropNotifications(gomock.Any(), gomock.Any()).
+					Times(2).
+					Return(int64(0), errors.New("database error"))
+			},
+			checkChanged: func(t *testing.T, changed []db.ApplyDueItemPricesRow, err error) {
+				require.NoError(t, err)
+				require.Len(t, changed, 2)
+			},
+		},
+		{
+			name: "OK_NothingDue",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyDueItemPrices(gomock.Any(), arg).
+					Times(1).
+					Return([]db.ApplyDueItemPricesRow{}, nil)
+			},
+			checkChanged: func(t *testing.T, changed []db.ApplyDueItemPricesRow, err error) {
+				require.NoError(t, err)
+				require.Empty(t, changed)
+			},
+		},
+		{
+			name: "StoreError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ApplyDueItemPrices(gomock.Any(), arg).
+					Times(1).
+					Return(nil, errors.New("database error"))
+			},
+			checkChanged: func(t *testing.T, changed []db.ApplyDueItemPricesRow, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewPriceWorker(store, time.Minute)
+			changed, err := worker.applyDue(context.Background(), now)
+			tc.checkChanged(t, changed, err)
+		})
+	}
+}
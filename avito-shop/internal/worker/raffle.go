@@ -0,0 +1,57 @@
+package worker
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// raffleBatchSize - сколько розыгрышей проводится за один проход
+const raffleBatchSize = 50
+
+// RaffleWorker периодически выбирает победителей розыгрышей, продажа билетов по которым закончилась
+type RaffleWorker struct {
+	store    db.Store
+	interval time.Duration
+}
+
+func NewRaffleWorker(store db.Store, interval time.Duration) *RaffleWorker {
+	return &RaffleWorker{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start раз в interval проводит закончившиеся розыгрыши до отмены контекста
+func (worker *RaffleWorker) Start(ctx context.Context) {
+	run(ctx, worker.interval, "raffle worker", func(ctx context.Context, now time.Time) error {
+		_, err := worker.drawDue(ctx, now)
+		return err
+	})
+}
+
+// drawDue проводит каждый закончившийся розыгрыш в отдельной транзакции:
+// ошибка одного розыгрыша не мешает провести остальные. Возвращает число выданных призов
+func (worker *RaffleWorker) drawDue(ctx context.Context, now time.Time) (int, error) {
+	ids, err := worker.store.ListDueRaffles(ctx, db.ListDueRafflesParams{
+		Now:      pgtype.Timestamp{Time: now, Valid: true},
+		RowLimit: raffleBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	prizes := 0
+	for _, id := range ids {
+		result, err := worker.store.DrawRaffleTx(ctx, db.DrawRaffleTxParams{RaffleID: id, Now: now})
+		if err != nil {
+			log.Printf("raffle worker: raffle %d: %v", id, err)
+			continue
+		}
+		prizes += len(result.Purchases)
+	}
+	return prizes, nil
+}
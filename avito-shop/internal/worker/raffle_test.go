@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrawDueRaffles(t *testing.T) {
+	now := time.Now()
+
+	testCases := []struct {
+		name        string
+		buildStubs  func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, prizes int, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDueRaffles(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int32{1, 2}, nil)
+				store.EXPECT().
+					DrawRaffleTx(gomock.Any(), db.DrawRaffleTxParams{RaffleID: 1, Now: now}).
+					Times(1).
+					Return(db.DrawRaffleTxResult{
+						Raffle:    db.Raffle{ID: 1, Status: db.RaffleStatusDrawn},
+						Purchases: []db.Purchase{{ID: 10}, {ID: 11}},
+					}, nil)
+				store.EXPECT().
+					DrawRaffleTx(gomock.Any(), db.DrawRaffleTxParams{RaffleID: 2, Now: now}).
+					Times(1).
+					Return(db.DrawRaffleTxResult{Raffle: db.Raffle{ID: 2, Status: db.RaffleStatusDrawn}}, nil)
+			},
+			checkResult: func(t *testing.T, prizes int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, prizes)
+			},
+		},
+		{
+			name: "DrawErrorDoesNotStopOthers",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDueRaffles(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]int32{1, 2}, nil)
+				store.EXPECT().
+					DrawRaffleTx(gomock.Any(), db.DrawRaffleTxParams{RaffleID: 1, Now: now}).
+					Times(1).
+					Return(db.DrawRaffleTxResult{}, errors.New("database error"))
+				store.EXPECT().
+					DrawRaffleTx(gomock.Any(), db.DrawRaffleTxParams{RaffleID: 2, Now: now}).
+					Times(1).
+					Return(db.DrawRaffleTxResult{Purchases: []db.Purchase{{ID: 10}}}, nil)
+			},
+			checkResult: func(t *testing.T, prizes int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, prizes)
+			},
+		},
+		{
+			name: "ListError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ListDueRaffles(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, errors.New("database error"))
+				store.EXPECT().
+					DrawRaffleTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, prizes int, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewRaffleWorker(store, time.Minute)
+			prizes, err := worker.drawDue(context.Background(), now)
+			tc.checkResult(t, prizes, err)
+		})
+	}
+}
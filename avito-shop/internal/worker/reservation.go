@@ -0,0 +1,45 @@
+package worker
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"context"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ReservationWorker периодически снимает истекшие резервы остатков под позиции корзин
+type ReservationWorker struct {
+	store    db.Store
+	interval time.Duration
+}
+
+func NewReservationWorker(store db.Store, interval time.Duration) *ReservationWorker {
+	return &ReservationWorker{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start раз в interval снимает истекшие резервы корзин до отмены контекста
+func (worker *ReservationWorker) Start(ctx context.Context) {
+	run(ctx, worker.interval, "reservation worker", func(ctx context.Context, now time.Time) error {
+		_, err := worker.releaseExpired(ctx, now)
+		return err
+	})
+}
+
+// releaseExpired снимает резервы, срок которых истек к моменту now, и возвращает их число.
+// Истекший резерв и так не учитывается при покупках, сброс освобождает сами записи.
+// Обновление выполняется одним запросом, поэтому отдельная транзакция не нужна.
+func (worker *ReservationWorker) releaseExpired(ctx context.Context, now time.Time) (int64, error) {
+	released, err := worker.store.ReleaseExpiredReservations(ctx, pgtype.Timestamp{Time: now, Valid: true})
+	if err != nil {
+		return 0, err
+	}
+	if released > 0 {
+		log.Printf("reservation worker: released %d expired cart reservations", released)
+	}
+	return released, nil
+}
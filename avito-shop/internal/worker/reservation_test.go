@@ -0,0 +1,68 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleaseExpiredReservations(t *testing.T) {
+	now := time.Now()
+	arg := pgtype.Timestamp{Time: now, Valid: true}
+
+	testCases := []struct {
+		name        string
+		buildStubs  func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, released int64, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReleaseExpiredReservations(gomock.Any(), arg).
+					Times(1).
+					Return(int64(3), nil)
+			},
+			checkResult: func(t *testing.T, released int64, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(3), released)
+			},
+		},
+		{
+			name: "StoreError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ReleaseExpiredReservations(gomock.Any(), arg).
+					Times(1).
+					Return(int64(0), errors.New("database error"))
+			},
+			checkResult: func(t *testing.T, released int64, err error) {
+				require.Error(t, err)
+				require.Zero(t, released)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewReservationWorker(store, time.Minute)
+			released, err := worker.releaseExpired(context.Background(), now)
+			tc.checkResult(t, released, err)
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"context"
+	"time"
+)
+
+// settlementBatchSize - сколько переводов зачисляется за одну транзакцию
+const settlementBatchSize = 100
+
+// SettlementWorker периодически зачисляет получателям отложенные переводы,
+// у которых истекло окно отмены
+type SettlementWorker struct {
+	store    db.Store
+	interval time.Duration
+}
+
+func NewSettlementWorker(store db.Store, interval time.Duration) *SettlementWorker {
+	return &SettlementWorker{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start раз в interval зачисляет созревшие переводы до отмены контекста
+func (worker *SettlementWorker) Start(ctx context.Context) {
+	run(ctx, worker.interval, "settlement worker", func(ctx context.Context, now time.Time) error {
+		_, err := worker.settleDue(ctx, now)
+		return err
+	})
+}
+
+// settleDue зачисляет все созревшие переводы пачками и возвращает их количество
+func (worker *SettlementWorker) settleDue(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		result, err := worker.store.SettleTransfersTx(ctx, db.SettleTransfersTxParams{
+			Now:   now,
+			Limit: settlementBatchSize,
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += len(result.Settled)
+		if len(result.Settled) < settlementBatchSize {
+			return total, nil
+		}
+	}
+}
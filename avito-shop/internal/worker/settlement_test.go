@@ -0,0 +1,89 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	db "avito-shop/internal/db/sqlc"
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettleDue(t *testing.T) {
+	now := time.Now()
+	arg := db.SettleTransfersTxParams{
+		Now:   now,
+		Limit: settlementBatchSize,
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		checkCount func(t *testing.T, count int, err error)
+	}{
+		{
+			name: "OK_SingleBatch",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SettleTransfersTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.SettleTransfersTxResult{Settled: make([]db.Transaction, 3)}, nil)
+			},
+			checkCount: func(t *testing.T, count int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 3, count)
+			},
+		},
+		{
+			name: "OK_MultipleBatches",
+			buildStubs: func(store *mockdb.MockStore) {
+				// Полная пачка означает, что могут остаться еще переводы
+				gomock.InOrder(
+					store.EXPECT().
+						SettleTransfersTx(gomock.Any(), arg).
+						Return(db.SettleTransfersTxResult{Settled: make([]db.Transaction, settlementBatchSize)}, nil),
+					store.EXPECT().
+						SettleTransfersTx(gomock.Any(), arg).
+						Return(db.SettleTransfersTxResult{}, nil),
+				)
+			},
+			checkCount: func(t *testing.T, count int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, settlementBatchSize, count)
+			},
+		},
+		{
+			name: "StoreError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SettleTransfersTx(gomock.Any(), arg).
+					Times(1).
+					Return(db.SettleTransfersTxResult{}, errors.New("database error"))
+			},
+			checkCount: func(t *testing.T, count int, err error) {
+				require.Error(t, err)
+				require.Zero(t, count)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewSettlementWorker(store, time.Minute)
+			count, err := worker.settleDue(context.Background(), now)
+			tc.checkCount(t, count, err)
+		})
+	}
+}
@@ -0,0 +1,44 @@
+package worker
+
+import (
+	db "avito-shop/internal/db/sqlc"
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// WishlistWorker периодически уведомляет пользователей о товарах из списка желаний,
+// на которые стало хватать баланса. Баланс меняется во многих операциях,
+// поэтому проверка выполняется отдельно от них
+type WishlistWorker struct {
+	store    db.Store
+	interval time.Duration
+}
+
+func NewWishlistWorker(store db.Store, interval time.Duration) *WishlistWorker {
+	return &WishlistWorker{
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start раз в interval проверяет списки желаний до отмены контекста
+func (worker *WishlistWorker) Start(ctx context.Context) {
+	run(ctx, worker.interval, "wishlist worker", func(ctx context.Context, now time.Time) error {
+		_, err := worker.notifyAffordable(ctx, now)
+		return err
+	})
+}
+
+// notifyAffordable сначала снимает отметки с товаров, на которые баланса снова
+// не хватает, чтобы следующее пополнение уведомило повторно, затем уведомляет
+// о товарах, на которые баланса стало хватать. Баланс считается по монетам,
+// не истекшим к моменту now, как и в GET /api/wishlist. Возвращает число уведомлений
+func (worker *WishlistWorker) notifyAffordable(ctx context.Context, now time.Time) (int64, error) {
+	ts := pgtype.Timestamp{Time: now, Valid: true}
+	if _, err := worker.store.ResetUncoveredWishlistItems(ctx, ts); err != nil {
+		return 0, err
+	}
+	return worker.store.CreateAffordableNotifications(ctx, ts)
+}
@@ -0,0 +1,72 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mockdb "avito-shop/internal/mock"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifyAffordable(t *testing.T) {
+	testCases := []struct {
+		name        string
+		buildStubs  func(store *mockdb.MockStore)
+		checkResult func(t *testing.T, notified int64, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				gomock.InOrder(
+					store.EXPECT().
+						ResetUncoveredWishlistItems(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(int64(1), nil),
+					store.EXPECT().
+						CreateAffordableNotifications(gomock.Any(), gomock.Any()).
+						Times(1).
+						Return(int64(3), nil),
+				)
+			},
+			checkResult: func(t *testing.T, notified int64, err error) {
+				require.NoError(t, err)
+				require.Equal(t, int64(3), notified)
+			},
+		},
+		{
+			name: "ResetError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetUncoveredWishlistItems(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), errors.New("database error"))
+				store.EXPECT().
+					CreateAffordableNotifications(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResult: func(t *testing.T, notified int64, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewWishlistWorker(store, time.Minute)
+			notified, err := worker.notifyAffordable(context.Background(), time.Now())
+			tc.checkResult(t, notified, err)
+		})
+	}
+}
@@ -0,0 +1,26 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// run вызывает fn раз в interval, пока не отменен ctx; ошибка попадает в лог
+// с именем воркера и не останавливает следующие запуски. Блокируется до отмены
+// контекста, поэтому воркеры запускаются в отдельной горутине
+func run(ctx context.Context, interval time.Duration, name string, fn func(ctx context.Context, now time.Time) error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := fn(ctx, time.Now()); err != nil {
+				log.Printf("%s: %v", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// Ошибка не останавливает следующие запуски
+		run(ctx, time.Millisecond, "test worker", func(context.Context, time.Time) error {
+			calls++
+			if calls == 3 {
+				cancel()
+			}
+			return errors.New("temporary failure")
+		})
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("run did not stop after the context was cancelled")
+	}
+	require.GreaterOrEqual(t, calls, 3)
+}
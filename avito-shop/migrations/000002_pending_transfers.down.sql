@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_transactions_pending_settles_at;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS resolved_at,
+    DROP COLUMN IF EXISTS settles_at,
+    DROP COLUMN IF EXISTS status;
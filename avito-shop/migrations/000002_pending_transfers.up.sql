@@ -0,0 +1,10 @@
+-- Статус перевода: завершенные переводы видны в истории, отложенные
+-- удерживаются (эскроу) до наступления settles_at и могут быть отменены отправителем
+ALTER TABLE transactions
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'completed'
+        CHECK (status IN ('completed', 'pending', 'cancelled')),
+    ADD COLUMN settles_at TIMESTAMP,
+    ADD COLUMN resolved_at TIMESTAMP;
+
+-- Индекс для воркера, зачисляющего отложенные переводы
+CREATE INDEX idx_transactions_pending_settles_at ON transactions (settles_at) WHERE status = 'pending';
@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_transactions_kind;
+
+ALTER TABLE transactions
+    DROP COLUMN IF EXISTS created_by,
+    DROP COLUMN IF EXISTS comment,
+    DROP COLUMN IF EXISTS reason_code,
+    DROP COLUMN IF EXISTS kind;
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS role;
@@ -0,0 +1,15 @@
+-- Роль пользователя: администраторы могут начислять и списывать монеты
+ALTER TABLE users
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'user'
+        CHECK (role IN ('user', 'admin'));
+
+-- Вид операции: перевод между пользователями или корректировка от имени системы.
+-- У начисления нет отправителя, у списания нет получателя (системный счет).
+ALTER TABLE transactions
+    ADD COLUMN kind VARCHAR(20) NOT NULL DEFAULT 'transfer'
+        CHECK (kind IN ('transfer', 'grant', 'deduction')),
+    ADD COLUMN reason_code VARCHAR(50),
+    ADD COLUMN comment TEXT,
+    ADD COLUMN created_by INTEGER REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_transactions_kind ON transactions (kind);
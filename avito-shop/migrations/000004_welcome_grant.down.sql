@@ -0,0 +1,5 @@
+DELETE FROM transactions WHERE kind = 'grant' AND reason_code = 'welcome';
+
+ALTER TABLE IF EXISTS users DROP COLUMN IF EXISTS department;
+
+ALTER TABLE IF EXISTS users ALTER COLUMN balance SET DEFAULT 1000;
@@ -0,0 +1,12 @@
+-- Стартовый баланс больше не задается значением по умолчанию:
+-- пользователь создается с нулевым балансом и получает приветственное начисление
+ALTER TABLE users ALTER COLUMN balance SET DEFAULT 0;
+
+-- Отдел пользователя, от которого может зависеть размер приветственного начисления
+ALTER TABLE users ADD COLUMN department VARCHAR(100);
+
+-- Записываем уже выданные стартовые 1000 монет как явные начисления,
+-- чтобы баланс сходился с историей операций
+INSERT INTO transactions (receiver_id, amount, kind, reason_code, comment, timestamp)
+SELECT id, 1000, 'grant', 'welcome', 'initial balance', created_at
+FROM users;
@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS transaction_lots;
+DROP TABLE IF EXISTS coin_lots;
+
+DELETE FROM transactions WHERE kind = 'expiry';
+
+ALTER TABLE IF EXISTS transactions DROP CONSTRAINT IF EXISTS transactions_kind_check;
+ALTER TABLE IF EXISTS transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('transfer', 'grant', 'deduction'));
@@ -0,0 +1,44 @@
+-- Лоты монет: каждое поступление хранится отдельно со своим сроком действия.
+-- Сумма remaining по лотам пользователя равна users.balance.
+CREATE TABLE coin_lots (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    source_transaction_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    remaining INTEGER NOT NULL CHECK (remaining >= 0 AND remaining <= amount),
+    acquired_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    -- NULL - монеты без срока действия
+    expires_at TIMESTAMP
+);
+
+CREATE INDEX idx_coin_lots_user_open ON coin_lots (user_id, expires_at) WHERE remaining > 0;
+CREATE INDEX idx_coin_lots_expires_at ON coin_lots (expires_at) WHERE remaining > 0;
+
+-- Части лотов, удерживаемые отложенным переводом: при зачислении или отмене
+-- монеты возвращаются в лоты с исходным сроком действия
+CREATE TABLE transaction_lots (
+    id SERIAL PRIMARY KEY,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    expires_at TIMESTAMP
+);
+
+CREATE INDEX idx_transaction_lots_transaction_id ON transaction_lots (transaction_id);
+
+-- Сгорание монет - отдельный вид операции, монеты уходят на системный счет
+ALTER TABLE transactions DROP CONSTRAINT transactions_kind_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('transfer', 'grant', 'deduction', 'expiry'));
+
+-- Текущие балансы превращаются в лоты со сроком действия 12 месяцев
+INSERT INTO coin_lots (user_id, amount, remaining, acquired_at, expires_at)
+SELECT id, balance, balance, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP + INTERVAL '12 months'
+FROM users
+WHERE balance > 0;
+
+
+-- Монеты, удерживаемые отложенными переводами, получают тот же срок
+INSERT INTO transaction_lots (transaction_id, amount, expires_at)
+SELECT id, amount, CURRENT_TIMESTAMP + INTERVAL '12 months'
+FROM transactions
+WHERE status = 'pending';
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_transactions_purchase_id;
+
+DELETE FROM transactions WHERE kind = 'refund';
+
+ALTER TABLE IF EXISTS transactions DROP CONSTRAINT IF EXISTS transactions_kind_check;
+ALTER TABLE IF EXISTS transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('transfer', 'grant', 'deduction', 'expiry'));
+
+ALTER TABLE IF EXISTS transactions
+    DROP COLUMN IF EXISTS purchase_id;
+
+ALTER TABLE IF EXISTS purchases
+    DROP CONSTRAINT IF EXISTS purchases_refunded_quantity_check,
+    DROP COLUMN IF EXISTS refunded_at,
+    DROP COLUMN IF EXISTS refunded_quantity;
+
+ALTER TABLE IF EXISTS items
+    DROP COLUMN IF EXISTS stock;
@@ -0,0 +1,20 @@
+-- Остаток товара на складе, NULL - остаток не отслеживается
+ALTER TABLE items
+    ADD COLUMN stock INTEGER CHECK (stock >= 0);
+
+-- Возвраты: сколько единиц покупки уже возвращено и когда был последний возврат
+ALTER TABLE purchases
+    ADD COLUMN refunded_quantity INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN refunded_at TIMESTAMP,
+    ADD CONSTRAINT purchases_refunded_quantity_check
+        CHECK (refunded_quantity >= 0 AND refunded_quantity <= quantity);
+
+-- Возврат монет за покупку - отдельный вид операции от системного счета
+ALTER TABLE transactions
+    ADD COLUMN purchase_id INTEGER REFERENCES purchases(id) ON DELETE SET NULL;
+
+ALTER TABLE transactions DROP CONSTRAINT transactions_kind_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('transfer', 'grant', 'deduction', 'expiry', 'refund'));
+
+CREATE INDEX idx_transactions_purchase_id ON transactions (purchase_id) WHERE purchase_id IS NOT NULL;
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_purchases_recipient_id;
+
+ALTER TABLE IF EXISTS purchases
+    DROP COLUMN IF EXISTS gift_message,
+    DROP COLUMN IF EXISTS recipient_id;
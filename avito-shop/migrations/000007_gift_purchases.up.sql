@@ -0,0 +1,7 @@
+-- Подарок: товар оплачивает buyer_id, а в инвентарь он попадает recipient_id.
+-- NULL - покупка для себя.
+ALTER TABLE purchases
+    ADD COLUMN recipient_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    ADD COLUMN gift_message VARCHAR(200);
+
+CREATE INDEX idx_purchases_recipient_id ON purchases (recipient_id) WHERE recipient_id IS NOT NULL;
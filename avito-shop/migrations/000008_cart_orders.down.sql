@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_purchases_order_id;
+
+ALTER TABLE IF EXISTS purchases
+    DROP COLUMN IF EXISTS order_id;
+
+DROP TABLE IF EXISTS orders;
+DROP TABLE IF EXISTS cart_items;
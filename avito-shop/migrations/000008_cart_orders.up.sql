@@ -0,0 +1,24 @@
+-- Корзина пользователя: одна строка на товар
+CREATE TABLE cart_items (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0),
+    added_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, item_id)
+);
+
+-- Заказ объединяет покупки, оформленные одним оформлением корзины
+CREATE TABLE orders (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    total_cost INTEGER NOT NULL CHECK (total_cost >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_orders_user_id ON orders (user_id);
+
+ALTER TABLE purchases
+    ADD COLUMN order_id INTEGER REFERENCES orders(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_purchases_order_id ON purchases (order_id);
@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS order_status_history;
+
+DROP INDEX IF EXISTS idx_orders_status;
+
+ALTER TABLE IF EXISTS orders
+    DROP COLUMN IF EXISTS status_updated_at,
+    DROP COLUMN IF EXISTS status;
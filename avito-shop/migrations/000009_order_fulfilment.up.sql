@@ -0,0 +1,63 @@
+-- Статус выдачи заказа: placed -> ready_for_pickup/shipped -> delivered, либо cancelled
+ALTER TABLE orders
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'placed'
+        CHECK (status IN ('placed', 'ready_for_pickup', 'shipped', 'delivered', 'cancelled')),
+    ADD COLUMN status_updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+CREATE INDEX idx_orders_status ON orders (status);
+
+-- История смены статусов с комментарием; changed_by NULL - изменение системой
+CREATE TABLE order_status_history (
+    id SERIAL PRIMARY KEY,
+    order_id INTEGER NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL,
+    note VARCHAR(500),
+    changed_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_status_history_order_id ON order_status_history (order_id);
+
+-- Покупки, оформленные до появления заказов, получают заказ из одной позиции
+DO $$
+DECLARE
+    p RECORD;
+    new_order_id INTEGER;
+BEGIN
+    FOR p IN
+        SELECT id, buyer_id, total_cost, purchase_date
+        FROM purchases
+        WHERE order_id IS NULL AND buyer_id IS NOT NULL
+        ORDER BY id
+    LOOP
+        INSERT INTO orders (user_id, total_cost, created_at)
+        VALUES (p.buyer_id, p.total_cost, p.purchase_date)
+        RETURNING id INTO new_order_id;
+
+        UPDATE purchases SET order_id = new_order_id WHERE id = p.id;
+    END LOOP;
+END $$;
+
+-- Заказы, оформленные до учета выдачи, уже выданы: полностью возвращенные считаются
+-- отмененными, остальные - выданными
+UPDATE orders o
+SET
+    status = CASE
+        WHEN EXISTS (
+            SELECT 1 FROM purchases p
+            WHERE p.order_id = o.id AND p.refunded_quantity < p.quantity
+        ) THEN 'delivered'
+        ELSE 'cancelled'
+    END,
+    status_updated_at = o.created_at;
+
+-- История таких заказов: оформление и итоговый статус, выставленный системой
+INSERT INTO order_status_history (order_id, status, note, changed_at)
+SELECT o.id, s.status, s.note, o.created_at
+FROM orders o
+CROSS JOIN LATERAL (
+    VALUES
+        (1, 'placed', NULL),
+        (2, o.status, 'placed before order fulfilment tracking')
+) AS s (step, status, note)
+ORDER BY o.id, s.step;
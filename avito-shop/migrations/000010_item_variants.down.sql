@@ -0,0 +1,21 @@
+DROP INDEX IF EXISTS idx_cart_items_line;
+
+ALTER TABLE IF EXISTS cart_items
+    DROP COLUMN IF EXISTS variant_id,
+    DROP COLUMN IF EXISTS id;
+
+-- Первичный ключ восстанавливается, только если таблица уже была без него
+DO $$
+BEGIN
+    IF to_regclass('cart_items') IS NOT NULL
+       AND NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'cart_items_pkey') THEN
+        DELETE FROM cart_items a USING cart_items b
+        WHERE a.user_id = b.user_id AND a.item_id = b.item_id AND a.ctid > b.ctid;
+        ALTER TABLE cart_items ADD PRIMARY KEY (user_id, item_id);
+    END IF;
+END $$;
+
+ALTER TABLE IF EXISTS purchases
+    DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS item_variants;
@@ -0,0 +1,25 @@
+-- Варианты товара (размер, цвет) со своим артикулом, ценой и остатком.
+-- price NULL - цена товара, stock NULL - остаток варианта не отслеживается.
+CREATE TABLE item_variants (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    sku VARCHAR(64) UNIQUE NOT NULL,
+    size VARCHAR(20),
+    colour VARCHAR(30),
+    price INTEGER CHECK (price > 0),
+    stock INTEGER CHECK (stock >= 0),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_item_variants_item_id ON item_variants (item_id);
+
+ALTER TABLE purchases
+    ADD COLUMN variant_id INTEGER REFERENCES item_variants(id) ON DELETE SET NULL;
+
+-- Позиция корзины теперь определяется товаром и вариантом
+ALTER TABLE cart_items DROP CONSTRAINT cart_items_pkey;
+ALTER TABLE cart_items
+    ADD COLUMN id SERIAL PRIMARY KEY,
+    ADD COLUMN variant_id INTEGER REFERENCES item_variants(id) ON DELETE CASCADE;
+
+CREATE UNIQUE INDEX idx_cart_items_line ON cart_items (user_id, item_id, COALESCE(variant_id, 0));
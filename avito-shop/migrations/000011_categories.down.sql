@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_items_category_id;
+
+ALTER TABLE IF EXISTS items
+    DROP COLUMN IF EXISTS category_id;
+
+DROP TABLE IF EXISTS categories;
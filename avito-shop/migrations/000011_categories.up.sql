@@ -0,0 +1,21 @@
+-- Категории товаров
+CREATE TABLE categories (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(50) UNIQUE NOT NULL
+);
+
+ALTER TABLE items
+    ADD COLUMN category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+
+CREATE INDEX idx_items_category_id ON items (category_id);
+
+INSERT INTO categories (name)
+VALUES ('clothing'), ('accessories'), ('stationery')
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE items SET category_id = (SELECT id FROM categories WHERE name = 'clothing')
+WHERE name IN ('t-shirt', 'hoody', 'pink-hoody', 'socks') AND category_id IS NULL;
+UPDATE items SET category_id = (SELECT id FROM categories WHERE name = 'accessories')
+WHERE name IN ('cup', 'powerbank', 'umbrella', 'wallet') AND category_id IS NULL;
+UPDATE items SET category_id = (SELECT id FROM categories WHERE name = 'stationery')
+WHERE name IN ('book', 'pen') AND category_id IS NULL;
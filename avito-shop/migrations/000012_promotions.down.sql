@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_purchases_promo_code_id;
+
+ALTER TABLE IF EXISTS purchases
+    DROP COLUMN IF EXISTS discount,
+    DROP COLUMN IF EXISTS promo_code_id;
+
+DROP TABLE IF EXISTS promo_codes;
@@ -0,0 +1,31 @@
+-- Промокоды: скидка в процентах или фиксированная сумма монет.
+-- item_id и category_id NULL - скидка на любой товар.
+-- valid_from/valid_until NULL - без ограничения по времени,
+-- max_uses/max_uses_per_user NULL - без ограничения числа использований.
+CREATE TABLE promo_codes (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(50) UNIQUE NOT NULL,
+    description VARCHAR(200),
+    discount_type VARCHAR(10) NOT NULL CHECK (discount_type IN ('percent', 'fixed')),
+    discount_value INTEGER NOT NULL CHECK (discount_value > 0),
+    item_id INTEGER REFERENCES items(id) ON DELETE CASCADE,
+    category_id INTEGER REFERENCES categories(id) ON DELETE CASCADE,
+    valid_from TIMESTAMP,
+    valid_until TIMESTAMP,
+    max_uses INTEGER CHECK (max_uses > 0),
+    max_uses_per_user INTEGER CHECK (max_uses_per_user > 0),
+    uses INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (discount_type <> 'percent' OR discount_value <= 100),
+    CHECK (item_id IS NULL OR category_id IS NULL),
+    CHECK (valid_from IS NULL OR valid_until IS NULL OR valid_from < valid_until)
+);
+
+-- Скидка, примененная к покупке
+ALTER TABLE purchases
+    ADD COLUMN promo_code_id INTEGER REFERENCES promo_codes(id) ON DELETE SET NULL,
+    ADD COLUMN discount INTEGER NOT NULL DEFAULT 0 CHECK (discount >= 0);
+
+CREATE INDEX idx_purchases_promo_code_id ON purchases (promo_code_id, buyer_id) WHERE promo_code_id IS NOT NULL;
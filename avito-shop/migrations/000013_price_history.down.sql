@@ -0,0 +1,4 @@
+ALTER TABLE IF EXISTS purchases
+    DROP COLUMN IF EXISTS unit_price;
+
+DROP TABLE IF EXISTS item_prices;
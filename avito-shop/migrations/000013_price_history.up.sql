@@ -0,0 +1,30 @@
+-- История цен товаров: действующая цена - последняя запись с effective_from <= now.
+-- Записи с effective_from в будущем - запланированные изменения,
+-- фоновый процесс переносит их в items.price при наступлении срока.
+CREATE TABLE item_prices (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    price INTEGER NOT NULL CHECK (price > 0),
+    effective_from TIMESTAMP NOT NULL,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (item_id, effective_from)
+);
+
+-- Текущие цены считаем действующими с начала эпохи: раньше история не велась
+INSERT INTO item_prices (item_id, price, effective_from)
+SELECT id, price, 'epoch'::timestamp
+FROM items
+ON CONFLICT (item_id, effective_from) DO NOTHING;
+
+-- Цена единицы на момент покупки до скидки
+ALTER TABLE purchases
+    ADD COLUMN unit_price INTEGER;
+
+UPDATE purchases
+SET unit_price = (total_cost + discount) / quantity
+WHERE unit_price IS NULL;
+
+ALTER TABLE purchases
+    ALTER COLUMN unit_price SET NOT NULL,
+    ADD CONSTRAINT purchases_unit_price_check CHECK (unit_price >= 0);
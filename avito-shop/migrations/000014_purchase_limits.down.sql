@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_purchases_owner_item;
+
+ALTER TABLE IF EXISTS items
+    DROP COLUMN IF EXISTS max_per_user,
+    DROP COLUMN IF EXISTS cooldown_days;
@@ -0,0 +1,13 @@
+-- Ограничения покупки товара одним сотрудником:
+-- max_per_user - сколько единиц может быть у сотрудника за все время,
+-- cooldown_days - сколько дней должно пройти между покупками (по одной единице).
+-- NULL - без ограничения. Возвращенные единицы не учитываются.
+ALTER TABLE items
+    ADD COLUMN max_per_user INTEGER CHECK (max_per_user > 0),
+    ADD COLUMN cooldown_days INTEGER CHECK (cooldown_days > 0);
+
+UPDATE items SET max_per_user = 1
+WHERE name = 'pink-hoody' AND max_per_user IS NULL;
+
+-- Подсчет купленного владельцем: подарок принадлежит получателю
+CREATE INDEX idx_purchases_owner_item ON purchases (item_id, (COALESCE(recipient_id, buyer_id)));
@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS item_tags;
+
+DROP INDEX IF EXISTS idx_items_search;
+
+ALTER TABLE IF EXISTS items
+    DROP COLUMN IF EXISTS description;
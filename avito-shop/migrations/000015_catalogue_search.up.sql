@@ -0,0 +1,17 @@
+-- Описание товара участвует в полнотекстовом поиске вместе с названием
+ALTER TABLE items
+    ADD COLUMN description TEXT;
+
+-- Конфигурация simple: названия и описания смешивают русский и английский,
+-- поэтому слова ищутся без стемминга
+CREATE INDEX idx_items_search ON items
+    USING GIN (to_tsvector('simple', name || ' ' || COALESCE(description, '')));
+
+-- Свободные теги товаров в нижнем регистре
+CREATE TABLE item_tags (
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    tag VARCHAR(30) NOT NULL,
+    PRIMARY KEY (item_id, tag)
+);
+
+CREATE INDEX idx_item_tags_tag ON item_tags (tag);
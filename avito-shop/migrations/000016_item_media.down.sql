@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS item_attributes;
+
+DROP TABLE IF EXISTS item_images;
@@ -0,0 +1,24 @@
+-- Изображения товара: оригинал и миниатюра лежат в хранилище файлов,
+-- в базе только ключи; position задает порядок показа
+CREATE TABLE item_images (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    storage_key VARCHAR(255) NOT NULL UNIQUE,
+    thumbnail_key VARCHAR(255) NOT NULL UNIQUE,
+    content_type VARCHAR(50) NOT NULL,
+    width INTEGER NOT NULL,
+    height INTEGER NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_item_images_item ON item_images (item_id, position);
+
+-- Характеристики товара: материал, размер упаковки и т.п.
+CREATE TABLE item_attributes (
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (item_id, name)
+);
@@ -0,0 +1,25 @@
+-- Список желаний: товары, которые пользователь хочет купить позже.
+-- affordable_notified - уведомление о том, что баланса хватает на товар, уже отправлено;
+-- флаг сбрасывается, когда баланса снова не хватает
+CREATE TABLE wishlist_items (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    affordable_notified BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, item_id)
+);
+
+CREATE INDEX idx_wishlist_items_item ON wishlist_items (item_id);
+
+-- Уведомления пользователей
+CREATE TABLE notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(30) NOT NULL,
+    item_id INTEGER REFERENCES items(id) ON DELETE CASCADE,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    read_at TIMESTAMP
+);
+
+CREATE INDEX idx_notifications_user ON notifications (user_id, id DESC);
@@ -0,0 +1,11 @@
+DROP TABLE IF EXISTS drop_queue;
+
+DROP INDEX IF EXISTS idx_items_available_from;
+
+ALTER TABLE IF EXISTS items
+    DROP CONSTRAINT IF EXISTS items_queue_check,
+    DROP CONSTRAINT IF EXISTS items_available_window_check,
+    DROP COLUMN IF EXISTS queue_rate,
+    DROP COLUMN IF EXISTS queue_minutes,
+    DROP COLUMN IF EXISTS available_until,
+    DROP COLUMN IF EXISTS available_from;
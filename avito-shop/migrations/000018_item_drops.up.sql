@@ -0,0 +1,29 @@
+-- Окно продаж товара: до available_from товар виден только как анонс,
+-- после available_until не продается. NULL - без ограничения
+ALTER TABLE items
+    ADD COLUMN available_from TIMESTAMP,
+    ADD COLUMN available_until TIMESTAMP,
+    -- Честная очередь первых queue_minutes минут продаж: каждую минуту
+    -- к покупке допускается еще queue_rate участников очереди
+    ADD COLUMN queue_minutes INTEGER CHECK (queue_minutes > 0),
+    ADD COLUMN queue_rate INTEGER CHECK (queue_rate > 0),
+    ADD CONSTRAINT items_available_window_check
+        CHECK (available_until IS NULL OR available_from IS NULL OR available_until > available_from),
+    ADD CONSTRAINT items_queue_check
+        CHECK ((queue_minutes IS NULL) = (queue_rate IS NULL)
+               AND (queue_minutes IS NULL OR available_from IS NOT NULL));
+
+CREATE INDEX idx_items_available_from ON items (available_from)
+    WHERE available_from IS NOT NULL;
+
+-- Очередь на дроп. position задает порядок: до начала продаж место случайное,
+-- после - по времени входа в очередь
+CREATE TABLE drop_queue (
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    position BIGINT NOT NULL,
+    joined_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (item_id, user_id)
+);
+
+CREATE INDEX idx_drop_queue_position ON drop_queue (item_id, position, user_id);
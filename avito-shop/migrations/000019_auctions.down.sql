@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS auction_bid_lots;
+DROP TABLE IF EXISTS auction_bids;
+DROP TABLE IF EXISTS auctions;
@@ -0,0 +1,50 @@
+-- Аукционы: товар уходит участнику с наибольшей ставкой не ниже резервной цены.
+-- status: active - прием ставок или ожидание закрытия, settled - продан победителю,
+-- unsold - ставок не было или они ниже резервной цены, cancelled - отменен администратором
+CREATE TABLE auctions (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    reserve_price INTEGER NOT NULL DEFAULT 0 CHECK (reserve_price >= 0),
+    min_increment INTEGER NOT NULL DEFAULT 1 CHECK (min_increment > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'settled', 'unsold', 'cancelled')),
+    purchase_id INTEGER REFERENCES purchases(id) ON DELETE SET NULL,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_auctions_active_ends_at ON auctions (ends_at) WHERE status = 'active';
+
+-- Ставки. Монеты активной ставки зарезервированы: списаны с баланса участника
+-- и хранятся в auction_bid_lots. status: active - лидирующая ставка,
+-- released - перебита или аукцион не состоялся, монеты возвращены, won - выигравшая
+CREATE TABLE auction_bids (
+    id SERIAL PRIMARY KEY,
+    auction_id INTEGER NOT NULL REFERENCES auctions(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'released', 'won')),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX idx_auction_bids_auction ON auction_bids (auction_id, amount DESC);
+CREATE INDEX idx_auction_bids_user ON auction_bids (user_id) WHERE status = 'active';
+-- У аукциона не больше одной лидирующей ставки
+CREATE UNIQUE INDEX idx_auction_bids_active ON auction_bids (auction_id) WHERE status = 'active';
+
+-- Части лотов, зарезервированные ставкой: при освобождении монеты
+-- возвращаются в лоты с исходным сроком действия
+CREATE TABLE auction_bid_lots (
+    id SERIAL PRIMARY KEY,
+    bid_id INTEGER NOT NULL REFERENCES auction_bids(id) ON DELETE CASCADE,
+    amount INTEGER NOT NULL CHECK (amount > 0),
+    expires_at TIMESTAMP
+);
+
+CREATE INDEX idx_auction_bid_lots_bid_id ON auction_bid_lots (bid_id);
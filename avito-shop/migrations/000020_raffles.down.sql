@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS raffle_tickets;
+DROP TABLE IF EXISTS raffles;
+
+DELETE FROM transactions WHERE kind = 'raffle';
+
+ALTER TABLE IF EXISTS transactions DROP CONSTRAINT IF EXISTS transactions_kind_check;
+ALTER TABLE IF EXISTS transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('transfer', 'grant', 'deduction', 'expiry', 'refund'));
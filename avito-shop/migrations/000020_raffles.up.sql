@@ -0,0 +1,42 @@
+-- Розыгрыши: участники покупают билеты за монеты, победители получают приз.
+-- Перед продажей билетов публикуется seed_hash - sha256 от секретного зерна;
+-- зерно раскрывается при розыгрыше, и любой участник может повторить выбор победителей.
+-- Единицы приза резервируются на складе при создании розыгрыша.
+-- status: open - идет продажа билетов или ожидание розыгрыша, drawn - победители выбраны
+CREATE TABLE raffles (
+    id SERIAL PRIMARY KEY,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    ticket_price INTEGER NOT NULL CHECK (ticket_price > 0),
+    -- Сколько билетов может купить один участник, NULL - без ограничения
+    max_tickets_per_user INTEGER CHECK (max_tickets_per_user > 0),
+    winners INTEGER NOT NULL DEFAULT 1 CHECK (winners > 0),
+    sales_end_at TIMESTAMP NOT NULL,
+    seed_hash VARCHAR(64) NOT NULL,
+    seed VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open'
+        CHECK (status IN ('open', 'drawn')),
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    drawn_at TIMESTAMP
+);
+
+CREATE INDEX idx_raffles_open_sales_end_at ON raffles (sales_end_at) WHERE status = 'open';
+
+-- Билеты. Порядок id определяет номер билета при розыгрыше;
+-- purchase_id заполняется у выигравших билетов
+CREATE TABLE raffle_tickets (
+    id SERIAL PRIMARY KEY,
+    raffle_id INTEGER NOT NULL REFERENCES raffles(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    transaction_id INTEGER NOT NULL REFERENCES transactions(id) ON DELETE CASCADE,
+    purchase_id INTEGER REFERENCES purchases(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_raffle_tickets_raffle ON raffle_tickets (raffle_id, id);
+CREATE INDEX idx_raffle_tickets_user ON raffle_tickets (raffle_id, user_id);
+
+-- Оплата билетов - отдельный вид операции: монеты уходят на системный счет
+ALTER TABLE transactions DROP CONSTRAINT transactions_kind_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('transfer', 'grant', 'deduction', 'expiry', 'refund', 'raffle'));
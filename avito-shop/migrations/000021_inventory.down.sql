@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS inventory_transfers;
+DROP TABLE IF EXISTS inventory_items;
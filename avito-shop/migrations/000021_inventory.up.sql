@@ -0,0 +1,47 @@
+-- Инвентарь: каждая принадлежащая пользователю единица товара - отдельная строка.
+-- purchase_id - покупка, с которой единица появилась, acquired_via и acquired_from -
+-- как и от кого ее получил текущий владелец: purchase - купил сам, gift - подарок
+-- при покупке, transfer - передана другим пользователем
+CREATE TABLE inventory_items (
+    id SERIAL PRIMARY KEY,
+    owner_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES item_variants(id) ON DELETE SET NULL,
+    purchase_id INTEGER REFERENCES purchases(id) ON DELETE CASCADE,
+    acquired_via VARCHAR(20) NOT NULL
+        CHECK (acquired_via IN ('purchase', 'gift', 'transfer')),
+    acquired_from INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    acquired_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_inventory_items_owner ON inventory_items (owner_id, item_id);
+CREATE INDEX idx_inventory_items_purchase ON inventory_items (purchase_id);
+
+-- История передач единиц между пользователями
+CREATE TABLE inventory_transfers (
+    id SERIAL PRIMARY KEY,
+    inventory_item_id INTEGER NOT NULL REFERENCES inventory_items(id) ON DELETE CASCADE,
+    from_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    to_user_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'give'
+        CHECK (kind IN ('give')),
+    message VARCHAR(200),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_inventory_transfers_item ON inventory_transfers (inventory_item_id);
+
+-- Невозвращенные единицы существующих покупок принадлежат получателю подарка или покупателю
+INSERT INTO inventory_items (owner_id, item_id, variant_id, purchase_id, acquired_via, acquired_from, acquired_at)
+SELECT
+    COALESCE(p.recipient_id, p.buyer_id),
+    p.item_id,
+    p.variant_id,
+    p.id,
+    CASE WHEN p.recipient_id IS NULL THEN 'purchase' ELSE 'gift' END,
+    CASE WHEN p.recipient_id IS NULL THEN NULL ELSE p.buyer_id END,
+    COALESCE(p.purchase_date, CURRENT_TIMESTAMP)
+FROM purchases p
+CROSS JOIN LATERAL generate_series(1, p.quantity - p.refunded_quantity)
+WHERE COALESCE(p.recipient_id, p.buyer_id) IS NOT NULL
+  AND p.item_id IS NOT NULL;
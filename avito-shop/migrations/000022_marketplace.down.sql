@@ -0,0 +1,19 @@
+DROP TABLE IF EXISTS marketplace_listings;
+
+DELETE FROM transactions WHERE kind IN ('sale', 'commission');
+
+ALTER TABLE IF EXISTS transactions DROP CONSTRAINT IF EXISTS transactions_kind_check;
+ALTER TABLE IF EXISTS transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('transfer', 'grant', 'deduction', 'expiry', 'refund', 'raffle'));
+
+DELETE FROM inventory_transfers WHERE kind = 'sale';
+
+ALTER TABLE IF EXISTS inventory_transfers DROP CONSTRAINT IF EXISTS inventory_transfers_kind_check;
+ALTER TABLE IF EXISTS inventory_transfers ADD CONSTRAINT inventory_transfers_kind_check
+    CHECK (kind IN ('give'));
+
+UPDATE inventory_items SET acquired_via = 'transfer' WHERE acquired_via = 'marketplace';
+
+ALTER TABLE IF EXISTS inventory_items DROP CONSTRAINT IF EXISTS inventory_items_acquired_via_check;
+ALTER TABLE IF EXISTS inventory_items ADD CONSTRAINT inventory_items_acquired_via_check
+    CHECK (acquired_via IN ('purchase', 'gift', 'transfer'));
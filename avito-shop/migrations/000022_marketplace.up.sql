@@ -0,0 +1,38 @@
+-- Объявления о продаже единиц товара из инвентаря другим пользователям.
+-- У единицы может быть только одно активное объявление; commission - комиссия магазина,
+-- удержанная при продаже, payment_id - зачисление продавцу
+CREATE TABLE marketplace_listings (
+    id SERIAL PRIMARY KEY,
+    seller_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    inventory_item_id INTEGER NOT NULL REFERENCES inventory_items(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES item_variants(id) ON DELETE SET NULL,
+    price INTEGER NOT NULL CHECK (price > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'sold', 'cancelled')),
+    buyer_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    commission INTEGER NOT NULL DEFAULT 0 CHECK (commission >= 0),
+    payment_id INTEGER REFERENCES transactions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_marketplace_listings_active_unit ON marketplace_listings (inventory_item_id)
+    WHERE status = 'active';
+CREATE INDEX idx_marketplace_listings_active_item ON marketplace_listings (item_id, created_at)
+    WHERE status = 'active';
+CREATE INDEX idx_marketplace_listings_seller ON marketplace_listings (seller_id, created_at);
+
+-- Купленная у коллеги единица и сама продажа отличаются от безвозмездной передачи
+ALTER TABLE inventory_items DROP CONSTRAINT inventory_items_acquired_via_check;
+ALTER TABLE inventory_items ADD CONSTRAINT inventory_items_acquired_via_check
+    CHECK (acquired_via IN ('purchase', 'gift', 'transfer', 'marketplace'));
+
+ALTER TABLE inventory_transfers DROP CONSTRAINT inventory_transfers_kind_check;
+ALTER TABLE inventory_transfers ADD CONSTRAINT inventory_transfers_kind_check
+    CHECK (kind IN ('give', 'sale'));
+
+-- Оплата продавцу и комиссия магазина, которая уходит на системный счет
+ALTER TABLE transactions DROP CONSTRAINT transactions_kind_check;
+ALTER TABLE transactions ADD CONSTRAINT transactions_kind_check
+    CHECK (kind IN ('transfer', 'grant', 'deduction', 'expiry', 'refund', 'raffle', 'sale', 'commission'));
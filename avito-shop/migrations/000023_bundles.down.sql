@@ -0,0 +1,4 @@
+ALTER TABLE IF EXISTS orders DROP COLUMN IF EXISTS bundle_id;
+
+DROP TABLE IF EXISTS bundle_items;
+DROP TABLE IF EXISTS bundles;
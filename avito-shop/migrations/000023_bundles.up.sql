@@ -0,0 +1,25 @@
+-- Наборы товаров, которые продаются вместе по общей цене
+CREATE TABLE bundles (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT,
+    price INTEGER NOT NULL CHECK (price > 0),
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Состав набора: товар или его вариант и количество
+CREATE TABLE bundle_items (
+    id SERIAL PRIMARY KEY,
+    bundle_id INTEGER NOT NULL REFERENCES bundles(id) ON DELETE CASCADE,
+    item_id INTEGER NOT NULL REFERENCES items(id) ON DELETE CASCADE,
+    variant_id INTEGER REFERENCES item_variants(id) ON DELETE CASCADE,
+    quantity INTEGER NOT NULL CHECK (quantity > 0)
+);
+
+CREATE UNIQUE INDEX idx_bundle_items_component ON bundle_items (bundle_id, item_id, COALESCE(variant_id, 0));
+
+-- Заказ, оформленный покупкой набора
+ALTER TABLE orders
+    ADD COLUMN bundle_id INTEGER REFERENCES bundles(id) ON DELETE SET NULL;
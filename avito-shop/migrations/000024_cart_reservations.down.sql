@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_cart_items_reservations;
+
+ALTER TABLE IF EXISTS cart_items
+    DROP COLUMN IF EXISTS reserved_until,
+    DROP COLUMN IF EXISTS reserved_quantity;
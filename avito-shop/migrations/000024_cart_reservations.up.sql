@@ -0,0 +1,8 @@
+-- Резерв остатка под позицию корзины: reserved_quantity единиц недоступны
+-- другим покупателям до reserved_until
+ALTER TABLE cart_items
+    ADD COLUMN reserved_quantity INTEGER NOT NULL DEFAULT 0 CHECK (reserved_quantity >= 0),
+    ADD COLUMN reserved_until TIMESTAMP;
+
+CREATE INDEX idx_cart_items_reservations ON cart_items (item_id, variant_id, reserved_until)
+    WHERE reserved_quantity > 0;